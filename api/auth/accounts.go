@@ -0,0 +1,102 @@
+package auth
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// MinPasswordLength はパスワードの最小の長さ（バイト数）です。
+const MinPasswordLength = 8
+
+// maxPasswordBytes は bcrypt で扱えるパスワードの最大のバイト数
+const maxPasswordBytes = 72
+
+// guestPrefix はゲストの PlayerID の接頭辞（登録プレイヤーの名前には使えない）
+const guestPrefix = "guest-"
+
+// validName は登録プレイヤーの名前（3〜32 文字の英数字・_・-）
+var validName = regexp.MustCompile(`^[A-Za-z0-9_-]{3,32}$`)
+
+// AccountService はプレイヤーの登録・ログインとゲストの発行を行うインタフェース
+type AccountService interface {
+	// Register は名前とパスワードでプレイヤーを登録する。名前は大文字・小文字を区別せず、小文字にそろえて保存する
+	Register(name, password string) (Identity, error)
+	// Login は名前とパスワードを確かめ、登録プレイヤーを返す。名前は大文字・小文字を区別しない
+	Login(name, password string) (Identity, error)
+	// Guest は新しいゲストを発行する
+	Guest() (Identity, error)
+}
+
+type accountService struct {
+	mu     sync.RWMutex
+	hashes map[string][]byte // 小文字にそろえた名前 → bcrypt のハッシュ
+	cost   int
+	// dummyHash は存在しない名前のログインでも照合の時間をかけ、名前の有無を応答時間から推測させないためのハッシュ
+	dummyHash []byte
+}
+
+// NewAccountService はメモリ上にプレイヤーを保持するサービスを生成します。
+// パスワードは cost の bcrypt でハッシュして保存します（0 なら bcrypt.DefaultCost）。
+func NewAccountService(cost int) AccountService {
+	if cost == 0 {
+		cost = bcrypt.DefaultCost
+	}
+	dummy, err := bcrypt.GenerateFromPassword([]byte("dummy password"), cost)
+	if err != nil {
+		panic(err)
+	}
+	return &accountService{hashes: make(map[string][]byte), cost: cost, dummyHash: dummy}
+}
+
+func (s *accountService) Register(name, password string) (Identity, error) {
+	// Alice と alice が別のプレイヤーにならないよう、名前は小文字にそろえる
+	name = strings.ToLower(name)
+	if !validName.MatchString(name) || strings.HasPrefix(name, guestPrefix) {
+		return Identity{}, fmt.Errorf("%w: name must be 3-32 letters, digits, _ or - and must not start with %q", ErrInvalidName, guestPrefix)
+	}
+	// bcrypt は 72 バイトまでしか扱えない
+	if len(password) < MinPasswordLength || len(password) > maxPasswordBytes {
+		return Identity{}, fmt.Errorf("%w: password must be %d to %d bytes", ErrInvalidPassword, MinPasswordLength, maxPasswordBytes)
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), s.cost)
+	if err != nil {
+		return Identity{}, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.hashes[name]; ok {
+		return Identity{}, ErrNameTaken
+	}
+	s.hashes[name] = hash
+	return Identity{PlayerID: name, Name: name}, nil
+}
+
+func (s *accountService) Login(name, password string) (Identity, error) {
+	name = strings.ToLower(name)
+	s.mu.RLock()
+	hash, ok := s.hashes[name]
+	s.mu.RUnlock()
+	if !ok {
+		bcrypt.CompareHashAndPassword(s.dummyHash, []byte(password))
+		return Identity{}, ErrInvalidCredentials
+	}
+	if err := bcrypt.CompareHashAndPassword(hash, []byte(password)); err != nil {
+		return Identity{}, ErrInvalidCredentials
+	}
+	return Identity{PlayerID: name, Name: name}, nil
+}
+
+func (s *accountService) Guest() (Identity, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return Identity{}, err
+	}
+	return Identity{PlayerID: guestPrefix + hex.EncodeToString(b), Guest: true}, nil
+}
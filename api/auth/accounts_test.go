@@ -0,0 +1,86 @@
+package auth
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestAccountService_RegisterAndLogin(t *testing.T) {
+	s := NewAccountService(bcrypt.MinCost)
+
+	id, err := s.Register("alice", "correct horse")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != (Identity{PlayerID: "alice", Name: "alice"}) {
+		t.Errorf("unexpected identity: %+v", id)
+	}
+	if _, err := s.Register("alice", "another pass"); !errors.Is(err, ErrNameTaken) {
+		t.Errorf("duplicate register: got %v, want ErrNameTaken", err)
+	}
+
+	if got, err := s.Login("alice", "correct horse"); err != nil || got != id {
+		t.Errorf("login = %+v, %v", got, err)
+	}
+	for _, tc := range []struct{ name, password string }{
+		{"alice", "wrong password"},
+		{"nobody", "correct horse"},
+	} {
+		if _, err := s.Login(tc.name, tc.password); !errors.Is(err, ErrInvalidCredentials) {
+			t.Errorf("login(%q, %q): got %v, want ErrInvalidCredentials", tc.name, tc.password, err)
+		}
+	}
+}
+
+func TestAccountService_NamesAreCaseInsensitive(t *testing.T) {
+	s := NewAccountService(bcrypt.MinCost)
+
+	id, err := s.Register("Alice", "correct horse")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != (Identity{PlayerID: "alice", Name: "alice"}) {
+		t.Errorf("unexpected identity: %+v", id)
+	}
+	if _, err := s.Register("ALICE", "another pass"); !errors.Is(err, ErrNameTaken) {
+		t.Errorf("register with another case: got %v, want ErrNameTaken", err)
+	}
+	for _, name := range []string{"alice", "ALICE", "aLiCe"} {
+		if got, err := s.Login(name, "correct horse"); err != nil || got != id {
+			t.Errorf("login(%q) = %+v, %v", name, got, err)
+		}
+	}
+}
+
+func TestAccountService_RegisterValidates(t *testing.T) {
+	s := NewAccountService(bcrypt.MinCost)
+	for _, tc := range []struct {
+		name, password string
+		want           error
+	}{
+		{"al", "correct horse", ErrInvalidName},
+		{"alice bob", "correct horse", ErrInvalidName},
+		{"guest-alice", "correct horse", ErrInvalidName},
+		{"alice", "short", ErrInvalidPassword},
+		{"alice", strings.Repeat("x", maxPasswordBytes+1), ErrInvalidPassword},
+	} {
+		if _, err := s.Register(tc.name, tc.password); !errors.Is(err, tc.want) {
+			t.Errorf("register(%q, %d bytes): got %v, want %v", tc.name, len(tc.password), err, tc.want)
+		}
+	}
+}
+
+func TestAccountService_Guest(t *testing.T) {
+	s := NewAccountService(bcrypt.MinCost)
+	a, err := s.Guest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := s.Guest()
+	if !a.Guest || !strings.HasPrefix(a.PlayerID, guestPrefix) || a.PlayerID == b.PlayerID {
+		t.Errorf("unexpected guests: %+v, %+v", a, b)
+	}
+}
@@ -0,0 +1,40 @@
+// Package auth はプレイヤーの登録・ログイン、ゲスト、署名付きトークン（JWT）による認証を提供します。
+//
+// 認証したプレイヤーは Identity としてリクエストのコンテキストに入れ、成績の記録やトーナメント・
+// テーブルの操作はその PlayerID で行います。登録していないプレイヤーはゲストのトークンで遊べます。
+package auth
+
+import (
+	"context"
+	"errors"
+)
+
+// 認証で返すエラー
+// 詳細を付ける場合は fmt.Errorf("%w: ...", Err...) で包むので、判定には errors.Is を使ってください。
+var (
+	ErrInvalidName        = errors.New("invalid player name")
+	ErrInvalidPassword    = errors.New("invalid password")
+	ErrNameTaken          = errors.New("player name is already taken")
+	ErrInvalidCredentials = errors.New("invalid name or password")
+	ErrInvalidToken       = errors.New("invalid or expired token")
+)
+
+// Identity は認証したプレイヤーです。
+type Identity struct {
+	PlayerID string `json:"player_id"`      // 成績やランキングに使う ID（登録プレイヤーは名前、ゲストは guest- で始まる ID）
+	Name     string `json:"name,omitempty"` // 登録プレイヤーの名前（ゲストは空）
+	Guest    bool   `json:"guest"`
+}
+
+type identityKey struct{}
+
+// WithIdentity は ctx に認証したプレイヤーを記録します。
+func WithIdentity(ctx context.Context, id Identity) context.Context {
+	return context.WithValue(ctx, identityKey{}, id)
+}
+
+// FromContext は ctx に記録された認証済みのプレイヤーを返します。認証していなければ false を返します。
+func FromContext(ctx context.Context) (Identity, bool) {
+	id, ok := ctx.Value(identityKey{}).(Identity)
+	return id, ok
+}
@@ -0,0 +1,91 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// TokenService はプレイヤーのトークンを発行・検証するインタフェース
+type TokenService interface {
+	// Issue はプレイヤーのトークンと有効期限を返す
+	Issue(id Identity) (string, time.Time, error)
+	// Verify はトークンの署名と有効期限を確かめ、プレイヤーを返す
+	Verify(token string) (Identity, error)
+}
+
+// jwtHeader は HS256 の JWT のヘッダ（固定）
+var jwtHeader = base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`))
+
+// claims は JWT のペイロード
+type claims struct {
+	Subject   string `json:"sub"`
+	Name      string `json:"name,omitempty"`
+	Guest     bool   `json:"guest,omitempty"`
+	IssuedAt  int64  `json:"iat"`
+	ExpiresAt int64  `json:"exp"`
+}
+
+type tokenService struct {
+	secret []byte
+	ttl    time.Duration
+	now    func() time.Time
+}
+
+// NewTokenService は secret で HMAC-SHA256 の署名をした、ttl の間有効な JWT を扱うサービスを生成します。
+func NewTokenService(secret []byte, ttl time.Duration) TokenService {
+	if len(secret) == 0 {
+		panic("token secret must not be empty")
+	}
+	return &tokenService{secret: secret, ttl: ttl, now: time.Now}
+}
+
+func (s *tokenService) Issue(id Identity) (string, time.Time, error) {
+	now := s.now()
+	expires := now.Add(s.ttl)
+	payload, err := json.Marshal(claims{
+		Subject:   id.PlayerID,
+		Name:      id.Name,
+		Guest:     id.Guest,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: expires.Unix(),
+	})
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	signed := jwtHeader + "." + base64.RawURLEncoding.EncodeToString(payload)
+	return signed + "." + s.sign(signed), expires, nil
+}
+
+func (s *tokenService) Verify(token string) (Identity, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 || parts[0] != jwtHeader {
+		return Identity{}, fmt.Errorf("%w: malformed token", ErrInvalidToken)
+	}
+	if !hmac.Equal([]byte(parts[2]), []byte(s.sign(parts[0]+"."+parts[1]))) {
+		return Identity{}, fmt.Errorf("%w: bad signature", ErrInvalidToken)
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return Identity{}, fmt.Errorf("%w: malformed payload", ErrInvalidToken)
+	}
+	var c claims
+	if err := json.Unmarshal(payload, &c); err != nil || c.Subject == "" {
+		return Identity{}, fmt.Errorf("%w: malformed payload", ErrInvalidToken)
+	}
+	if !s.now().Before(time.Unix(c.ExpiresAt, 0)) {
+		return Identity{}, fmt.Errorf("%w: token expired", ErrInvalidToken)
+	}
+	return Identity{PlayerID: c.Subject, Name: c.Name, Guest: c.Guest}, nil
+}
+
+// sign は署名対象（ヘッダ.ペイロード）の HMAC-SHA256 を base64url で返します。
+func (s *tokenService) sign(signed string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(signed))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,59 @@
+package auth
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTokenService_IssueAndVerify(t *testing.T) {
+	now := time.Date(2026, time.October, 19, 12, 0, 0, 0, time.UTC)
+	s := &tokenService{secret: []byte("secret"), ttl: time.Hour, now: func() time.Time { return now }}
+
+	id := Identity{PlayerID: "guest-0123456789abcdef", Guest: true}
+	token, expires, err := s.Issue(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !expires.Equal(now.Add(time.Hour)) {
+		t.Errorf("expires = %v, want %v", expires, now.Add(time.Hour))
+	}
+	if got, err := s.Verify(token); err != nil || got != id {
+		t.Errorf("verify = %+v, %v, want %+v", got, err, id)
+	}
+
+	// 有効期限を過ぎたトークンは使えない
+	now = now.Add(time.Hour)
+	if _, err := s.Verify(token); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("expired token: got %v, want ErrInvalidToken", err)
+	}
+}
+
+func TestTokenService_RejectsTamperedToken(t *testing.T) {
+	s := NewTokenService([]byte("secret"), time.Hour)
+	token, _, _ := s.Issue(Identity{PlayerID: "alice", Name: "alice"})
+	other, _, _ := s.Issue(Identity{PlayerID: "bob", Name: "bob"})
+	parts := strings.Split(token, ".")
+	otherParts := strings.Split(other, ".")
+
+	for name, tampered := range map[string]string{
+		"payload swapped": parts[0] + "." + otherParts[1] + "." + parts[2],
+		"other secret":    mustIssue(t, NewTokenService([]byte("other"), time.Hour)),
+		"alg none":        "eyJhbGciOiJub25lIn0." + parts[1] + ".",
+		"truncated":       parts[0] + "." + parts[1],
+	} {
+		if _, err := s.Verify(tampered); !errors.Is(err, ErrInvalidToken) {
+			t.Errorf("%s: got %v, want ErrInvalidToken", name, err)
+		}
+	}
+}
+
+func mustIssue(t *testing.T, s TokenService) string {
+	t.Helper()
+	token, _, err := s.Issue(Identity{PlayerID: "alice", Name: "alice"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return token
+}
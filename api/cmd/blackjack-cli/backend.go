@@ -0,0 +1,130 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"blackjack/api/game"
+	"blackjack/api/handlers"
+	"blackjack/api/services"
+	"blackjack/api/strategy"
+)
+
+// backend はゲームを進める相手（プロセス内のサービスまたはリモートのサーバー）を表すインタフェース
+type backend interface {
+	services.GameStarter
+	services.Hitter
+	services.Stander
+	services.Surrenderer
+	services.StrategyAdvisor
+}
+
+// inProcessBackend は services.GameService と StrategyAdvisor を直接呼び出すバックエンド
+type inProcessBackend struct {
+	services.GameService
+	services.StrategyAdvisor
+}
+
+// newInProcessBackend は deck で配るプロセス内のバックエンドを生成します。
+func newInProcessBackend(deck game.Deck) backend {
+	return inProcessBackend{
+		GameService:     services.NewGameService(deck),
+		StrategyAdvisor: services.NewStrategyService(),
+	}
+}
+
+// remoteBackend は HTTP API の /api/v2 を呼び出すバックエンド
+type remoteBackend struct {
+	baseURL string
+	client  *http.Client
+}
+
+// newRemoteBackend は baseURL（例: http://localhost:8080）のサーバーを使うバックエンドを生成します。
+func newRemoteBackend(baseURL string, client *http.Client) backend {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &remoteBackend{baseURL: strings.TrimRight(baseURL, "/"), client: client}
+}
+
+// post は path に req を JSON で送り、レスポンスを resp に読み込みます。
+// エラーのレスポンスはサーバーのメッセージをエラーとして返します。
+func (b *remoteBackend) post(path string, req, resp interface{}) error {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+	res, err := b.client.Post(b.baseURL+"/api/v2"+path, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		var e handlers.ErrorResponse
+		if err := json.NewDecoder(res.Body).Decode(&e); err != nil || e.Message == "" {
+			return fmt.Errorf("%s %s: %s", http.MethodPost, path, res.Status)
+		}
+		return fmt.Errorf("%s (%s)", e.Message, e.Code)
+	}
+	return json.NewDecoder(res.Body).Decode(resp)
+}
+
+func (b *remoteBackend) NewGame(bet int, config *game.GameConfig) (game.Game, error) {
+	var resp handlers.GameV2
+	if err := b.post("/game/new", handlers.NewGameRequest{Bet: bet, Config: config}, &resp); err != nil {
+		return game.Game{}, err
+	}
+	return resp.Game()
+}
+
+// act は path の行動をゲームの ID で送り、g をレスポンスのゲームに置き換えます。
+// ゲームとルールはサーバーが保持しているので、config は送りません。
+func (b *remoteBackend) act(path string, g *game.Game, config *game.GameConfig) error {
+	var resp handlers.ActionResponseV2
+	if err := b.post(path, handlers.ActionRequestV2{GameID: g.ID}, &resp); err != nil {
+		return err
+	}
+	next, err := resp.GameV2.Game()
+	if err != nil {
+		return err
+	}
+	*g = next
+	return nil
+}
+
+func (b *remoteBackend) Hit(g *game.Game, config *game.GameConfig) error {
+	return b.act("/game/hit", g, config)
+}
+
+func (b *remoteBackend) Stand(g *game.Game, config *game.GameConfig) error {
+	return b.act("/game/stand", g, config)
+}
+
+func (b *remoteBackend) Surrender(g *game.Game, config *game.GameConfig) error {
+	return b.act("/game/surrender", g, config)
+}
+
+func (b *remoteBackend) Advise(g game.Game, config *game.GameConfig) (strategy.StrategyExpectedPayouts, error) {
+	var resp handlers.StrategyResponse
+	if err := b.post("/strategy/advise", handlers.StrategyRequestV2{Game: handlers.NewGameV2(g), Config: *config}, &resp); err != nil {
+		return strategy.StrategyExpectedPayouts{}, err
+	}
+	// HTTP API は最善の期待値を返さないので、3 つの行動から求める
+	best := resp.HitPayout
+	if resp.StandPayout > best {
+		best = resp.StandPayout
+	}
+	if resp.SurrenderPayout > best {
+		best = resp.SurrenderPayout
+	}
+	return strategy.StrategyExpectedPayouts{
+		HitPayout:       resp.HitPayout,
+		StandPayout:     resp.StandPayout,
+		SurrenderPayout: resp.SurrenderPayout,
+		BestPayout:      best,
+	}, nil
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"blackjack/api/game"
+	"blackjack/api/handlers"
+	"blackjack/api/services"
+
+	"github.com/gorilla/mux"
+)
+
+// newTestServer は deck で配る v2 のゲームと戦略アドバイスのエンドポイントを持つサーバーを起動します。
+func newTestServer(t *testing.T, deck game.Deck) *httptest.Server {
+	t.Helper()
+	gameSvc := services.NewGameService(deck)
+	store := services.NewGameStore()
+	router := mux.NewRouter()
+	v2 := router.PathPrefix("/api/v2").Subrouter()
+	v2.HandleFunc("/game/new", handlers.NewGameV2Handler(gameSvc, services.NewSideBetService(game.DefaultSideBetPaytables()), store, nil)).Methods("POST")
+	v2.HandleFunc("/game/hit", handlers.HitV2Handler(gameSvc, store, nil, nil)).Methods("POST")
+	v2.HandleFunc("/game/stand", handlers.StandV2Handler(gameSvc, store, nil, nil)).Methods("POST")
+	v2.HandleFunc("/game/surrender", handlers.SurrenderV2Handler(gameSvc, store, nil, nil)).Methods("POST")
+	v2.HandleFunc("/strategy/advise", handlers.StrategyV2Handler(services.NewStrategyService())).Methods("POST")
+	srv := httptest.NewServer(router)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestRemoteBackend_PlaysLikeInProcess(t *testing.T) {
+	config := game.GameConfig{DealerStandThreshold: 17}
+	const input = "\n?\nh\ns\n20\nr\nq\n"
+
+	var local, remote bytes.Buffer
+	if err := newSession(newInProcessBackend(twoRounds()), config, strings.NewReader(input), &local, 100, 10).run(); err != nil {
+		t.Fatal(err)
+	}
+	srv := newTestServer(t, twoRounds())
+	if err := newSession(newRemoteBackend(srv.URL+"/", srv.Client()), config, strings.NewReader(input), &remote, 100, 10).run(); err != nil {
+		t.Fatal(err)
+	}
+	if local.String() != remote.String() {
+		t.Errorf("remote output differs from in-process:\n%s\n---\n%s", remote.String(), local.String())
+	}
+}
+
+func TestRemoteBackend_ReturnsServerError(t *testing.T) {
+	srv := newTestServer(t, twoRounds())
+	b := newRemoteBackend(srv.URL, srv.Client())
+	config := game.GameConfig{DealerStandThreshold: 17}
+
+	g, err := b.NewGame(10, &config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Hit(&g, &config); err != nil {
+		t.Fatal(err)
+	}
+	// ヒットした後はサレンダーできない
+	err = b.Surrender(&g, &config)
+	if err == nil || !strings.Contains(err.Error(), "(surrender_not_allowed)") {
+		t.Errorf("err = %v, want surrender_not_allowed", err)
+	}
+	if _, err := b.NewGame(0, &config); err == nil || !strings.Contains(err.Error(), "(invalid_bet)") {
+		t.Errorf("err = %v, want invalid_bet", err)
+	}
+}
@@ -0,0 +1,48 @@
+// blackjack-cli は端末でブラックジャックを遊ぶクライアントです。
+//
+// -server を指定すると HTTP API（/api/v2）のサーバーと、省略するとプロセス内で
+// services.GameService と StrategyAdvisor を直接呼び出して遊びます。
+//
+//	go run ./cmd/blackjack-cli -bankroll 500 -bet 10
+//	go run ./cmd/blackjack-cli -server http://localhost:8080 -variant spanish21
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"blackjack/api/game"
+)
+
+func main() {
+	server := flag.String("server", "", "API サーバーの URL（例: http://localhost:8080）。省略時はプロセス内で遊ぶ")
+	bankroll := flag.Int("bankroll", 1000, "セッションの開始時の所持金")
+	bet := flag.Int("bet", 10, "掛け金の既定値")
+	variant := flag.String("variant", "", "ルールのバリエーション（classic, spanish21, switch, double_exposure）")
+	threshold := flag.Int("threshold", 17, "ディーラーがスタンドする閾値")
+	charlie := flag.Int("charlie", 0, "N枚チャーリーの枚数（0 なら無効）")
+	flag.Parse()
+
+	config := game.GameConfig{DealerStandThreshold: *threshold, Variant: game.Variant(*variant), CharlieCards: *charlie}
+	if _, err := config.Rules(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	if *bankroll < 1 || *bet < 1 {
+		fmt.Fprintln(os.Stderr, "bankroll and bet must be positive")
+		os.Exit(2)
+	}
+
+	var b backend
+	if *server != "" {
+		b = newRemoteBackend(*server, nil)
+	} else {
+		b = newInProcessBackend(&game.RandomDeck{})
+	}
+
+	if err := newSession(b, config, os.Stdin, os.Stdout, *bankroll, *bet).run(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"blackjack/api/game"
+	"blackjack/api/strategy"
+)
+
+// suitSymbols はスートの表示記号
+var suitSymbols = map[game.Suit]string{
+	game.Spade:   "♠",
+	game.Heart:   "♥",
+	game.Diamond: "♦",
+	game.Club:    "♣",
+}
+
+// formatCard はカードをランクとスートの記号で表します（例: 10♥）。
+func formatCard(c game.Card) string {
+	symbol, ok := suitSymbols[c.Suit]
+	if !ok {
+		symbol = "?"
+	}
+	return string(c.Rank) + symbol
+}
+
+// formatHand は手札のカードと点数を表します。バーストした手（点数 0）は BUST と表示します。
+func formatHand(h game.Hand) string {
+	cards := make([]string, len(h.Cards))
+	for i, c := range h.Cards {
+		cards[i] = formatCard(c)
+	}
+	score := fmt.Sprint(h.Score)
+	if h.Score == 0 && len(h.Cards) > 0 {
+		score = "BUST"
+	}
+	return fmt.Sprintf("%s (%s)", strings.Join(cards, " "), score)
+}
+
+// renderGame はディーラーとプレイヤーの手札を表示用の文字列にします。
+// スイッチでは 2 つの手を並べ、行動中の手に印を付けます。
+func renderGame(g game.Game) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "ディーラー: %s\n", formatHand(g.DealerHand))
+	if g.Switch == nil {
+		fmt.Fprintf(&b, "あなた    : %s\n", formatHand(g.PlayerHand))
+		return b.String()
+	}
+	for i, h := range g.Switch.Hands {
+		mark := " "
+		// 行動中の手は PlayerHand が最新
+		if i == g.Switch.Active && g.State == game.PlayerTurn {
+			h, mark = g.PlayerHand, ">"
+		}
+		fmt.Fprintf(&b, "%s手 %d     : %s\n", mark, i+1, formatHand(h))
+	}
+	return b.String()
+}
+
+// renderAdvice は各行動の期待払い戻しと、最も期待値の高い行動を表示用の文字列にします。
+// とれない行動は表示しません。
+func renderAdvice(p strategy.StrategyExpectedPayouts, allowed []game.Action) string {
+	candidates := []struct {
+		action game.Action
+		label  string
+		payout float64
+	}{
+		{game.ActionHit, "ヒット", p.HitPayout},
+		{game.ActionStand, "スタンド", p.StandPayout},
+		{game.ActionSurrender, "サレンダー", p.SurrenderPayout},
+	}
+	var b strings.Builder
+	best, bestPayout := "", 0.0
+	for _, c := range candidates {
+		if !hasAction(allowed, c.action) {
+			continue
+		}
+		fmt.Fprintf(&b, "  %s: 期待払い戻し %.2f\n", c.label, c.payout)
+		if best == "" || c.payout > bestPayout {
+			best, bestPayout = c.label, c.payout
+		}
+	}
+	if best != "" {
+		fmt.Fprintf(&b, "  おすすめ: %s\n", best)
+	}
+	return b.String()
+}
+
+func hasAction(allowed []game.Action, action game.Action) bool {
+	for _, a := range allowed {
+		if a == action {
+			return true
+		}
+	}
+	return false
+}
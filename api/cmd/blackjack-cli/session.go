@@ -0,0 +1,164 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"blackjack/api/game"
+)
+
+// errQuit は利用者がセッションを終了したことを表します。
+var errQuit = errors.New("quit")
+
+// session は所持金を引き継いでゲームを続けて遊ぶ 1 回のセッションです。
+type session struct {
+	backend  backend
+	config   game.GameConfig
+	in       *bufio.Scanner
+	out      io.Writer
+	bankroll int // 所持金
+	bet      int // 掛け金の既定値
+
+	played, won, lost, pushed int
+	startBankroll             int
+}
+
+func newSession(b backend, config game.GameConfig, in io.Reader, out io.Writer, bankroll, bet int) *session {
+	return &session{
+		backend:       b,
+		config:        config,
+		in:            bufio.NewScanner(in),
+		out:           out,
+		bankroll:      bankroll,
+		bet:           bet,
+		startBankroll: bankroll,
+	}
+}
+
+// run は所持金がなくなるか利用者が終了するまでゲームを続け、最後に成績を表示します。
+func (s *session) run() error {
+	fmt.Fprintf(s.out, "所持金: %d\n", s.bankroll)
+	for s.bankroll > 0 {
+		bet, err := s.askBet()
+		if err == nil {
+			err = s.playRound(bet)
+		}
+		if errors.Is(err, errQuit) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if s.bankroll <= 0 {
+		fmt.Fprintln(s.out, "所持金がなくなりました。")
+	}
+	fmt.Fprintf(s.out, "%d ハンド: 勝ち %d / 負け %d / 引き分け %d、所持金 %d（%+d）\n",
+		s.played, s.won, s.lost, s.pushed, s.bankroll, s.bankroll-s.startBankroll)
+	return nil
+}
+
+// prompt は message を表示して 1 行を読みます。入力が終わったら errQuit を返します。
+func (s *session) prompt(message string) (string, error) {
+	fmt.Fprint(s.out, message)
+	if !s.in.Scan() {
+		fmt.Fprintln(s.out)
+		if err := s.in.Err(); err != nil {
+			return "", err
+		}
+		return "", errQuit
+	}
+	return strings.ToLower(strings.TrimSpace(s.in.Text())), nil
+}
+
+// askBet は次のゲームの掛け金を尋ねます。空行なら既定の掛け金、q なら errQuit を返します。
+// スイッチでは 2 つの手にそれぞれ掛けるので、その合計が所持金を超えないようにします。
+func (s *session) askBet() (int, error) {
+	hands := 1
+	if rules, err := s.config.Rules(); err == nil {
+		hands = rules.Hands
+	}
+	for {
+		line, err := s.prompt(fmt.Sprintf("掛け金（Enter で %d、q で終了）: ", s.bet))
+		if err != nil {
+			return 0, err
+		}
+		if line == "q" {
+			return 0, errQuit
+		}
+		bet := s.bet
+		if line != "" {
+			if bet, err = strconv.Atoi(line); err != nil || bet < 1 {
+				fmt.Fprintln(s.out, "掛け金は 1 以上の整数で入力してください。")
+				continue
+			}
+		}
+		if bet*hands > s.bankroll {
+			fmt.Fprintf(s.out, "所持金（%d）が足りません。\n", s.bankroll)
+			continue
+		}
+		s.bet = bet
+		return bet, nil
+	}
+}
+
+const actionHelp = "[h]ヒット [s]スタンド [r]サレンダー [d]ダブル [p]スプリット [?]ヒント: "
+
+// playRound は 1 ゲームを配ってから決着まで進め、所持金と成績に反映します。
+// 入力が途中で終わった場合、そのゲームの掛け金は戻りません。
+func (s *session) playRound(bet int) error {
+	g, err := s.backend.NewGame(bet, &s.config)
+	if err != nil {
+		return err
+	}
+	s.bankroll -= g.Bet
+
+	for g.State == game.PlayerTurn {
+		fmt.Fprint(s.out, "\n"+renderGame(g))
+		key, err := s.prompt(actionHelp)
+		if err != nil {
+			return err
+		}
+		switch key {
+		case "h":
+			err = s.backend.Hit(&g, &s.config)
+		case "s":
+			err = s.backend.Stand(&g, &s.config)
+		case "r":
+			err = s.backend.Surrender(&g, &s.config)
+		case "d", "p":
+			fmt.Fprintln(s.out, "このサーバーのルールではダブルダウンとスプリットはできません。")
+		case "?":
+			payouts, aerr := s.backend.Advise(g, &s.config)
+			if aerr != nil {
+				err = aerr
+				break
+			}
+			fmt.Fprint(s.out, renderAdvice(payouts, g.AllowedActions))
+		default:
+			fmt.Fprintln(s.out, "h / s / r / d / p / ? のいずれかを入力してください。")
+		}
+		if err != nil {
+			// とれない行動などはサーバーのメッセージを表示して入力をやり直す
+			fmt.Fprintf(s.out, "エラー: %v\n", err)
+		}
+	}
+
+	s.bankroll += g.Payout
+	s.played++
+	switch g.Result {
+	case game.PlayerWin:
+		s.won++
+	case game.Push:
+		s.pushed++
+	default:
+		s.lost++
+	}
+	fmt.Fprint(s.out, "\n"+renderGame(g))
+	fmt.Fprintf(s.out, "%s 払い戻し %d（%+d）、所持金 %d\n\n", g.ResultMessage, g.Payout, g.Payout-g.Bet, s.bankroll)
+	return nil
+}
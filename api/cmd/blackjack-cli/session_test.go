@@ -0,0 +1,93 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"blackjack/api/game"
+)
+
+// scriptedDeck は指定した順にカードを配るデッキ
+type scriptedDeck struct {
+	cards []game.Card
+}
+
+func (d *scriptedDeck) Deal() game.Card {
+	c := d.cards[0]
+	d.cards = d.cards[1:]
+	return c
+}
+
+func cards(spec ...string) []game.Card {
+	suits := map[byte]game.Suit{'S': game.Spade, 'H': game.Heart, 'D': game.Diamond, 'C': game.Club}
+	var cs []game.Card
+	for _, s := range spec {
+		cs = append(cs, game.Card{Suit: suits[s[len(s)-1]], Rank: game.Rank(s[:len(s)-1])})
+	}
+	return cs
+}
+
+// twoRounds は 1 ゲーム目: プレイヤー 10,6 → 4 (20)、ディーラー 9 → 8 (17) で勝ち、
+// 2 ゲーム目: プレイヤー 10,7、ディーラー 10 でサレンダーの順に配るデッキです。
+func twoRounds() game.Deck {
+	return &scriptedDeck{cards: cards("10S", "6H", "9C", "4D", "8H", "10H", "7C", "10D")}
+}
+
+func TestSession_TracksBankroll(t *testing.T) {
+	var out bytes.Buffer
+	in := strings.NewReader("\n?\nh\ns\n20\nd\nr\nq\n")
+	s := newSession(newInProcessBackend(twoRounds()), game.GameConfig{DealerStandThreshold: 17}, in, &out, 100, 10)
+	if err := s.run(); err != nil {
+		t.Fatal(err)
+	}
+
+	if s.bankroll != 100 || s.played != 2 || s.won != 1 || s.lost != 1 {
+		t.Errorf("bankroll = %d, played/won/lost = %d/%d/%d, want 100, 2/1/1", s.bankroll, s.played, s.won, s.lost)
+	}
+	for _, want := range []string{
+		"あなた    : 10♠ 6♥ (16)",
+		"おすすめ: ",
+		"ディーラー: 9♣ 8♥ (17)",
+		"払い戻し 20（+10）、所持金 110",
+		"ダブルダウンとスプリットはできません",
+		"払い戻し 10（-10）、所持金 100",
+		"2 ハンド: 勝ち 1 / 負け 1 / 引き分け 0、所持金 100（+0）",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output does not contain %q:\n%s", want, out.String())
+		}
+	}
+}
+
+func TestSession_RejectsBetOverBankroll(t *testing.T) {
+	var out bytes.Buffer
+	in := strings.NewReader("50\n0\nq\n")
+	s := newSession(newInProcessBackend(twoRounds()), game.GameConfig{DealerStandThreshold: 17, Variant: game.VariantSwitch}, in, &out, 80, 10)
+	if err := s.run(); err != nil {
+		t.Fatal(err)
+	}
+	// スイッチは 2 つの手に掛けるので 50 × 2 は所持金を超える
+	if !strings.Contains(out.String(), "所持金（80）が足りません") || !strings.Contains(out.String(), "1 以上の整数") {
+		t.Errorf("unexpected output:\n%s", out.String())
+	}
+	if s.played != 0 || s.bankroll != 80 {
+		t.Errorf("played = %d, bankroll = %d", s.played, s.bankroll)
+	}
+}
+
+func TestRenderGame_Switch(t *testing.T) {
+	g := game.Game{
+		PlayerHand: game.Hand{Cards: cards("10S", "6H", "5D"), Score: 21},
+		DealerHand: game.Hand{Cards: cards("9C"), Score: 9},
+		State:      game.PlayerTurn,
+		Switch: &game.SwitchHands{
+			Hands:  [2]game.Hand{{Cards: cards("10S", "6H"), Score: 16}, {Cards: cards("KC", "QD", "5S")}},
+			Active: 0,
+		},
+	}
+	want := "ディーラー: 9♣ (9)\n>手 1     : 10♠ 6♥ 5♦ (21)\n 手 2     : K♣ Q♦ 5♠ (BUST)\n"
+	if got := renderGame(g); got != want {
+		t.Errorf("renderGame =\n%s\nwant\n%s", got, want)
+	}
+}
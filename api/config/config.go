@@ -0,0 +1,227 @@
+// Package config はサーバーの設定を読み込み、起動時に検証します。
+//
+// 設定は 既定値 < 設定ファイル（JSON） < 環境変数 < コマンドラインフラグ の順に上書きします。
+// 設定ファイルは -config フラグまたは CONFIG_FILE 環境変数で指定します（例: config.example.json）。
+// 秘密の値（AUTH_SECRET）はプロセスの一覧に見えないよう、フラグでは指定できません。
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"time"
+
+	"blackjack/api/game"
+	"blackjack/api/ratelimit"
+)
+
+// 対応しているデッキとストレージ
+const (
+	DeckRandom    = "random" // 毎回ランダムに 1 枚を配る（無限デッキ）
+	StorageMemory = "memory" // 成績・ランキング・アカウントをメモリに保持する（再起動で消える）
+)
+
+// minSecretBytes は AUTH_SECRET の最小のバイト数
+const minSecretBytes = 16
+
+// Config はサーバーの設定です。
+type Config struct {
+	Port     string `json:"port"`      // HTTP の待ち受けポート
+	GRPCPort string `json:"grpc_port"` // gRPC の待ち受けポート
+	CORS     CORS   `json:"cors"`
+	// Game はリクエストで設定を省略した新規ゲームとテーブルのルール
+	Game game.GameConfig `json:"game"`
+	// SideBets はサイドベットの精算とハウスエッジの計算に使う配当表（x to 1）
+	SideBets   game.SideBetPaytables `json:"side_bets"`
+	Deck       string                `json:"deck"`    // カードの配り方（random）
+	Storage    string                `json:"storage"` // 保存先（memory）
+	Auth       Auth                  `json:"auth"`
+	TrustProxy bool                  `json:"trust_proxy"` // レート制限の IP アドレスに X-Forwarded-For を使う
+	RateLimits RateLimits            `json:"rate_limits"`
+	Timeouts   Timeouts              `json:"timeouts"`
+}
+
+// CORS はブラウザからのクロスオリジンのリクエストの設定です。
+type CORS struct {
+	// AllowedOrigins は許可するオリジン（例: https://example.com）。"*" は全てのオリジンを許可します
+	// テーブルの WebSocket も同じオリジンからの接続だけを受け付けます
+	AllowedOrigins []string `json:"allowed_origins"`
+}
+
+// Auth は認証の設定です。
+type Auth struct {
+	// Secret はトークンの署名に使う鍵。空ならランダムな鍵を使う（再起動すると発行済みのトークンは使えなくなる）
+	Secret   string   `json:"secret"`
+	TokenTTL Duration `json:"token_ttl"` // トークンの有効期間
+}
+
+// RateLimits はエンドポイントの種類ごとのレート制限（IP アドレスごと、認証したプレイヤーはさらにプレイヤーごと）です。
+type RateLimits struct {
+	Game     ratelimit.Limit `json:"game"`     // ゲーム・テーブル・トーナメント・ドリルの操作
+	Strategy ratelimit.Limit `json:"strategy"` // 戦略の計算（キャッシュにない局面は CPU を使う）
+	Auth     ratelimit.Limit `json:"auth"`     // 登録・ログイン・ゲストの発行（パスワードの総当たりを防ぐ）
+}
+
+// Timeouts は時間に関する設定です。
+type Timeouts struct {
+	TableDecision        Duration `json:"table_decision"`         // テーブルの手番で自動行動するまでの時間
+	TableDisconnectGrace Duration `json:"table_disconnect_grace"` // テーブルで切断から離席扱いにするまでの時間
+	StandStreamInterval  Duration `json:"stand_stream_interval"`  // ディーラーのドローを SSE で配信する間隔
+	// HTTP サーバーのタイムアウト（WebSocket は接続後にソケット自身のタイムアウトを使う）
+	HTTPReadHeader Duration `json:"http_read_header"` // リクエストヘッダを読み終えるまで
+	HTTPRead       Duration `json:"http_read"`        // リクエスト全体を読み終えるまで
+	HTTPWrite      Duration `json:"http_write"`       // レスポンスを書き終えるまで（SSE の配信も含む）
+	HTTPIdle       Duration `json:"http_idle"`        // keep-alive の接続で次のリクエストを待つ時間
+	Shutdown       Duration `json:"shutdown"`         // 停止のシグナルから処理中のリクエストを待つ最大の時間
+}
+
+// Duration は JSON では "30s" のような time.ParseDuration の形式で表す時間です。
+type Duration time.Duration
+
+// UnmarshalJSON は "30s" のような文字列を読み込みます。
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("duration must be a string such as \"30s\"")
+	}
+	v, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = Duration(v)
+	return nil
+}
+
+// MarshalJSON は "30s" のような文字列で書き出します。
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+// Default は既定の設定を返します。
+func Default() Config {
+	return Config{
+		Port:     "8080",
+		GRPCPort: "9090",
+		CORS:     CORS{AllowedOrigins: []string{"*"}},
+		Game:     game.GameConfig{DealerStandThreshold: game.DefaultDealerStandThreshold},
+		SideBets: game.DefaultSideBetPaytables(),
+		Deck:     DeckRandom,
+		Storage:  StorageMemory,
+		Auth:     Auth{TokenTTL: Duration(24 * time.Hour)},
+		RateLimits: RateLimits{
+			Game:     ratelimit.Limit{Rate: 10, Burst: 30},
+			Strategy: ratelimit.Limit{Rate: 2, Burst: 10},
+			Auth:     ratelimit.Limit{Rate: 0.2, Burst: 5},
+		},
+		Timeouts: Timeouts{
+			TableDecision:        Duration(30 * time.Second),
+			TableDisconnectGrace: Duration(60 * time.Second),
+			StandStreamInterval:  Duration(600 * time.Millisecond),
+			HTTPReadHeader:       Duration(5 * time.Second),
+			HTTPRead:             Duration(15 * time.Second),
+			HTTPWrite:            Duration(30 * time.Second),
+			HTTPIdle:             Duration(120 * time.Second),
+			Shutdown:             Duration(20 * time.Second),
+		},
+	}
+}
+
+// NewDeck は設定のデッキを生成します。検証済みの設定では Deck は random です。
+func (c *Config) NewDeck() game.Deck {
+	return &game.RandomDeck{}
+}
+
+// Validate は設定を検証し、全ての誤りをまとめたエラーを返します。
+func (c *Config) Validate() error {
+	var errs []error
+	fail := func(format string, args ...any) {
+		errs = append(errs, fmt.Errorf(format, args...))
+	}
+
+	for name, port := range map[string]string{"port": c.Port, "grpc_port": c.GRPCPort} {
+		if n, err := strconv.Atoi(port); err != nil || n < 1 || n > 65535 {
+			fail("%s: %q is not a port number between 1 and 65535", name, port)
+		}
+	}
+	if c.Port == c.GRPCPort {
+		fail("port and grpc_port must differ (both are %q)", c.Port)
+	}
+
+	if len(c.CORS.AllowedOrigins) == 0 {
+		fail("cors.allowed_origins: must list at least one origin (use \"*\" to allow all)")
+	}
+	for _, origin := range c.CORS.AllowedOrigins {
+		if err := validateOrigin(origin); err != nil {
+			fail("cors.allowed_origins: %q %v", origin, err)
+		}
+	}
+
+	if c.Game.DealerStandThreshold < 1 || c.Game.DealerStandThreshold > 21 {
+		fail("game.dealer_stand_threshold: %d is not between 1 and 21", c.Game.DealerStandThreshold)
+	}
+	if _, err := c.Game.Rules(); err != nil {
+		fail("game: %v", err)
+	}
+	if err := c.SideBets.Validate(); err != nil {
+		// 役ごとの誤りを 1 行ずつ表示する
+		for _, err := range err.(interface{ Unwrap() []error }).Unwrap() {
+			fail("side_bets: %v", err)
+		}
+	}
+	if c.Deck != DeckRandom {
+		fail("deck: %q is not supported (supported: %s)", c.Deck, DeckRandom)
+	}
+	if c.Storage != StorageMemory {
+		fail("storage: %q is not supported (supported: %s)", c.Storage, StorageMemory)
+	}
+
+	if c.Auth.Secret != "" && len(c.Auth.Secret) < minSecretBytes {
+		fail("auth.secret: must be at least %d bytes", minSecretBytes)
+	}
+	for name, d := range map[string]Duration{
+		"auth.token_ttl":                  c.Auth.TokenTTL,
+		"timeouts.table_decision":         c.Timeouts.TableDecision,
+		"timeouts.table_disconnect_grace": c.Timeouts.TableDisconnectGrace,
+		"timeouts.stand_stream_interval":  c.Timeouts.StandStreamInterval,
+		"timeouts.http_read_header":       c.Timeouts.HTTPReadHeader,
+		"timeouts.http_read":              c.Timeouts.HTTPRead,
+		"timeouts.http_write":             c.Timeouts.HTTPWrite,
+		"timeouts.http_idle":              c.Timeouts.HTTPIdle,
+		"timeouts.shutdown":               c.Timeouts.Shutdown,
+	} {
+		if d <= 0 {
+			fail("%s: must be positive (got %s)", name, time.Duration(d))
+		}
+	}
+	for name, l := range map[string]ratelimit.Limit{
+		"rate_limits.game":     c.RateLimits.Game,
+		"rate_limits.strategy": c.RateLimits.Strategy,
+		"rate_limits.auth":     c.RateLimits.Auth,
+	} {
+		if l.Rate <= 0 || l.Burst < 1 {
+			fail("%s: rate must be positive and burst at least 1 (got rate %g, burst %d)", name, l.Rate, l.Burst)
+		}
+	}
+
+	// マップの走査順によらず同じ順序で表示する
+	sort.Slice(errs, func(i, j int) bool { return errs[i].Error() < errs[j].Error() })
+	return errors.Join(errs...)
+}
+
+// validateOrigin はオリジンが "*" か scheme://host[:port] の形であることを確かめます。
+func validateOrigin(origin string) error {
+	if origin == "*" {
+		return nil
+	}
+	u, err := url.Parse(origin)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errors.New("is not an origin such as https://example.com")
+	}
+	if (u.Path != "" && u.Path != "/") || u.RawQuery != "" || u.Fragment != "" || u.User != nil {
+		return errors.New("must not have a path, query or credentials")
+	}
+	return nil
+}
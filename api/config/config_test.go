@@ -0,0 +1,131 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"blackjack/api/game"
+)
+
+// env はテスト用の環境変数
+func env(vars map[string]string) func(string) string {
+	return func(key string) string { return vars[key] }
+}
+
+func writeFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoad_Defaults(t *testing.T) {
+	c, err := Load(nil, env(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(c, Default()) {
+		t.Errorf("Load without overrides = %+v, want defaults", c)
+	}
+}
+
+func TestLoad_ExampleFileIsValid(t *testing.T) {
+	if _, err := Load([]string{"-config", "../config.example.json"}, env(nil)); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoad_Precedence(t *testing.T) {
+	path := writeFile(t, `{
+		"port": "7000",
+		"grpc_port": "7100",
+		"cors": {"allowed_origins": ["https://app.example.com"]},
+		"game": {"dealer_stand_threshold": 16},
+		"timeouts": {"table_decision": "10s"}
+	}`)
+	c, err := Load([]string{"-port", "7002", "-table-decision-timeout", "5s"}, env(map[string]string{
+		"CONFIG_FILE":  path,
+		"PORT":         "7001",
+		"GAME_VARIANT": "spanish21",
+		"AUTH_SECRET":  "a secret that is long enough",
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 既定値 < ファイル < 環境変数 < フラグ
+	if c.Port != "7002" || c.GRPCPort != "7100" {
+		t.Errorf("ports = %q, %q, want 7002, 7100", c.Port, c.GRPCPort)
+	}
+	if want := (game.GameConfig{DealerStandThreshold: 16, Variant: game.VariantSpanish21}); c.Game != want {
+		t.Errorf("game = %+v, want %+v", c.Game, want)
+	}
+	if !reflect.DeepEqual(c.CORS.AllowedOrigins, []string{"https://app.example.com"}) {
+		t.Errorf("allowed origins = %q", c.CORS.AllowedOrigins)
+	}
+	if time.Duration(c.Timeouts.TableDecision) != 5*time.Second || time.Duration(c.Timeouts.TableDisconnectGrace) != 60*time.Second {
+		t.Errorf("timeouts = %+v", c.Timeouts)
+	}
+	if c.Auth.Secret != "a secret that is long enough" {
+		t.Errorf("secret was not read from the environment")
+	}
+}
+
+func TestLoad_ReportsAllErrors(t *testing.T) {
+	path := writeFile(t, `{
+		"cors": {"allowed_origins": ["https://app.example.com/path", "example.com"]},
+		"deck": "shoe",
+		"storage": "postgres",
+		"rate_limits": {"game": {"rate": 0, "burst": 1}},
+		"side_bets": {"perfect_pairs": {"mixed_pair": -1, "colored_pair": 0}}
+	}`)
+	_, err := Load([]string{"-config", path}, env(map[string]string{
+		"GRPC_PORT":                   "8080",
+		"GAME_DEALER_STAND_THRESHOLD": "30",
+		"AUTH_SECRET":                 "short",
+	}))
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	for _, want := range []string{
+		`cors.allowed_origins: "https://app.example.com/path" must not have a path`,
+		`cors.allowed_origins: "example.com" is not an origin`,
+		`deck: "shoe" is not supported`,
+		`storage: "postgres" is not supported`,
+		`rate_limits.game: rate must be positive`,
+		`port and grpc_port must differ`,
+		`game.dealer_stand_threshold: 30 is not between 1 and 21`,
+		`auth.secret: must be at least 16 bytes`,
+		`side_bets: invalid config: perfect_pairs.mixed_pair: payout -1 must not be negative`,
+		`side_bets: invalid config: perfect_pairs.colored_pair: payout is missing`,
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error does not mention %q:\n%v", want, err)
+		}
+	}
+}
+
+func TestLoad_RejectsMalformedInput(t *testing.T) {
+	for name, tc := range map[string]struct {
+		args []string
+		env  map[string]string
+		want string
+	}{
+		"unknown file field": {args: []string{"-config", writeFile(t, `{"prot": "8080"}`)}, want: `unknown field "prot"`},
+		"bad duration":       {args: []string{"-config", writeFile(t, `{"auth": {"token_ttl": 60}}`)}, want: "duration must be a string"},
+		"missing file":       {args: []string{"-config", "does-not-exist.json"}, want: "does-not-exist.json"},
+		"bad env":            {env: map[string]string{"TRUST_PROXY": "yes please"}, want: `TRUST_PROXY: "yes please" is not a boolean`},
+		"bad flag":           {args: []string{"-stand-stream-interval", "fast"}, want: `-stand-stream-interval: "fast" is not a duration`},
+	} {
+		_, err := Load(tc.args, env(tc.env))
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%s: got %v, want an error containing %q", name, err, tc.want)
+		}
+	}
+}
@@ -0,0 +1,170 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"blackjack/api/game"
+)
+
+// override は環境変数とフラグで上書きできる設定です。env または flag が空ならその方法では指定できません。
+type override struct {
+	env   string
+	flag  string
+	usage string
+	set   func(c *Config, v string) error
+}
+
+var overrides = []override{
+	{"PORT", "port", "HTTP の待ち受けポート", func(c *Config, v string) error { c.Port = v; return nil }},
+	{"GRPC_PORT", "grpc-port", "gRPC の待ち受けポート", func(c *Config, v string) error { c.GRPCPort = v; return nil }},
+	{"CORS_ALLOWED_ORIGINS", "cors-allowed-origins", "許可するオリジン（カンマ区切り、* は全て）", func(c *Config, v string) error {
+		c.CORS.AllowedOrigins = splitList(v)
+		return nil
+	}},
+	{"GAME_DEALER_STAND_THRESHOLD", "game-dealer-stand-threshold", "既定のルールのディーラーのスタンドの閾値", func(c *Config, v string) error {
+		return setInt(&c.Game.DealerStandThreshold, v)
+	}},
+	{"GAME_VARIANT", "game-variant", "既定のルールのバリエーション", func(c *Config, v string) error { c.Game.Variant = game.Variant(v); return nil }},
+	{"GAME_CHARLIE_CARDS", "game-charlie-cards", "既定のルールの N 枚チャーリー（0 なら無効）", func(c *Config, v string) error {
+		return setInt(&c.Game.CharlieCards, v)
+	}},
+	{"DECK", "deck", "カードの配り方（random）", func(c *Config, v string) error { c.Deck = v; return nil }},
+	{"STORAGE", "storage", "保存先（memory）", func(c *Config, v string) error { c.Storage = v; return nil }},
+	{"AUTH_SECRET", "", "", func(c *Config, v string) error { c.Auth.Secret = v; return nil }},
+	{"AUTH_TOKEN_TTL", "auth-token-ttl", "トークンの有効期間（例: 24h）", func(c *Config, v string) error {
+		return setDuration(&c.Auth.TokenTTL, v)
+	}},
+	{"TRUST_PROXY", "trust-proxy", "レート制限の IP アドレスに X-Forwarded-For を使う（true/false）", func(c *Config, v string) error {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return fmt.Errorf("%q is not a boolean", v)
+		}
+		c.TrustProxy = b
+		return nil
+	}},
+	{"TABLE_DECISION_TIMEOUT", "table-decision-timeout", "テーブルの手番で自動行動するまでの時間", func(c *Config, v string) error {
+		return setDuration(&c.Timeouts.TableDecision, v)
+	}},
+	{"TABLE_DISCONNECT_GRACE", "table-disconnect-grace", "テーブルで切断から離席扱いにするまでの時間", func(c *Config, v string) error {
+		return setDuration(&c.Timeouts.TableDisconnectGrace, v)
+	}},
+	{"STAND_STREAM_INTERVAL", "stand-stream-interval", "ディーラーのドローを SSE で配信する間隔", func(c *Config, v string) error {
+		return setDuration(&c.Timeouts.StandStreamInterval, v)
+	}},
+	{"HTTP_WRITE_TIMEOUT", "http-write-timeout", "レスポンスを書き終えるまでのタイムアウト", func(c *Config, v string) error {
+		return setDuration(&c.Timeouts.HTTPWrite, v)
+	}},
+	{"SHUTDOWN_TIMEOUT", "shutdown-timeout", "停止のシグナルから処理中のリクエストを待つ最大の時間", func(c *Config, v string) error {
+		return setDuration(&c.Timeouts.Shutdown, v)
+	}},
+}
+
+// Load は既定値に設定ファイル・環境変数・フラグ（args）の順に上書きした設定を検証して返します。
+// getenv には os.Getenv を渡してください（テストでは差し替えます）。
+// 設定の誤りは、全ての誤りを 1 行ずつ並べたエラーにして返します。
+func Load(args []string, getenv func(string) string) (Config, error) {
+	c := Default()
+
+	fs := flag.NewFlagSet("blackjack-api", flag.ContinueOnError)
+	path := fs.String("config", "", "設定ファイル（JSON）のパス（CONFIG_FILE 環境変数でも指定できる）")
+	type flagValue struct {
+		o override
+		v string
+	}
+	var flags []flagValue
+	for _, o := range overrides {
+		if o.flag == "" {
+			continue
+		}
+		o := o
+		fs.Func(o.flag, fmt.Sprintf("%s（%s 環境変数でも指定できる）", o.usage, o.env), func(v string) error {
+			flags = append(flags, flagValue{o, v})
+			return nil
+		})
+	}
+	if err := fs.Parse(args); err != nil {
+		return c, fmt.Errorf("config: %w", err)
+	}
+	if fs.NArg() > 0 {
+		return c, fmt.Errorf("config: unexpected arguments %q", fs.Args())
+	}
+
+	if *path == "" {
+		*path = getenv("CONFIG_FILE")
+	}
+	if *path != "" {
+		if err := loadFile(&c, *path); err != nil {
+			return c, fmt.Errorf("config: %s: %w", *path, err)
+		}
+	}
+
+	var errs []error
+	for _, o := range overrides {
+		if v := getenv(o.env); v != "" {
+			if err := o.set(&c, v); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", o.env, err))
+			}
+		}
+	}
+	for _, f := range flags {
+		if err := f.o.set(&c, f.v); err != nil {
+			errs = append(errs, fmt.Errorf("-%s: %w", f.o.flag, err))
+		}
+	}
+	if len(errs) == 0 {
+		errs = append(errs, c.Validate())
+	}
+	if err := errors.Join(errs...); err != nil {
+		return c, fmt.Errorf("config: invalid configuration:\n%w", err)
+	}
+	return c, nil
+}
+
+// loadFile は JSON の設定ファイルで c を上書きします。書かれていない項目は元の値のままです。
+func loadFile(c *Config, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	// 項目名の打ち間違いに気付けるよう、知らない項目はエラーにする
+	dec.DisallowUnknownFields()
+	return dec.Decode(c)
+}
+
+// splitList はカンマ区切りの値を空白を除いて分割します。
+func splitList(v string) []string {
+	var items []string
+	for _, item := range strings.Split(v, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func setInt(dst *int, v string) error {
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return fmt.Errorf("%q is not an integer", v)
+	}
+	*dst = n
+	return nil
+}
+
+func setDuration(dst *Duration, v string) error {
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		return fmt.Errorf("%q is not a duration such as 30s", v)
+	}
+	*dst = Duration(d)
+	return nil
+}
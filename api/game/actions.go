@@ -0,0 +1,79 @@
+package game
+
+import "fmt"
+
+// Action はプレイヤーがとれる行動を表します。
+type Action string
+
+const (
+	ActionHit       Action = "hit"
+	ActionStand     Action = "stand"
+	ActionSurrender Action = "surrender"
+	ActionSwitch    Action = "switch"
+)
+
+// allActions は AllowedActions が返す順序を兼ねる
+var allActions = []Action{ActionHit, ActionStand, ActionSurrender, ActionSwitch}
+
+// CheckAction はゲームの状態とルールで action がとれるかを検証し、とれなければ理由をエラーで返します。
+// サービス層の検証と AllowedActions はどちらもこの関数を使うので、両者の判断は常に一致します。
+// 手札の枚数などの基本整合性（ValidateCore）は呼び出し側で検証してください。
+func (r VariantRules) CheckAction(g *Game, action Action) error {
+	if g.State != PlayerTurn {
+		return ErrNotPlayerTurn
+	}
+	if g.Result != Pending {
+		return ErrGameFinished
+	}
+	if r.Hands == 2 && (g.Switch == nil || g.Switch.Active < 0 || g.Switch.Active > 1) {
+		return fmt.Errorf("%w: switch game must have two hands", ErrInvalidState)
+	}
+
+	switch action {
+	case ActionHit:
+		return nil
+	case ActionStand:
+		if len(g.DealerHand.Cards) != r.InitialDealerCards {
+			if r.InitialDealerCards == 1 {
+				return fmt.Errorf("%w: dealer must have exactly 1 card", ErrInvalidState)
+			}
+			return fmt.Errorf("%w: dealer must have exactly %d cards", ErrInvalidState, r.InitialDealerCards)
+		}
+		return nil
+	case ActionSurrender:
+		if !r.AllowSurrender {
+			return fmt.Errorf("%w in this variant", ErrSurrenderNotAllowed)
+		}
+		if len(g.PlayerHand.Cards) != 2 {
+			return fmt.Errorf("%w: only with the initial 2 cards", ErrSurrenderNotAllowed)
+		}
+		return nil
+	case ActionSwitch:
+		if r.Hands != 2 {
+			return fmt.Errorf("%w: only in blackjack switch", ErrSwitchNotAllowed)
+		}
+		sw := g.Switch
+		if sw.Switched || sw.Active != 0 || len(sw.Hands[0].Cards) != 2 || len(sw.Hands[1].Cards) != 2 {
+			return fmt.Errorf("%w: cards can only be switched once before playing", ErrSwitchNotAllowed)
+		}
+		return nil
+	default:
+		return fmt.Errorf("%w %q", ErrUnknownAction, action)
+	}
+}
+
+// AllowedActions は現在とれる行動を返します。ゲームが終わっていれば空のスライスを返します。
+func (r VariantRules) AllowedActions(g *Game) []Action {
+	allowed := []Action{}
+	for _, a := range allActions {
+		if r.CheckAction(g, a) == nil {
+			allowed = append(allowed, a)
+		}
+	}
+	return allowed
+}
+
+// UpdateAllowedActions はルールに従って AllowedActions を計算し直します。
+func (g *Game) UpdateAllowedActions(r VariantRules) {
+	g.AllowedActions = r.AllowedActions(g)
+}
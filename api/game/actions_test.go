@@ -0,0 +1,56 @@
+package game
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAllowedActions(t *testing.T) {
+	classic := ClassicRules()
+	g := Game{
+		PlayerHand: Hand{Cards: []Card{{Heart, "9"}, {Heart, "5"}}, Score: 14},
+		DealerHand: Hand{Cards: []Card{{Spade, "K"}}, Score: 10},
+		State:      PlayerTurn,
+		Result:     Pending,
+		Bet:        100,
+	}
+	if got := classic.AllowedActions(&g); !reflect.DeepEqual(got, []Action{ActionHit, ActionStand, ActionSurrender}) {
+		t.Fatalf("unexpected actions for the initial hand: %v", got)
+	}
+
+	g.PlayerHand.Cards = append(g.PlayerHand.Cards, Card{Heart, "2"})
+	if got := classic.AllowedActions(&g); !reflect.DeepEqual(got, []Action{ActionHit, ActionStand}) {
+		t.Fatalf("expected surrender to be unavailable after hitting, got %v", got)
+	}
+	if err := classic.CheckAction(&g, ActionSurrender); err == nil {
+		t.Fatalf("expected surrender after hitting to be rejected")
+	}
+
+	g.State, g.Result = Finished, DealerWin
+	if got := classic.AllowedActions(&g); got == nil || len(got) != 0 {
+		t.Fatalf("expected an empty (non-nil) list for a finished game, got %#v", got)
+	}
+	if err := classic.CheckAction(&g, "double"); err == nil {
+		t.Fatalf("expected unknown actions to be rejected")
+	}
+}
+
+func TestAllowedActions_Switch(t *testing.T) {
+	rules, _ := RulesFor(VariantSwitch)
+	hand := Hand{Cards: []Card{{Heart, "9"}, {Heart, "5"}}, Score: 14}
+	g := Game{
+		PlayerHand: hand,
+		DealerHand: Hand{Cards: []Card{{Spade, "K"}}, Score: 10},
+		State:      PlayerTurn,
+		Result:     Pending,
+		Bet:        200,
+		Switch:     &SwitchHands{Hands: [2]Hand{hand, hand}, HandBet: 100},
+	}
+	if got := rules.AllowedActions(&g); !reflect.DeepEqual(got, []Action{ActionHit, ActionStand, ActionSwitch}) {
+		t.Fatalf("unexpected actions before switching: %v", got)
+	}
+	g.Switch.Switched = true
+	if got := rules.AllowedActions(&g); !reflect.DeepEqual(got, []Action{ActionHit, ActionStand}) {
+		t.Fatalf("expected switch to be offered only once, got %v", got)
+	}
+}
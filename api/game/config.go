@@ -1,7 +1,34 @@
 package game
 
+import "fmt"
+
+// MinCharlieCards はN枚チャーリーに指定できる最小の枚数です。
+const MinCharlieCards = 3
+
+// MaxCharlieCards はN枚チャーリーに指定できる最大の枚数です（戦略の計算が手札の枚数で深くなりすぎないように）。
+const MaxCharlieCards = 7
+
+// DefaultDealerStandThreshold はサーバーの既定のディーラーがスタンドする閾値です。
+const DefaultDealerStandThreshold = 17
+
 // GameConfig はゲーム全体の設定を表します。
 type GameConfig struct {
-	DealerStandThreshold int `json:"dealer_stand_threshold"` // ディーラーがスタンドする閾値
+	DealerStandThreshold int     `json:"dealer_stand_threshold"`  // ディーラーがスタンドする閾値
+	Variant              Variant `json:"variant,omitempty"`       // ルールのバリエーション（省略時はクラシック）
+	CharlieCards         int     `json:"charlie_cards,omitempty"` // N枚チャーリーの枚数（0 なら無効）
+}
+
+// Rules は設定のバリエーションに対応するルールを返します。
+// N枚チャーリーが指定されていればルールに反映します。
+func (c *GameConfig) Rules() (VariantRules, error) {
+	rules, err := RulesFor(c.Variant)
+	if err != nil {
+		return VariantRules{}, err
+	}
+	if c.CharlieCards != 0 && (c.CharlieCards < MinCharlieCards || c.CharlieCards > MaxCharlieCards) {
+		return VariantRules{}, fmt.Errorf("%w: charlie_cards must be 0 or between %d and %d", ErrInvalidConfig, MinCharlieCards, MaxCharlieCards)
+	}
+	rules.CharlieCards = c.CharlieCards
+	return rules, nil
 }
 
@@ -0,0 +1,16 @@
+package game
+
+import "errors"
+
+// ゲームのルールと状態の検証で返すエラー
+// 詳細を付ける場合は fmt.Errorf("%w: ...", Err...) で包むので、判定には errors.Is を使ってください。
+var (
+	ErrInvalidState        = errors.New("invalid state")
+	ErrNotPlayerTurn       = errors.New("invalid state: game is not in player turn")
+	ErrGameFinished        = errors.New("invalid state: game already finished")
+	ErrSurrenderNotAllowed = errors.New("invalid action: surrender is not allowed")
+	ErrSwitchNotAllowed    = errors.New("invalid action: switch is not allowed")
+	ErrUnknownAction       = errors.New("invalid action: unknown action")
+	ErrInvalidConfig       = errors.New("invalid config")
+	ErrInvalidSideBet      = errors.New("invalid side bet")
+)
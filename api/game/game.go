@@ -1,6 +1,9 @@
 package game
 
-import "errors"
+import (
+	"fmt"
+	"slices"
+)
 
 type Suit string
 
@@ -50,6 +53,8 @@ const (
 
 // Game はゲーム全体の状態を保持します。
 type Game struct {
+	// ID はサーバーが保持するゲームの ID（テーブルやトーナメントのゲームは空）
+	ID            string    `json:"id,omitempty"`
 	PlayerHand    Hand      `json:"player_hand"`
 	DealerHand    Hand      `json:"dealer_hand"`
 	State         GameState `json:"state"`
@@ -57,6 +62,46 @@ type Game struct {
 	ResultMessage string    `json:"result_message"`
 	Bet           int       `json:"bet"`    // 掛け金
 	Payout        int       `json:"payout"` // 払戻金（勝利額／Push はベット返却）
+	// ResultReason は決着の理由（決着前は空）。ResultMessage はこの理由をクライアントの言語に翻訳した文言
+	ResultReason ResultReason `json:"result_reason,omitempty"`
+	// SideBets は配られた直後に精算済みのサイドベット（本体の Bet・Payout には含まない）
+	SideBets []SideBetResult `json:"side_bets,omitempty"`
+	// Variant はこのゲームのルール（クラシックなら空）
+	Variant Variant `json:"variant,omitempty"`
+	// Switch はブラックジャック・スイッチの2つの手。PlayerHand は行動中の手の写しで、Bet・Payout は2つの手の合計
+	Switch *SwitchHands `json:"switch,omitempty"`
+	// AllowedActions は現在とれる行動（サービス層の検証と同じ VariantRules.CheckAction から計算する）
+	AllowedActions []Action `json:"allowed_actions"`
+}
+
+// SwitchHands はブラックジャック・スイッチの2つの手を表します。
+type SwitchHands struct {
+	Hands    [2]Hand         `json:"hands"`
+	HandBet  int             `json:"hand_bet"` // 1 つの手あたりの掛け金
+	Active   int             `json:"active"`   // 行動中の手（0 または 1）
+	Switched bool            `json:"switched"` // 2枚目のカードを入れ替えたか
+	Results  [2]Result       `json:"results"`
+	Reasons  [2]ResultReason `json:"reasons"`
+	Payouts  [2]int          `json:"payouts"`
+}
+
+// Clone は手札やサイドベットのスライスを共有しない写しを返します。
+func (g Game) Clone() Game {
+	c := g
+	c.PlayerHand = g.PlayerHand.clone()
+	c.DealerHand = g.DealerHand.clone()
+	c.SideBets = slices.Clone(g.SideBets)
+	c.AllowedActions = slices.Clone(g.AllowedActions)
+	if g.Switch != nil {
+		sw := *g.Switch
+		sw.Hands = [2]Hand{g.Switch.Hands[0].clone(), g.Switch.Hands[1].clone()}
+		c.Switch = &sw
+	}
+	return c
+}
+
+func (h Hand) clone() Hand {
+	return Hand{Cards: slices.Clone(h.Cards), Score: h.Score}
 }
 
 // ValidateCore はゲーム状態の基本整合性を検証する
@@ -65,22 +110,22 @@ type Game struct {
 // - Bet と Payout の簡易整合性
 func (g *Game) ValidateCore() error {
 	if len(g.PlayerHand.Cards) < 2 {
-		return errors.New("invalid state: player must have at least 2 cards")
+		return fmt.Errorf("%w: player must have at least 2 cards", ErrInvalidState)
 	}
 	if len(g.DealerHand.Cards) < 1 {
-		return errors.New("invalid state: dealer must have at least 1 card")
+		return fmt.Errorf("%w: dealer must have at least 1 card", ErrInvalidState)
 	}
 	if g.State == PlayerTurn && g.Result != Pending {
-		return errors.New("invalid state: player turn but result is not pending")
+		return fmt.Errorf("%w: player turn but result is not pending", ErrInvalidState)
 	}
 	if g.State == Finished && g.Result == Pending {
-		return errors.New("invalid state: finished but result is pending")
+		return fmt.Errorf("%w: finished but result is pending", ErrInvalidState)
 	}
 	if g.Bet <= 0 {
-		return errors.New("invalid state: bet must be positive")
+		return fmt.Errorf("%w: bet must be positive", ErrInvalidState)
 	}
 	if g.Payout < 0 {
-		return errors.New("invalid state: payout must be non-negative")
+		return fmt.Errorf("%w: payout must be non-negative", ErrInvalidState)
 	}
 	return nil
 }
@@ -1,5 +1,53 @@
 package game
 
+// ResultReason は決着の理由を表す機械可読なコードです。
+// ResultMessage は表示用の文言（既定は日本語）で、クライアントの言語に翻訳されることがあるので、判定には ResultReason を使ってください。
+type ResultReason string
+
+const (
+	ReasonBlackjack       ResultReason = "blackjack"        // 初手のブラックジャックで勝ち
+	ReasonPlayerBust      ResultReason = "player_bust"      // プレイヤーのバーストで負け
+	ReasonDealerBust      ResultReason = "dealer_bust"      // ディーラーのバーストで勝ち
+	ReasonPlayerWin       ResultReason = "player_win"       // 点数で勝ち
+	ReasonDealerWin       ResultReason = "dealer_win"       // 点数で負け
+	ReasonPush            ResultReason = "push"             // 引き分け
+	ReasonSurrender       ResultReason = "surrender"        // サレンダー
+	ReasonBonus21         ResultReason = "bonus21"          // スパニッシュ21 のボーナス配当つきの勝ち
+	ReasonCharlie         ResultReason = "charlie"          // N枚チャーリーで勝ち
+	ReasonDealerBlackjack ResultReason = "dealer_blackjack" // ディーラーの初手のブラックジャックで負け
+	ReasonTieDealerWin    ResultReason = "tie_dealer_win"   // 同点で負け
+	ReasonDealer22Push    ResultReason = "dealer22_push"    // ディーラーの 22 で引き分け
+	ReasonSwitchSettled   ResultReason = "switch_settled"   // スイッチの2つの手の精算
+)
+
+// reasonMessages は決着の理由ごとの既定（日本語）の文言
+var reasonMessages = map[ResultReason]string{
+	ReasonBlackjack:       MessageBlackjackPlayerWin,
+	ReasonPlayerBust:      MessagePlayerBustDealerWin,
+	ReasonDealerBust:      MessageDealerBustPlayerWin,
+	ReasonPlayerWin:       MessagePlayerWin,
+	ReasonDealerWin:       MessageDealerWin,
+	ReasonPush:            MessagePush,
+	ReasonSurrender:       MessagePlayerSurrendered,
+	ReasonBonus21:         MessageBonus21PlayerWin,
+	ReasonCharlie:         MessageCharliePlayerWin,
+	ReasonDealerBlackjack: MessageDealerBlackjackDealerWin,
+	ReasonTieDealerWin:    MessageTieDealerWin,
+	ReasonDealer22Push:    MessageDealer22Push,
+	ReasonSwitchSettled:   MessageSwitchSettled,
+}
+
+// Message は決着の理由の既定（日本語）の文言を返します。
+func (r ResultReason) Message() string {
+	return reasonMessages[r]
+}
+
+// SetReason は決着の理由と、その既定の文言を設定します。
+func (g *Game) SetReason(r ResultReason) {
+	g.ResultReason = r
+	g.ResultMessage = r.Message()
+}
+
 // 結果メッセージ定数（日本語）
 const (
 	// ブラックジャック（初手21）でプレイヤー勝利
@@ -20,4 +68,18 @@ const (
 
 	// サレンダー
 	MessagePlayerSurrendered = "プレイヤーがサレンダーしました。"
+
+	// スパニッシュ21 のボーナス配当つきの 21
+	MessageBonus21PlayerWin = "ボーナス21！プレイヤーの勝ちです"
+
+	// N枚チャーリー
+	MessageCharliePlayerWin = "チャーリー！(規定枚数でバーストなし)プレイヤーの勝ちです"
+
+	// ダブル・エクスポージャー
+	MessageDealerBlackjackDealerWin = "ディーラーがブラックジャック！ディーラーの勝ちです"
+	MessageTieDealerWin             = "同点のためディーラーの勝ちです"
+
+	// ブラックジャック・スイッチ
+	MessageDealer22Push  = "ディーラーが22のため引き分けです"
+	MessageSwitchSettled = "2つの手の精算が完了しました"
 )
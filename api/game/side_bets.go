@@ -0,0 +1,263 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// SideBetType はサイドベットの種類を表します。
+type SideBetType string
+
+const (
+	SideBetPerfectPairs       SideBetType = "perfect_pairs" // プレイヤーの初手2枚がペアか
+	SideBetTwentyOnePlusThree SideBetType = "21+3"          // プレイヤーの初手2枚とディーラーのアップカードのポーカー役
+)
+
+// SideBetOutcome はサイドベットの役を表します。役なしは SideBetLose です。
+type SideBetOutcome string
+
+const (
+	SideBetLose SideBetOutcome = "lose"
+
+	// パーフェクトペア
+	PairMixed   SideBetOutcome = "mixed_pair"   // 色違いのペア
+	PairColored SideBetOutcome = "colored_pair" // 同じ色・別スートのペア
+	PairPerfect SideBetOutcome = "perfect_pair" // 同じスートのペア
+
+	// 21+3
+	HandFlush         SideBetOutcome = "flush"
+	HandStraight      SideBetOutcome = "straight"
+	HandThreeOfAKind  SideBetOutcome = "three_of_a_kind"
+	HandStraightFlush SideBetOutcome = "straight_flush"
+	HandSuitedTrips   SideBetOutcome = "suited_trips"
+)
+
+// PerfectPairsPaytable はパーフェクトペアの配当（x to 1）です。
+type PerfectPairsPaytable struct {
+	Mixed   int `json:"mixed_pair"`
+	Colored int `json:"colored_pair"`
+	Perfect int `json:"perfect_pair"`
+}
+
+// TwentyOnePlusThreePaytable は 21+3 の配当（x to 1）です。
+type TwentyOnePlusThreePaytable struct {
+	Flush         int `json:"flush"`
+	Straight      int `json:"straight"`
+	ThreeOfAKind  int `json:"three_of_a_kind"`
+	StraightFlush int `json:"straight_flush"`
+	SuitedTrips   int `json:"suited_trips"`
+}
+
+// SideBetPaytables はサーバーが支払うサイドベットの配当表です。サーバーの設定で決め、クライアントからは変更できません。
+type SideBetPaytables struct {
+	PerfectPairs       PerfectPairsPaytable       `json:"perfect_pairs"`
+	TwentyOnePlusThree TwentyOnePlusThreePaytable `json:"twenty_one_plus_three"`
+}
+
+// 既定の配当表（一般的なカジノの配当表）
+var (
+	DefaultPerfectPairsPaytable       = PerfectPairsPaytable{Mixed: 6, Colored: 12, Perfect: 25}
+	DefaultTwentyOnePlusThreePaytable = TwentyOnePlusThreePaytable{Flush: 5, Straight: 10, ThreeOfAKind: 30, StraightFlush: 40, SuitedTrips: 100}
+)
+
+// 配当表に載せる役（SideBetLose 以外の全ての役）
+var (
+	PerfectPairsOutcomes       = []SideBetOutcome{PairMixed, PairColored, PairPerfect}
+	TwentyOnePlusThreeOutcomes = []SideBetOutcome{HandFlush, HandStraight, HandThreeOfAKind, HandStraightFlush, HandSuitedTrips}
+)
+
+// MaxSideBetOdds は配当表の配当（x to 1）の上限（MaxSideBet を掛けても払い戻しが int に収まる）
+const MaxSideBetOdds = 1000
+
+// DefaultSideBetPaytables は既定の配当表を返します。
+func DefaultSideBetPaytables() SideBetPaytables {
+	return SideBetPaytables{PerfectPairs: DefaultPerfectPairsPaytable, TwentyOnePlusThree: DefaultTwentyOnePlusThreePaytable}
+}
+
+// Validate は両方の配当表の全ての役に 1 以上 MaxSideBetOdds 以下の配当があることを検証し、
+// 全ての誤りを errors.Join でまとめて返します。配当 0 は役が配当表にない（勝っても掛け金が戻るだけ）とみなします。
+func (p SideBetPaytables) Validate() error {
+	var errs []error
+	check := func(name string, outcomes []SideBetOutcome, odds func(SideBetOutcome) int) {
+		for _, outcome := range outcomes {
+			switch o := odds(outcome); {
+			case o < 0:
+				errs = append(errs, fmt.Errorf("%w: %s.%s: payout %d must not be negative", ErrInvalidConfig, name, outcome, o))
+			case o == 0:
+				errs = append(errs, fmt.Errorf("%w: %s.%s: payout is missing (every outcome must pay at least 1 to 1)", ErrInvalidConfig, name, outcome))
+			case o > MaxSideBetOdds:
+				errs = append(errs, fmt.Errorf("%w: %s.%s: payout %d exceeds %d to 1", ErrInvalidConfig, name, outcome, o, MaxSideBetOdds))
+			}
+		}
+	}
+	check("perfect_pairs", PerfectPairsOutcomes, p.PerfectPairs.Odds)
+	check("twenty_one_plus_three", TwentyOnePlusThreeOutcomes, p.TwentyOnePlusThree.Odds)
+	return errors.Join(errs...)
+}
+
+// Odds は役の配当（x to 1）を返します。役なしなら 0 を返します。
+func (p PerfectPairsPaytable) Odds(outcome SideBetOutcome) int {
+	switch outcome {
+	case PairMixed:
+		return p.Mixed
+	case PairColored:
+		return p.Colored
+	case PairPerfect:
+		return p.Perfect
+	default:
+		return 0
+	}
+}
+
+// Odds は役の配当（x to 1）を返します。役なしなら 0 を返します。
+func (p TwentyOnePlusThreePaytable) Odds(outcome SideBetOutcome) int {
+	switch outcome {
+	case HandFlush:
+		return p.Flush
+	case HandStraight:
+		return p.Straight
+	case HandThreeOfAKind:
+		return p.ThreeOfAKind
+	case HandStraightFlush:
+		return p.StraightFlush
+	case HandSuitedTrips:
+		return p.SuitedTrips
+	default:
+		return 0
+	}
+}
+
+// SideBetResult は精算済みのサイドベットです。Payout は掛け金を含む払い戻し（負けなら 0）です。
+type SideBetResult struct {
+	Type    SideBetType    `json:"type"`
+	Bet     int            `json:"bet"`
+	Outcome SideBetOutcome `json:"outcome"`
+	Odds    int            `json:"odds"` // 適用された配当（x to 1）
+	Payout  int            `json:"payout"`
+}
+
+// EvaluatePerfectPairs はプレイヤーの初手2枚のペアの役を判定します。
+func EvaluatePerfectPairs(first, second Card) SideBetOutcome {
+	switch {
+	case first.Rank != second.Rank:
+		return SideBetLose
+	case first.Suit == second.Suit:
+		return PairPerfect
+	case isRed(first.Suit) == isRed(second.Suit):
+		return PairColored
+	default:
+		return PairMixed
+	}
+}
+
+// EvaluateTwentyOnePlusThree はプレイヤーの初手2枚とディーラーのアップカードの3枚でポーカーの役を判定します。
+// ストレートは A を 1 としても 14 としても扱います（A-2-3 と Q-K-A はストレート、K-A-2 は違う）。
+func EvaluateTwentyOnePlusThree(first, second, upcard Card) SideBetOutcome {
+	cards := []Card{first, second, upcard}
+	flush := first.Suit == second.Suit && second.Suit == upcard.Suit
+	trips := first.Rank == second.Rank && second.Rank == upcard.Rank
+	straight := isStraight(cards)
+
+	switch {
+	case trips && flush:
+		return HandSuitedTrips
+	case straight && flush:
+		return HandStraightFlush
+	case trips:
+		return HandThreeOfAKind
+	case straight:
+		return HandStraight
+	case flush:
+		return HandFlush
+	default:
+		return SideBetLose
+	}
+}
+
+// SettleSideBet は役と配当表の配当から払い戻しを求めます。
+func SettleSideBet(typ SideBetType, bet int, outcome SideBetOutcome, odds int) SideBetResult {
+	res := SideBetResult{Type: typ, Bet: bet, Outcome: outcome}
+	if outcome != SideBetLose {
+		res.Odds = odds
+		res.Payout = bet * (odds + 1)
+	}
+	return res
+}
+
+func isRed(s Suit) bool {
+	return s == Heart || s == Diamond
+}
+
+// rankOrder は A を 1、J/Q/K を 11/12/13 とした並び順を返します。
+func rankOrder(r Rank) int {
+	switch r {
+	case "A":
+		return 1
+	case "J":
+		return 11
+	case "Q":
+		return 12
+	case "K":
+		return 13
+	default:
+		return RankToScore(r)
+	}
+}
+
+func isStraight(cards []Card) bool {
+	orders := make([]int, len(cards))
+	for i, c := range cards {
+		orders[i] = rankOrder(c.Rank)
+	}
+	sort.Ints(orders)
+	consecutive := func(o []int) bool {
+		for i := 1; i < len(o); i++ {
+			if o[i] != o[i-1]+1 {
+				return false
+			}
+		}
+		return true
+	}
+	if consecutive(orders) {
+		return true
+	}
+	// A をハイとして扱う（Q-K-A）
+	if orders[0] == 1 {
+		high := append(orders[1:len(orders):len(orders)], 14)
+		return consecutive(high)
+	}
+	return false
+}
+
+// AllCards は 52 枚のカードを全て返します。
+func AllCards() []Card {
+	cards := make([]Card, 0, len(suits)*len(ranks))
+	for _, s := range suits {
+		for _, r := range ranks {
+			cards = append(cards, Card{Suit: s, Rank: r})
+		}
+	}
+	return cards
+}
+
+// MaxSideBet はサイドベット 1 つの掛け金の上限（配当表の上限 MaxSideBetOdds でも払い戻しが int に収まる）
+const MaxSideBet = 1_000_000_000
+
+// SideBets は新規ゲームと同時に置くサイドベットです。掛け金 0 のサイドベットは置かない扱いです。
+// 配当はサーバーの設定の配当表（SideBetPaytables）に従います。
+type SideBets struct {
+	PerfectPairs       int `json:"perfect_pairs,omitempty"`
+	TwentyOnePlusThree int `json:"twenty_one_plus_three,omitempty"`
+}
+
+// Validate は掛け金が 0 以上 MaxSideBet 以下であることを検証します。
+func (b SideBets) Validate() error {
+	if b.PerfectPairs < 0 || b.TwentyOnePlusThree < 0 {
+		return fmt.Errorf("%w: side bets must not be negative", ErrInvalidSideBet)
+	}
+	if b.PerfectPairs > MaxSideBet || b.TwentyOnePlusThree > MaxSideBet {
+		return fmt.Errorf("%w: side bets must not exceed %d", ErrInvalidSideBet, MaxSideBet)
+	}
+	return nil
+}
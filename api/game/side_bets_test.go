@@ -0,0 +1,83 @@
+package game
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestEvaluatePerfectPairs(t *testing.T) {
+	tests := []struct {
+		name     string
+		a, b     Card
+		expected SideBetOutcome
+	}{
+		{"perfect", Card{Spade, "8"}, Card{Spade, "8"}, PairPerfect},
+		{"colored", Card{Heart, "Q"}, Card{Diamond, "Q"}, PairColored},
+		{"mixed", Card{Club, "A"}, Card{Heart, "A"}, PairMixed},
+		{"ten and king are not a pair", Card{Spade, "10"}, Card{Spade, "K"}, SideBetLose},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := EvaluatePerfectPairs(tt.a, tt.b); got != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestEvaluateTwentyOnePlusThree(t *testing.T) {
+	tests := []struct {
+		name     string
+		cards    [3]Card
+		expected SideBetOutcome
+	}{
+		{"suited trips", [3]Card{{Heart, "7"}, {Heart, "7"}, {Heart, "7"}}, HandSuitedTrips},
+		{"straight flush", [3]Card{{Club, "9"}, {Club, "J"}, {Club, "10"}}, HandStraightFlush},
+		{"three of a kind", [3]Card{{Club, "7"}, {Heart, "7"}, {Spade, "7"}}, HandThreeOfAKind},
+		{"ace low straight", [3]Card{{Club, "A"}, {Heart, "2"}, {Spade, "3"}}, HandStraight},
+		{"ace high straight", [3]Card{{Club, "Q"}, {Heart, "A"}, {Spade, "K"}}, HandStraight},
+		{"no wraparound straight", [3]Card{{Club, "K"}, {Heart, "A"}, {Spade, "2"}}, SideBetLose},
+		{"flush", [3]Card{{Diamond, "2"}, {Diamond, "9"}, {Diamond, "K"}}, HandFlush},
+		{"nothing", [3]Card{{Diamond, "2"}, {Heart, "9"}, {Diamond, "K"}}, SideBetLose},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := EvaluateTwentyOnePlusThree(tt.cards[0], tt.cards[1], tt.cards[2]); got != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestSettleSideBet(t *testing.T) {
+	if got := SettleSideBet(SideBetPerfectPairs, 10, PairColored, 12); got.Payout != 130 || got.Odds != 12 {
+		t.Errorf("expected 12:1 to pay 130 including stake, got %+v", got)
+	}
+	if got := SettleSideBet(SideBetTwentyOnePlusThree, 10, SideBetLose, 0); got.Payout != 0 {
+		t.Errorf("expected a losing side bet to pay nothing, got %+v", got)
+	}
+}
+
+func TestSideBetPaytables_Validate(t *testing.T) {
+	if err := DefaultSideBetPaytables().Validate(); err != nil {
+		t.Fatalf("expected the default paytables to be valid, got %v", err)
+	}
+
+	p := DefaultSideBetPaytables()
+	p.PerfectPairs.Mixed = -1
+	p.TwentyOnePlusThree.Straight = 0
+	p.TwentyOnePlusThree.SuitedTrips = MaxSideBetOdds + 1
+	err := p.Validate()
+	if !errors.Is(err, ErrInvalidConfig) {
+		t.Fatalf("expected ErrInvalidConfig, got %v", err)
+	}
+	for _, want := range []string{"perfect_pairs.mixed_pair", "twenty_one_plus_three.straight", "twenty_one_plus_three.suited_trips"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected an error for %s, got %v", want, err)
+		}
+	}
+	if n := len(err.(interface{ Unwrap() []error }).Unwrap()); n != 3 {
+		t.Errorf("expected 3 errors, got %d: %v", n, err)
+	}
+}
@@ -0,0 +1,162 @@
+package game
+
+import "fmt"
+
+// Variant はブラックジャックのルールのバリエーションを表します。
+type Variant string
+
+const (
+	VariantClassic        Variant = "classic"         // 通常のブラックジャック（空文字も同じ扱い）
+	VariantSpanish21      Variant = "spanish21"       // スパニッシュ21
+	VariantSwitch         Variant = "switch"          // ブラックジャック・スイッチ
+	VariantDoubleExposure Variant = "double_exposure" // ダブル・エクスポージャー
+)
+
+// VariantRules はバリエーションごとのルールです。
+// デッキの構成・初期配布・精算・戦略計算はこのルールを参照して動作を切り替えます。
+type VariantRules struct {
+	Variant Variant
+	// ExcludedRanks はデッキから取り除くランク（スパニッシュ21 は 10 を除く。J/Q/K は残る）
+	ExcludedRanks []Rank
+	// InitialDealerCards は初期配布でディーラーに配る枚数（全て表向き）
+	InitialDealerCards int
+	// Hands はプレイヤーの手の数（スイッチは 2）
+	Hands int
+	// BlackjackPayoutNum / BlackjackPayoutDen はブラックジャックの払い戻し倍率（掛け金を含む）
+	BlackjackPayoutNum int
+	BlackjackPayoutDen int
+	// NaturalsSettleImmediately が true なら初手の 21 は配った時点で精算する
+	NaturalsSettleImmediately bool
+	// TiesLose が true なら同点はディーラーの勝ち
+	TiesLose bool
+	// Dealer22Pushes が true ならディーラーの 22 はバーストしていないプレイヤーと引き分け
+	Dealer22Pushes bool
+	// Player21AlwaysWins が true ならプレイヤーの 21 はディーラーの 21 にも勝つ
+	Player21AlwaysWins bool
+	// Bonus21 が true なら 5 枚以上の 21 と 6-7-8 / 7-7-7 の 21 にボーナス配当がつく
+	Bonus21 bool
+	// AllowSurrender が false ならサレンダーできない
+	AllowSurrender bool
+	// CharlieCards が 0 でなければ、その枚数でバーストしていない手はプレイヤーの勝ち（GameConfig から設定する）
+	CharlieCards int
+}
+
+// RulesFor はバリエーションのルールを返します。空文字はクラシックとして扱います。
+func RulesFor(v Variant) (VariantRules, error) {
+	switch v {
+	case "", VariantClassic:
+		return ClassicRules(), nil
+	case VariantSpanish21:
+		r := ClassicRules()
+		r.Variant = VariantSpanish21
+		r.ExcludedRanks = []Rank{"10"}
+		r.Player21AlwaysWins = true
+		r.Bonus21 = true
+		return r, nil
+	case VariantSwitch:
+		r := ClassicRules()
+		r.Variant = VariantSwitch
+		r.Hands = 2
+		// スイッチではブラックジャックは 21 と同じ扱いで、同じく等倍で払われる
+		r.BlackjackPayoutNum, r.BlackjackPayoutDen = 2, 1
+		r.NaturalsSettleImmediately = false
+		r.Dealer22Pushes = true
+		r.AllowSurrender = false
+		return r, nil
+	case VariantDoubleExposure:
+		r := ClassicRules()
+		r.Variant = VariantDoubleExposure
+		r.InitialDealerCards = 2
+		r.BlackjackPayoutNum, r.BlackjackPayoutDen = 2, 1
+		r.TiesLose = true
+		r.AllowSurrender = false
+		return r, nil
+	default:
+		return VariantRules{}, fmt.Errorf("%w: unknown variant: %s", ErrInvalidConfig, v)
+	}
+}
+
+// ClassicRules は通常のブラックジャックのルールを返します。
+func ClassicRules() VariantRules {
+	return VariantRules{
+		Variant:                   VariantClassic,
+		InitialDealerCards:        1,
+		Hands:                     1,
+		BlackjackPayoutNum:        5,
+		BlackjackPayoutDen:        2,
+		NaturalsSettleImmediately: true,
+		AllowSurrender:            true,
+	}
+}
+
+// AllowsCard はカードがこのバリエーションのデッキに含まれるかを返します。
+func (r VariantRules) AllowsCard(c Card) bool {
+	for _, rank := range r.ExcludedRanks {
+		if c.Rank == rank {
+			return false
+		}
+	}
+	return true
+}
+
+// IsCharlie は手札がN枚チャーリー（指定枚数に達してバーストしていない）かを返します。
+func (r VariantRules) IsCharlie(h Hand) bool {
+	return r.CharlieCards > 0 && len(h.Cards) >= r.CharlieCards && CalculateScore(h.Cards) != 0
+}
+
+// BlackjackPayout はブラックジャックの払い戻し（掛け金を含む）を返します。
+func (r VariantRules) BlackjackPayout(bet int) int {
+	return bet * r.BlackjackPayoutNum / r.BlackjackPayoutDen
+}
+
+// Bonus21Payout はスパニッシュ21 のボーナス配当の払い戻し（掛け金を含む）を返します。
+// ボーナスの対象でなければ false を返します。
+// 5 枚の 21 は 3:2、6 枚は 2:1、7 枚以上は 3:1。6-7-8 と 7-7-7 はスート混合 3:2、同スート 2:1、スペード 3:1。
+func (r VariantRules) Bonus21Payout(h Hand, bet int) (int, bool) {
+	if !r.Bonus21 || CalculateScore(h.Cards) != 21 {
+		return 0, false
+	}
+	best := Bonus21CardsRatio(len(h.Cards))
+	if len(h.Cards) == 3 && isSixSevenEightOrTripleSeven(h.Cards) {
+		suited := h.Cards[0].Suit == h.Cards[1].Suit && h.Cards[1].Suit == h.Cards[2].Suit
+		if ratio := Bonus21SevensRatio(suited, suited && h.Cards[0].Suit == Spade); ratio > best {
+			best = ratio
+		}
+	}
+	if best == 0 {
+		return 0, false
+	}
+	return bet + bet*best/2, true
+}
+
+// Bonus21CardsRatio は n 枚の 21 のボーナス配当の倍率の分子（分母は 2）を返します。対象でなければ 0 です。
+func Bonus21CardsRatio(n int) int {
+	switch {
+	case n == 5:
+		return 3
+	case n == 6:
+		return 4
+	case n >= 7:
+		return 6
+	}
+	return 0
+}
+
+// Bonus21SevensRatio は 6-7-8 / 7-7-7 の 21 のボーナス配当の倍率の分子（分母は 2）を返します。
+func Bonus21SevensRatio(suited, spades bool) int {
+	switch {
+	case spades:
+		return 6
+	case suited:
+		return 4
+	}
+	return 3
+}
+
+func isSixSevenEightOrTripleSeven(cards []Card) bool {
+	counts := map[Rank]int{}
+	for _, c := range cards {
+		counts[c.Rank]++
+	}
+	return counts["7"] == 3 || (counts["6"] == 1 && counts["7"] == 1 && counts["8"] == 1)
+}
@@ -0,0 +1,71 @@
+package game
+
+import "testing"
+
+func TestRulesFor(t *testing.T) {
+	classic, err := RulesFor("")
+	if err != nil || classic.Variant != VariantClassic {
+		t.Fatalf("expected empty variant to be classic, got %+v, %v", classic, err)
+	}
+	spanish, _ := RulesFor(VariantSpanish21)
+	if spanish.AllowsCard(Card{Spade, "10"}) || !spanish.AllowsCard(Card{Spade, "K"}) {
+		t.Fatalf("expected spanish 21 to remove tens but keep face cards")
+	}
+	if _, err := RulesFor("pontoon"); err == nil {
+		t.Fatalf("expected error for unknown variant")
+	}
+}
+
+func TestBonus21Payout(t *testing.T) {
+	rules, _ := RulesFor(VariantSpanish21)
+	hand := func(cards ...Card) Hand {
+		return Hand{Cards: cards, Score: CalculateScore(cards)}
+	}
+	tests := []struct {
+		name     string
+		hand     Hand
+		expected int
+		ok       bool
+	}{
+		{"five card 21 pays 3:2", hand(Card{Club, "2"}, Card{Club, "3"}, Card{Heart, "4"}, Card{Heart, "5"}, Card{Spade, "7"}), 250, true},
+		{"six card 21 pays 2:1", hand(Card{Club, "A"}, Card{Club, "2"}, Card{Heart, "3"}, Card{Heart, "4"}, Card{Spade, "5"}, Card{Spade, "6"}), 300, true},
+		{"mixed 6-7-8 pays 3:2", hand(Card{Club, "6"}, Card{Heart, "7"}, Card{Spade, "8"}), 250, true},
+		{"suited 7-7-7 pays 2:1", hand(Card{Heart, "7"}, Card{Heart, "7"}, Card{Heart, "7"}), 300, true},
+		{"spade 6-7-8 pays 3:1", hand(Card{Spade, "6"}, Card{Spade, "7"}, Card{Spade, "8"}), 400, true},
+		{"three card 21 without bonus", hand(Card{Spade, "K"}, Card{Spade, "5"}, Card{Spade, "6"}), 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := rules.Bonus21Payout(tt.hand, 100)
+			if got != tt.expected || ok != tt.ok {
+				t.Errorf("expected (%d, %v), got (%d, %v)", tt.expected, tt.ok, got, ok)
+			}
+		})
+	}
+
+	if _, ok := ClassicRules().Bonus21Payout(hand(Card{Spade, "6"}, Card{Spade, "7"}, Card{Spade, "8"}), 100); ok {
+		t.Errorf("expected no bonus in classic rules")
+	}
+}
+
+func TestGameConfigRules_Charlie(t *testing.T) {
+	rules, err := (&GameConfig{DealerStandThreshold: 17, CharlieCards: 5}).Rules()
+	if err != nil || rules.CharlieCards != 5 {
+		t.Fatalf("expected charlie to be copied into rules, got %+v, %v", rules, err)
+	}
+	if _, err := (&GameConfig{DealerStandThreshold: 17, CharlieCards: 2}).Rules(); err == nil {
+		t.Fatalf("expected error for a two-card charlie")
+	}
+	if _, err := (&GameConfig{DealerStandThreshold: 17, CharlieCards: MaxCharlieCards + 1}).Rules(); err == nil {
+		t.Fatalf("expected error for a charlie above %d cards", MaxCharlieCards)
+	}
+
+	five := Hand{Cards: []Card{{Heart, "2"}, {Heart, "3"}, {Heart, "2"}, {Heart, "4"}, {Heart, "5"}}}
+	if !rules.IsCharlie(five) {
+		t.Fatalf("expected five cards totalling 16 to be a charlie")
+	}
+	bust := Hand{Cards: append(append([]Card{}, five.Cards[:4]...), Card{Heart, "K"}, Card{Heart, "9"})}
+	if rules.IsCharlie(bust) || ClassicRules().IsCharlie(five) {
+		t.Fatalf("expected busted hands and rules without charlie not to be a charlie")
+	}
+}
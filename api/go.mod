@@ -3,3 +3,17 @@ module blackjack/api
 go 1.22
 
 require github.com/gorilla/mux v1.8.1
+
+require (
+	github.com/gorilla/websocket v1.5.3
+	golang.org/x/crypto v0.31.0
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20241202173237-19429a94021a
+	google.golang.org/grpc v1.70.0
+	google.golang.org/protobuf v1.35.2
+)
+
+require (
+	golang.org/x/net v0.32.0 // indirect
+	golang.org/x/sys v0.28.0 // indirect
+	golang.org/x/text v0.21.0 // indirect
+)
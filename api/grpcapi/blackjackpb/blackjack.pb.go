@@ -0,0 +1,954 @@
+// gRPC で提供するブラックジャックのゲームと戦略アドバイスの API です。
+// 文字列の値（スート・ランク・状態・結果・バリエーションなど）は HTTP API の JSON と同じです。
+// ゲームの状態はサーバーに保存しないので、行動のたびに直前のレスポンスの Game をそのまま送ってください。
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.35.2
+// 	protoc        (unknown)
+// source: blackjack.proto
+
+package blackjackpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Card は 1 枚のカードです。
+type Card struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Suit string `protobuf:"bytes,1,opt,name=suit,proto3" json:"suit,omitempty"` // "Spade", "Heart", "Diamond", "Club"
+	Rank string `protobuf:"bytes,2,opt,name=rank,proto3" json:"rank,omitempty"` // "A", "2" 〜 "10", "J", "Q", "K"
+}
+
+func (x *Card) Reset() {
+	*x = Card{}
+	mi := &file_blackjack_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Card) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Card) ProtoMessage() {}
+
+func (x *Card) ProtoReflect() protoreflect.Message {
+	mi := &file_blackjack_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Card.ProtoReflect.Descriptor instead.
+func (*Card) Descriptor() ([]byte, []int) {
+	return file_blackjack_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Card) GetSuit() string {
+	if x != nil {
+		return x.Suit
+	}
+	return ""
+}
+
+func (x *Card) GetRank() string {
+	if x != nil {
+		return x.Rank
+	}
+	return ""
+}
+
+// Hand は手札です。
+type Hand struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Cards []*Card `protobuf:"bytes,1,rep,name=cards,proto3" json:"cards,omitempty"`
+	Score int32   `protobuf:"varint,2,opt,name=score,proto3" json:"score,omitempty"` // バーストしていれば 0
+}
+
+func (x *Hand) Reset() {
+	*x = Hand{}
+	mi := &file_blackjack_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Hand) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Hand) ProtoMessage() {}
+
+func (x *Hand) ProtoReflect() protoreflect.Message {
+	mi := &file_blackjack_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Hand.ProtoReflect.Descriptor instead.
+func (*Hand) Descriptor() ([]byte, []int) {
+	return file_blackjack_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Hand) GetCards() []*Card {
+	if x != nil {
+		return x.Cards
+	}
+	return nil
+}
+
+func (x *Hand) GetScore() int32 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+// SideBetResult は精算済みのサイドベットです。
+type SideBetResult struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Type    string `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"` // "perfect_pairs", "21+3"
+	Bet     int32  `protobuf:"varint,2,opt,name=bet,proto3" json:"bet,omitempty"`
+	Outcome string `protobuf:"bytes,3,opt,name=outcome,proto3" json:"outcome,omitempty"`
+	Odds    int32  `protobuf:"varint,4,opt,name=odds,proto3" json:"odds,omitempty"` // 適用された配当（x to 1）
+	Payout  int32  `protobuf:"varint,5,opt,name=payout,proto3" json:"payout,omitempty"`
+}
+
+func (x *SideBetResult) Reset() {
+	*x = SideBetResult{}
+	mi := &file_blackjack_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SideBetResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SideBetResult) ProtoMessage() {}
+
+func (x *SideBetResult) ProtoReflect() protoreflect.Message {
+	mi := &file_blackjack_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SideBetResult.ProtoReflect.Descriptor instead.
+func (*SideBetResult) Descriptor() ([]byte, []int) {
+	return file_blackjack_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *SideBetResult) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *SideBetResult) GetBet() int32 {
+	if x != nil {
+		return x.Bet
+	}
+	return 0
+}
+
+func (x *SideBetResult) GetOutcome() string {
+	if x != nil {
+		return x.Outcome
+	}
+	return ""
+}
+
+func (x *SideBetResult) GetOdds() int32 {
+	if x != nil {
+		return x.Odds
+	}
+	return 0
+}
+
+func (x *SideBetResult) GetPayout() int32 {
+	if x != nil {
+		return x.Payout
+	}
+	return 0
+}
+
+// SwitchHands はブラックジャック・スイッチの 2 つの手です。repeated のフィールドは常に 2 要素です。
+type SwitchHands struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Hands    []*Hand  `protobuf:"bytes,1,rep,name=hands,proto3" json:"hands,omitempty"`
+	HandBet  int32    `protobuf:"varint,2,opt,name=hand_bet,json=handBet,proto3" json:"hand_bet,omitempty"` // 1 つの手あたりの掛け金
+	Active   int32    `protobuf:"varint,3,opt,name=active,proto3" json:"active,omitempty"`                  // 行動中の手（0 または 1）
+	Switched bool     `protobuf:"varint,4,opt,name=switched,proto3" json:"switched,omitempty"`
+	Results  []string `protobuf:"bytes,5,rep,name=results,proto3" json:"results,omitempty"`
+	Reasons  []string `protobuf:"bytes,6,rep,name=reasons,proto3" json:"reasons,omitempty"`
+	Payouts  []int32  `protobuf:"varint,7,rep,packed,name=payouts,proto3" json:"payouts,omitempty"`
+}
+
+func (x *SwitchHands) Reset() {
+	*x = SwitchHands{}
+	mi := &file_blackjack_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SwitchHands) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SwitchHands) ProtoMessage() {}
+
+func (x *SwitchHands) ProtoReflect() protoreflect.Message {
+	mi := &file_blackjack_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SwitchHands.ProtoReflect.Descriptor instead.
+func (*SwitchHands) Descriptor() ([]byte, []int) {
+	return file_blackjack_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *SwitchHands) GetHands() []*Hand {
+	if x != nil {
+		return x.Hands
+	}
+	return nil
+}
+
+func (x *SwitchHands) GetHandBet() int32 {
+	if x != nil {
+		return x.HandBet
+	}
+	return 0
+}
+
+func (x *SwitchHands) GetActive() int32 {
+	if x != nil {
+		return x.Active
+	}
+	return 0
+}
+
+func (x *SwitchHands) GetSwitched() bool {
+	if x != nil {
+		return x.Switched
+	}
+	return false
+}
+
+func (x *SwitchHands) GetResults() []string {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+func (x *SwitchHands) GetReasons() []string {
+	if x != nil {
+		return x.Reasons
+	}
+	return nil
+}
+
+func (x *SwitchHands) GetPayouts() []int32 {
+	if x != nil {
+		return x.Payouts
+	}
+	return nil
+}
+
+// Game はゲーム全体の状態です。
+type Game struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	PlayerHand     *Hand            `protobuf:"bytes,1,opt,name=player_hand,json=playerHand,proto3" json:"player_hand,omitempty"`
+	DealerHand     *Hand            `protobuf:"bytes,2,opt,name=dealer_hand,json=dealerHand,proto3" json:"dealer_hand,omitempty"`
+	State          string           `protobuf:"bytes,3,opt,name=state,proto3" json:"state,omitempty"`   // "PlayerTurn", "Finished"
+	Result         string           `protobuf:"bytes,4,opt,name=result,proto3" json:"result,omitempty"` // "Pending", "PlayerWin", "DealerWin", "Push", "Surrender"
+	ResultMessage  string           `protobuf:"bytes,5,opt,name=result_message,json=resultMessage,proto3" json:"result_message,omitempty"`
+	Bet            int32            `protobuf:"varint,6,opt,name=bet,proto3" json:"bet,omitempty"`
+	Payout         int32            `protobuf:"varint,7,opt,name=payout,proto3" json:"payout,omitempty"`
+	ResultReason   string           `protobuf:"bytes,8,opt,name=result_reason,json=resultReason,proto3" json:"result_reason,omitempty"` // 決着の理由（決着前は空）
+	SideBets       []*SideBetResult `protobuf:"bytes,9,rep,name=side_bets,json=sideBets,proto3" json:"side_bets,omitempty"`
+	Variant        string           `protobuf:"bytes,10,opt,name=variant,proto3" json:"variant,omitempty"`                                     // クラシックなら空
+	Switch         *SwitchHands     `protobuf:"bytes,11,opt,name=switch,proto3" json:"switch,omitempty"`                                       // スイッチのゲームのみ
+	AllowedActions []string         `protobuf:"bytes,12,rep,name=allowed_actions,json=allowedActions,proto3" json:"allowed_actions,omitempty"` // "hit", "stand", "surrender", "switch"
+}
+
+func (x *Game) Reset() {
+	*x = Game{}
+	mi := &file_blackjack_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Game) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Game) ProtoMessage() {}
+
+func (x *Game) ProtoReflect() protoreflect.Message {
+	mi := &file_blackjack_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Game.ProtoReflect.Descriptor instead.
+func (*Game) Descriptor() ([]byte, []int) {
+	return file_blackjack_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *Game) GetPlayerHand() *Hand {
+	if x != nil {
+		return x.PlayerHand
+	}
+	return nil
+}
+
+func (x *Game) GetDealerHand() *Hand {
+	if x != nil {
+		return x.DealerHand
+	}
+	return nil
+}
+
+func (x *Game) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+func (x *Game) GetResult() string {
+	if x != nil {
+		return x.Result
+	}
+	return ""
+}
+
+func (x *Game) GetResultMessage() string {
+	if x != nil {
+		return x.ResultMessage
+	}
+	return ""
+}
+
+func (x *Game) GetBet() int32 {
+	if x != nil {
+		return x.Bet
+	}
+	return 0
+}
+
+func (x *Game) GetPayout() int32 {
+	if x != nil {
+		return x.Payout
+	}
+	return 0
+}
+
+func (x *Game) GetResultReason() string {
+	if x != nil {
+		return x.ResultReason
+	}
+	return ""
+}
+
+func (x *Game) GetSideBets() []*SideBetResult {
+	if x != nil {
+		return x.SideBets
+	}
+	return nil
+}
+
+func (x *Game) GetVariant() string {
+	if x != nil {
+		return x.Variant
+	}
+	return ""
+}
+
+func (x *Game) GetSwitch() *SwitchHands {
+	if x != nil {
+		return x.Switch
+	}
+	return nil
+}
+
+func (x *Game) GetAllowedActions() []string {
+	if x != nil {
+		return x.AllowedActions
+	}
+	return nil
+}
+
+// GameConfig はゲームの設定です。
+type GameConfig struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	DealerStandThreshold int32  `protobuf:"varint,1,opt,name=dealer_stand_threshold,json=dealerStandThreshold,proto3" json:"dealer_stand_threshold,omitempty"`
+	Variant              string `protobuf:"bytes,2,opt,name=variant,proto3" json:"variant,omitempty"`                                // "classic"（空も同じ）, "spanish21", "switch", "double_exposure"
+	CharlieCards         int32  `protobuf:"varint,3,opt,name=charlie_cards,json=charlieCards,proto3" json:"charlie_cards,omitempty"` // N枚チャーリーの枚数（0 なら無効）
+}
+
+func (x *GameConfig) Reset() {
+	*x = GameConfig{}
+	mi := &file_blackjack_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GameConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GameConfig) ProtoMessage() {}
+
+func (x *GameConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_blackjack_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GameConfig.ProtoReflect.Descriptor instead.
+func (*GameConfig) Descriptor() ([]byte, []int) {
+	return file_blackjack_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *GameConfig) GetDealerStandThreshold() int32 {
+	if x != nil {
+		return x.DealerStandThreshold
+	}
+	return 0
+}
+
+func (x *GameConfig) GetVariant() string {
+	if x != nil {
+		return x.Variant
+	}
+	return ""
+}
+
+func (x *GameConfig) GetCharlieCards() int32 {
+	if x != nil {
+		return x.CharlieCards
+	}
+	return 0
+}
+
+// StrategyExpectedPayouts は各行動の期待払い戻し（掛け金を含む額）です。
+type StrategyExpectedPayouts struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	HitPayout       float64 `protobuf:"fixed64,1,opt,name=hit_payout,json=hitPayout,proto3" json:"hit_payout,omitempty"`
+	StandPayout     float64 `protobuf:"fixed64,2,opt,name=stand_payout,json=standPayout,proto3" json:"stand_payout,omitempty"`
+	SurrenderPayout float64 `protobuf:"fixed64,3,opt,name=surrender_payout,json=surrenderPayout,proto3" json:"surrender_payout,omitempty"`
+	BestPayout      float64 `protobuf:"fixed64,4,opt,name=best_payout,json=bestPayout,proto3" json:"best_payout,omitempty"` // 上の 3 つのうち最も高いもの
+}
+
+func (x *StrategyExpectedPayouts) Reset() {
+	*x = StrategyExpectedPayouts{}
+	mi := &file_blackjack_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StrategyExpectedPayouts) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StrategyExpectedPayouts) ProtoMessage() {}
+
+func (x *StrategyExpectedPayouts) ProtoReflect() protoreflect.Message {
+	mi := &file_blackjack_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StrategyExpectedPayouts.ProtoReflect.Descriptor instead.
+func (*StrategyExpectedPayouts) Descriptor() ([]byte, []int) {
+	return file_blackjack_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *StrategyExpectedPayouts) GetHitPayout() float64 {
+	if x != nil {
+		return x.HitPayout
+	}
+	return 0
+}
+
+func (x *StrategyExpectedPayouts) GetStandPayout() float64 {
+	if x != nil {
+		return x.StandPayout
+	}
+	return 0
+}
+
+func (x *StrategyExpectedPayouts) GetSurrenderPayout() float64 {
+	if x != nil {
+		return x.SurrenderPayout
+	}
+	return 0
+}
+
+func (x *StrategyExpectedPayouts) GetBestPayout() float64 {
+	if x != nil {
+		return x.BestPayout
+	}
+	return 0
+}
+
+type NewGameRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Bet    int32       `protobuf:"varint,1,opt,name=bet,proto3" json:"bet,omitempty"`
+	Config *GameConfig `protobuf:"bytes,2,opt,name=config,proto3" json:"config,omitempty"` // 省略時はクラシック
+	Locale string      `protobuf:"bytes,3,opt,name=locale,proto3" json:"locale,omitempty"` // 結果の文言とエラーメッセージの言語（"ja", "en"）。省略時は accept-language メタデータに従います
+}
+
+func (x *NewGameRequest) Reset() {
+	*x = NewGameRequest{}
+	mi := &file_blackjack_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NewGameRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NewGameRequest) ProtoMessage() {}
+
+func (x *NewGameRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_blackjack_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NewGameRequest.ProtoReflect.Descriptor instead.
+func (*NewGameRequest) Descriptor() ([]byte, []int) {
+	return file_blackjack_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *NewGameRequest) GetBet() int32 {
+	if x != nil {
+		return x.Bet
+	}
+	return 0
+}
+
+func (x *NewGameRequest) GetConfig() *GameConfig {
+	if x != nil {
+		return x.Config
+	}
+	return nil
+}
+
+func (x *NewGameRequest) GetLocale() string {
+	if x != nil {
+		return x.Locale
+	}
+	return ""
+}
+
+type ActionRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Game   *Game       `protobuf:"bytes,1,opt,name=game,proto3" json:"game,omitempty"`
+	Config *GameConfig `protobuf:"bytes,2,opt,name=config,proto3" json:"config,omitempty"`
+	Locale string      `protobuf:"bytes,3,opt,name=locale,proto3" json:"locale,omitempty"`
+}
+
+func (x *ActionRequest) Reset() {
+	*x = ActionRequest{}
+	mi := &file_blackjack_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ActionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ActionRequest) ProtoMessage() {}
+
+func (x *ActionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_blackjack_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ActionRequest.ProtoReflect.Descriptor instead.
+func (*ActionRequest) Descriptor() ([]byte, []int) {
+	return file_blackjack_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ActionRequest) GetGame() *Game {
+	if x != nil {
+		return x.Game
+	}
+	return nil
+}
+
+func (x *ActionRequest) GetConfig() *GameConfig {
+	if x != nil {
+		return x.Config
+	}
+	return nil
+}
+
+func (x *ActionRequest) GetLocale() string {
+	if x != nil {
+		return x.Locale
+	}
+	return ""
+}
+
+type AdviseRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Game   *Game       `protobuf:"bytes,1,opt,name=game,proto3" json:"game,omitempty"`
+	Config *GameConfig `protobuf:"bytes,2,opt,name=config,proto3" json:"config,omitempty"`
+}
+
+func (x *AdviseRequest) Reset() {
+	*x = AdviseRequest{}
+	mi := &file_blackjack_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AdviseRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AdviseRequest) ProtoMessage() {}
+
+func (x *AdviseRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_blackjack_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AdviseRequest.ProtoReflect.Descriptor instead.
+func (*AdviseRequest) Descriptor() ([]byte, []int) {
+	return file_blackjack_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *AdviseRequest) GetGame() *Game {
+	if x != nil {
+		return x.Game
+	}
+	return nil
+}
+
+func (x *AdviseRequest) GetConfig() *GameConfig {
+	if x != nil {
+		return x.Config
+	}
+	return nil
+}
+
+var File_blackjack_proto protoreflect.FileDescriptor
+
+var file_blackjack_proto_rawDesc = []byte{
+	0x0a, 0x0f, 0x62, 0x6c, 0x61, 0x63, 0x6b, 0x6a, 0x61, 0x63, 0x6b, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x12, 0x0c, 0x62, 0x6c, 0x61, 0x63, 0x6b, 0x6a, 0x61, 0x63, 0x6b, 0x2e, 0x76, 0x31, 0x22,
+	0x2e, 0x0a, 0x04, 0x43, 0x61, 0x72, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x73, 0x75, 0x69, 0x74, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x73, 0x75, 0x69, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x72,
+	0x61, 0x6e, 0x6b, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x72, 0x61, 0x6e, 0x6b, 0x22,
+	0x46, 0x0a, 0x04, 0x48, 0x61, 0x6e, 0x64, 0x12, 0x28, 0x0a, 0x05, 0x63, 0x61, 0x72, 0x64, 0x73,
+	0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x62, 0x6c, 0x61, 0x63, 0x6b, 0x6a, 0x61,
+	0x63, 0x6b, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x61, 0x72, 0x64, 0x52, 0x05, 0x63, 0x61, 0x72, 0x64,
+	0x73, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x63, 0x6f, 0x72, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x05, 0x73, 0x63, 0x6f, 0x72, 0x65, 0x22, 0x7b, 0x0a, 0x0d, 0x53, 0x69, 0x64, 0x65, 0x42,
+	0x65, 0x74, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x10, 0x0a, 0x03,
+	0x62, 0x65, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x03, 0x62, 0x65, 0x74, 0x12, 0x18,
+	0x0a, 0x07, 0x6f, 0x75, 0x74, 0x63, 0x6f, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x07, 0x6f, 0x75, 0x74, 0x63, 0x6f, 0x6d, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6f, 0x64, 0x64, 0x73,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x6f, 0x64, 0x64, 0x73, 0x12, 0x16, 0x0a, 0x06,
+	0x70, 0x61, 0x79, 0x6f, 0x75, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x70, 0x61,
+	0x79, 0x6f, 0x75, 0x74, 0x22, 0xd4, 0x01, 0x0a, 0x0b, 0x53, 0x77, 0x69, 0x74, 0x63, 0x68, 0x48,
+	0x61, 0x6e, 0x64, 0x73, 0x12, 0x28, 0x0a, 0x05, 0x68, 0x61, 0x6e, 0x64, 0x73, 0x18, 0x01, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x62, 0x6c, 0x61, 0x63, 0x6b, 0x6a, 0x61, 0x63, 0x6b, 0x2e,
+	0x76, 0x31, 0x2e, 0x48, 0x61, 0x6e, 0x64, 0x52, 0x05, 0x68, 0x61, 0x6e, 0x64, 0x73, 0x12, 0x19,
+	0x0a, 0x08, 0x68, 0x61, 0x6e, 0x64, 0x5f, 0x62, 0x65, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x07, 0x68, 0x61, 0x6e, 0x64, 0x42, 0x65, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x61, 0x63, 0x74,
+	0x69, 0x76, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x61, 0x63, 0x74, 0x69, 0x76,
+	0x65, 0x12, 0x1a, 0x0a, 0x08, 0x73, 0x77, 0x69, 0x74, 0x63, 0x68, 0x65, 0x64, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x08, 0x73, 0x77, 0x69, 0x74, 0x63, 0x68, 0x65, 0x64, 0x12, 0x18, 0x0a,
+	0x07, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x09, 0x52, 0x07,
+	0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x72, 0x65, 0x61, 0x73, 0x6f,
+	0x6e, 0x73, 0x18, 0x06, 0x20, 0x03, 0x28, 0x09, 0x52, 0x07, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e,
+	0x73, 0x12, 0x18, 0x0a, 0x07, 0x70, 0x61, 0x79, 0x6f, 0x75, 0x74, 0x73, 0x18, 0x07, 0x20, 0x03,
+	0x28, 0x05, 0x52, 0x07, 0x70, 0x61, 0x79, 0x6f, 0x75, 0x74, 0x73, 0x22, 0xc4, 0x03, 0x0a, 0x04,
+	0x47, 0x61, 0x6d, 0x65, 0x12, 0x33, 0x0a, 0x0b, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x5f, 0x68,
+	0x61, 0x6e, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x62, 0x6c, 0x61, 0x63,
+	0x6b, 0x6a, 0x61, 0x63, 0x6b, 0x2e, 0x76, 0x31, 0x2e, 0x48, 0x61, 0x6e, 0x64, 0x52, 0x0a, 0x70,
+	0x6c, 0x61, 0x79, 0x65, 0x72, 0x48, 0x61, 0x6e, 0x64, 0x12, 0x33, 0x0a, 0x0b, 0x64, 0x65, 0x61,
+	0x6c, 0x65, 0x72, 0x5f, 0x68, 0x61, 0x6e, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12,
+	0x2e, 0x62, 0x6c, 0x61, 0x63, 0x6b, 0x6a, 0x61, 0x63, 0x6b, 0x2e, 0x76, 0x31, 0x2e, 0x48, 0x61,
+	0x6e, 0x64, 0x52, 0x0a, 0x64, 0x65, 0x61, 0x6c, 0x65, 0x72, 0x48, 0x61, 0x6e, 0x64, 0x12, 0x14,
+	0x0a, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x73,
+	0x74, 0x61, 0x74, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x25, 0x0a, 0x0e,
+	0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x5f, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x05,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x4d, 0x65, 0x73, 0x73,
+	0x61, 0x67, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x62, 0x65, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x03, 0x62, 0x65, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x70, 0x61, 0x79, 0x6f, 0x75, 0x74, 0x18,
+	0x07, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x70, 0x61, 0x79, 0x6f, 0x75, 0x74, 0x12, 0x23, 0x0a,
+	0x0d, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x5f, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x18, 0x08,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x52, 0x65, 0x61, 0x73,
+	0x6f, 0x6e, 0x12, 0x38, 0x0a, 0x09, 0x73, 0x69, 0x64, 0x65, 0x5f, 0x62, 0x65, 0x74, 0x73, 0x18,
+	0x09, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x62, 0x6c, 0x61, 0x63, 0x6b, 0x6a, 0x61, 0x63,
+	0x6b, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x69, 0x64, 0x65, 0x42, 0x65, 0x74, 0x52, 0x65, 0x73, 0x75,
+	0x6c, 0x74, 0x52, 0x08, 0x73, 0x69, 0x64, 0x65, 0x42, 0x65, 0x74, 0x73, 0x12, 0x18, 0x0a, 0x07,
+	0x76, 0x61, 0x72, 0x69, 0x61, 0x6e, 0x74, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x76,
+	0x61, 0x72, 0x69, 0x61, 0x6e, 0x74, 0x12, 0x31, 0x0a, 0x06, 0x73, 0x77, 0x69, 0x74, 0x63, 0x68,
+	0x18, 0x0b, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x62, 0x6c, 0x61, 0x63, 0x6b, 0x6a, 0x61,
+	0x63, 0x6b, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x77, 0x69, 0x74, 0x63, 0x68, 0x48, 0x61, 0x6e, 0x64,
+	0x73, 0x52, 0x06, 0x73, 0x77, 0x69, 0x74, 0x63, 0x68, 0x12, 0x27, 0x0a, 0x0f, 0x61, 0x6c, 0x6c,
+	0x6f, 0x77, 0x65, 0x64, 0x5f, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x0c, 0x20, 0x03,
+	0x28, 0x09, 0x52, 0x0e, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x65, 0x64, 0x41, 0x63, 0x74, 0x69, 0x6f,
+	0x6e, 0x73, 0x22, 0x81, 0x01, 0x0a, 0x0a, 0x47, 0x61, 0x6d, 0x65, 0x43, 0x6f, 0x6e, 0x66, 0x69,
+	0x67, 0x12, 0x34, 0x0a, 0x16, 0x64, 0x65, 0x61, 0x6c, 0x65, 0x72, 0x5f, 0x73, 0x74, 0x61, 0x6e,
+	0x64, 0x5f, 0x74, 0x68, 0x72, 0x65, 0x73, 0x68, 0x6f, 0x6c, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x05, 0x52, 0x14, 0x64, 0x65, 0x61, 0x6c, 0x65, 0x72, 0x53, 0x74, 0x61, 0x6e, 0x64, 0x54, 0x68,
+	0x72, 0x65, 0x73, 0x68, 0x6f, 0x6c, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x61, 0x72, 0x69, 0x61,
+	0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x76, 0x61, 0x72, 0x69, 0x61, 0x6e,
+	0x74, 0x12, 0x23, 0x0a, 0x0d, 0x63, 0x68, 0x61, 0x72, 0x6c, 0x69, 0x65, 0x5f, 0x63, 0x61, 0x72,
+	0x64, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0c, 0x63, 0x68, 0x61, 0x72, 0x6c, 0x69,
+	0x65, 0x43, 0x61, 0x72, 0x64, 0x73, 0x22, 0xa7, 0x01, 0x0a, 0x17, 0x53, 0x74, 0x72, 0x61, 0x74,
+	0x65, 0x67, 0x79, 0x45, 0x78, 0x70, 0x65, 0x63, 0x74, 0x65, 0x64, 0x50, 0x61, 0x79, 0x6f, 0x75,
+	0x74, 0x73, 0x12, 0x1d, 0x0a, 0x0a, 0x68, 0x69, 0x74, 0x5f, 0x70, 0x61, 0x79, 0x6f, 0x75, 0x74,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x01, 0x52, 0x09, 0x68, 0x69, 0x74, 0x50, 0x61, 0x79, 0x6f, 0x75,
+	0x74, 0x12, 0x21, 0x0a, 0x0c, 0x73, 0x74, 0x61, 0x6e, 0x64, 0x5f, 0x70, 0x61, 0x79, 0x6f, 0x75,
+	0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0b, 0x73, 0x74, 0x61, 0x6e, 0x64, 0x50, 0x61,
+	0x79, 0x6f, 0x75, 0x74, 0x12, 0x29, 0x0a, 0x10, 0x73, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x64, 0x65,
+	0x72, 0x5f, 0x70, 0x61, 0x79, 0x6f, 0x75, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0f,
+	0x73, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x50, 0x61, 0x79, 0x6f, 0x75, 0x74, 0x12,
+	0x1f, 0x0a, 0x0b, 0x62, 0x65, 0x73, 0x74, 0x5f, 0x70, 0x61, 0x79, 0x6f, 0x75, 0x74, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x01, 0x52, 0x0a, 0x62, 0x65, 0x73, 0x74, 0x50, 0x61, 0x79, 0x6f, 0x75, 0x74,
+	0x22, 0x6c, 0x0a, 0x0e, 0x4e, 0x65, 0x77, 0x47, 0x61, 0x6d, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x62, 0x65, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x03, 0x62, 0x65, 0x74, 0x12, 0x30, 0x0a, 0x06, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x62, 0x6c, 0x61, 0x63, 0x6b, 0x6a, 0x61, 0x63, 0x6b,
+	0x2e, 0x76, 0x31, 0x2e, 0x47, 0x61, 0x6d, 0x65, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x06,
+	0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x16, 0x0a, 0x06, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x65,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x65, 0x22, 0x81,
+	0x01, 0x0a, 0x0d, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x12, 0x26, 0x0a, 0x04, 0x67, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12,
+	0x2e, 0x62, 0x6c, 0x61, 0x63, 0x6b, 0x6a, 0x61, 0x63, 0x6b, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x61,
+	0x6d, 0x65, 0x52, 0x04, 0x67, 0x61, 0x6d, 0x65, 0x12, 0x30, 0x0a, 0x06, 0x63, 0x6f, 0x6e, 0x66,
+	0x69, 0x67, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x62, 0x6c, 0x61, 0x63, 0x6b,
+	0x6a, 0x61, 0x63, 0x6b, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x61, 0x6d, 0x65, 0x43, 0x6f, 0x6e, 0x66,
+	0x69, 0x67, 0x52, 0x06, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x16, 0x0a, 0x06, 0x6c, 0x6f,
+	0x63, 0x61, 0x6c, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x6c, 0x6f, 0x63, 0x61,
+	0x6c, 0x65, 0x22, 0x69, 0x0a, 0x0d, 0x41, 0x64, 0x76, 0x69, 0x73, 0x65, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x12, 0x26, 0x0a, 0x04, 0x67, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x12, 0x2e, 0x62, 0x6c, 0x61, 0x63, 0x6b, 0x6a, 0x61, 0x63, 0x6b, 0x2e, 0x76, 0x31,
+	0x2e, 0x47, 0x61, 0x6d, 0x65, 0x52, 0x04, 0x67, 0x61, 0x6d, 0x65, 0x12, 0x30, 0x0a, 0x06, 0x63,
+	0x6f, 0x6e, 0x66, 0x69, 0x67, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x62, 0x6c,
+	0x61, 0x63, 0x6b, 0x6a, 0x61, 0x63, 0x6b, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x61, 0x6d, 0x65, 0x43,
+	0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x06, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x32, 0xc6, 0x02,
+	0x0a, 0x09, 0x42, 0x6c, 0x61, 0x63, 0x6b, 0x6a, 0x61, 0x63, 0x6b, 0x12, 0x3b, 0x0a, 0x07, 0x4e,
+	0x65, 0x77, 0x47, 0x61, 0x6d, 0x65, 0x12, 0x1c, 0x2e, 0x62, 0x6c, 0x61, 0x63, 0x6b, 0x6a, 0x61,
+	0x63, 0x6b, 0x2e, 0x76, 0x31, 0x2e, 0x4e, 0x65, 0x77, 0x47, 0x61, 0x6d, 0x65, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x12, 0x2e, 0x62, 0x6c, 0x61, 0x63, 0x6b, 0x6a, 0x61, 0x63, 0x6b,
+	0x2e, 0x76, 0x31, 0x2e, 0x47, 0x61, 0x6d, 0x65, 0x12, 0x36, 0x0a, 0x03, 0x48, 0x69, 0x74, 0x12,
+	0x1b, 0x2e, 0x62, 0x6c, 0x61, 0x63, 0x6b, 0x6a, 0x61, 0x63, 0x6b, 0x2e, 0x76, 0x31, 0x2e, 0x41,
+	0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x12, 0x2e, 0x62,
+	0x6c, 0x61, 0x63, 0x6b, 0x6a, 0x61, 0x63, 0x6b, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x61, 0x6d, 0x65,
+	0x12, 0x38, 0x0a, 0x05, 0x53, 0x74, 0x61, 0x6e, 0x64, 0x12, 0x1b, 0x2e, 0x62, 0x6c, 0x61, 0x63,
+	0x6b, 0x6a, 0x61, 0x63, 0x6b, 0x2e, 0x76, 0x31, 0x2e, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x12, 0x2e, 0x62, 0x6c, 0x61, 0x63, 0x6b, 0x6a, 0x61,
+	0x63, 0x6b, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x61, 0x6d, 0x65, 0x12, 0x3c, 0x0a, 0x09, 0x53, 0x75,
+	0x72, 0x72, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x12, 0x1b, 0x2e, 0x62, 0x6c, 0x61, 0x63, 0x6b, 0x6a,
+	0x61, 0x63, 0x6b, 0x2e, 0x76, 0x31, 0x2e, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x12, 0x2e, 0x62, 0x6c, 0x61, 0x63, 0x6b, 0x6a, 0x61, 0x63, 0x6b,
+	0x2e, 0x76, 0x31, 0x2e, 0x47, 0x61, 0x6d, 0x65, 0x12, 0x4c, 0x0a, 0x06, 0x41, 0x64, 0x76, 0x69,
+	0x73, 0x65, 0x12, 0x1b, 0x2e, 0x62, 0x6c, 0x61, 0x63, 0x6b, 0x6a, 0x61, 0x63, 0x6b, 0x2e, 0x76,
+	0x31, 0x2e, 0x41, 0x64, 0x76, 0x69, 0x73, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x25, 0x2e, 0x62, 0x6c, 0x61, 0x63, 0x6b, 0x6a, 0x61, 0x63, 0x6b, 0x2e, 0x76, 0x31, 0x2e, 0x53,
+	0x74, 0x72, 0x61, 0x74, 0x65, 0x67, 0x79, 0x45, 0x78, 0x70, 0x65, 0x63, 0x74, 0x65, 0x64, 0x50,
+	0x61, 0x79, 0x6f, 0x75, 0x74, 0x73, 0x42, 0x23, 0x5a, 0x21, 0x62, 0x6c, 0x61, 0x63, 0x6b, 0x6a,
+	0x61, 0x63, 0x6b, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x67, 0x72, 0x70, 0x63, 0x61, 0x70, 0x69, 0x2f,
+	0x62, 0x6c, 0x61, 0x63, 0x6b, 0x6a, 0x61, 0x63, 0x6b, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x33,
+}
+
+var (
+	file_blackjack_proto_rawDescOnce sync.Once
+	file_blackjack_proto_rawDescData = file_blackjack_proto_rawDesc
+)
+
+func file_blackjack_proto_rawDescGZIP() []byte {
+	file_blackjack_proto_rawDescOnce.Do(func() {
+		file_blackjack_proto_rawDescData = protoimpl.X.CompressGZIP(file_blackjack_proto_rawDescData)
+	})
+	return file_blackjack_proto_rawDescData
+}
+
+var file_blackjack_proto_msgTypes = make([]protoimpl.MessageInfo, 10)
+var file_blackjack_proto_goTypes = []any{
+	(*Card)(nil),                    // 0: blackjack.v1.Card
+	(*Hand)(nil),                    // 1: blackjack.v1.Hand
+	(*SideBetResult)(nil),           // 2: blackjack.v1.SideBetResult
+	(*SwitchHands)(nil),             // 3: blackjack.v1.SwitchHands
+	(*Game)(nil),                    // 4: blackjack.v1.Game
+	(*GameConfig)(nil),              // 5: blackjack.v1.GameConfig
+	(*StrategyExpectedPayouts)(nil), // 6: blackjack.v1.StrategyExpectedPayouts
+	(*NewGameRequest)(nil),          // 7: blackjack.v1.NewGameRequest
+	(*ActionRequest)(nil),           // 8: blackjack.v1.ActionRequest
+	(*AdviseRequest)(nil),           // 9: blackjack.v1.AdviseRequest
+}
+var file_blackjack_proto_depIdxs = []int32{
+	0,  // 0: blackjack.v1.Hand.cards:type_name -> blackjack.v1.Card
+	1,  // 1: blackjack.v1.SwitchHands.hands:type_name -> blackjack.v1.Hand
+	1,  // 2: blackjack.v1.Game.player_hand:type_name -> blackjack.v1.Hand
+	1,  // 3: blackjack.v1.Game.dealer_hand:type_name -> blackjack.v1.Hand
+	2,  // 4: blackjack.v1.Game.side_bets:type_name -> blackjack.v1.SideBetResult
+	3,  // 5: blackjack.v1.Game.switch:type_name -> blackjack.v1.SwitchHands
+	5,  // 6: blackjack.v1.NewGameRequest.config:type_name -> blackjack.v1.GameConfig
+	4,  // 7: blackjack.v1.ActionRequest.game:type_name -> blackjack.v1.Game
+	5,  // 8: blackjack.v1.ActionRequest.config:type_name -> blackjack.v1.GameConfig
+	4,  // 9: blackjack.v1.AdviseRequest.game:type_name -> blackjack.v1.Game
+	5,  // 10: blackjack.v1.AdviseRequest.config:type_name -> blackjack.v1.GameConfig
+	7,  // 11: blackjack.v1.Blackjack.NewGame:input_type -> blackjack.v1.NewGameRequest
+	8,  // 12: blackjack.v1.Blackjack.Hit:input_type -> blackjack.v1.ActionRequest
+	8,  // 13: blackjack.v1.Blackjack.Stand:input_type -> blackjack.v1.ActionRequest
+	8,  // 14: blackjack.v1.Blackjack.Surrender:input_type -> blackjack.v1.ActionRequest
+	9,  // 15: blackjack.v1.Blackjack.Advise:input_type -> blackjack.v1.AdviseRequest
+	4,  // 16: blackjack.v1.Blackjack.NewGame:output_type -> blackjack.v1.Game
+	4,  // 17: blackjack.v1.Blackjack.Hit:output_type -> blackjack.v1.Game
+	4,  // 18: blackjack.v1.Blackjack.Stand:output_type -> blackjack.v1.Game
+	4,  // 19: blackjack.v1.Blackjack.Surrender:output_type -> blackjack.v1.Game
+	6,  // 20: blackjack.v1.Blackjack.Advise:output_type -> blackjack.v1.StrategyExpectedPayouts
+	16, // [16:21] is the sub-list for method output_type
+	11, // [11:16] is the sub-list for method input_type
+	11, // [11:11] is the sub-list for extension type_name
+	11, // [11:11] is the sub-list for extension extendee
+	0,  // [0:11] is the sub-list for field type_name
+}
+
+func init() { file_blackjack_proto_init() }
+func file_blackjack_proto_init() {
+	if File_blackjack_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_blackjack_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   10,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_blackjack_proto_goTypes,
+		DependencyIndexes: file_blackjack_proto_depIdxs,
+		MessageInfos:      file_blackjack_proto_msgTypes,
+	}.Build()
+	File_blackjack_proto = out.File
+	file_blackjack_proto_rawDesc = nil
+	file_blackjack_proto_goTypes = nil
+	file_blackjack_proto_depIdxs = nil
+}
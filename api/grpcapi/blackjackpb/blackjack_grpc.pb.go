@@ -0,0 +1,291 @@
+// gRPC で提供するブラックジャックのゲームと戦略アドバイスの API です。
+// 文字列の値（スート・ランク・状態・結果・バリエーションなど）は HTTP API の JSON と同じです。
+// ゲームの状態はサーバーに保存しないので、行動のたびに直前のレスポンスの Game をそのまま送ってください。
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: blackjack.proto
+
+package blackjackpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	Blackjack_NewGame_FullMethodName   = "/blackjack.v1.Blackjack/NewGame"
+	Blackjack_Hit_FullMethodName       = "/blackjack.v1.Blackjack/Hit"
+	Blackjack_Stand_FullMethodName     = "/blackjack.v1.Blackjack/Stand"
+	Blackjack_Surrender_FullMethodName = "/blackjack.v1.Blackjack/Surrender"
+	Blackjack_Advise_FullMethodName    = "/blackjack.v1.Blackjack/Advise"
+)
+
+// BlackjackClient is the client API for Blackjack service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Blackjack はゲームの進行と戦略アドバイスのサービスです。
+type BlackjackClient interface {
+	// NewGame は新しいゲームを配ります。
+	NewGame(ctx context.Context, in *NewGameRequest, opts ...grpc.CallOption) (*Game, error)
+	// Hit はカードを 1 枚引きます。
+	Hit(ctx context.Context, in *ActionRequest, opts ...grpc.CallOption) (*Game, error)
+	// Stand はスタンドし、ディーラーの手番を進めて精算します。
+	Stand(ctx context.Context, in *ActionRequest, opts ...grpc.CallOption) (*Game, error)
+	// Surrender はサレンダーします。
+	Surrender(ctx context.Context, in *ActionRequest, opts ...grpc.CallOption) (*Game, error)
+	// Advise は現在のゲームで各行動をとった場合の期待払い戻しを返します。
+	Advise(ctx context.Context, in *AdviseRequest, opts ...grpc.CallOption) (*StrategyExpectedPayouts, error)
+}
+
+type blackjackClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewBlackjackClient(cc grpc.ClientConnInterface) BlackjackClient {
+	return &blackjackClient{cc}
+}
+
+func (c *blackjackClient) NewGame(ctx context.Context, in *NewGameRequest, opts ...grpc.CallOption) (*Game, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Game)
+	err := c.cc.Invoke(ctx, Blackjack_NewGame_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *blackjackClient) Hit(ctx context.Context, in *ActionRequest, opts ...grpc.CallOption) (*Game, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Game)
+	err := c.cc.Invoke(ctx, Blackjack_Hit_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *blackjackClient) Stand(ctx context.Context, in *ActionRequest, opts ...grpc.CallOption) (*Game, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Game)
+	err := c.cc.Invoke(ctx, Blackjack_Stand_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *blackjackClient) Surrender(ctx context.Context, in *ActionRequest, opts ...grpc.CallOption) (*Game, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Game)
+	err := c.cc.Invoke(ctx, Blackjack_Surrender_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *blackjackClient) Advise(ctx context.Context, in *AdviseRequest, opts ...grpc.CallOption) (*StrategyExpectedPayouts, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StrategyExpectedPayouts)
+	err := c.cc.Invoke(ctx, Blackjack_Advise_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// BlackjackServer is the server API for Blackjack service.
+// All implementations must embed UnimplementedBlackjackServer
+// for forward compatibility.
+//
+// Blackjack はゲームの進行と戦略アドバイスのサービスです。
+type BlackjackServer interface {
+	// NewGame は新しいゲームを配ります。
+	NewGame(context.Context, *NewGameRequest) (*Game, error)
+	// Hit はカードを 1 枚引きます。
+	Hit(context.Context, *ActionRequest) (*Game, error)
+	// Stand はスタンドし、ディーラーの手番を進めて精算します。
+	Stand(context.Context, *ActionRequest) (*Game, error)
+	// Surrender はサレンダーします。
+	Surrender(context.Context, *ActionRequest) (*Game, error)
+	// Advise は現在のゲームで各行動をとった場合の期待払い戻しを返します。
+	Advise(context.Context, *AdviseRequest) (*StrategyExpectedPayouts, error)
+	mustEmbedUnimplementedBlackjackServer()
+}
+
+// UnimplementedBlackjackServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedBlackjackServer struct{}
+
+func (UnimplementedBlackjackServer) NewGame(context.Context, *NewGameRequest) (*Game, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method NewGame not implemented")
+}
+func (UnimplementedBlackjackServer) Hit(context.Context, *ActionRequest) (*Game, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Hit not implemented")
+}
+func (UnimplementedBlackjackServer) Stand(context.Context, *ActionRequest) (*Game, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Stand not implemented")
+}
+func (UnimplementedBlackjackServer) Surrender(context.Context, *ActionRequest) (*Game, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Surrender not implemented")
+}
+func (UnimplementedBlackjackServer) Advise(context.Context, *AdviseRequest) (*StrategyExpectedPayouts, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Advise not implemented")
+}
+func (UnimplementedBlackjackServer) mustEmbedUnimplementedBlackjackServer() {}
+func (UnimplementedBlackjackServer) testEmbeddedByValue()                   {}
+
+// UnsafeBlackjackServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to BlackjackServer will
+// result in compilation errors.
+type UnsafeBlackjackServer interface {
+	mustEmbedUnimplementedBlackjackServer()
+}
+
+func RegisterBlackjackServer(s grpc.ServiceRegistrar, srv BlackjackServer) {
+	// If the following call pancis, it indicates UnimplementedBlackjackServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&Blackjack_ServiceDesc, srv)
+}
+
+func _Blackjack_NewGame_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NewGameRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BlackjackServer).NewGame(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Blackjack_NewGame_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BlackjackServer).NewGame(ctx, req.(*NewGameRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Blackjack_Hit_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ActionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BlackjackServer).Hit(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Blackjack_Hit_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BlackjackServer).Hit(ctx, req.(*ActionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Blackjack_Stand_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ActionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BlackjackServer).Stand(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Blackjack_Stand_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BlackjackServer).Stand(ctx, req.(*ActionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Blackjack_Surrender_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ActionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BlackjackServer).Surrender(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Blackjack_Surrender_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BlackjackServer).Surrender(ctx, req.(*ActionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Blackjack_Advise_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AdviseRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BlackjackServer).Advise(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Blackjack_Advise_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BlackjackServer).Advise(ctx, req.(*AdviseRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Blackjack_ServiceDesc is the grpc.ServiceDesc for Blackjack service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Blackjack_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "blackjack.v1.Blackjack",
+	HandlerType: (*BlackjackServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "NewGame",
+			Handler:    _Blackjack_NewGame_Handler,
+		},
+		{
+			MethodName: "Hit",
+			Handler:    _Blackjack_Hit_Handler,
+		},
+		{
+			MethodName: "Stand",
+			Handler:    _Blackjack_Stand_Handler,
+		},
+		{
+			MethodName: "Surrender",
+			Handler:    _Blackjack_Surrender_Handler,
+		},
+		{
+			MethodName: "Advise",
+			Handler:    _Blackjack_Advise_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "blackjack.proto",
+}
@@ -0,0 +1,5 @@
+// Package blackjackpb は blackjack.proto から生成した gRPC のメッセージとサービスの定義です。
+// blackjack.proto を変更したら go generate で再生成してください（protoc, protoc-gen-go v1.35.2, protoc-gen-go-grpc v1.5.1 が必要）。
+package blackjackpb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative blackjack.proto
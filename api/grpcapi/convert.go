@@ -0,0 +1,147 @@
+package grpcapi
+
+import (
+	"fmt"
+
+	"blackjack/api/game"
+	"blackjack/api/grpcapi/blackjackpb"
+	"blackjack/api/services"
+	"blackjack/api/strategy"
+)
+
+// protobuf のメッセージと game パッケージの型の相互変換
+// 文字列の値は JSON と同じなのでそのまま変換し、内容の検証はサービスに任せます。
+
+func cardToProto(c game.Card) *blackjackpb.Card {
+	return &blackjackpb.Card{Suit: string(c.Suit), Rank: string(c.Rank)}
+}
+
+func cardFromProto(c *blackjackpb.Card) game.Card {
+	return game.Card{Suit: game.Suit(c.GetSuit()), Rank: game.Rank(c.GetRank())}
+}
+
+func handToProto(h game.Hand) *blackjackpb.Hand {
+	cards := make([]*blackjackpb.Card, len(h.Cards))
+	for i, c := range h.Cards {
+		cards[i] = cardToProto(c)
+	}
+	return &blackjackpb.Hand{Cards: cards, Score: int32(h.Score)}
+}
+
+func handFromProto(h *blackjackpb.Hand) game.Hand {
+	cards := make([]game.Card, len(h.GetCards()))
+	for i, c := range h.GetCards() {
+		cards[i] = cardFromProto(c)
+	}
+	return game.Hand{Cards: cards, Score: int(h.GetScore())}
+}
+
+// gameToProto は game.Game をメッセージに変換します。
+func gameToProto(g game.Game) *blackjackpb.Game {
+	pb := &blackjackpb.Game{
+		PlayerHand:     handToProto(g.PlayerHand),
+		DealerHand:     handToProto(g.DealerHand),
+		State:          string(g.State),
+		Result:         string(g.Result),
+		ResultMessage:  g.ResultMessage,
+		Bet:            int32(g.Bet),
+		Payout:         int32(g.Payout),
+		ResultReason:   string(g.ResultReason),
+		Variant:        string(g.Variant),
+		AllowedActions: make([]string, len(g.AllowedActions)),
+	}
+	for i, a := range g.AllowedActions {
+		pb.AllowedActions[i] = string(a)
+	}
+	for _, sb := range g.SideBets {
+		pb.SideBets = append(pb.SideBets, &blackjackpb.SideBetResult{
+			Type:    string(sb.Type),
+			Bet:     int32(sb.Bet),
+			Outcome: string(sb.Outcome),
+			Odds:    int32(sb.Odds),
+			Payout:  int32(sb.Payout),
+		})
+	}
+	if s := g.Switch; s != nil {
+		pb.Switch = &blackjackpb.SwitchHands{
+			HandBet:  int32(s.HandBet),
+			Active:   int32(s.Active),
+			Switched: s.Switched,
+		}
+		for i := range s.Hands {
+			pb.Switch.Hands = append(pb.Switch.Hands, handToProto(s.Hands[i]))
+			pb.Switch.Results = append(pb.Switch.Results, string(s.Results[i]))
+			pb.Switch.Reasons = append(pb.Switch.Reasons, string(s.Reasons[i]))
+			pb.Switch.Payouts = append(pb.Switch.Payouts, int32(s.Payouts[i]))
+		}
+	}
+	return pb
+}
+
+// gameFromProto はメッセージを game.Game に戻します。
+// スイッチの手の数が 2 でなければ ErrInvalidGameState を返します。
+func gameFromProto(pb *blackjackpb.Game) (game.Game, error) {
+	g := game.Game{
+		PlayerHand:     handFromProto(pb.GetPlayerHand()),
+		DealerHand:     handFromProto(pb.GetDealerHand()),
+		State:          game.GameState(pb.GetState()),
+		Result:         game.Result(pb.GetResult()),
+		ResultMessage:  pb.GetResultMessage(),
+		Bet:            int(pb.GetBet()),
+		Payout:         int(pb.GetPayout()),
+		ResultReason:   game.ResultReason(pb.GetResultReason()),
+		Variant:        game.Variant(pb.GetVariant()),
+		AllowedActions: make([]game.Action, len(pb.GetAllowedActions())),
+	}
+	for i, a := range pb.GetAllowedActions() {
+		g.AllowedActions[i] = game.Action(a)
+	}
+	for _, sb := range pb.GetSideBets() {
+		g.SideBets = append(g.SideBets, game.SideBetResult{
+			Type:    game.SideBetType(sb.GetType()),
+			Bet:     int(sb.GetBet()),
+			Outcome: game.SideBetOutcome(sb.GetOutcome()),
+			Odds:    int(sb.GetOdds()),
+			Payout:  int(sb.GetPayout()),
+		})
+	}
+	if s := pb.GetSwitch(); s != nil {
+		const hands = len(game.SwitchHands{}.Hands)
+		if len(s.GetHands()) != hands || len(s.GetResults()) != hands || len(s.GetReasons()) != hands || len(s.GetPayouts()) != hands {
+			return game.Game{}, fmt.Errorf("%w: switch must have %d hands", services.ErrInvalidGameState, hands)
+		}
+		g.Switch = &game.SwitchHands{
+			HandBet:  int(s.GetHandBet()),
+			Active:   int(s.GetActive()),
+			Switched: s.GetSwitched(),
+		}
+		for i := 0; i < hands; i++ {
+			g.Switch.Hands[i] = handFromProto(s.GetHands()[i])
+			g.Switch.Results[i] = game.Result(s.GetResults()[i])
+			g.Switch.Reasons[i] = game.ResultReason(s.GetReasons()[i])
+			g.Switch.Payouts[i] = int(s.GetPayouts()[i])
+		}
+	}
+	return g, nil
+}
+
+// configFromProto は設定を game.GameConfig に変換します。nil なら nil（クラシック）を返します。
+func configFromProto(pb *blackjackpb.GameConfig) *game.GameConfig {
+	if pb == nil {
+		return nil
+	}
+	return &game.GameConfig{
+		DealerStandThreshold: int(pb.GetDealerStandThreshold()),
+		Variant:              game.Variant(pb.GetVariant()),
+		CharlieCards:         int(pb.GetCharlieCards()),
+	}
+}
+
+func payoutsToProto(p strategy.StrategyExpectedPayouts) *blackjackpb.StrategyExpectedPayouts {
+	return &blackjackpb.StrategyExpectedPayouts{
+		HitPayout:       p.HitPayout,
+		StandPayout:     p.StandPayout,
+		SurrenderPayout: p.SurrenderPayout,
+		BestPayout:      p.BestPayout,
+	}
+}
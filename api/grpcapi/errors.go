@@ -0,0 +1,62 @@
+package grpcapi
+
+import (
+	"errors"
+
+	"blackjack/api/auth"
+	"blackjack/api/i18n"
+	"blackjack/api/services"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ErrorDomain は ErrorInfo の Domain に入れる値です。
+const ErrorDomain = "blackjack"
+
+// errorKind はエラーと gRPC のステータスコード・エラーコードの対応
+type errorKind struct {
+	target error
+	code   codes.Code
+	reason string // HTTP API の ErrorResponse.code と同じエラーコード
+}
+
+// errorKinds は上から順に errors.Is で判定する
+// Unauthenticated: 認証に失敗した、ResourceExhausted: リクエストが多すぎる、
+// FailedPrecondition: 現在の状態ではできない操作、InvalidArgument: 内容が不正
+var errorKinds = []errorKind{
+	{auth.ErrInvalidToken, codes.Unauthenticated, "invalid_token"},
+	{errRateLimited, codes.ResourceExhausted, "rate_limited"},
+
+	{services.ErrNotPlayerTurn, codes.FailedPrecondition, "not_player_turn"},
+	{services.ErrGameFinished, codes.FailedPrecondition, "game_finished"},
+	{services.ErrSurrenderNotAllowed, codes.FailedPrecondition, "surrender_not_allowed"},
+
+	{services.ErrInvalidBet, codes.InvalidArgument, "invalid_bet"},
+	{services.ErrInvalidConfig, codes.InvalidArgument, "invalid_config"},
+	{services.ErrVariantMismatch, codes.InvalidArgument, "variant_mismatch"},
+	{services.ErrInvalidGameState, codes.InvalidArgument, "invalid_game_state"},
+	{services.ErrUnknownAction, codes.InvalidArgument, "unknown_action"},
+}
+
+// toStatus はサービスのエラーを、lang に翻訳したメッセージと ErrorInfo（Reason にエラーコード、
+// Metadata の details に元のエラーの内容）を持つ gRPC のステータスに変換します。
+// 対応が登録されていないエラーは内部エラーとして扱い、内容はクライアントに返しません。
+func toStatus(err error, lang i18n.Lang) error {
+	for _, k := range errorKinds {
+		if !errors.Is(err, k.target) {
+			continue
+		}
+		st := status.New(k.code, i18n.Message(lang, "error."+k.reason))
+		if detailed, derr := st.WithDetails(&errdetails.ErrorInfo{
+			Reason:   k.reason,
+			Domain:   ErrorDomain,
+			Metadata: map[string]string{"details": err.Error()},
+		}); derr == nil {
+			st = detailed
+		}
+		return st.Err()
+	}
+	return status.Error(codes.Internal, i18n.Message(lang, "error.internal_error"))
+}
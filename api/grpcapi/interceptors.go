@@ -0,0 +1,84 @@
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+
+	"blackjack/api/auth"
+	"blackjack/api/grpcapi/blackjackpb"
+	"blackjack/api/ratelimit"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+// errRateLimited は頻度の制限を超えた RPC に返すエラー
+var errRateLimited = errors.New("rate limit exceeded")
+
+// Authenticate は authorization メタデータの Bearer トークンを検証し、認証したプレイヤーをコンテキストに記録するインタセプタです。
+// HTTP API と同じく、トークンのない RPC は匿名として通し、正しくないトークンは Unauthenticated にします。
+func Authenticate(tokens auth.TokenService) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		token := bearerToken(ctx)
+		if token == "" {
+			return handler(ctx, req)
+		}
+		id, err := tokens.Verify(token)
+		if err != nil {
+			return nil, toStatus(err, requestLang(ctx, ""))
+		}
+		return handler(auth.WithIdentity(ctx, id), req)
+	}
+}
+
+// RateLimit は RPC の頻度を制限するインタセプタです。Advise は strategy、それ以外は game の Limiter を使います
+// （HTTP API と同じ Limiter を渡せばバケットを共有します）。どの RPC も接続元の IP アドレスのバケットを使い、
+// 認証したプレイヤーはさらにプレイヤーのバケットも使います。超えた RPC は ResourceExhausted にします。
+// Authenticate より後に連結してください。
+func RateLimit(game, strategy ratelimit.Limiter) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		limiter := game
+		if info.FullMethod == blackjackpb.Blackjack_Advise_FullMethodName {
+			limiter = strategy
+		}
+		keys := []string{"ip:" + peerIP(ctx)}
+		if id, ok := auth.FromContext(ctx); ok {
+			keys = append(keys, "player:"+id.PlayerID)
+		}
+		if ok, wait := limiter.AllowAll(keys, 1); !ok {
+			return nil, toStatus(fmt.Errorf("%w: retry after %s", errRateLimited, wait), requestLang(ctx, ""))
+		}
+		return handler(ctx, req)
+	}
+}
+
+// bearerToken は authorization メタデータのトークンを返します。なければ空文字を返します。
+func bearerToken(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	for _, v := range md.Get("authorization") {
+		if scheme, token, ok := strings.Cut(v, " "); ok && strings.EqualFold(scheme, "Bearer") {
+			return strings.TrimSpace(token)
+		}
+	}
+	return ""
+}
+
+// peerIP は RPC の接続元の IP アドレスを返します。
+func peerIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+	return host
+}
@@ -0,0 +1,121 @@
+// Package grpcapi は HTTP API と同じサービスを使う gRPC のサーバーです（ボットやシミュレーター向け）。
+// メッセージとサービスの定義は blackjackpb/blackjack.proto にあります。
+package grpcapi
+
+import (
+	"context"
+	"fmt"
+
+	"blackjack/api/game"
+	"blackjack/api/grpcapi/blackjackpb"
+	"blackjack/api/i18n"
+	"blackjack/api/services"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+type blackjackServer struct {
+	blackjackpb.UnimplementedBlackjackServer
+	gameSvc       services.GameService
+	advisor       services.StrategyAdvisor
+	defaultConfig game.GameConfig
+}
+
+// NewBlackjackServer は GameService と StrategyAdvisor で Blackjack サービスを実装したサーバーを生成します。
+// 設定を省略したリクエストは defaultConfig（HTTP API と同じサーバーの既定のルール）で処理します。
+func NewBlackjackServer(gameSvc services.GameService, advisor services.StrategyAdvisor, defaultConfig game.GameConfig) blackjackpb.BlackjackServer {
+	if gameSvc == nil || advisor == nil {
+		panic("game service and strategy advisor must not be nil")
+	}
+	return &blackjackServer{gameSvc: gameSvc, advisor: advisor, defaultConfig: defaultConfig}
+}
+
+// NewServer は Blackjack サービスを登録した gRPC サーバーを生成します。
+// 認証とレート制限は opts のインタセプタ（Authenticate、RateLimit）で設定します。
+func NewServer(gameSvc services.GameService, advisor services.StrategyAdvisor, defaultConfig game.GameConfig, opts ...grpc.ServerOption) *grpc.Server {
+	s := grpc.NewServer(opts...)
+	blackjackpb.RegisterBlackjackServer(s, NewBlackjackServer(gameSvc, advisor, defaultConfig))
+	return s
+}
+
+// configOrDefault はリクエストの設定を返します。省略した場合はサーバーの既定のルールを返します。
+func (s *blackjackServer) configOrDefault(pb *blackjackpb.GameConfig) *game.GameConfig {
+	if config := configFromProto(pb); config != nil {
+		return config
+	}
+	config := s.defaultConfig
+	return &config
+}
+
+// requestLang はレスポンスの言語を、リクエストの locale、accept-language メタデータ、既定の言語の順に決めます。
+func requestLang(ctx context.Context, locale string) i18n.Lang {
+	if lang, ok := i18n.Parse(locale); ok {
+		return lang
+	}
+	var header string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if v := md.Get("accept-language"); len(v) > 0 {
+			header = v[0]
+		}
+	}
+	return i18n.FromAcceptLanguage(header)
+}
+
+// NewGame は新しいゲームを配ります。
+func (s *blackjackServer) NewGame(ctx context.Context, req *blackjackpb.NewGameRequest) (*blackjackpb.Game, error) {
+	lang := requestLang(ctx, req.GetLocale())
+	g, err := s.gameSvc.NewGame(int(req.GetBet()), s.configOrDefault(req.GetConfig()))
+	if err != nil {
+		return nil, toStatus(err, lang)
+	}
+	i18n.Localize(&g, lang)
+	return gameToProto(g), nil
+}
+
+// Hit はカードを 1 枚引きます。
+func (s *blackjackServer) Hit(ctx context.Context, req *blackjackpb.ActionRequest) (*blackjackpb.Game, error) {
+	return s.act(ctx, req, s.gameSvc.Hit)
+}
+
+// Stand はスタンドします。
+func (s *blackjackServer) Stand(ctx context.Context, req *blackjackpb.ActionRequest) (*blackjackpb.Game, error) {
+	return s.act(ctx, req, s.gameSvc.Stand)
+}
+
+// Surrender はサレンダーします。
+func (s *blackjackServer) Surrender(ctx context.Context, req *blackjackpb.ActionRequest) (*blackjackpb.Game, error) {
+	return s.act(ctx, req, s.gameSvc.Surrender)
+}
+
+// act はリクエストのゲームに apply を適用し、進めたゲームを返します。
+func (s *blackjackServer) act(ctx context.Context, req *blackjackpb.ActionRequest, apply func(*game.Game, *game.GameConfig) error) (*blackjackpb.Game, error) {
+	lang := requestLang(ctx, req.GetLocale())
+	g, err := gameFromProto(req.GetGame())
+	if err != nil {
+		return nil, toStatus(err, lang)
+	}
+	if err := apply(&g, s.configOrDefault(req.GetConfig())); err != nil {
+		return nil, toStatus(err, lang)
+	}
+	i18n.Localize(&g, lang)
+	return gameToProto(g), nil
+}
+
+// Advise は各行動の期待払い戻しを返します。
+func (s *blackjackServer) Advise(ctx context.Context, req *blackjackpb.AdviseRequest) (*blackjackpb.StrategyExpectedPayouts, error) {
+	lang := requestLang(ctx, "")
+	config := s.configOrDefault(req.GetConfig())
+	if config.DealerStandThreshold < 1 || config.DealerStandThreshold > 21 {
+		return nil, toStatus(fmt.Errorf("%w: dealer stand threshold must be between 1 and 21", services.ErrInvalidConfig), lang)
+	}
+	g, err := gameFromProto(req.GetGame())
+	if err != nil {
+		return nil, toStatus(err, lang)
+	}
+	payouts, err := s.advisor.Advise(g, config)
+	if err != nil {
+		return nil, toStatus(err, lang)
+	}
+	return payoutsToProto(payouts), nil
+}
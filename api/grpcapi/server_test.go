@@ -0,0 +1,258 @@
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+
+	"blackjack/api/auth"
+	"blackjack/api/game"
+	"blackjack/api/grpcapi/blackjackpb"
+	"blackjack/api/ratelimit"
+	"blackjack/api/services"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// scriptedDeck は指定した順にカードを配るデッキ
+type scriptedDeck struct {
+	cards []game.Card
+}
+
+func (d *scriptedDeck) Deal() game.Card {
+	c := d.cards[0]
+	d.cards = d.cards[1:]
+	return c
+}
+
+// newClient は bufconn 上でサーバー（既定のルールはディーラーが 17 でスタンド）を起動し、接続したクライアントを返します。
+func newClient(t *testing.T, deck game.Deck, opts ...grpc.ServerOption) blackjackpb.BlackjackClient {
+	t.Helper()
+	lis := bufconn.Listen(1 << 20)
+	srv := NewServer(services.NewGameService(deck), services.NewStrategyService(), game.GameConfig{DealerStandThreshold: 17}, opts...)
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return blackjackpb.NewBlackjackClient(conn)
+}
+
+func TestServer_PlaysGame(t *testing.T) {
+	// プレイヤー: 10, 6 → 4 (20)。ディーラー: 9 → 8 (17)
+	client := newClient(t, &scriptedDeck{cards: []game.Card{
+		{Suit: game.Spade, Rank: "10"}, {Suit: game.Heart, Rank: "6"}, {Suit: game.Club, Rank: "9"},
+		{Suit: game.Diamond, Rank: "4"}, {Suit: game.Heart, Rank: "8"},
+	}})
+	ctx := context.Background()
+	config := &blackjackpb.GameConfig{DealerStandThreshold: 17}
+
+	g, err := client.NewGame(ctx, &blackjackpb.NewGameRequest{Bet: 10, Config: config})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g.GetState() != string(game.PlayerTurn) || g.GetPlayerHand().GetScore() != 16 || g.GetDealerHand().GetScore() != 9 {
+		t.Fatalf("new game = %v", g)
+	}
+	if want := []string{"hit", "stand", "surrender"}; !reflect.DeepEqual(g.GetAllowedActions(), want) {
+		t.Errorf("allowed_actions = %v, want %v", g.GetAllowedActions(), want)
+	}
+
+	payouts, err := client.Advise(ctx, &blackjackpb.AdviseRequest{Game: g, Config: config})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if payouts.GetHitPayout() <= 0 || payouts.GetBestPayout() < payouts.GetStandPayout() {
+		t.Errorf("payouts = %v", payouts)
+	}
+
+	g, err = client.Hit(ctx, &blackjackpb.ActionRequest{Game: g, Config: config})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g.GetPlayerHand().GetScore() != 20 {
+		t.Fatalf("after hit = %v", g)
+	}
+
+	g, err = client.Stand(ctx, &blackjackpb.ActionRequest{Game: g, Config: config, Locale: "en"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g.GetResult() != string(game.PlayerWin) || g.GetPayout() != 20 || g.GetDealerHand().GetScore() != 17 {
+		t.Errorf("after stand = %v", g)
+	}
+	if g.GetResultReason() != string(game.ReasonPlayerWin) || g.GetResultMessage() != "The player wins." {
+		t.Errorf("result = %q %q, want English player_win", g.GetResultReason(), g.GetResultMessage())
+	}
+}
+
+func TestServer_ReturnsStatusErrors(t *testing.T) {
+	client := newClient(t, &game.RandomDeck{})
+	finished := gameToProto(game.Game{
+		PlayerHand: game.Hand{Cards: []game.Card{{Suit: game.Spade, Rank: "10"}, {Suit: game.Heart, Rank: "9"}}, Score: 19},
+		DealerHand: game.Hand{Cards: []game.Card{{Suit: game.Club, Rank: "10"}, {Suit: game.Club, Rank: "7"}}, Score: 17},
+		State:      game.Finished,
+		Result:     game.PlayerWin,
+		Bet:        10,
+		Payout:     20,
+	})
+	english := metadata.AppendToOutgoingContext(context.Background(), "accept-language", "en-US,en;q=0.9")
+
+	tests := []struct {
+		name    string
+		call    func() error
+		code    codes.Code
+		reason  string
+		message string
+	}{
+		{"finished", func() error {
+			_, err := client.Hit(english, &blackjackpb.ActionRequest{Game: finished, Config: &blackjackpb.GameConfig{DealerStandThreshold: 17}})
+			return err
+		}, codes.FailedPrecondition, "not_player_turn", "It is not the player's turn."},
+		{"invalid bet", func() error {
+			_, err := client.NewGame(context.Background(), &blackjackpb.NewGameRequest{Bet: 0})
+			return err
+		}, codes.InvalidArgument, "invalid_bet", "掛け金は1以上にしてください"},
+		{"invalid config", func() error {
+			_, err := client.Advise(english, &blackjackpb.AdviseRequest{Game: finished, Config: &blackjackpb.GameConfig{DealerStandThreshold: 30}})
+			return err
+		}, codes.InvalidArgument, "invalid_config", "The configuration is invalid."},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			st, ok := status.FromError(tc.call())
+			if !ok || st.Code() != tc.code || st.Message() != tc.message {
+				t.Fatalf("status = %v, want %v %q", st, tc.code, tc.message)
+			}
+			var info *errdetails.ErrorInfo
+			for _, d := range st.Details() {
+				if i, ok := d.(*errdetails.ErrorInfo); ok {
+					info = i
+				}
+			}
+			if info == nil || info.GetReason() != tc.reason || info.GetDomain() != ErrorDomain || info.GetMetadata()["details"] == "" {
+				t.Errorf("error info = %v, want reason %q", info, tc.reason)
+			}
+		})
+	}
+}
+
+func TestServer_UsesDefaultConfig(t *testing.T) {
+	// プレイヤー: 10, 8 (18)。ディーラー: 9 → 7 (16) → 2 (18)。既定のルールでは 16 でスタンドしない
+	client := newClient(t, &scriptedDeck{cards: []game.Card{
+		{Suit: game.Spade, Rank: "10"}, {Suit: game.Heart, Rank: "8"}, {Suit: game.Club, Rank: "9"},
+		{Suit: game.Diamond, Rank: "7"}, {Suit: game.Heart, Rank: "2"},
+	}})
+	ctx := context.Background()
+
+	g, err := client.NewGame(ctx, &blackjackpb.NewGameRequest{Bet: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Advise(ctx, &blackjackpb.AdviseRequest{Game: g}); err != nil {
+		t.Fatalf("advise without config: %v", err)
+	}
+	g, err = client.Stand(ctx, &blackjackpb.ActionRequest{Game: g})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g.GetDealerHand().GetScore() != 18 || g.GetResult() != string(game.Push) {
+		t.Errorf("after stand = %v, want the dealer to draw to 18", g)
+	}
+}
+
+func TestServer_AuthenticatesAndRateLimits(t *testing.T) {
+	tokens := auth.NewTokenService([]byte("secret"), time.Hour)
+	limiter := ratelimit.New(ratelimit.Limit{Rate: 0.001, Burst: 1})
+	client := newClient(t, &game.RandomDeck{},
+		grpc.ChainUnaryInterceptor(Authenticate(tokens), RateLimit(limiter, limiter)))
+	token, _, err := tokens.Issue(auth.Identity{PlayerID: "alice"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	withToken := func(token string) context.Context {
+		return metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+token)
+	}
+
+	_, err = client.NewGame(withToken("forged"), &blackjackpb.NewGameRequest{Bet: 10})
+	if st, _ := status.FromError(err); st.Code() != codes.Unauthenticated {
+		t.Errorf("forged token: status = %v, want Unauthenticated", st)
+	}
+	if _, err := client.NewGame(withToken(token), &blackjackpb.NewGameRequest{Bet: 10}); err != nil {
+		t.Fatalf("first call: %v", err)
+	}
+	_, err = client.NewGame(withToken(token), &blackjackpb.NewGameRequest{Bet: 10})
+	if st, _ := status.FromError(err); st.Code() != codes.ResourceExhausted {
+		t.Errorf("second call: status = %v, want ResourceExhausted", st)
+	}
+
+	// プレイヤーのバケットで断った RPC は IP アドレスのトークンを使わない
+	bobToken, _, err := tokens.Issue(auth.Identity{PlayerID: "bob"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	fresh := ratelimit.New(ratelimit.Limit{Rate: 0.001, Burst: 1})
+	fresh.Allow("player:bob")
+	client = newClient(t, &game.RandomDeck{},
+		grpc.ChainUnaryInterceptor(Authenticate(tokens), RateLimit(fresh, fresh)))
+	_, err = client.NewGame(withToken(bobToken), &blackjackpb.NewGameRequest{Bet: 10})
+	if st, _ := status.FromError(err); st.Code() != codes.ResourceExhausted {
+		t.Errorf("player with an empty bucket: status = %v, want ResourceExhausted", st)
+	}
+	if _, err := client.NewGame(context.Background(), &blackjackpb.NewGameRequest{Bet: 10}); err != nil {
+		t.Errorf("anonymous call from the same IP: %v", err)
+	}
+}
+
+func TestGameProto_RoundTrip(t *testing.T) {
+	hand := func(ranks ...game.Rank) game.Hand {
+		var cards []game.Card
+		for _, r := range ranks {
+			cards = append(cards, game.Card{Suit: game.Heart, Rank: r})
+		}
+		return game.Hand{Cards: cards, Score: game.CalculateScore(cards)}
+	}
+	g := game.Game{
+		PlayerHand:     hand("10", "6"),
+		DealerHand:     hand("9"),
+		State:          game.PlayerTurn,
+		Result:         game.Pending,
+		Bet:            20,
+		Variant:        game.VariantSwitch,
+		SideBets:       []game.SideBetResult{{Type: game.SideBetPerfectPairs, Bet: 5, Outcome: game.SideBetLose}},
+		AllowedActions: []game.Action{game.ActionHit, game.ActionStand},
+		Switch: &game.SwitchHands{
+			Hands:   [2]game.Hand{hand("10", "6"), hand("A", "8")},
+			HandBet: 10,
+			Results: [2]game.Result{game.Pending, game.Pending},
+		},
+	}
+	got, err := gameFromProto(gameToProto(g))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, g) {
+		t.Errorf("round trip = %+v, want %+v", got, g)
+	}
+
+	pb := gameToProto(g)
+	pb.Switch.Hands = pb.Switch.Hands[:1]
+	if _, err := gameFromProto(pb); !errors.Is(err, services.ErrInvalidGameState) {
+		t.Errorf("err = %v, want ErrInvalidGameState", err)
+	}
+}
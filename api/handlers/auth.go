@@ -0,0 +1,163 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"blackjack/api/auth"
+
+	"github.com/gorilla/mux"
+)
+
+// 認証に関するハンドラ自身が返すエラー
+var (
+	errUnauthenticated = errors.New("authentication required")
+	errForbidden       = errors.New("not allowed for the authenticated player")
+)
+
+// AuthRequest はプレイヤーの登録・ログインのリクエストボディ
+type AuthRequest struct {
+	Name     string `json:"name"`
+	Password string `json:"password"`
+}
+
+// AuthResponse は発行したトークンとプレイヤー
+// 以降のリクエストには Authorization: Bearer <token> を付けてください。
+type AuthResponse struct {
+	Token     string        `json:"token"`
+	ExpiresAt time.Time     `json:"expires_at"`
+	Player    auth.Identity `json:"player"`
+}
+
+// Authenticate は Authorization: Bearer のトークン（WebSocket ではクエリの access_token も可）を検証し、
+// プレイヤーをリクエストのコンテキストに記録するミドルウェアです。
+// トークンがなければ匿名のまま通し、不正・期限切れのトークンは 401 にします。
+func Authenticate(tokens auth.TokenService) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token := bearerToken(r)
+			if token == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			id, err := tokens.Verify(token)
+			if err != nil {
+				w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+				writeError(w, r, err)
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(auth.WithIdentity(r.Context(), id)))
+		})
+	}
+}
+
+// bearerToken はリクエストのトークンを返します。なければ空文字を返します。
+func bearerToken(r *http.Request) string {
+	if scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " "); ok && strings.EqualFold(scheme, "Bearer") {
+		return strings.TrimSpace(token)
+	}
+	return r.URL.Query().Get("access_token")
+}
+
+// playerFor はリクエストを行うプレイヤーの ID を返します。
+// 認証していれば認証したプレイヤー（claimed を指定する場合は同じ ID でなければ errForbidden）、
+// 匿名なら空文字（claimed を指定した場合は errUnauthenticated）を返します。
+func playerFor(r *http.Request, claimed string) (string, error) {
+	id, ok := auth.FromContext(r.Context())
+	if !ok {
+		if claimed != "" {
+			return "", errUnauthenticated
+		}
+		return "", nil
+	}
+	if claimed != "" && claimed != id.PlayerID {
+		return "", errForbidden
+	}
+	return id.PlayerID, nil
+}
+
+// requirePlayer は playerFor と同じですが、匿名のリクエストは errUnauthenticated にします。
+func requirePlayer(r *http.Request, claimed string) (string, error) {
+	playerID, err := playerFor(r, claimed)
+	if err == nil && playerID == "" {
+		err = errUnauthenticated
+	}
+	return playerID, err
+}
+
+// issueToken はプレイヤーのトークンを発行してレスポンスに書き込みます。
+func issueToken(w http.ResponseWriter, r *http.Request, tokens auth.TokenService, id auth.Identity) {
+	token, expires, err := tokens.Issue(id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	json.NewEncoder(w).Encode(AuthResponse{Token: token, ExpiresAt: expires, Player: id})
+}
+
+// RegisterHandler はプレイヤーを登録し、トークンを返すハンドラ
+func RegisterHandler(accounts auth.AccountService, tokens auth.TokenService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		var req AuthRequest
+		if err := decodeJSON(w, r, &req); err != nil {
+			writeError(w, r, err)
+			return
+		}
+		id, err := accounts.Register(req.Name, req.Password)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		issueToken(w, r, tokens, id)
+	}
+}
+
+// LoginHandler は名前とパスワードを確かめ、トークンを返すハンドラ
+func LoginHandler(accounts auth.AccountService, tokens auth.TokenService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		var req AuthRequest
+		if err := decodeJSON(w, r, &req); err != nil {
+			writeError(w, r, err)
+			return
+		}
+		id, err := accounts.Login(req.Name, req.Password)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		issueToken(w, r, tokens, id)
+	}
+}
+
+// GuestHandler は登録せずに遊ぶゲストを発行し、トークンを返すハンドラ
+func GuestHandler(accounts auth.AccountService, tokens auth.TokenService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		id, err := accounts.Guest()
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		issueToken(w, r, tokens, id)
+	}
+}
+
+// MeHandler は認証したプレイヤーを返すハンドラ
+func MeHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	id, ok := auth.FromContext(r.Context())
+	if !ok {
+		writeError(w, r, errUnauthenticated)
+		return
+	}
+	json.NewEncoder(w).Encode(id)
+}
@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"blackjack/api/auth"
+
+	"github.com/gorilla/mux"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// testTokens はテスト用のトークンサービス
+var testTokens = auth.NewTokenService([]byte("test secret"), time.Hour)
+
+// tokenFor は playerID のプレイヤーのトークンを返します。
+func tokenFor(t *testing.T, playerID string) string {
+	t.Helper()
+	token, _, err := testTokens.Issue(auth.Identity{PlayerID: playerID, Name: playerID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return token
+}
+
+// asPlayer は r を playerID として認証したリクエストにします。
+func asPlayer(r *http.Request, playerID string) *http.Request {
+	return r.WithContext(auth.WithIdentity(r.Context(), auth.Identity{PlayerID: playerID, Name: playerID}))
+}
+
+func newAuthRouter() *mux.Router {
+	accounts := auth.NewAccountService(bcrypt.MinCost)
+	router := mux.NewRouter()
+	router.HandleFunc("/api/auth/register", RegisterHandler(accounts, testTokens)).Methods("POST")
+	router.HandleFunc("/api/auth/login", LoginHandler(accounts, testTokens)).Methods("POST")
+	router.HandleFunc("/api/auth/guest", GuestHandler(accounts, testTokens)).Methods("POST")
+	router.HandleFunc("/api/auth/me", MeHandler).Methods("GET")
+	router.Use(Authenticate(testTokens))
+	return router
+}
+
+func TestAuthHandlers_RegisterLoginAndMe(t *testing.T) {
+	router := newAuthRouter()
+
+	rr := doJSON(t, router, http.MethodPost, "/api/auth/register", AuthRequest{Name: "alice", Password: "correct horse"})
+	if rr.Code != http.StatusOK {
+		t.Fatalf("register: got %d: %s", rr.Code, rr.Body.String())
+	}
+	if rr := doJSON(t, router, http.MethodPost, "/api/auth/register", AuthRequest{Name: "alice", Password: "another pass"}); rr.Code != http.StatusConflict {
+		t.Errorf("duplicate register: got %d, want 409", rr.Code)
+	}
+	if rr := doJSON(t, router, http.MethodPost, "/api/auth/login", AuthRequest{Name: "alice", Password: "wrong password"}); rr.Code != http.StatusUnauthorized {
+		t.Errorf("wrong password: got %d, want 401", rr.Code)
+	}
+
+	rr = doJSON(t, router, http.MethodPost, "/api/auth/login", AuthRequest{Name: "alice", Password: "correct horse"})
+	var login AuthResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &login); err != nil || rr.Code != http.StatusOK {
+		t.Fatalf("login: got %d: %s", rr.Code, rr.Body.String())
+	}
+	if login.Player != (auth.Identity{PlayerID: "alice", Name: "alice"}) || login.Token == "" || !login.ExpiresAt.After(time.Now()) {
+		t.Fatalf("unexpected login response: %+v", login)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/auth/me", nil)
+	req.Header.Set("Authorization", "Bearer "+login.Token)
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	var me auth.Identity
+	json.Unmarshal(rr.Body.Bytes(), &me)
+	if rr.Code != http.StatusOK || me.PlayerID != "alice" {
+		t.Errorf("me: got %d %+v", rr.Code, me)
+	}
+
+	if rr := doJSON(t, router, http.MethodGet, "/api/auth/me", nil); rr.Code != http.StatusUnauthorized {
+		t.Errorf("anonymous me: got %d, want 401", rr.Code)
+	}
+}
+
+func TestAuthHandlers_Guest(t *testing.T) {
+	router := newAuthRouter()
+	rr := doJSON(t, router, http.MethodPost, "/api/auth/guest", nil)
+	var resp AuthResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil || rr.Code != http.StatusOK {
+		t.Fatalf("guest: got %d: %s", rr.Code, rr.Body.String())
+	}
+	if !resp.Player.Guest || !strings.HasPrefix(resp.Player.PlayerID, "guest-") {
+		t.Errorf("unexpected guest: %+v", resp.Player)
+	}
+	id, err := testTokens.Verify(resp.Token)
+	if err != nil || id != resp.Player {
+		t.Errorf("token identity = %+v, %v, want %+v", id, err, resp.Player)
+	}
+}
+
+func TestAuthenticate_RejectsInvalidToken(t *testing.T) {
+	handler := Authenticate(testTokens)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	expired, _, _ := auth.NewTokenService([]byte("test secret"), -time.Minute).Issue(auth.Identity{PlayerID: "alice"})
+	forged, _, _ := auth.NewTokenService([]byte("other secret"), time.Hour).Issue(auth.Identity{PlayerID: "alice"})
+
+	for name, token := range map[string]string{"garbage": "not-a-token", "expired": expired, "forged": forged} {
+		req := httptest.NewRequest(http.MethodGet, "/api/health", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		var resp ErrorResponse
+		json.Unmarshal(rr.Body.Bytes(), &resp)
+		if rr.Code != http.StatusUnauthorized || resp.Code != "invalid_token" || rr.Header().Get("WWW-Authenticate") == "" {
+			t.Errorf("%s: got %d %+v", name, rr.Code, resp)
+		}
+	}
+
+	// トークンがなければ匿名のまま通す
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/health", bytes.NewReader(nil)))
+	if rr.Code != http.StatusNoContent {
+		t.Errorf("anonymous: got %d, want 204", rr.Code)
+	}
+}
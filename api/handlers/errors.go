@@ -0,0 +1,132 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"blackjack/api/auth"
+	"blackjack/api/i18n"
+	"blackjack/api/services"
+	"blackjack/api/table"
+	"blackjack/api/tournament"
+)
+
+// ErrorResponse はエラー時のレスポンスボディ
+type ErrorResponse struct {
+	Code    string `json:"code"`              // 機械可読なエラーコード
+	Message string `json:"message"`           // リクエストの言語に翻訳したメッセージ
+	Details string `json:"details,omitempty"` // 元のエラーの内容（英語）
+}
+
+// ハンドラ自身が返すエラー
+var (
+	errInvalidRequest       = errors.New("invalid request")
+	errTableNotFound        = errors.New("table not found")
+	errStreamingUnsupported = errors.New("streaming is not supported")
+)
+
+// invalidRequest はリクエストの形式の誤り（JSON やクエリの不正）を表すエラーを返します。
+func invalidRequest(detail string) error {
+	return fmt.Errorf("%w: %s", errInvalidRequest, detail)
+}
+
+// errorKind はエラーとエラーコード・ステータスコードの対応
+type errorKind struct {
+	target error
+	status int
+	code   string
+}
+
+// errorKinds は上から順に errors.Is で判定する
+// 400: リクエストの形式の誤り、401: 認証していない・認証に失敗した、403: 他のプレイヤーとしての操作、
+// 406: 提供していない API のバージョン、404: 対象が見つからない、409: 現在の状態ではできない操作、
+// 413: リクエストボディが大きすぎる、422: 内容が不正、429: リクエストが多すぎる
+var errorKinds = []errorKind{
+	{errInvalidRequest, http.StatusBadRequest, "invalid_request"},
+	{errRequestTooLarge, http.StatusRequestEntityTooLarge, "request_too_large"},
+	{errRateLimited, http.StatusTooManyRequests, "rate_limited"},
+	{errUnauthenticated, http.StatusUnauthorized, "unauthenticated"},
+	{auth.ErrInvalidToken, http.StatusUnauthorized, "invalid_token"},
+	{auth.ErrInvalidCredentials, http.StatusUnauthorized, "invalid_credentials"},
+	{errForbidden, http.StatusForbidden, "forbidden"},
+	{tournament.ErrNotCreator, http.StatusForbidden, "forbidden"},
+	{errUnsupportedVersion, http.StatusNotAcceptable, "unsupported_version"},
+
+	{errTableNotFound, http.StatusNotFound, "not_found"},
+	{tournament.ErrNotFound, http.StatusNotFound, "not_found"},
+	{services.ErrGameNotFound, http.StatusNotFound, "not_found"},
+	{services.ErrSessionNotFound, http.StatusNotFound, "not_found"},
+	{services.ErrPlayerNotFound, http.StatusNotFound, "not_found"},
+	{services.ErrSpotNotFound, http.StatusNotFound, "not_found"},
+
+	{services.ErrNotPlayerTurn, http.StatusConflict, "not_player_turn"},
+	{services.ErrGameFinished, http.StatusConflict, "game_finished"},
+	{auth.ErrNameTaken, http.StatusConflict, "name_taken"},
+	{services.ErrSurrenderNotAllowed, http.StatusConflict, "surrender_not_allowed"},
+	{services.ErrSwitchNotAllowed, http.StatusConflict, "switch_not_allowed"},
+	{services.ErrGameNotFinished, http.StatusConflict, "game_not_finished"},
+	{table.ErrTableFull, http.StatusConflict, "table_full"},
+	{table.ErrSeatTaken, http.StatusConflict, "seat_taken"},
+	{table.ErrNotSeated, http.StatusConflict, "not_seated"},
+	{table.ErrAlreadySeated, http.StatusConflict, "already_seated"},
+	{table.ErrWrongPhase, http.StatusConflict, "wrong_phase"},
+	{table.ErrNotYourTurn, http.StatusConflict, "not_your_turn"},
+	{table.ErrNoBets, http.StatusConflict, "no_bets"},
+	{tournament.ErrNotRegistering, http.StatusConflict, "not_registering"},
+	{tournament.ErrAlreadyRegistered, http.StatusConflict, "already_registered"},
+	{tournament.ErrNotRegistered, http.StatusConflict, "not_registered"},
+	{tournament.ErrNotEnoughPlayers, http.StatusConflict, "not_enough_players"},
+	{tournament.ErrNotRunning, http.StatusConflict, "not_running"},
+	{tournament.ErrEliminated, http.StatusConflict, "eliminated"},
+	{tournament.ErrHandInProgress, http.StatusConflict, "hand_in_progress"},
+	{tournament.ErrNoHandInProgress, http.StatusConflict, "no_hand_in_progress"},
+	{tournament.ErrNoHandsLeft, http.StatusConflict, "no_hands_left"},
+
+	{services.ErrInvalidBet, http.StatusUnprocessableEntity, "invalid_bet"},
+	{tournament.ErrBetOutOfRange, http.StatusUnprocessableEntity, "bet_out_of_range"},
+	{tournament.ErrInsufficientChips, http.StatusUnprocessableEntity, "insufficient_chips"},
+	{services.ErrInvalidConfig, http.StatusUnprocessableEntity, "invalid_config"},
+	{tournament.ErrInvalidConfig, http.StatusUnprocessableEntity, "invalid_config"},
+	{services.ErrVariantMismatch, http.StatusUnprocessableEntity, "variant_mismatch"},
+	{services.ErrInvalidGameState, http.StatusUnprocessableEntity, "invalid_game_state"},
+	{services.ErrUnknownAction, http.StatusUnprocessableEntity, "unknown_action"},
+	{services.ErrInvalidSideBet, http.StatusUnprocessableEntity, "invalid_side_bet"},
+	{services.ErrPlayerIDRequired, http.StatusUnprocessableEntity, "player_id_required"},
+	{services.ErrInvalidArgument, http.StatusUnprocessableEntity, "invalid_argument"},
+	{table.ErrInvalidSeat, http.StatusUnprocessableEntity, "invalid_seat"},
+	{auth.ErrInvalidName, http.StatusUnprocessableEntity, "invalid_name"},
+	{auth.ErrInvalidPassword, http.StatusUnprocessableEntity, "invalid_password"},
+}
+
+// classifyError はエラーに対応するステータスコードとエラーコードを返します。
+// 対応が登録されていないエラーは内部エラーとして扱います。
+func classifyError(err error) (int, string) {
+	for _, k := range errorKinds {
+		if errors.Is(err, k.target) {
+			return k.status, k.code
+		}
+	}
+	return http.StatusInternalServerError, "internal_error"
+}
+
+// newErrorResponse はエラーを lang で翻訳したレスポンスボディに変換します。
+func newErrorResponse(err error, lang i18n.Lang) (int, ErrorResponse) {
+	status, code := classifyError(err)
+	resp := ErrorResponse{Code: code, Message: i18n.Message(lang, "error."+code)}
+	// 内部エラーの内容はクライアントに返さない
+	if status != http.StatusInternalServerError {
+		resp.Details = err.Error()
+	}
+	return status, resp
+}
+
+// writeError はエラーを {code, message, details} の JSON で書き込みます。
+// メッセージはリクエストの言語（requestLang）に翻訳します。
+func writeError(w http.ResponseWriter, r *http.Request, err error) {
+	status, resp := newErrorResponse(err, requestLang(r))
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(resp)
+}
@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"blackjack/api/i18n"
+	"blackjack/api/services"
+	"blackjack/api/table"
+)
+
+func TestWriteError(t *testing.T) {
+	cases := []struct {
+		err    error
+		status int
+		code   string
+	}{
+		{invalidRequest("invalid request body"), http.StatusBadRequest, "invalid_request"},
+		{services.ErrSessionNotFound, http.StatusNotFound, "not_found"},
+		{fmt.Errorf("%w: only with the initial 2 cards", services.ErrSurrenderNotAllowed), http.StatusConflict, "surrender_not_allowed"},
+		{table.ErrNotYourTurn, http.StatusConflict, "not_your_turn"},
+		{services.ErrInvalidBet, http.StatusUnprocessableEntity, "invalid_bet"},
+		{fmt.Errorf("%w: unknown variant: pontoon", services.ErrInvalidConfig), http.StatusUnprocessableEntity, "invalid_config"},
+	}
+	for _, c := range cases {
+		rr := httptest.NewRecorder()
+		writeError(rr, httptest.NewRequest(http.MethodPost, "/", nil), c.err)
+
+		if rr.Code != c.status {
+			t.Errorf("%v: expected status %d, got %d", c.err, c.status, rr.Code)
+		}
+		if got := rr.Header().Get("Content-Type"); got != "application/json" {
+			t.Errorf("%v: expected JSON content type, got %s", c.err, got)
+		}
+		var resp ErrorResponse
+		if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("%v: failed to unmarshal response: %v", c.err, err)
+		}
+		if resp.Code != c.code || resp.Details != c.err.Error() || resp.Message == "" {
+			t.Errorf("%v: unexpected response %+v", c.err, resp)
+		}
+	}
+}
+
+func TestWriteError_LocalizesMessage(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	req.Header.Set("Accept-Language", "en-US,en;q=0.9,ja;q=0.8")
+	rr := httptest.NewRecorder()
+	writeError(rr, req, services.ErrNotPlayerTurn)
+
+	var resp ErrorResponse
+	json.Unmarshal(rr.Body.Bytes(), &resp)
+	if resp.Message != i18n.Message(i18n.English, "error.not_player_turn") {
+		t.Fatalf("expected an english message, got %+v", resp)
+	}
+
+	rr = httptest.NewRecorder()
+	writeError(rr, httptest.NewRequest(http.MethodPost, "/", nil), services.ErrNotPlayerTurn)
+	json.Unmarshal(rr.Body.Bytes(), &resp)
+	if resp.Message != i18n.Message(i18n.Japanese, "error.not_player_turn") {
+		t.Fatalf("expected the default japanese message, got %+v", resp)
+	}
+}
+
+func TestWriteError_HidesInternalDetails(t *testing.T) {
+	rr := httptest.NewRecorder()
+	writeError(rr, httptest.NewRequest(http.MethodGet, "/", nil), fmt.Errorf("disk on fire"))
+
+	var resp ErrorResponse
+	json.Unmarshal(rr.Body.Bytes(), &resp)
+	if rr.Code != http.StatusInternalServerError || resp.Code != "internal_error" || resp.Details != "" {
+		t.Fatalf("expected an opaque internal error, got %d %+v", rr.Code, resp)
+	}
+}
@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"blackjack/api/game"
+
+	"github.com/gorilla/mux"
+)
+
+type defaultConfigKey struct{}
+
+// DefaultGameConfig は、リクエストで設定を省略した新規ゲームとテーブルに config のルールを使うミドルウェアです。
+// 適用しなければ、新規ゲームはクラシックのルールで始め、テーブルは設定を必須にします。
+func DefaultGameConfig(config game.GameConfig) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), defaultConfigKey{}, config)))
+		})
+	}
+}
+
+// gameConfigFor は新規ゲームの設定を返します。config が nil ならサーバーの既定のルール（なければ nil）を返します。
+func gameConfigFor(r *http.Request, config *game.GameConfig) *game.GameConfig {
+	if config != nil {
+		return config
+	}
+	if def, ok := r.Context().Value(defaultConfigKey{}).(game.GameConfig); ok {
+		return &def
+	}
+	return nil
+}
+
+// tableConfigFor はテーブルの設定を返します。config を省略した（ゼロ値の）場合はサーバーの既定のルールを返します。
+func tableConfigFor(r *http.Request, config game.GameConfig) game.GameConfig {
+	if config == (game.GameConfig{}) {
+		if def := gameConfigFor(r, nil); def != nil {
+			return *def
+		}
+	}
+	return config
+}
@@ -0,0 +1,221 @@
+package handlers
+
+import (
+	"fmt"
+
+	"blackjack/api/game"
+	"blackjack/api/services"
+	"blackjack/api/table"
+	"blackjack/api/tournament"
+)
+
+// HandV2 は v2 のプレイヤーの手。掛け金と精算結果を手ごとに持ちます。
+type HandV2 struct {
+	Cards        []game.Card       `json:"cards"`
+	Score        int               `json:"score"`
+	Bet          int               `json:"bet"`
+	Result       game.Result       `json:"result"`
+	ResultReason game.ResultReason `json:"result_reason,omitempty"`
+	Payout       int               `json:"payout"`
+}
+
+// GameV2 は v2 のゲームの表現です。
+// v1 の player_hand と switch の代わりに、プレイヤーの手を常に hands の配列で持ちます
+// （クラシックは 1 つ、ブラックジャック・スイッチは 2 つ）。Bet・Payout は全ての手の合計です。
+type GameV2 struct {
+	ID            string            `json:"id,omitempty"` // サーバーが保持するゲームの ID（テーブルやトーナメントのゲームは空）
+	Hands         []HandV2          `json:"hands"`
+	ActiveHand    int               `json:"active_hand"` // 行動中の手の添字
+	DealerHand    game.Hand         `json:"dealer_hand"`
+	State         game.GameState    `json:"state"`
+	Result        game.Result       `json:"result"`
+	ResultReason  game.ResultReason `json:"result_reason,omitempty"`
+	ResultMessage string            `json:"result_message"`
+	Bet           int               `json:"bet"`
+	Payout        int               `json:"payout"`
+	// SideBets は配られた直後に精算済みのサイドベット（本体の Bet・Payout には含まない）
+	SideBets       []game.SideBetResult `json:"side_bets,omitempty"`
+	Variant        game.Variant         `json:"variant,omitempty"`
+	Switched       bool                 `json:"switched,omitempty"` // スイッチで2枚目のカードを入れ替えたか
+	AllowedActions []game.Action        `json:"allowed_actions"`
+}
+
+// NewGameV2 は game.Game を v2 の表現に変換します。
+func NewGameV2(g game.Game) GameV2 {
+	v := GameV2{
+		ID:             g.ID,
+		DealerHand:     g.DealerHand,
+		State:          g.State,
+		Result:         g.Result,
+		ResultReason:   g.ResultReason,
+		ResultMessage:  g.ResultMessage,
+		Bet:            g.Bet,
+		Payout:         g.Payout,
+		SideBets:       g.SideBets,
+		Variant:        g.Variant,
+		AllowedActions: g.AllowedActions,
+	}
+	if v.AllowedActions == nil {
+		v.AllowedActions = []game.Action{}
+	}
+	if sw := g.Switch; sw != nil {
+		v.ActiveHand = sw.Active
+		v.Switched = sw.Switched
+		for i, h := range sw.Hands {
+			// 行動中の手は PlayerHand が最新
+			if i == sw.Active && g.State == game.PlayerTurn {
+				h = g.PlayerHand
+			}
+			v.Hands = append(v.Hands, HandV2{
+				Cards: h.Cards, Score: h.Score, Bet: sw.HandBet,
+				Result: sw.Results[i], ResultReason: sw.Reasons[i], Payout: sw.Payouts[i],
+			})
+		}
+		return v
+	}
+	v.Hands = []HandV2{{
+		Cards: g.PlayerHand.Cards, Score: g.PlayerHand.Score, Bet: g.Bet,
+		Result: g.Result, ResultReason: g.ResultReason, Payout: g.Payout,
+	}}
+	return v
+}
+
+// Game は v2 の表現を game.Game に戻します。手の数がバリエーションに合わなければエラーを返します。
+func (v GameV2) Game() (game.Game, error) {
+	g := game.Game{
+		ID:             v.ID,
+		DealerHand:     v.DealerHand,
+		State:          v.State,
+		Result:         v.Result,
+		ResultReason:   v.ResultReason,
+		ResultMessage:  v.ResultMessage,
+		Bet:            v.Bet,
+		Payout:         v.Payout,
+		SideBets:       v.SideBets,
+		Variant:        v.Variant,
+		AllowedActions: v.AllowedActions,
+	}
+	hand := func(h HandV2) game.Hand {
+		return game.Hand{Cards: h.Cards, Score: h.Score}
+	}
+
+	if v.Variant != game.VariantSwitch {
+		if len(v.Hands) != 1 {
+			return game.Game{}, fmt.Errorf("%w: game must have exactly 1 hand", services.ErrInvalidGameState)
+		}
+		if v.ActiveHand != 0 {
+			return game.Game{}, fmt.Errorf("%w: active_hand is out of range", services.ErrInvalidGameState)
+		}
+		g.PlayerHand = hand(v.Hands[0])
+		return g, nil
+	}
+
+	if len(v.Hands) != 2 {
+		return game.Game{}, fmt.Errorf("%w: switch game must have two hands", services.ErrInvalidGameState)
+	}
+	if v.ActiveHand < 0 || v.ActiveHand > 1 {
+		return game.Game{}, fmt.Errorf("%w: active_hand is out of range", services.ErrInvalidGameState)
+	}
+	sw := &game.SwitchHands{HandBet: v.Hands[0].Bet, Active: v.ActiveHand, Switched: v.Switched}
+	for i, h := range v.Hands {
+		sw.Hands[i] = hand(h)
+		sw.Results[i], sw.Reasons[i], sw.Payouts[i] = h.Result, h.ResultReason, h.Payout
+	}
+	g.Switch = sw
+	g.PlayerHand = hand(v.Hands[v.ActiveHand])
+	return g, nil
+}
+
+// ActionRequestV2 は v2 のヒット・スタンド・サレンダーのリクエストボディ（各フィールドは v1 の HitRequest と同じ）
+type ActionRequestV2 struct {
+	GameID    string `json:"game_id"`
+	Grade     bool   `json:"grade,omitempty"`
+	SessionID string `json:"session_id,omitempty"`
+	PlayerID  string `json:"player_id,omitempty"`
+	Locale    string `json:"locale,omitempty"`
+}
+
+// ActionResponseV2 は v2 のヒット・スタンド・サレンダーのレスポンス（GameV2 のフィールドを展開し、採点結果を付ける）
+type ActionResponseV2 struct {
+	GameV2
+	Grade *GradeResponse `json:"grade,omitempty"`
+}
+
+// SwitchRequestV2 は v2 のスイッチのリクエストボディ
+type SwitchRequestV2 struct {
+	GameID string `json:"game_id"`
+	Locale string `json:"locale,omitempty"`
+}
+
+// StrategyRequestV2 は v2 の戦略アドバイスのリクエストボディ
+type StrategyRequestV2 struct {
+	Game   GameV2          `json:"game"`
+	Config game.GameConfig `json:"config"`
+}
+
+// StrategyBatchRequestV2 は v2 の一括の戦略アドバイスのリクエストボディ
+type StrategyBatchRequestV2 struct {
+	Positions []StrategyRequestV2 `json:"positions"`
+}
+
+// TournamentPlayResponseV2 は v2 のトーナメントのハンドを進めた結果
+type TournamentPlayResponseV2 struct {
+	Game       GameV2                `json:"game"`
+	Tournament tournament.Tournament `json:"tournament"`
+}
+
+// TableSeatV2 は v2 のテーブルの席（game を v2 の表現で返す）
+type TableSeatV2 struct {
+	table.Seat
+	Game GameV2 `json:"game"`
+}
+
+// TableSnapshotV2 は v2 のテーブルのスナップショット
+type TableSnapshotV2 struct {
+	table.Snapshot
+	Seats []TableSeatV2 `json:"seats"`
+}
+
+// TableEventV2 は v2 のテーブルのイベント（精算結果の game を v2 の表現で返す）
+type TableEventV2 struct {
+	table.Event
+	Game *GameV2 `json:"game,omitempty"`
+}
+
+// TableMessageV2 は v2 の WebSocket でサーバーから送るメッセージ
+type TableMessageV2 struct {
+	TableMessage
+	Event    *TableEventV2    `json:"event,omitempty"`
+	Snapshot *TableSnapshotV2 `json:"snapshot,omitempty"`
+}
+
+func newTableSnapshotV2(s table.Snapshot) TableSnapshotV2 {
+	v := TableSnapshotV2{Snapshot: s, Seats: make([]TableSeatV2, len(s.Seats))}
+	for i, seat := range s.Seats {
+		v.Seats[i] = TableSeatV2{Seat: seat, Game: NewGameV2(seat.Game)}
+	}
+	return v
+}
+
+func newTableEventV2(e table.Event) TableEventV2 {
+	v := TableEventV2{Event: e}
+	if e.Game != nil {
+		g := NewGameV2(*e.Game)
+		v.Game = &g
+	}
+	return v
+}
+
+// newTableMessageV2 は v1 のメッセージを v2 の表現に変換します。
+func newTableMessageV2(m TableMessage) TableMessageV2 {
+	v := TableMessageV2{TableMessage: m}
+	if m.Event != nil {
+		e := newTableEventV2(*m.Event)
+		v.Event = &e
+	}
+	if m.Snapshot != nil {
+		s := newTableSnapshotV2(*m.Snapshot)
+		v.Snapshot = &s
+	}
+	return v
+}
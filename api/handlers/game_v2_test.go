@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"blackjack/api/game"
+	"blackjack/api/services"
+)
+
+func v2Hand(ranks ...game.Rank) game.Hand {
+	cards := make([]game.Card, len(ranks))
+	for i, r := range ranks {
+		cards[i] = game.Card{Suit: game.Spade, Rank: r}
+	}
+	return game.Hand{Cards: cards, Score: game.CalculateScore(cards)}
+}
+
+func TestGameV2_Classic(t *testing.T) {
+	g := game.Game{
+		PlayerHand:     v2Hand("10", "9"),
+		DealerHand:     v2Hand("7", "10"),
+		State:          game.Finished,
+		Result:         game.PlayerWin,
+		ResultReason:   game.ReasonPlayerWin,
+		Bet:            100,
+		Payout:         200,
+		AllowedActions: []game.Action{},
+	}
+	v := NewGameV2(g)
+	want := []HandV2{{Cards: g.PlayerHand.Cards, Score: 19, Bet: 100, Result: game.PlayerWin, ResultReason: game.ReasonPlayerWin, Payout: 200}}
+	if !reflect.DeepEqual(v.Hands, want) || v.ActiveHand != 0 {
+		t.Fatalf("hands = %+v active = %d, want %+v", v.Hands, v.ActiveHand, want)
+	}
+	back, err := v.Game()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(back, g) {
+		t.Errorf("round trip = %+v, want %+v", back, g)
+	}
+}
+
+func TestGameV2_Switch(t *testing.T) {
+	// 1つ目の手でヒットした直後（Switch.Hands[0] はまだ古く、PlayerHand が最新）
+	g := game.Game{
+		PlayerHand: v2Hand("10", "2", "5"),
+		DealerHand: v2Hand("6"),
+		State:      game.PlayerTurn,
+		Result:     game.Pending,
+		Bet:        200,
+		Variant:    game.VariantSwitch,
+		Switch: &game.SwitchHands{
+			Hands:    [2]game.Hand{v2Hand("10", "2"), v2Hand("9", "K")},
+			HandBet:  100,
+			Switched: true,
+			Results:  [2]game.Result{game.Pending, game.Pending},
+		},
+		AllowedActions: []game.Action{game.ActionHit, game.ActionStand},
+	}
+	v := NewGameV2(g)
+	if len(v.Hands) != 2 || v.Hands[0].Score != 17 || v.Hands[1].Score != 19 || !v.Switched {
+		t.Fatalf("hands = %+v switched = %v", v.Hands, v.Switched)
+	}
+	if v.Hands[0].Bet != 100 || v.Bet != 200 {
+		t.Errorf("bets = %d/%d, want 100 per hand and 200 in total", v.Hands[0].Bet, v.Bet)
+	}
+
+	back, err := v.Game()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(back.PlayerHand, g.PlayerHand) || !reflect.DeepEqual(back.Switch.Hands[0], g.PlayerHand) {
+		t.Errorf("active hand = %+v / %+v, want %+v", back.PlayerHand, back.Switch.Hands[0], g.PlayerHand)
+	}
+	if back.Switch.HandBet != 100 || back.Switch.Active != 0 || !back.Switch.Switched {
+		t.Errorf("switch = %+v", back.Switch)
+	}
+}
+
+func TestGameV2_RejectsWrongHandCount(t *testing.T) {
+	tests := []struct {
+		name string
+		v    GameV2
+	}{
+		{"classic without hands", GameV2{}},
+		{"classic with two hands", GameV2{Hands: make([]HandV2, 2)}},
+		{"classic active hand out of range", GameV2{Hands: make([]HandV2, 1), ActiveHand: 1}},
+		{"switch with one hand", GameV2{Variant: game.VariantSwitch, Hands: make([]HandV2, 1)}},
+		{"switch active hand out of range", GameV2{Variant: game.VariantSwitch, Hands: make([]HandV2, 2), ActiveHand: 2}},
+	}
+	for _, tc := range tests {
+		if _, err := tc.v.Game(); !errors.Is(err, services.ErrInvalidGameState) {
+			t.Errorf("%s: err = %v, want ErrInvalidGameState", tc.name, err)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"blackjack/api/game"
+	"blackjack/api/services"
+	"blackjack/api/strategy"
+
+	"github.com/gorilla/mux"
+)
+
+// ActionResponse はヒット・スタンド・サレンダーのレスポンス
+// Game のフィールドはそのまま展開され、採点を要求した場合のみ grade が付きます。
+type ActionResponse struct {
+	game.Game
+	Grade *GradeResponse `json:"grade,omitempty"`
+}
+
+// GradeResponse は行動の採点結果（期待値は金額ベース）
+type GradeResponse struct {
+	Action        strategy.Action         `json:"action"`
+	OptimalAction strategy.Action         `json:"optimal_action"`
+	ChosenEV      float64                 `json:"chosen_ev"`
+	OptimalEV     float64                 `json:"optimal_ev"`
+	EVLoss        float64                 `json:"ev_loss"`
+	Session       *SessionMistakeResponse `json:"session,omitempty"`
+}
+
+// SessionMistakeResponse はセッションごとのミスのコストの集計
+type SessionMistakeResponse struct {
+	SessionID   string  `json:"session_id"`
+	Decisions   int     `json:"decisions"`
+	Mistakes    int     `json:"mistakes"`
+	MistakeCost float64 `json:"mistake_cost"`
+}
+
+func toSessionMistakeResponse(s services.SessionMistakeSummary) SessionMistakeResponse {
+	return SessionMistakeResponse{
+		SessionID:   s.SessionID,
+		Decisions:   s.Decisions,
+		Mistakes:    s.Mistakes,
+		MistakeCost: s.MistakeCost,
+	}
+}
+
+// gradeAction は行動前のゲーム状態を採点します。grader が nil または採点不要なら nil を返します。
+// 採点に失敗してもゲームを進めずに済むよう、行動を適用する前に呼びます。
+// とれない行動はセッションに記録せず、行動したときと同じエラーを返します。
+func gradeAction(grader services.DecisionGrader, enabled bool, sessionID string, before game.Game, config *game.GameConfig, action strategy.Action) (*GradeResponse, error) {
+	if grader == nil || !enabled {
+		return nil, nil
+	}
+	if err := services.CheckAction(before, config, game.Action(action)); err != nil {
+		return nil, err
+	}
+	grade, err := grader.Grade(sessionID, before, config, action)
+	if err != nil {
+		return nil, err
+	}
+	resp := &GradeResponse{
+		Action:        grade.Action,
+		OptimalAction: grade.OptimalAction,
+		ChosenEV:      grade.ChosenEV,
+		OptimalEV:     grade.OptimalEV,
+		EVLoss:        grade.EVLoss,
+	}
+	if sessionID != "" {
+		session := toSessionMistakeResponse(grade.Session)
+		resp.Session = &session
+	}
+	return resp, nil
+}
+
+// SessionMistakesHandler はセッションのミスのコストの集計を返すハンドラ
+func SessionMistakesHandler(grader services.DecisionGrader) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		sessionID := mux.Vars(r)["id"]
+		if sessionID == "" {
+			writeError(w, r, invalidRequest("session id is required"))
+			return
+		}
+
+		json.NewEncoder(w).Encode(toSessionMistakeResponse(grader.SessionSummary(sessionID)))
+	}
+}
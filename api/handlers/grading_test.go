@@ -0,0 +1,194 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"blackjack/api/game"
+	"blackjack/api/services"
+	"blackjack/api/strategy"
+
+	"github.com/gorilla/mux"
+)
+
+// mockGrader は固定の EV ロスで採点し、呼び出しを記録するモック
+type mockGrader struct {
+	calls     int
+	gotAction strategy.Action
+	gotGame   game.Game
+	err       error
+}
+
+func (m *mockGrader) Grade(sessionID string, g game.Game, config *game.GameConfig, action strategy.Action) (services.DecisionGrade, error) {
+	m.calls++
+	m.gotAction = action
+	m.gotGame = g
+	if m.err != nil {
+		return services.DecisionGrade{}, m.err
+	}
+	return services.DecisionGrade{
+		Action:        action,
+		OptimalAction: strategy.ActionStand,
+		ChosenEV:      40,
+		OptimalEV:     60,
+		EVLoss:        20,
+		Session:       services.SessionMistakeSummary{SessionID: sessionID, Decisions: 1, Mistakes: 1, MistakeCost: 20},
+	}, nil
+}
+
+func (m *mockGrader) SessionSummary(sessionID string) services.SessionMistakeSummary {
+	return services.SessionMistakeSummary{SessionID: sessionID, Decisions: 3, Mistakes: 1, MistakeCost: 12.5}
+}
+
+func TestHitHandler_GradesMoveWhenRequested(t *testing.T) {
+	playerCards := []game.Card{{Suit: game.Spade, Rank: "10"}, {Suit: game.Heart, Rank: "8"}}
+	dealerCards := []game.Card{{Suit: game.Club, Rank: "9"}}
+	g := game.Game{
+		PlayerHand: game.Hand{Cards: playerCards, Score: game.CalculateScore(playerCards)},
+		DealerHand: game.Hand{Cards: dealerCards, Score: game.CalculateScore(dealerCards)},
+		Bet:        100,
+		State:      game.PlayerTurn,
+		Result:     game.Pending,
+	}
+
+	grader := &mockGrader{}
+	handler := HitHandler(mockHitService{}, grader)
+
+	body, _ := json.Marshal(HitRequest{Game: g, Config: game.GameConfig{DealerStandThreshold: 17}, Grade: true, SessionID: "s1"})
+	req := httptest.NewRequest(http.MethodPost, "/api/game/hit", bytes.NewReader(body))
+	rr := httptest.NewRecorder()
+
+	handler.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, rr.Code)
+	}
+	if grader.calls != 1 || grader.gotAction != strategy.ActionHit {
+		t.Fatalf("expected one hit grading, got calls=%d action=%s", grader.calls, grader.gotAction)
+	}
+	// 採点は行動前の状態に対して行われる
+	if grader.gotGame.State != game.PlayerTurn {
+		t.Fatalf("expected grading on pre-action state, got %s", grader.gotGame.State)
+	}
+
+	var resp ActionResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if resp.Result != game.DealerWin {
+		t.Fatalf("expected game fields in response, got result %s", resp.Result)
+	}
+	if resp.Grade == nil || resp.Grade.EVLoss != 20 || resp.Grade.Session == nil || resp.Grade.Session.MistakeCost != 20 {
+		t.Fatalf("unexpected grade: %+v", resp.Grade)
+	}
+}
+
+// countingHitService はヒットの呼び出し回数を数えるモック
+type countingHitService struct {
+	calls *int
+}
+
+func (m countingHitService) Hit(g *game.Game, config *game.GameConfig) error {
+	*m.calls++
+	return nil
+}
+
+func TestHitHandler_DoesNotApplyActionWhenGradingFails(t *testing.T) {
+	playerCards := []game.Card{{Suit: game.Spade, Rank: "10"}, {Suit: game.Heart, Rank: "6"}}
+	dealerCards := []game.Card{{Suit: game.Club, Rank: "9"}}
+	g := game.Game{
+		PlayerHand: game.Hand{Cards: playerCards, Score: game.CalculateScore(playerCards)},
+		DealerHand: game.Hand{Cards: dealerCards, Score: game.CalculateScore(dealerCards)},
+		Bet:        100,
+		State:      game.PlayerTurn,
+		Result:     game.Pending,
+	}
+
+	var hits int
+	grader := &mockGrader{err: services.ErrInvalidConfig}
+	handler := HitHandler(countingHitService{calls: &hits}, grader)
+
+	body, _ := json.Marshal(HitRequest{Game: g, Config: game.GameConfig{DealerStandThreshold: 17}, Grade: true})
+	req := httptest.NewRequest(http.MethodPost, "/api/game/hit", bytes.NewReader(body))
+	rr := httptest.NewRecorder()
+
+	handler.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("expected status %d, got %d", http.StatusUnprocessableEntity, rr.Code)
+	}
+	// 採点に失敗したらカードを配らない
+	if hits != 0 {
+		t.Fatalf("expected no hit after grading failed, got %d", hits)
+	}
+}
+
+func TestHitHandler_DoesNotGradeActionThatIsNotAllowed(t *testing.T) {
+	grader := &mockGrader{}
+	handler := HitHandler(mockHitService{}, grader)
+
+	finished := game.Game{
+		PlayerHand: game.Hand{Cards: []game.Card{{Suit: game.Spade, Rank: "10"}, {Suit: game.Heart, Rank: "8"}}, Score: 18},
+		DealerHand: game.Hand{Cards: []game.Card{{Suit: game.Club, Rank: "9"}}, Score: 9},
+		Bet:        100,
+		State:      game.Finished,
+		Result:     game.PlayerWin,
+	}
+	body, _ := json.Marshal(HitRequest{Game: finished, Config: game.GameConfig{DealerStandThreshold: 17}, Grade: true, SessionID: "s1"})
+	req := httptest.NewRequest(http.MethodPost, "/api/game/hit", bytes.NewReader(body))
+	rr := httptest.NewRecorder()
+
+	handler.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusConflict {
+		t.Fatalf("expected status %d, got %d", http.StatusConflict, rr.Code)
+	}
+	if grader.calls != 0 {
+		t.Fatalf("expected no grading for a finished game, got %d calls", grader.calls)
+	}
+}
+
+func TestStandHandler_DoesNotGradeByDefault(t *testing.T) {
+	grader := &mockGrader{}
+	handler := StandHandler(mockStandService{}, grader)
+
+	body, _ := json.Marshal(StandRequest{Game: game.Game{Bet: 100}, Config: game.GameConfig{DealerStandThreshold: 17}})
+	req := httptest.NewRequest(http.MethodPost, "/api/game/stand", bytes.NewReader(body))
+	rr := httptest.NewRecorder()
+
+	handler.ServeHTTP(rr, req)
+
+	if grader.calls != 0 {
+		t.Fatalf("expected no grading, got %d calls", grader.calls)
+	}
+	var resp map[string]any
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if _, ok := resp["grade"]; ok {
+		t.Fatalf("expected no grade field, got %v", resp["grade"])
+	}
+}
+
+func TestSessionMistakesHandler(t *testing.T) {
+	router := mux.NewRouter()
+	router.HandleFunc("/api/grading/sessions/{id}", SessionMistakesHandler(&mockGrader{}))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/grading/sessions/abc", nil)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, rr.Code)
+	}
+	var resp SessionMistakeResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if resp.SessionID != "abc" || resp.Decisions != 3 || resp.MistakeCost != 12.5 {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+}
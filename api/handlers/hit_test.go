@@ -38,7 +38,7 @@ func TestHitHandler_ReturnsUpdatedGameJSON(t *testing.T) {
 
 	svc := mockHitService{}
 
-	handler := HitHandler(svc)
+	handler := HitHandler(svc, nil)
 
 	req_body := HitRequest{
 		Game:   g,
@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"blackjack/api/services"
+)
+
+// 既定のページサイズ
+const defaultLeaderboardLimit = 20
+
+// LeaderboardEntryResponse はランキングの1行
+type LeaderboardEntryResponse struct {
+	Rank     int     `json:"rank"`
+	PlayerID string  `json:"player_id"`
+	Value    float64 `json:"value"`
+}
+
+// LeaderboardResponse はランキングの1ページ
+type LeaderboardResponse struct {
+	Metric  services.LeaderboardMetric `json:"metric"`
+	Window  services.LeaderboardWindow `json:"window"`
+	Total   int                        `json:"total"`
+	Offset  int                        `json:"offset"`
+	Limit   int                        `json:"limit"`
+	Entries []LeaderboardEntryResponse `json:"entries"`
+}
+
+// LeaderboardHandler はランキングを返すハンドラ（集計するゲームは main で services.HouseRulesOnly により絞り込む）
+// クエリ: metric（既定 net_winnings）, window（既定 all_time）, offset（既定 0）, limit（既定 20）
+func LeaderboardHandler(leaderboard services.LeaderboardService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		q := r.URL.Query()
+		metric := services.LeaderboardMetric(q.Get("metric"))
+		if metric == "" {
+			metric = services.MetricNetWinnings
+		}
+		window := services.LeaderboardWindow(q.Get("window"))
+		if window == "" {
+			window = services.WindowAllTime
+		}
+		offset, err := intQuery(q.Get("offset"), 0)
+		if err != nil {
+			writeError(w, r, invalidRequest("offset must be an integer"))
+			return
+		}
+		limit, err := intQuery(q.Get("limit"), defaultLeaderboardLimit)
+		if err != nil {
+			writeError(w, r, invalidRequest("limit must be an integer"))
+			return
+		}
+
+		page, err := leaderboard.Leaderboard(metric, window, offset, limit)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		resp := LeaderboardResponse{
+			Metric:  page.Metric,
+			Window:  page.Window,
+			Total:   page.Total,
+			Offset:  page.Offset,
+			Limit:   limit,
+			Entries: make([]LeaderboardEntryResponse, 0, len(page.Entries)),
+		}
+		for _, e := range page.Entries {
+			resp.Entries = append(resp.Entries, LeaderboardEntryResponse{Rank: e.Rank, PlayerID: e.PlayerID, Value: e.Value})
+		}
+		json.NewEncoder(w).Encode(resp)
+	}
+}
+
+// intQuery はクエリ文字列を整数に変換します。空なら def を返します。
+func intQuery(v string, def int) (int, error) {
+	if v == "" {
+		return def, nil
+	}
+	return strconv.Atoi(v)
+}
@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"blackjack/api/game"
+	"blackjack/api/services"
+)
+
+// mockLeaderboard は受け取ったクエリを記録し、固定のページを返すモック
+type mockLeaderboard struct {
+	metric        services.LeaderboardMetric
+	window        services.LeaderboardWindow
+	offset, limit int
+}
+
+func (m *mockLeaderboard) RecordGame(playerID, sessionID string, g game.Game, config *game.GameConfig) error {
+	return nil
+}
+
+func (m *mockLeaderboard) RecordTrainerAnswer(playerID string, correct bool) error {
+	return nil
+}
+
+func (m *mockLeaderboard) Leaderboard(metric services.LeaderboardMetric, window services.LeaderboardWindow, offset, limit int) (services.LeaderboardPage, error) {
+	m.metric, m.window, m.offset, m.limit = metric, window, offset, limit
+	return services.LeaderboardPage{
+		Metric:  metric,
+		Window:  window,
+		Total:   42,
+		Offset:  offset,
+		Entries: []services.LeaderboardEntry{{Rank: 11, PlayerID: "alice", Value: 0.8}},
+	}, nil
+}
+
+func TestLeaderboardHandler(t *testing.T) {
+	lb := &mockLeaderboard{}
+	handler := LeaderboardHandler(lb)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/leaderboard?metric=roi&window=weekly&offset=10&limit=5", nil)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, rr.Code)
+	}
+	if lb.metric != services.MetricROI || lb.window != services.WindowWeekly || lb.offset != 10 || lb.limit != 5 {
+		t.Fatalf("unexpected query passed to service: %+v", lb)
+	}
+	var resp LeaderboardResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if resp.Total != 42 || len(resp.Entries) != 1 || resp.Entries[0].Rank != 11 {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+}
+
+func TestLeaderboardHandler_Defaults(t *testing.T) {
+	lb := &mockLeaderboard{}
+	handler := LeaderboardHandler(lb)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/leaderboard", nil)
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	if lb.metric != services.MetricNetWinnings || lb.window != services.WindowAllTime || lb.offset != 0 || lb.limit != defaultLeaderboardLimit {
+		t.Fatalf("unexpected defaults: %+v", lb)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/leaderboard?limit=abc", nil)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("expected status %d, got %d", http.StatusBadRequest, rr.Code)
+	}
+}
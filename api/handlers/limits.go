@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"blackjack/api/auth"
+	"blackjack/api/ratelimit"
+
+	"github.com/gorilla/mux"
+)
+
+// MaxBodyBytes は JSON のリクエストボディの最大のバイト数
+// 最も大きいのは戦略の一括評価（局面 200 件）で、それでも 256 KiB に十分収まります。
+const MaxBodyBytes = 256 << 10
+
+// リクエストの大きさと頻度の制限で返すエラー
+var (
+	errRequestTooLarge = errors.New("request body is too large")
+	errRateLimited     = errors.New("rate limit exceeded")
+)
+
+// decodeJSON はリクエストボディの JSON を v に読み込みます。
+// MaxBodyBytes を超えるボディは errRequestTooLarge、JSON の誤りは invalidRequest のエラーを返します。
+func decodeJSON(w http.ResponseWriter, r *http.Request, v interface{}) error {
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, MaxBodyBytes)).Decode(v); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			return errRequestTooLarge
+		}
+		return invalidRequest("invalid request body")
+	}
+	return nil
+}
+
+// chargeKey は RateLimit が追加のトークンを使う関数を記録するコンテキストのキー
+type chargeKey struct{}
+
+// RateLimit は limiter でリクエストの頻度を制限するミドルウェアです。
+// どのリクエストも送信元の IP アドレスのバケットを使い、認証したプレイヤーはさらにプレイヤーのバケットも使います
+// （トークンを作り直しても IP アドレスの制限は逃れられない）。超えたリクエストは Retry-After ヘッダ（秒）を付けた 429 にします。
+// Authenticate より後に適用してください。
+// trustProxy が true なら、前段のプロキシが付けた X-Forwarded-For の最後のアドレスを IP アドレスとして使います。
+func RateLimit(limiter ratelimit.Limiter, trustProxy bool) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			keys := []string{"ip:" + clientIP(r, trustProxy)}
+			if id, ok := auth.FromContext(r.Context()); ok {
+				keys = append(keys, "player:"+id.PlayerID)
+			}
+			// どれかのバケットで断ったリクエストは、ほかのバケットのトークンも使わない
+			charge := func(n int) error {
+				if ok, wait := limiter.AllowAll(keys, n); !ok {
+					w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+					return errRateLimited
+				}
+				return nil
+			}
+			if err := charge(1); err != nil {
+				writeError(w, r, err)
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), chargeKey{}, charge)))
+		})
+	}
+}
+
+// chargeRateLimit は RateLimit を適用したリクエストで、受け付けたときの 1 トークンに加えて n トークンを使います。
+// 戦略の一括評価のように、ボディを読むまで重さのわからないリクエストに使います。
+// 足りなければ Retry-After ヘッダを付けて errRateLimited を返します。RateLimit を適用していなければ何もしません。
+func chargeRateLimit(r *http.Request, n int) error {
+	charge, ok := r.Context().Value(chargeKey{}).(func(int) error)
+	if !ok || n <= 0 {
+		return nil
+	}
+	return charge(n)
+}
+
+// clientIP はリクエストの送信元の IP アドレスを返します。
+func clientIP(r *http.Request, trustProxy bool) string {
+	if trustProxy {
+		if fwd := r.Header.Get("X-Forwarded-For"); fwd != "" {
+			parts := strings.Split(fwd, ",")
+			return strings.TrimSpace(parts[len(parts)-1])
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
@@ -0,0 +1,171 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"blackjack/api/ratelimit"
+)
+
+// countingLimiter はキーごとに allowed 回まで許可する Limiter
+type countingLimiter struct {
+	allowed int
+	counts  map[string]int
+}
+
+func (l *countingLimiter) Allow(key string) (bool, time.Duration) {
+	return l.AllowN(key, 1)
+}
+
+func (l *countingLimiter) AllowN(key string, n int) (bool, time.Duration) {
+	return l.AllowAll([]string{key}, n)
+}
+
+func (l *countingLimiter) AllowAll(keys []string, n int) (bool, time.Duration) {
+	for _, key := range keys {
+		if l.counts[key] >= l.allowed {
+			return false, 1500 * time.Millisecond
+		}
+	}
+	for _, key := range keys {
+		l.counts[key] += n
+	}
+	return true, 0
+}
+
+func TestRateLimit_RejectsWithRetryAfter(t *testing.T) {
+	limiter := &countingLimiter{allowed: 1, counts: make(map[string]int)}
+	handler := RateLimit(limiter, false)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	send := func(r *http.Request) *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, r)
+		return rr
+	}
+	anonymous := func(addr string) *http.Request {
+		r := httptest.NewRequest(http.MethodPost, "/api/strategy/advise", nil)
+		r.RemoteAddr = addr
+		return r
+	}
+
+	if rr := send(anonymous("192.0.2.1:1234")); rr.Code != http.StatusNoContent {
+		t.Fatalf("first request: got %d", rr.Code)
+	}
+	rr := send(anonymous("192.0.2.1:5678"))
+	var resp ErrorResponse
+	json.Unmarshal(rr.Body.Bytes(), &resp)
+	if rr.Code != http.StatusTooManyRequests || resp.Code != "rate_limited" || rr.Header().Get("Retry-After") != "2" {
+		t.Fatalf("second request from the same IP: got %d %+v, Retry-After %q", rr.Code, resp, rr.Header().Get("Retry-After"))
+	}
+
+	// 別の IP アドレスは別のバケット
+	if rr := send(anonymous("192.0.2.2:1234")); rr.Code != http.StatusNoContent {
+		t.Errorf("another IP: got %d", rr.Code)
+	}
+	// 認証したプレイヤーも IP アドレスのバケットを使う（トークンを作り直しても逃れられない）
+	if rr := send(asPlayer(anonymous("192.0.2.1:1234"), "alice")); rr.Code != http.StatusTooManyRequests {
+		t.Errorf("authenticated player from a limited IP: got %d, want 429", rr.Code)
+	}
+	// さらにプレイヤーのバケットも使う
+	if rr := send(asPlayer(anonymous("192.0.2.3:1234"), "alice")); rr.Code != http.StatusNoContent {
+		t.Errorf("authenticated player: got %d", rr.Code)
+	}
+	if rr := send(asPlayer(anonymous("192.0.2.4:1234"), "alice")); rr.Code != http.StatusTooManyRequests {
+		t.Errorf("same player from another IP: got %d, want 429", rr.Code)
+	}
+}
+
+func TestRateLimit_RefusedPlayerKeepsIPToken(t *testing.T) {
+	limiter := ratelimit.New(ratelimit.Limit{Rate: 0.001, Burst: 1})
+	handler := RateLimit(limiter, false)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	request := func() *http.Request {
+		r := httptest.NewRequest(http.MethodPost, "/api/game/new", nil)
+		r.RemoteAddr = "192.0.2.1:1234"
+		return r
+	}
+
+	// プレイヤーのバケットが空なら断り、IP アドレスのトークンも使わない
+	limiter.Allow("player:alice")
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, asPlayer(request(), "alice"))
+	if rr.Code != http.StatusTooManyRequests {
+		t.Fatalf("player with an empty bucket: got %d, want 429", rr.Code)
+	}
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, request())
+	if rr.Code != http.StatusNoContent {
+		t.Errorf("anonymous request from the same IP: got %d, want 204", rr.Code)
+	}
+}
+
+func TestRateLimit_ChargesBatchPerPosition(t *testing.T) {
+	limit := RateLimit(ratelimit.New(ratelimit.Limit{Rate: 0.001, Burst: 3}), false)
+	batch := limit(StrategyBatchHandler(mockStrategyService{}))
+	single := limit(StrategyHandler(mockStrategyService{}))
+	position := `{"game": {}, "config": {"dealer_stand_threshold": 17}}`
+
+	rr := httptest.NewRecorder()
+	body := `{"positions": [` + strings.Join([]string{position, position, position}, ",") + `]}`
+	batch.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/api/strategy/advise/batch", strings.NewReader(body)))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("batch: got %d: %s", rr.Code, rr.Body.String())
+	}
+	// 3 局面で 3 トークン使い切っている
+	rr = httptest.NewRecorder()
+	single.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/api/strategy/advise", strings.NewReader(position)))
+	if rr.Code != http.StatusTooManyRequests {
+		t.Errorf("request after the batch: got %d, want 429", rr.Code)
+	}
+}
+
+func TestClientIP_TrustProxy(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/api/health", nil)
+	r.RemoteAddr = "10.0.0.1:443"
+	r.Header.Set("X-Forwarded-For", "203.0.113.9, 198.51.100.7")
+	if got := clientIP(r, false); got != "10.0.0.1" {
+		t.Errorf("without trusted proxy: got %q", got)
+	}
+	if got := clientIP(r, true); got != "198.51.100.7" {
+		t.Errorf("with trusted proxy: got %q", got)
+	}
+}
+
+func TestRateLimit_WithTokenBucket(t *testing.T) {
+	handler := RateLimit(ratelimit.New(ratelimit.Limit{Rate: 1, Burst: 2}), false)(StrategyHandler(mockStrategyService{}))
+	body := `{"game": {}, "config": {"dealer_stand_threshold": 17}}`
+	var codes []int
+	for i := 0; i < 3; i++ {
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/api/strategy/advise", strings.NewReader(body)))
+		codes = append(codes, rr.Code)
+	}
+	if codes[0] == http.StatusTooManyRequests || codes[1] == http.StatusTooManyRequests || codes[2] != http.StatusTooManyRequests {
+		t.Errorf("status codes = %v, want the third to be 429", codes)
+	}
+}
+
+func TestDecodeJSON_RejectsLargeBody(t *testing.T) {
+	handler := StandHandler(mockStandService{}, nil)
+	large := `{"game": {"bet": 10, "player_hand": {"cards": [` + strings.Repeat(`{"suit": "♠", "rank": "A"},`, MaxBodyBytes/20) + `]}}}`
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/api/game/stand", strings.NewReader(large)))
+	var resp ErrorResponse
+	json.Unmarshal(rr.Body.Bytes(), &resp)
+	if rr.Code != http.StatusRequestEntityTooLarge || resp.Code != "request_too_large" {
+		t.Errorf("large body: got %d %+v", rr.Code, resp)
+	}
+
+	body := `{"game": {"bet": 10}, "config": {"dealer_stand_threshold": 17}`
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/api/game/stand", strings.NewReader(body)))
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("truncated body: got %d, want 400", rr.Code)
+	}
+}
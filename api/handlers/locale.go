@@ -0,0 +1,28 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"blackjack/api/i18n"
+)
+
+type localeKey struct{}
+
+// withLocale はリクエストボディの locale を、以降のレスポンス（エラーを含む）の言語として r に記録します。
+// 対応していない言語や空文字の場合は r をそのまま返します。
+func withLocale(r *http.Request, locale string) *http.Request {
+	lang, ok := i18n.Parse(locale)
+	if !ok {
+		return r
+	}
+	return r.WithContext(context.WithValue(r.Context(), localeKey{}, lang))
+}
+
+// requestLang はレスポンスの言語を、リクエストボディの locale、Accept-Language、既定の言語の順に決めます。
+func requestLang(r *http.Request) i18n.Lang {
+	if lang, ok := r.Context().Value(localeKey{}).(i18n.Lang); ok {
+		return lang
+	}
+	return i18n.FromAcceptLanguage(r.Header.Get("Accept-Language"))
+}
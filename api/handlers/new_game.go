@@ -4,34 +4,50 @@ import (
 	"encoding/json"
 	"net/http"
 
+	"blackjack/api/game"
+	"blackjack/api/i18n"
 	"blackjack/api/services"
 )
 
 // NewGameRequest は新規ゲーム開始時に受け取るリクエストボディ
-// 例: {"bet": 100}
-// Bet は必須で 1 以上の整数であることを想定します。
+// 例: {"bet": 100, "side_bets": {"perfect_pairs": 10, "twenty_one_plus_three": 10}}
+// Bet は必須で 1 以上の整数であることを想定します。サイドベットは任意で、配られた直後に精算されます。
 type NewGameRequest struct {
-	Bet int `json:"bet"`
+	Bet       int            `json:"bet"`
+	SessionID string         `json:"session_id,omitempty"` // 成績を集計するセッション
+	PlayerID  string         `json:"player_id,omitempty"`  // 認証したプレイヤーの ID（省略可）。v2 で初手ブラックジャックで決着したゲームは認証したプレイヤーの成績に記録します
+	SideBets  *game.SideBets `json:"side_bets,omitempty"`
+	// Config はルールのバリエーションなどの設定。省略時はサーバーの既定のルール（既定ではクラシック）。v2 では以後の行動もこのルールで進めます
+	Config *game.GameConfig `json:"config,omitempty"`
+	// 結果の文言とエラーメッセージの言語（"ja", "en"）。省略時は Accept-Language に従います
+	Locale string `json:"locale,omitempty"`
 }
 
-// NewGameHandler は GameStarter を用いて新規ゲームを開始するハンドラを生成します。
-func NewGameHandler(gameSvc services.GameStarter) http.HandlerFunc {
+// NewGameHandler は GameStarter を用いて新規ゲームを開始し、サイドベットを sideBets で精算するハンドラを生成します。
+// v1 ではゲームの状態をクライアントが持ち、以後の行動のリクエストで送り返します（成績には記録しません）。
+func NewGameHandler(gameSvc services.GameStarter, sideBets services.SideBetService) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
 
 		// リクエストパース
 		var req NewGameRequest
-		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-			http.Error(w, "invalid request body", http.StatusBadRequest)
+		if err := decodeJSON(w, r, &req); err != nil {
+			writeError(w, r, err)
+			return
+		}
+		r = withLocale(r, req.Locale)
+		if _, err := playerFor(r, req.PlayerID); err != nil {
+			writeError(w, r, err)
 			return
 		}
 
-		g, err := gameSvc.NewGame(req.Bet)
+		g, err := dealGame(gameSvc, sideBets, req, gameConfigFor(r, req.Config))
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusBadRequest)
+			writeError(w, r, err)
 			return
 		}
 
+		i18n.Localize(&g, requestLang(r))
 		json.NewEncoder(w).Encode(g)
 	}
 }
@@ -8,8 +8,12 @@ import (
 	"testing"
 
 	"blackjack/api/game"
+	"blackjack/api/services"
 )
 
+// defaultSideBets は既定の配当表でサイドベットを精算します。
+var defaultSideBets = services.NewSideBetService(game.DefaultSideBetPaytables())
+
 // mockGameService はテスト用に固定の Game を返すサービス実装です。
 type mockGameService struct {
 	expectedBet int
@@ -17,7 +21,7 @@ type mockGameService struct {
 	retErr      error
 }
 
-func (m mockGameService) NewGame(bet int) (game.Game, error) {
+func (m mockGameService) NewGame(bet int, config *game.GameConfig) (game.Game, error) {
 	if bet != m.expectedBet {
 		return game.Game{}, m.retErr
 	}
@@ -41,7 +45,7 @@ func TestNewGameHandler_ReturnsGameJSON(t *testing.T) {
 		retErr:      nil,
 	}
 
-	handler := NewGameHandler(svc)
+	handler := NewGameHandler(svc, defaultSideBets)
 
 	// リクエストボディ
 	body, _ := json.Marshal(NewGameRequest{Bet: expectedBet})
@@ -69,3 +73,53 @@ func TestNewGameHandler_ReturnsGameJSON(t *testing.T) {
 		t.Fatalf("unexpected response: %+v", resp)
 	}
 }
+
+func TestNewGameHandler_SettlesSideBets(t *testing.T) {
+	// プレイヤー 7♥ 7♥、ディーラー 7♥: パーフェクトペアと 21+3 のスーテッドトリップス
+	c := game.Card{Suit: game.Heart, Rank: "7"}
+	g := game.Game{
+		PlayerHand: game.Hand{Cards: []game.Card{c, c}, Score: 14},
+		DealerHand: game.Hand{Cards: []game.Card{c}, Score: 7},
+		Bet:        100,
+		State:      game.PlayerTurn,
+		Result:     game.Pending,
+	}
+	handler := NewGameHandler(mockGameService{expectedBet: 100, retGame: g}, defaultSideBets)
+
+	body, _ := json.Marshal(NewGameRequest{Bet: 100, SideBets: &game.SideBets{PerfectPairs: 10, TwentyOnePlusThree: 5}})
+	req := httptest.NewRequest(http.MethodPost, "/api/game/new", bytes.NewReader(body))
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+	var got game.Game
+	if err := json.Unmarshal(rr.Body.Bytes(), &got); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if len(got.SideBets) != 2 {
+		t.Fatalf("expected 2 side bets, got %+v", got.SideBets)
+	}
+	if pp := got.SideBets[0]; pp.Outcome != game.PairPerfect || pp.Payout != 260 {
+		t.Fatalf("expected perfect pair paying 25:1, got %+v", pp)
+	}
+	if tot := got.SideBets[1]; tot.Outcome != game.HandSuitedTrips || tot.Payout != 505 {
+		t.Fatalf("expected suited trips paying 100:1, got %+v", tot)
+	}
+}
+
+func TestNewGameHandler_RejectsInvalidSideBet(t *testing.T) {
+	handler := NewGameHandler(mockGameService{expectedBet: 100}, defaultSideBets)
+	// 負の掛け金と、払い戻しが int に収まらない掛け金
+	for _, bets := range []game.SideBets{{PerfectPairs: -1}, {TwentyOnePlusThree: game.MaxSideBet + 1}} {
+		body, _ := json.Marshal(NewGameRequest{Bet: 100, SideBets: &bets})
+		req := httptest.NewRequest(http.MethodPost, "/api/game/new", bytes.NewReader(body))
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+
+		if rr.Code != http.StatusUnprocessableEntity {
+			t.Fatalf("%+v: expected status %d, got %d", bets, http.StatusUnprocessableEntity, rr.Code)
+		}
+	}
+}
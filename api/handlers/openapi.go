@@ -0,0 +1,14 @@
+package handlers
+
+import (
+	"net/http"
+
+	"blackjack/api/openapi"
+)
+
+// OpenAPIHandler は API の OpenAPI 3 ドキュメントを返します。
+func OpenAPIHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(openapi.Document())
+}
@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"blackjack/api/game"
+	"blackjack/api/services"
+	"blackjack/api/strategy"
+)
+
+// dealGame はサイドベットを検証してから config のルールで新規ゲームを配り、sideBets の配当表でサイドベットを精算します（v1 と v2 で共通）。
+func dealGame(gameSvc services.GameStarter, sideBets services.SideBetService, req NewGameRequest, config *game.GameConfig) (game.Game, error) {
+	// サイドベットはカードを配る前に検証する
+	if req.SideBets != nil {
+		if err := req.SideBets.Validate(); err != nil {
+			return game.Game{}, err
+		}
+	}
+	g, err := gameSvc.NewGame(req.Bet, config)
+	if err != nil {
+		return game.Game{}, err
+	}
+	if req.SideBets != nil {
+		if err := sideBets.SettleSideBets(&g, *req.SideBets); err != nil {
+			return game.Game{}, err
+		}
+	}
+	return g, nil
+}
+
+// startGame は v2 の新規ゲームを配ってサーバーに保持し、ID の付いたゲームを返します。
+// 以後の行動は ID で指定し、このときの設定（ルール）で進めます。
+func startGame(gameSvc services.GameStarter, sideBets services.SideBetService, store services.GameStore, recorder services.GameRecorder, r *http.Request, playerID string, req NewGameRequest) (game.Game, error) {
+	config := gameConfigFor(r, req.Config)
+	if config == nil {
+		config = &game.GameConfig{DealerStandThreshold: game.DefaultDealerStandThreshold}
+	}
+	if err := validateStrategyConfig(*config); err != nil {
+		return game.Game{}, err
+	}
+	g, err := dealGame(gameSvc, sideBets, req, config)
+	if err != nil {
+		return game.Game{}, err
+	}
+
+	sg, err := store.Create(services.StoredGame{PlayerID: playerID, SessionID: req.SessionID, Game: g, Config: *config})
+	if err != nil {
+		return game.Game{}, err
+	}
+	// 初手で決着するのはブラックジャック（とディーラーの2枚が見えるルールでのディーラーのブラックジャック）のみ
+	if sg.Game.State == game.Finished {
+		sg, err = store.Update(sg.ID, func(sg *services.StoredGame) error {
+			recordIfFinished(recorder, sg)
+			return nil
+		})
+		if err != nil {
+			return game.Game{}, err
+		}
+	}
+	return sg.Game, nil
+}
+
+// playClientGame は v1 のリクエストのゲーム g（状態はクライアントが持つ）に apply で行動を適用し、進めたゲームを返します。
+// 採点（grade が true のとき）は行動前の状態に対して行い、失敗したらゲームを進めません。
+// サーバーが配ったゲームか確かめられないので、成績とランキングには記録しません。
+func playClientGame(grader services.DecisionGrader, g game.Game, config *game.GameConfig,
+	apply func(*game.Game, *game.GameConfig) error, action strategy.Action, grade bool, gradingSession string) (game.Game, *GradeResponse, error) {
+	gradeResp, err := gradeAction(grader, grade, gradingSession, g, config, action)
+	if err != nil {
+		return game.Game{}, nil, err
+	}
+	if err := apply(&g, config); err != nil {
+		return game.Game{}, nil, err
+	}
+	return g, gradeResp, nil
+}
+
+// playAction は v2 の保持しているゲーム gameID に apply で行動を適用し、進めたゲームを返します。
+// 行動できるのはゲームを始めたプレイヤー playerID（playerFor の結果。匿名で始めたゲームは匿名のリクエスト）だけで、
+// 未知のゲームは services.ErrGameNotFound、他のプレイヤーのゲームは errForbidden にします。
+// 採点（grade が true のとき）と成績の記録も同じゲームのロックの中で行うので、
+// 同じゲームに同時に行動しても、決着したゲームを記録するのは一度だけです。
+func playAction(store services.GameStore, grader services.DecisionGrader, recorder services.GameRecorder, gameID, playerID string,
+	apply func(*game.Game, *game.GameConfig) error, action strategy.Action, grade bool, gradingSession string) (game.Game, *GradeResponse, error) {
+	if gameID == "" {
+		return game.Game{}, nil, invalidRequest("game_id is required")
+	}
+	var gradeResp *GradeResponse
+	sg, err := store.Update(gameID, func(sg *services.StoredGame) error {
+		if sg.PlayerID != playerID {
+			return fmt.Errorf("%w: game %s belongs to another player", errForbidden, gameID)
+		}
+		// 採点は行動前の状態に対して行い、失敗したらゲームを進めない
+		var err error
+		gradeResp, err = gradeAction(grader, grade, gradingSession, sg.Game, &sg.Config, action)
+		if err != nil {
+			return err
+		}
+		if err := apply(&sg.Game, &sg.Config); err != nil {
+			return err
+		}
+		recordIfFinished(recorder, sg)
+		return nil
+	})
+	if err != nil {
+		return game.Game{}, nil, err
+	}
+	return sg.Game, gradeResp, nil
+}
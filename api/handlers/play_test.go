@@ -0,0 +1,18 @@
+package handlers
+
+import (
+	"testing"
+
+	"blackjack/api/game"
+	"blackjack/api/services"
+)
+
+// storeGame は playerID が始めたゲームとして g を設定 config で保持し、ゲームの ID を返します。
+func storeGame(t *testing.T, store services.GameStore, playerID string, g game.Game, config game.GameConfig) string {
+	t.Helper()
+	sg, err := store.Create(services.StoredGame{PlayerID: playerID, Game: g, Config: config})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sg.ID
+}
@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"blackjack/api/services"
+
+	"github.com/gorilla/mux"
+)
+
+// StatsSummaryResponse はある期間の成績（率はハンド数に対する割合）
+type StatsSummaryResponse struct {
+	HandsPlayed       int     `json:"hands_played"`
+	Wins              int     `json:"wins"`
+	Losses            int     `json:"losses"`
+	Pushes            int     `json:"pushes"`
+	Surrenders        int     `json:"surrenders"`
+	Blackjacks        int     `json:"blackjacks"`
+	WinRate           float64 `json:"win_rate"`
+	LossRate          float64 `json:"loss_rate"`
+	PushRate          float64 `json:"push_rate"`
+	SurrenderRate     float64 `json:"surrender_rate"`
+	BlackjackRate     float64 `json:"blackjack_rate"`
+	TotalWagered      int     `json:"total_wagered"`
+	NetResult         int     `json:"net_result"`
+	ExpectedNet       float64 `json:"expected_net"`
+	LongestWinStreak  int     `json:"longest_win_streak"`
+	LongestLossStreak int     `json:"longest_loss_streak"`
+}
+
+// SessionStatsResponse はセッションごとの成績
+type SessionStatsResponse struct {
+	SessionID string `json:"session_id"`
+	StatsSummaryResponse
+}
+
+// PlayerStatsResponse はプレイヤーの通算成績とセッションごとの成績
+type PlayerStatsResponse struct {
+	PlayerID string                 `json:"player_id"`
+	Lifetime StatsSummaryResponse   `json:"lifetime"`
+	Sessions []SessionStatsResponse `json:"sessions"`
+}
+
+func toStatsSummaryResponse(st services.StatsSummary) StatsSummaryResponse {
+	return StatsSummaryResponse{
+		HandsPlayed:       st.HandsPlayed,
+		Wins:              st.Wins,
+		Losses:            st.Losses,
+		Pushes:            st.Pushes,
+		Surrenders:        st.Surrenders,
+		Blackjacks:        st.Blackjacks,
+		WinRate:           accuracy(st.Wins, st.HandsPlayed),
+		LossRate:          accuracy(st.Losses, st.HandsPlayed),
+		PushRate:          accuracy(st.Pushes, st.HandsPlayed),
+		SurrenderRate:     accuracy(st.Surrenders, st.HandsPlayed),
+		BlackjackRate:     accuracy(st.Blackjacks, st.HandsPlayed),
+		TotalWagered:      st.TotalWagered,
+		NetResult:         st.NetResult,
+		ExpectedNet:       st.ExpectedNet,
+		LongestWinStreak:  st.LongestWinStreak,
+		LongestLossStreak: st.LongestLossStreak,
+	}
+}
+
+// PlayerStatsHandler は認証したプレイヤー本人の成績を返すハンドラ
+func PlayerStatsHandler(statsSvc services.StatsService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		// 成績は本人のものだけ見られる
+		playerID, err := requirePlayer(r, mux.Vars(r)["id"])
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		stats, err := statsSvc.PlayerStats(playerID)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		resp := PlayerStatsResponse{
+			PlayerID: stats.PlayerID,
+			Lifetime: toStatsSummaryResponse(stats.Lifetime),
+			Sessions: make([]SessionStatsResponse, 0, len(stats.Sessions)),
+		}
+		for _, s := range stats.Sessions {
+			resp.Sessions = append(resp.Sessions, SessionStatsResponse{SessionID: s.SessionID, StatsSummaryResponse: toStatsSummaryResponse(s.Summary)})
+		}
+		json.NewEncoder(w).Encode(resp)
+	}
+}
@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"blackjack/api/game"
+	"blackjack/api/services"
+
+	"github.com/gorilla/mux"
+)
+
+// mockStatsService は記録されたゲームを保持し、固定の成績を返すモック
+type mockStatsService struct {
+	recorded []game.Game
+}
+
+func (m *mockStatsService) RecordGame(playerID, sessionID string, g game.Game, config *game.GameConfig) error {
+	m.recorded = append(m.recorded, g)
+	return nil
+}
+
+func (m *mockStatsService) PlayerStats(playerID string) (services.PlayerStats, error) {
+	if playerID != "p1" {
+		return services.PlayerStats{}, services.ErrPlayerNotFound
+	}
+	return services.PlayerStats{
+		PlayerID: "p1",
+		Lifetime: services.StatsSummary{HandsPlayed: 4, Wins: 2, Blackjacks: 1, Losses: 2, TotalWagered: 400, NetResult: 50},
+		Sessions: []services.SessionStats{{SessionID: "s1", Summary: services.StatsSummary{HandsPlayed: 4}}},
+	}, nil
+}
+
+func TestPlayerStatsHandler(t *testing.T) {
+	router := mux.NewRouter()
+	router.HandleFunc("/api/players/{id}/stats", PlayerStatsHandler(&mockStatsService{}))
+
+	req := asPlayer(httptest.NewRequest(http.MethodGet, "/api/players/p1/stats", nil), "p1")
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, rr.Code)
+	}
+	var resp PlayerStatsResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if resp.Lifetime.WinRate != 0.5 || resp.Lifetime.BlackjackRate != 0.25 || resp.Lifetime.NetResult != 50 {
+		t.Fatalf("unexpected lifetime stats: %+v", resp.Lifetime)
+	}
+	if len(resp.Sessions) != 1 || resp.Sessions[0].SessionID != "s1" {
+		t.Fatalf("unexpected sessions: %+v", resp.Sessions)
+	}
+
+	req = asPlayer(httptest.NewRequest(http.MethodGet, "/api/players/unknown/stats", nil), "unknown")
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	if rr.Code != http.StatusNotFound {
+		t.Fatalf("expected status %d, got %d", http.StatusNotFound, rr.Code)
+	}
+
+	// 他のプレイヤーの成績と、認証していないリクエストは見られない
+	for _, tc := range []struct {
+		req  *http.Request
+		want int
+	}{
+		{asPlayer(httptest.NewRequest(http.MethodGet, "/api/players/p1/stats", nil), "p2"), http.StatusForbidden},
+		{httptest.NewRequest(http.MethodGet, "/api/players/p1/stats", nil), http.StatusUnauthorized},
+	} {
+		rr = httptest.NewRecorder()
+		router.ServeHTTP(rr, tc.req)
+		if rr.Code != tc.want {
+			t.Errorf("expected status %d, got %d", tc.want, rr.Code)
+		}
+	}
+}
+
+func TestStandV2Handler_RecordsFinishedGameOnce(t *testing.T) {
+	recorder := &mockStatsService{}
+	store := services.NewGameStore()
+	handler := StandV2Handler(mockStandService{}, store, nil, recorder)
+	stand := func(gameID string) {
+		body, _ := json.Marshal(ActionRequestV2{GameID: gameID})
+		req := asPlayer(httptest.NewRequest(http.MethodPost, "/api/v2/game/stand", bytes.NewReader(body)), "p1")
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	id := storeGame(t, store, "p1", game.Game{Bet: 100}, game.GameConfig{DealerStandThreshold: 17})
+	stand(id)
+	if len(recorder.recorded) != 1 || recorder.recorded[0].Result != game.Push || recorder.recorded[0].ID != id {
+		t.Fatalf("expected finished game to be recorded, got %+v", recorder.recorded)
+	}
+
+	// 同じゲームを何度送っても記録は一度だけ
+	stand(id)
+	if len(recorder.recorded) != 1 {
+		t.Fatalf("expected the game to be recorded once, got %d", len(recorder.recorded))
+	}
+
+	// 匿名で始めたゲームは記録しない
+	stand(storeGame(t, store, "", game.Game{Bet: 100}, game.GameConfig{DealerStandThreshold: 17}))
+	if len(recorder.recorded) != 1 {
+		t.Fatalf("expected no recording for an anonymous game, got %d", len(recorder.recorded))
+	}
+
+	// 認証せずに player_id を指定することはできない
+	body, _ := json.Marshal(ActionRequestV2{GameID: id, PlayerID: "p1"})
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/api/v2/game/stand", bytes.NewReader(body)))
+	if rr.Code != http.StatusUnauthorized || len(recorder.recorded) != 1 {
+		t.Fatalf("expected 401 without recording, got %d and %d records", rr.Code, len(recorder.recorded))
+	}
+}
@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"time"
+)
+
+// readinessTimeout は 1 つの依存先の確認にかける最大の時間
+const readinessTimeout = 2 * time.Second
+
+// ReadinessCheck は依存先の 1 つが使える状態かを確かめます。使えなければエラーを返します。
+type ReadinessCheck struct {
+	Name  string
+	Check func(ctx context.Context) error
+}
+
+// ReadinessCheckResult は 1 つの依存先の状態
+type ReadinessCheckResult struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`          // "ok" または "error"
+	Error  string `json:"error,omitempty"` // 使えない理由
+}
+
+// ReadinessResponse は準備状態のレスポンス。Status は全ての依存先が使えれば "ready"、そうでなければ "not_ready"
+type ReadinessResponse struct {
+	Status string                 `json:"status"`
+	Checks []ReadinessCheckResult `json:"checks"`
+}
+
+// ReadinessHandler は依存先の状態を確かめ、全て使えれば 200、そうでなければ 503 を返すハンドラです。
+// プロセスが動いていることだけを返す HealthHandler と違い、停止中や依存先の障害では
+// ロードバランサーがリクエストを振り分けないよう 503 にします。
+func ReadinessHandler(checks []ReadinessCheck) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-store")
+
+		results := make([]ReadinessCheckResult, len(checks))
+		done := make(chan struct{}, len(checks))
+		for i, c := range checks {
+			go func(i int, c ReadinessCheck) {
+				defer func() { done <- struct{}{} }()
+				ctx, cancel := context.WithTimeout(r.Context(), readinessTimeout)
+				defer cancel()
+				results[i] = ReadinessCheckResult{Name: c.Name, Status: "ok"}
+				if err := c.Check(ctx); err != nil {
+					results[i].Status, results[i].Error = "error", err.Error()
+				}
+			}(i, c)
+		}
+		for range checks {
+			<-done
+		}
+		sort.Slice(results, func(i, j int) bool { return results[i].Name < results[j].Name })
+
+		resp := ReadinessResponse{Status: "ready", Checks: results}
+		status := http.StatusOK
+		for _, res := range results {
+			if res.Status != "ok" {
+				resp.Status, status = "not_ready", http.StatusServiceUnavailable
+			}
+		}
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(resp)
+	}
+}
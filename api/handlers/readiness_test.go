@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestReadinessHandler(t *testing.T) {
+	ok := ReadinessCheck{Name: "storage", Check: func(context.Context) error { return nil }}
+	failing := ReadinessCheck{Name: "grpc", Check: func(context.Context) error { return errors.New("not serving") }}
+
+	for _, tc := range []struct {
+		name   string
+		checks []ReadinessCheck
+		status int
+		want   ReadinessResponse
+	}{
+		{"all ok", []ReadinessCheck{ok}, http.StatusOK, ReadinessResponse{
+			Status: "ready",
+			Checks: []ReadinessCheckResult{{Name: "storage", Status: "ok"}},
+		}},
+		{"one failing", []ReadinessCheck{ok, failing}, http.StatusServiceUnavailable, ReadinessResponse{
+			Status: "not_ready",
+			Checks: []ReadinessCheckResult{{Name: "grpc", Status: "error", Error: "not serving"}, {Name: "storage", Status: "ok"}},
+		}},
+	} {
+		rr := httptest.NewRecorder()
+		ReadinessHandler(tc.checks)(rr, httptest.NewRequest(http.MethodGet, "/api/ready", nil))
+		var got ReadinessResponse
+		if err := json.Unmarshal(rr.Body.Bytes(), &got); err != nil {
+			t.Fatal(err)
+		}
+		if rr.Code != tc.status || got.Status != tc.want.Status || len(got.Checks) != len(tc.want.Checks) {
+			t.Fatalf("%s: got %d %+v", tc.name, rr.Code, got)
+		}
+		for i := range got.Checks {
+			if got.Checks[i] != tc.want.Checks[i] {
+				t.Errorf("%s: check %d = %+v, want %+v", tc.name, i, got.Checks[i], tc.want.Checks[i])
+			}
+		}
+	}
+}
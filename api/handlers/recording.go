@@ -0,0 +1,21 @@
+package handlers
+
+import (
+	"log"
+
+	"blackjack/api/game"
+	"blackjack/api/services"
+)
+
+// recordIfFinished はサーバーが保持しているゲームが決着していれば、ゲームを始めたプレイヤーの成績に記録します。
+// 同じゲームは一度だけ記録します（Recorded を立てるので、sg は保存する前の写しを渡してください）。
+// 成績の記録に失敗してもゲームの結果は返したいので、エラーはログに残すだけにします。
+func recordIfFinished(recorder services.GameRecorder, sg *services.StoredGame) {
+	if recorder == nil || sg.PlayerID == "" || sg.Game.State != game.Finished || sg.Recorded {
+		return
+	}
+	sg.Recorded = true
+	if err := recorder.RecordGame(sg.PlayerID, sg.SessionID, sg.Game, &sg.Config); err != nil {
+		log.Printf("failed to record game %s for player %s: %v", sg.ID, sg.PlayerID, err)
+	}
+}
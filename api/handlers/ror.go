@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+
+	"blackjack/api/game"
+	"blackjack/api/services"
+	"blackjack/api/strategy"
+)
+
+// RiskOfRuinRequest は破産確率計算の入力
+// Mean / Variance を省略した場合は Config のルールで最適戦略をとったときの値を使います。
+type RiskOfRuinRequest struct {
+	Mean       *float64        `json:"mean,omitempty"`
+	Variance   *float64        `json:"variance,omitempty"`
+	Bankroll   float64         `json:"bankroll"`
+	BetUnit    float64         `json:"bet_unit"`
+	TargetRuin float64         `json:"target_ruin"`
+	Trials     int             `json:"trials,omitempty"`
+	MaxHands   int             `json:"max_hands,omitempty"`
+	Config     game.GameConfig `json:"config"`
+}
+
+// RiskOfRuinResponse は破産確率の計算結果
+// 有限値にならない項目（期待値が 0 以下の場合の必要資金など）は null になります。
+type RiskOfRuinResponse struct {
+	Mean             float64  `json:"mean"`
+	Variance         float64  `json:"variance"`
+	AnalyticRuin     float64  `json:"analytic_ruin"`
+	SimulatedRuin    float64  `json:"simulated_ruin"`
+	N0               *float64 `json:"n0"`
+	RequiredBankroll *float64 `json:"required_bankroll"`
+}
+
+// RiskOfRuinHandler は破産確率を返すハンドラ
+func RiskOfRuinHandler(estimator services.RiskOfRuinEstimator) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		var req RiskOfRuinRequest
+		if err := decodeJSON(w, r, &req); err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		// 平均と分散が与えられなければ戦略計算から求める
+		var mean, variance float64
+		if req.Mean != nil && req.Variance != nil {
+			mean, variance = *req.Mean, *req.Variance
+		} else {
+			m, v, err := estimator.HandStatistics(&req.Config)
+			if err != nil {
+				writeError(w, r, err)
+				return
+			}
+			mean, variance = m, v
+		}
+
+		// クライアントが切断したらシミュレーションをやめる
+		res, err := estimator.EstimateRiskOfRuin(r.Context(), strategy.RiskOfRuinInput{
+			Mean:       mean,
+			Variance:   variance,
+			Bankroll:   req.Bankroll,
+			BetUnit:    req.BetUnit,
+			TargetRuin: req.TargetRuin,
+			Trials:     req.Trials,
+			MaxHands:   req.MaxHands,
+		})
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		resp := RiskOfRuinResponse{
+			Mean:             mean,
+			Variance:         variance,
+			AnalyticRuin:     res.AnalyticRuin,
+			SimulatedRuin:    res.SimulatedRuin,
+			N0:               finiteOrNil(res.N0),
+			RequiredBankroll: finiteOrNil(res.RequiredBankroll),
+		}
+		json.NewEncoder(w).Encode(resp)
+	}
+}
+
+// JSON は Inf を表現できないため、有限値でなければ nil にする
+func finiteOrNil(v float64) *float64 {
+	if math.IsInf(v, 0) || math.IsNaN(v) {
+		return nil
+	}
+	return &v
+}
@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"blackjack/api/game"
+	"blackjack/api/strategy"
+)
+
+// mockRiskOfRuinService は固定の統計値と計算結果を返すモック
+type mockRiskOfRuinService struct {
+	mean, variance float64
+	result         strategy.RiskOfRuinResult
+	gotInput       *strategy.RiskOfRuinInput
+}
+
+func (m *mockRiskOfRuinService) HandStatistics(config *game.GameConfig) (float64, float64, error) {
+	return m.mean, m.variance, nil
+}
+
+func (m *mockRiskOfRuinService) EstimateRiskOfRuin(ctx context.Context, in strategy.RiskOfRuinInput) (strategy.RiskOfRuinResult, error) {
+	m.gotInput = &in
+	return m.result, nil
+}
+
+func TestRiskOfRuinHandler_UsesStrategyStatisticsWhenOmitted(t *testing.T) {
+	svc := &mockRiskOfRuinService{
+		mean:     -0.02,
+		variance: 1.2,
+		result: strategy.RiskOfRuinResult{
+			AnalyticRuin:     1.0,
+			SimulatedRuin:    0.9,
+			N0:               3000,
+			RequiredBankroll: math.Inf(1),
+		},
+	}
+	handler := RiskOfRuinHandler(svc)
+
+	body, _ := json.Marshal(RiskOfRuinRequest{
+		Bankroll:   10000,
+		BetUnit:    100,
+		TargetRuin: 0.05,
+		Config:     game.GameConfig{DealerStandThreshold: 17},
+	})
+	req := httptest.NewRequest(http.MethodPost, "/api/strategy/ror", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rr := httptest.NewRecorder()
+
+	handler.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, rr.Code)
+	}
+	if svc.gotInput == nil || svc.gotInput.Mean != -0.02 || svc.gotInput.Variance != 1.2 {
+		t.Fatalf("expected strategy statistics to be passed, got %+v", svc.gotInput)
+	}
+
+	var resp RiskOfRuinResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if resp.RequiredBankroll != nil {
+		t.Fatalf("expected null required bankroll for infinite value, got %v", *resp.RequiredBankroll)
+	}
+	if resp.N0 == nil || *resp.N0 != 3000 {
+		t.Fatalf("expected n0 3000, got %v", resp.N0)
+	}
+}
+
+func TestRiskOfRuinHandler_UsesGivenStatistics(t *testing.T) {
+	svc := &mockRiskOfRuinService{mean: -0.02, variance: 1.2}
+	handler := RiskOfRuinHandler(svc)
+
+	mean, variance := 0.01, 1.3
+	body, _ := json.Marshal(RiskOfRuinRequest{Mean: &mean, Variance: &variance, Bankroll: 10000, BetUnit: 100, TargetRuin: 0.05})
+	req := httptest.NewRequest(http.MethodPost, "/api/strategy/ror", bytes.NewReader(body))
+	rr := httptest.NewRecorder()
+
+	handler.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, rr.Code)
+	}
+	if svc.gotInput.Mean != mean || svc.gotInput.Variance != variance {
+		t.Fatalf("expected given statistics to be passed, got %+v", svc.gotInput)
+	}
+}
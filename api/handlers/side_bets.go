@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"blackjack/api/game"
+	"blackjack/api/services"
+	"blackjack/api/strategy"
+)
+
+// SideBetHouseEdgeRequest はハウスエッジを求めるルールのバリエーション（省略時はクラシック）
+// 配当表はサーバーの設定の配当表を使います。
+type SideBetHouseEdgeRequest struct {
+	Variant game.Variant `json:"variant,omitempty"`
+}
+
+// SideBetHouseEdge は 1 種類のサイドベットのハウスエッジと役ごとの確率
+type SideBetHouseEdge struct {
+	HouseEdge     float64                              `json:"house_edge"`
+	Probabilities strategy.SideBetOutcomeProbabilities `json:"probabilities"`
+	Paytable      interface{}                          `json:"paytable"`
+}
+
+// SideBetHouseEdgeResponse は各サイドベットのハウスエッジ
+type SideBetHouseEdgeResponse struct {
+	PerfectPairs       SideBetHouseEdge `json:"perfect_pairs"`
+	TwentyOnePlusThree SideBetHouseEdge `json:"twenty_one_plus_three"`
+}
+
+// SideBetHouseEdgeHandler はバリエーションのデッキでの、sideBets が精算に使う配当表のハウスエッジを返すハンドラを生成します。
+func SideBetHouseEdgeHandler(sideBets services.SideBetService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		var req SideBetHouseEdgeRequest
+		if err := decodeJSON(w, r, &req); err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		rules, err := game.RulesFor(req.Variant)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		paytables := sideBets.Paytables()
+		pp := paytables.PerfectPairs
+		tot := paytables.TwentyOnePlusThree
+
+		json.NewEncoder(w).Encode(SideBetHouseEdgeResponse{
+			PerfectPairs: SideBetHouseEdge{
+				HouseEdge:     strategy.PerfectPairsHouseEdge(pp, rules),
+				Probabilities: strategy.PerfectPairsProbabilities(rules),
+				Paytable:      pp,
+			},
+			TwentyOnePlusThree: SideBetHouseEdge{
+				HouseEdge:     strategy.TwentyOnePlusThreeHouseEdge(tot, rules),
+				Probabilities: strategy.TwentyOnePlusThreeProbabilities(rules),
+				Paytable:      tot,
+			},
+		})
+	}
+}
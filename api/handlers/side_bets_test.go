@@ -0,0 +1,41 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"blackjack/api/game"
+	"blackjack/api/services"
+	"blackjack/api/strategy"
+)
+
+func TestSideBetHouseEdgeHandler_UsesConfiguredPaytables(t *testing.T) {
+	paytables := game.DefaultSideBetPaytables()
+	paytables.PerfectPairs = game.PerfectPairsPaytable{Mixed: 5, Colored: 10, Perfect: 30}
+	handler := SideBetHouseEdgeHandler(services.NewSideBetService(paytables))
+
+	body, _ := json.Marshal(SideBetHouseEdgeRequest{})
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/api/strategy/side-bets", bytes.NewReader(body)))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+
+	var got struct {
+		PerfectPairs struct {
+			HouseEdge float64                   `json:"house_edge"`
+			Paytable  game.PerfectPairsPaytable `json:"paytable"`
+		} `json:"perfect_pairs"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	want := strategy.PerfectPairsHouseEdge(paytables.PerfectPairs, game.ClassicRules())
+	if got.PerfectPairs.Paytable != paytables.PerfectPairs || math.Abs(got.PerfectPairs.HouseEdge-want) > 1e-12 {
+		t.Fatalf("expected the configured paytable with house edge %f, got %+v", want, got.PerfectPairs)
+	}
+}
@@ -5,33 +5,47 @@ import (
 	"net/http"
 
 	"blackjack/api/game"
+	"blackjack/api/i18n"
 	"blackjack/api/services"
+	"blackjack/api/strategy"
 )
 
 // StandRequest はスタンド時にクライアントから送られてくる現在のゲーム状態と設定を表します。
 type StandRequest struct {
 	Game   game.Game       `json:"game"`
 	Config game.GameConfig `json:"config"`
+	// Grade が true の場合、行動を最適戦略と比較した採点結果をレスポンスに付けます。
+	Grade     bool   `json:"grade,omitempty"`
+	SessionID string `json:"session_id,omitempty"` // 採点結果を集計するセッション
+	PlayerID  string `json:"player_id,omitempty"`  // 認証したプレイヤーの ID（省略可）
+	Locale    string `json:"locale,omitempty"`     // 結果の文言とエラーメッセージの言語（"ja", "en"）。省略時は Accept-Language に従います
 }
 
 // StandHandler は Stander の Stand を呼び出すハンドラを返します。
-func StandHandler(gameSvc services.Stander) http.HandlerFunc {
+// ゲームの状態はクライアントが持つので成績には記録しません（記録するのはサーバーが保持する v2 のゲームだけです）。
+// grader が nil の場合、採点は行いません。
+func StandHandler(gameSvc services.Stander, grader services.DecisionGrader) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
 
 		var req StandRequest
-		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-			http.Error(w, "invalid request body", http.StatusBadRequest)
+		if err := decodeJSON(w, r, &req); err != nil {
+			writeError(w, r, err)
+			return
+		}
+		r = withLocale(r, req.Locale)
+		if _, err := playerFor(r, req.PlayerID); err != nil {
+			writeError(w, r, err)
 			return
 		}
 
-		g := req.Game
-
-		if err := gameSvc.Stand(&g, &req.Config); err != nil {
-			http.Error(w, err.Error(), http.StatusBadRequest)
+		g, grade, err := playClientGame(grader, req.Game, &req.Config, gameSvc.Stand, strategy.ActionStand, req.Grade, req.SessionID)
+		if err != nil {
+			writeError(w, r, err)
 			return
 		}
 
-		json.NewEncoder(w).Encode(g)
+		i18n.Localize(&g, requestLang(r))
+		json.NewEncoder(w).Encode(ActionResponse{Game: g, Grade: grade})
 	}
 }
@@ -0,0 +1,149 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"blackjack/api/game"
+	"blackjack/api/i18n"
+	"blackjack/api/services"
+	"blackjack/api/strategy"
+)
+
+// SSE のイベント名
+const (
+	StreamEventDealerCard = "dealer_card" // ディーラーが 1 枚引いた
+	StreamEventSettlement = "settlement"  // 最終結果
+)
+
+// DealerCardEvent はディーラーが引いた 1 枚と、その時点の手札を表します。
+type DealerCardEvent struct {
+	Index int       `json:"index"` // ディーラー手札の何枚目か（0 始まり。0 は配布済みのアップカード）
+	Card  game.Card `json:"card"`
+	Hand  game.Hand `json:"hand"`  // このカードを受け取った後の手札
+	Score int       `json:"score"` // game.CalculateScore による途中スコア（0 はバースト）
+}
+
+// standStreamRequest はスタンドのストリーミングのリクエストから読み取る内容です。
+type standStreamRequest struct {
+	PlayerID string
+	Locale   string
+	// play は playerID のプレイヤーとしてゲームに stand を適用し、進めたゲームと採点結果を返します。
+	play func(playerID string, stand func(*game.Game, *game.GameConfig) error) (game.Game, *GradeResponse, error)
+}
+
+// StandStreamHandler はスタンドの結果を Server-Sent Events で返すハンドラを返します。
+// リクエストは StandRequest と同じです。ディーラーのドローを dealer_card イベントとして
+// interval ごとに 1 枚ずつ送り、最後に settlement イベントで ActionResponse を送ります。
+// 検証エラーなどストリーム開始前の失敗は通常の HTTP エラーとして返します。
+func StandStreamHandler(gameSvc services.Stander, grader services.DecisionGrader, interval time.Duration) http.HandlerFunc {
+	decode := func(w http.ResponseWriter, r *http.Request) (standStreamRequest, error) {
+		var req StandRequest
+		if err := decodeJSON(w, r, &req); err != nil {
+			return standStreamRequest{}, err
+		}
+		play := func(_ string, stand func(*game.Game, *game.GameConfig) error) (game.Game, *GradeResponse, error) {
+			return playClientGame(grader, req.Game, &req.Config, stand, strategy.ActionStand, req.Grade, req.SessionID)
+		}
+		return standStreamRequest{PlayerID: req.PlayerID, Locale: req.Locale, play: play}, nil
+	}
+	settlement := func(g game.Game, grade *GradeResponse) interface{} {
+		return ActionResponse{Game: g, Grade: grade}
+	}
+	return standStreamHandler(gameSvc, interval, decode, settlement)
+}
+
+// standStreamHandler は API のバージョンに依らないスタンドのストリーミングの本体です。
+// decode はリクエストボディを読み、settlement は settlement イベントで送る値を作ります。
+func standStreamHandler(gameSvc services.Stander, interval time.Duration,
+	decode func(w http.ResponseWriter, r *http.Request) (standStreamRequest, error), settlement func(g game.Game, grade *GradeResponse) interface{}) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			writeError(w, r, errStreamingUnsupported)
+			return
+		}
+
+		req, err := decode(w, r)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		r = withLocale(r, req.Locale)
+		playerID, err := playerFor(r, req.PlayerID)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		// スタンド前から持っていたカードの枚数（引いたカードだけを送る）
+		var dealt int
+		stand := func(g *game.Game, config *game.GameConfig) error {
+			dealt = len(g.DealerHand.Cards)
+			return gameSvc.Stand(g, config)
+		}
+		// v2 の結果はクライアントが接続を切っても保持・記録する
+		g, grade, err := req.play(playerID, stand)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("Connection", "keep-alive")
+		w.WriteHeader(http.StatusOK)
+		flusher.Flush()
+
+		// スタンド前から持っていたカードは引いたカードではないので送らない
+		cards := g.DealerHand.Cards
+		for i := dealt; i < len(cards); i++ {
+			if i > dealt && !waitOrDone(r, interval) {
+				return
+			}
+			hand := append([]game.Card(nil), cards[:i+1]...)
+			score := game.CalculateScore(hand)
+			ev := DealerCardEvent{Index: i, Card: cards[i], Hand: game.Hand{Cards: hand, Score: score}, Score: score}
+			if err := writeSSE(w, StreamEventDealerCard, ev); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+
+		if !waitOrDone(r, interval) {
+			return
+		}
+		i18n.Localize(&g, requestLang(r))
+		if err := writeSSE(w, StreamEventSettlement, settlement(g, grade)); err != nil {
+			return
+		}
+		flusher.Flush()
+	}
+}
+
+// writeSSE は 1 件の Server-Sent Event を書き込みます。
+func writeSSE(w http.ResponseWriter, event string, data interface{}) error {
+	b, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, b)
+	return err
+}
+
+// waitOrDone は interval だけ待ちます。その間にクライアントが切断したら false を返します。
+func waitOrDone(r *http.Request, interval time.Duration) bool {
+	if interval <= 0 {
+		return r.Context().Err() == nil
+	}
+	timer := time.NewTimer(interval)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-r.Context().Done():
+		return false
+	}
+}
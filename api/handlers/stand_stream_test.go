@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"blackjack/api/game"
+	"blackjack/api/services"
+)
+
+// scriptedDeck は指定した順にカードを配るデッキ
+type scriptedDeck struct {
+	cards []game.Card
+}
+
+func (d *scriptedDeck) Deal() game.Card {
+	c := d.cards[0]
+	d.cards = d.cards[1:]
+	return c
+}
+
+type sseEvent struct {
+	name string
+	data string
+}
+
+func parseSSE(t *testing.T, body string) []sseEvent {
+	t.Helper()
+	var events []sseEvent
+	var cur sseEvent
+	sc := bufio.NewScanner(strings.NewReader(body))
+	for sc.Scan() {
+		line := sc.Text()
+		switch {
+		case strings.HasPrefix(line, "event: "):
+			cur.name = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			cur.data = strings.TrimPrefix(line, "data: ")
+		case line == "":
+			events = append(events, cur)
+			cur = sseEvent{}
+		}
+	}
+	return events
+}
+
+func TestStandStreamHandler_StreamsDealerCardsThenSettlement(t *testing.T) {
+	// ディーラー: 6 → 5 (11) → 10 (21)。プレイヤー 19 の負け
+	svc := services.NewGameService(&scriptedDeck{cards: []game.Card{
+		{Suit: game.Heart, Rank: "5"}, {Suit: game.Club, Rank: "10"},
+	}})
+	playerCards := []game.Card{{Suit: game.Spade, Rank: "10"}, {Suit: game.Heart, Rank: "9"}}
+	dealerCards := []game.Card{{Suit: game.Club, Rank: "6"}}
+	g := game.Game{
+		PlayerHand: game.Hand{Cards: playerCards, Score: game.CalculateScore(playerCards)},
+		DealerHand: game.Hand{Cards: dealerCards, Score: game.CalculateScore(dealerCards)},
+		Bet:        100,
+		State:      game.PlayerTurn,
+		Result:     game.Pending,
+	}
+	body, _ := json.Marshal(StandRequest{Game: g, Config: game.GameConfig{DealerStandThreshold: 17}})
+	req := httptest.NewRequest(http.MethodPost, "/api/game/stand/stream", bytes.NewReader(body))
+	rr := httptest.NewRecorder()
+
+	StandStreamHandler(svc, nil, 0).ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+	if got := rr.Header().Get("Content-Type"); got != "text/event-stream" {
+		t.Fatalf("expected Content-Type text/event-stream, got %s", got)
+	}
+
+	events := parseSSE(t, rr.Body.String())
+	if len(events) != 3 {
+		t.Fatalf("expected 3 events, got %d: %+v", len(events), events)
+	}
+	wantScores := []int{11, 21}
+	for i, want := range wantScores {
+		if events[i].name != StreamEventDealerCard {
+			t.Fatalf("event %d: expected %s, got %s", i, StreamEventDealerCard, events[i].name)
+		}
+		var ev DealerCardEvent
+		if err := json.Unmarshal([]byte(events[i].data), &ev); err != nil {
+			t.Fatalf("failed to decode dealer card: %v", err)
+		}
+		if ev.Index != i+1 || ev.Score != want || len(ev.Hand.Cards) != i+2 {
+			t.Fatalf("event %d: unexpected dealer card %+v", i, ev)
+		}
+	}
+
+	if events[2].name != StreamEventSettlement {
+		t.Fatalf("expected settlement event, got %s", events[2].name)
+	}
+	var res ActionResponse
+	if err := json.Unmarshal([]byte(events[2].data), &res); err != nil {
+		t.Fatalf("failed to decode settlement: %v", err)
+	}
+	if res.Result != game.DealerWin || res.DealerHand.Score != 21 {
+		t.Fatalf("unexpected settlement: %+v", res.Game)
+	}
+}
+
+func TestStandStreamHandler_InvalidStateReturnsHTTPError(t *testing.T) {
+	svc := services.NewGameService(&scriptedDeck{})
+	body, _ := json.Marshal(StandRequest{Game: game.Game{}, Config: game.GameConfig{DealerStandThreshold: 17}})
+	req := httptest.NewRequest(http.MethodPost, "/api/game/stand/stream", bytes.NewReader(body))
+	rr := httptest.NewRecorder()
+
+	StandStreamHandler(svc, nil, 0).ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("expected status %d, got %d", http.StatusUnprocessableEntity, rr.Code)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"testing"
 
 	"blackjack/api/game"
+	"blackjack/api/services"
 )
 
 type mockStandService struct{}
@@ -37,7 +38,7 @@ func TestStandHandler_ReturnsUpdatedGameJSON(t *testing.T) {
 
 	svc := mockStandService{}
 
-	handler := StandHandler(svc)
+	handler := StandHandler(svc, nil)
 
 	req_body := StandRequest{
 		Game:   g,
@@ -71,3 +72,59 @@ func TestStandHandler_ReturnsUpdatedGameJSON(t *testing.T) {
 		t.Fatalf("expected payout %d, got %d", bet, resp.Payout)
 	}
 }
+
+func TestStandHandler_LocalizesResultMessage(t *testing.T) {
+	// プレイヤー 18、ディーラー 10 が 10 を引いて 20 でディーラーの勝ち
+	newGame := func() game.Game {
+		return game.Game{
+			PlayerHand: game.Hand{Cards: []game.Card{{Suit: game.Spade, Rank: "10"}, {Suit: game.Heart, Rank: "8"}}, Score: 18},
+			DealerHand: game.Hand{Cards: []game.Card{{Suit: game.Club, Rank: "10"}}, Score: 10},
+			Bet:        100,
+			State:      game.PlayerTurn,
+			Result:     game.Pending,
+		}
+	}
+	stand := func(locale, acceptLanguage string) ActionResponse {
+		svc := services.NewGameService(&scriptedDeck{cards: []game.Card{{Suit: game.Diamond, Rank: "K"}}})
+		body, _ := json.Marshal(StandRequest{Game: newGame(), Config: game.GameConfig{DealerStandThreshold: 17}, Locale: locale})
+		req := httptest.NewRequest(http.MethodPost, "/api/game/stand", bytes.NewReader(body))
+		req.Header.Set("Accept-Language", acceptLanguage)
+		rr := httptest.NewRecorder()
+		StandHandler(svc, nil).ServeHTTP(rr, req)
+
+		var resp ActionResponse
+		if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("failed to unmarshal response: %v", err)
+		}
+		return resp
+	}
+
+	if resp := stand("", "en-US,en;q=0.9"); resp.ResultReason != game.ReasonDealerWin || resp.ResultMessage != "The dealer wins." {
+		t.Fatalf("expected an english message from Accept-Language, got %+v", resp.Game)
+	}
+	if resp := stand("ja", "en"); resp.ResultMessage != game.MessageDealerWin {
+		t.Fatalf("expected the locale field to take precedence, got %s", resp.ResultMessage)
+	}
+	if resp := stand("", ""); resp.ResultMessage != game.MessageDealerWin {
+		t.Fatalf("expected japanese by default, got %s", resp.ResultMessage)
+	}
+}
+
+func TestStandHandler_LocalizesValidationError(t *testing.T) {
+	finished := game.Game{
+		PlayerHand: game.Hand{Cards: []game.Card{{Suit: game.Spade, Rank: "10"}, {Suit: game.Heart, Rank: "8"}}, Score: 18},
+		DealerHand: game.Hand{Cards: []game.Card{{Suit: game.Club, Rank: "10"}}, Score: 10},
+		Bet:        100,
+		State:      game.Finished,
+		Result:     game.DealerWin,
+	}
+	body, _ := json.Marshal(StandRequest{Game: finished, Config: game.GameConfig{DealerStandThreshold: 17}, Locale: "en"})
+	rr := httptest.NewRecorder()
+	StandHandler(services.NewGameService(&scriptedDeck{}), nil).ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/api/game/stand", bytes.NewReader(body)))
+
+	var resp ErrorResponse
+	json.Unmarshal(rr.Body.Bytes(), &resp)
+	if rr.Code != http.StatusConflict || resp.Code != "not_player_turn" || resp.Message != "It is not the player's turn." {
+		t.Fatalf("expected a localized conflict error, got %d %+v", rr.Code, resp)
+	}
+}
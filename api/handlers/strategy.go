@@ -2,9 +2,12 @@ package handlers
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
+	"runtime"
 
 	"blackjack/api/game"
+	"blackjack/api/i18n"
 	"blackjack/api/services"
 )
 
@@ -27,20 +30,20 @@ func StrategyHandler(strategyAdvisor services.StrategyAdvisor) http.HandlerFunc
 		w.Header().Set("Content-Type", "application/json")
 
 		var req StrategyRequest
-		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-			http.Error(w, "invalid request body", http.StatusBadRequest)
+		if err := decodeJSON(w, r, &req); err != nil {
+			writeError(w, r, err)
 			return
 		}
 
 		// 不正なconfigじゃないかバリデーション
-		if req.Config.DealerStandThreshold < 1 || req.Config.DealerStandThreshold > 21 {
-			http.Error(w, "dealer stand threshold must be between 1 and 21", http.StatusBadRequest)
+		if err := validateStrategyConfig(req.Config); err != nil {
+			writeError(w, r, err)
 			return
 		}
 
 		payouts, err := strategyAdvisor.Advise(req.Game, &req.Config)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusBadRequest)
+			writeError(w, r, err)
 			return
 		}
 
@@ -53,3 +56,97 @@ func StrategyHandler(strategyAdvisor services.StrategyAdvisor) http.HandlerFunc
 		json.NewEncoder(w).Encode(resp)
 	}
 }
+
+// validateStrategyConfig は戦略計算に使う設定を検証します。
+func validateStrategyConfig(config game.GameConfig) error {
+	if config.DealerStandThreshold < 1 || config.DealerStandThreshold > 21 {
+		return fmt.Errorf("%w: dealer stand threshold must be between 1 and 21", services.ErrInvalidConfig)
+	}
+	return nil
+}
+
+// maxBatchPositions は一括評価の 1 回のリクエストで受け付ける局面の数の上限
+const maxBatchPositions = 200
+
+// StrategyBatchRequest は複数の局面（ゲームと設定）をまとめて評価するリクエスト
+type StrategyBatchRequest struct {
+	Positions []StrategyRequest `json:"positions"`
+}
+
+// StrategyBatchResult は一括評価の 1 つの局面の結果。Advice と Error のどちらか一方が入る
+type StrategyBatchResult struct {
+	Advice *StrategyResponse `json:"advice,omitempty"`
+	Error  *ErrorResponse    `json:"error,omitempty"`
+}
+
+// StrategyBatchResponse はリクエストの局面と同じ順序の評価結果
+type StrategyBatchResponse struct {
+	Results []StrategyBatchResult `json:"results"`
+}
+
+// StrategyBatchHandler は複数の局面の期待払い戻しを並行に計算して返すハンドラ
+// 局面ごとのエラーはその局面の結果に入れ、他の局面の評価は続けます。
+// RateLimit を適用した場合、レート制限のトークンは局面の数だけ使います。
+func StrategyBatchHandler(strategyAdvisor services.StrategyAdvisor) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		var req StrategyBatchRequest
+		if err := decodeJSON(w, r, &req); err != nil {
+			writeError(w, r, err)
+			return
+		}
+		if len(req.Positions) > maxBatchPositions {
+			writeError(w, r, invalidRequest(fmt.Sprintf("too many positions (max %d)", maxBatchPositions)))
+			return
+		}
+		// 局面 1 件ごとに 1 トークン使う（受け付けたときの 1 トークンに加えて）
+		if err := chargeRateLimit(r, len(req.Positions)-1); err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		positions := make([]services.AdvicePosition, len(req.Positions))
+		for i, p := range req.Positions {
+			positions[i] = services.AdvicePosition{Game: p.Game, Config: p.Config}
+		}
+		json.NewEncoder(w).Encode(adviseBatch(strategyAdvisor, positions, make([]error, len(positions)), requestLang(r)))
+	}
+}
+
+// adviseBatch は局面を共有の StrategyAdvisor（Calculator のキャッシュ）で並行に評価し、入力と同じ順序の結果を返します。
+// errs[i] が nil でない局面（リクエストの変換に失敗したもの）は評価せず、そのエラーを結果にします。
+func adviseBatch(strategyAdvisor services.StrategyAdvisor, positions []services.AdvicePosition, errs []error, lang i18n.Lang) StrategyBatchResponse {
+	var valid []services.AdvicePosition
+	var index []int
+	for i, p := range positions {
+		if errs[i] == nil {
+			errs[i] = validateStrategyConfig(p.Config)
+		}
+		if errs[i] == nil {
+			valid = append(valid, p)
+			index = append(index, i)
+		}
+	}
+
+	results := make([]StrategyBatchResult, len(positions))
+	for j, a := range services.AdviseBatch(strategyAdvisor, valid, runtime.GOMAXPROCS(0)) {
+		i := index[j]
+		if a.Err != nil {
+			errs[i] = a.Err
+			continue
+		}
+		results[i].Advice = &StrategyResponse{
+			HitPayout:       a.Payouts.HitPayout,
+			StandPayout:     a.Payouts.StandPayout,
+			SurrenderPayout: a.Payouts.SurrenderPayout,
+		}
+	}
+	for i, err := range errs {
+		if err != nil {
+			_, resp := newErrorResponse(err, lang)
+			results[i].Error = &resp
+		}
+	}
+	return StrategyBatchResponse{Results: results}
+}
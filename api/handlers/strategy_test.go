@@ -8,6 +8,7 @@ import (
 	"testing"
 
 	"blackjack/api/game"
+	"blackjack/api/services"
 	"blackjack/api/strategy"
 )
 
@@ -74,3 +75,69 @@ func TestStrategyHandler_ReturnsExpectedPayouts(t *testing.T) {
 		t.Fatalf("unexpected negative payouts: %+v", resp)
 	}
 }
+
+// adviseFunc は関数で期待払い戻しを返す StrategyAdvisor
+type adviseFunc func(g game.Game, config *game.GameConfig) (strategy.StrategyExpectedPayouts, error)
+
+func (f adviseFunc) Advise(g game.Game, config *game.GameConfig) (strategy.StrategyExpectedPayouts, error) {
+	return f(g, config)
+}
+
+func TestStrategyBatchHandler_ReturnsResultsInOrderWithItemErrors(t *testing.T) {
+	// 掛け金をそのまま期待値にし、掛け金 0 はサービスのエラーにする
+	advisor := adviseFunc(func(g game.Game, config *game.GameConfig) (strategy.StrategyExpectedPayouts, error) {
+		if g.Bet == 0 {
+			return strategy.StrategyExpectedPayouts{}, services.ErrInvalidGameState
+		}
+		return strategy.StrategyExpectedPayouts{HitPayout: float64(g.Bet), StandPayout: 1, SurrenderPayout: 0.5}, nil
+	})
+	valid := game.GameConfig{DealerStandThreshold: 17}
+	var positions []StrategyRequest
+	for i := 1; i <= 50; i++ {
+		positions = append(positions, StrategyRequest{Game: game.Game{Bet: i}, Config: valid})
+	}
+	positions[10].Game.Bet = 0
+	positions[20].Config.DealerStandThreshold = 0
+
+	body, _ := json.Marshal(StrategyBatchRequest{Positions: positions})
+	req := httptest.NewRequest(http.MethodPost, "/api/strategy/advise/batch", bytes.NewReader(body))
+	req.Header.Set("Accept-Language", "en")
+	rr := httptest.NewRecorder()
+	StrategyBatchHandler(advisor).ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", rr.Code, rr.Body.String())
+	}
+	var resp StrategyBatchResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Results) != len(positions) {
+		t.Fatalf("results = %d, want %d", len(resp.Results), len(positions))
+	}
+	for i, res := range resp.Results {
+		switch i {
+		case 10:
+			if res.Advice != nil || res.Error == nil || res.Error.Code != "invalid_game_state" {
+				t.Errorf("result[%d] = %+v, want invalid_game_state", i, res)
+			}
+		case 20:
+			if res.Advice != nil || res.Error == nil || res.Error.Code != "invalid_config" || res.Error.Message != "The configuration is invalid." {
+				t.Errorf("result[%d] = %+v, want invalid_config in English", i, res)
+			}
+		default:
+			if res.Error != nil || res.Advice == nil || res.Advice.HitPayout != float64(i+1) {
+				t.Errorf("result[%d] = %+v, want hit payout %d", i, res, i+1)
+			}
+		}
+	}
+}
+
+func TestStrategyBatchHandler_RejectsTooManyPositions(t *testing.T) {
+	body, _ := json.Marshal(StrategyBatchRequest{Positions: make([]StrategyRequest, maxBatchPositions+1)})
+	rr := httptest.NewRecorder()
+	StrategyBatchHandler(mockStrategyService{}).ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/api/strategy/advise/batch", bytes.NewReader(body)))
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want 400", rr.Code)
+	}
+}
@@ -38,7 +38,7 @@ func TestSurrenderHandler_ReturnsUpdatedGameJSON(t *testing.T) {
 
 	svc := mockSurrenderService{}
 
-	handler := SurrenderHandler(svc)
+	handler := SurrenderHandler(svc, nil)
 
 	req_body := SurrenderRequest{
 		Game:   g,
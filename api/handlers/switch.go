@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"blackjack/api/game"
+	"blackjack/api/i18n"
+	"blackjack/api/services"
+)
+
+// SwitchRequest はブラックジャック・スイッチで2枚目のカードを入れ替える時のリクエストボディ
+type SwitchRequest struct {
+	Game   game.Game       `json:"game"`
+	Config game.GameConfig `json:"config"`
+	Locale string          `json:"locale,omitempty"` // 結果の文言とエラーメッセージの言語（"ja", "en"）。省略時は Accept-Language に従います
+}
+
+// SwitchHandler は Switcher の Switch を呼び出すハンドラを返します。
+func SwitchHandler(gameSvc services.Switcher) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		var req SwitchRequest
+		if err := decodeJSON(w, r, &req); err != nil {
+			writeError(w, r, err)
+			return
+		}
+		r = withLocale(r, req.Locale)
+
+		g := req.Game
+		if err := gameSvc.Switch(&g, &req.Config); err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		i18n.Localize(&g, requestLang(r))
+		json.NewEncoder(w).Encode(g)
+	}
+}
@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"blackjack/api/game"
+	"blackjack/api/services"
+)
+
+// mockSwitchService は2つの手の2枚目を入れ替えるだけのモック
+type mockSwitchService struct {
+	err error
+}
+
+func (m mockSwitchService) Switch(g *game.Game, config *game.GameConfig) error {
+	if m.err != nil {
+		return m.err
+	}
+	h := &g.Switch.Hands
+	h[0].Cards[1], h[1].Cards[1] = h[1].Cards[1], h[0].Cards[1]
+	g.Switch.Switched = true
+	return nil
+}
+
+func TestSwitchHandler(t *testing.T) {
+	hand := func(a, b game.Rank) game.Hand {
+		cards := []game.Card{{Suit: game.Spade, Rank: a}, {Suit: game.Heart, Rank: b}}
+		return game.Hand{Cards: cards, Score: game.CalculateScore(cards)}
+	}
+	g := game.Game{
+		PlayerHand: hand("10", "5"),
+		DealerHand: game.Hand{Cards: []game.Card{{Suit: game.Club, Rank: "6"}}, Score: 6},
+		State:      game.PlayerTurn,
+		Result:     game.Pending,
+		Bet:        200,
+		Variant:    game.VariantSwitch,
+		Switch:     &game.SwitchHands{Hands: [2]game.Hand{hand("10", "5"), hand("6", "K")}, HandBet: 100},
+	}
+	body, _ := json.Marshal(SwitchRequest{Game: g, Config: game.GameConfig{DealerStandThreshold: 17, Variant: game.VariantSwitch}})
+
+	rr := httptest.NewRecorder()
+	SwitchHandler(mockSwitchService{}).ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/api/game/switch", bytes.NewReader(body)))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, rr.Code)
+	}
+	var got game.Game
+	json.Unmarshal(rr.Body.Bytes(), &got)
+	if !got.Switch.Switched || got.Switch.Hands[0].Cards[1].Rank != "K" {
+		t.Fatalf("expected switched hands, got %+v", got.Switch)
+	}
+
+	rr = httptest.NewRecorder()
+	SwitchHandler(mockSwitchService{err: services.ErrSwitchNotAllowed}).ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/api/game/switch", bytes.NewReader(body)))
+	if rr.Code != http.StatusConflict {
+		t.Fatalf("expected status %d, got %d", http.StatusConflict, rr.Code)
+	}
+}
@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"blackjack/api/game"
+	"blackjack/api/table"
+
+	"github.com/gorilla/mux"
+)
+
+// CreateTableRequest はテーブル作成時のリクエストボディ
+type CreateTableRequest struct {
+	Config game.GameConfig `json:"config"` // 省略時はサーバーの既定のルール
+}
+
+// CreateTableHandler は新しいマルチプレイヤーテーブルを作成し、そのスナップショットを返すハンドラ
+func CreateTableHandler(manager *table.Manager) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		var req CreateTableRequest
+		if err := decodeJSON(w, r, &req); err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		t, err := manager.Create(tableConfigFor(r, req.Config))
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		json.NewEncoder(w).Encode(t.Snapshot())
+	}
+}
+
+// GetTableHandler はテーブルのスナップショットを返すハンドラ
+func GetTableHandler(manager *table.Manager) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		t, ok := manager.Get(mux.Vars(r)["id"])
+		if !ok {
+			writeError(w, r, errTableNotFound)
+			return
+		}
+
+		json.NewEncoder(w).Encode(t.Snapshot())
+	}
+}
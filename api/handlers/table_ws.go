@@ -0,0 +1,271 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"blackjack/api/i18n"
+	"blackjack/api/services"
+	"blackjack/api/table"
+
+	"github.com/gorilla/mux"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	// 1 メッセージの書き込みにかけてよい時間
+	socketWriteWait = 10 * time.Second
+	// クライアントからの pong を待つ時間。これを過ぎると切断する
+	socketPongWait = 60 * time.Second
+	// ping の送信間隔（pong 待ちより短くする）
+	socketPingPeriod = socketPongWait * 9 / 10
+	// クライアントから受け取るメッセージの最大サイズ
+	socketMaxMessageSize = 4096
+)
+
+// TableCommand はクライアントからソケット経由で送られてくる操作
+// action: join（seat は省略可）, leave, bet（bet 必須）, deal, hit, stand, surrender, new_round,
+// autopilot（enabled でオン・オフ）
+type TableCommand struct {
+	RequestID string `json:"request_id,omitempty"`
+	Action    string `json:"action"`
+	Seat      *int   `json:"seat,omitempty"`
+	Bet       int    `json:"bet,omitempty"`
+	Enabled   bool   `json:"enabled,omitempty"`
+}
+
+// メッセージの種類
+const (
+	MessageKindEvent    = "event"    // テーブルのイベント
+	MessageKindSnapshot = "snapshot" // 接続直後（または再送できない再接続時）のテーブル状態
+	MessageKindAck      = "ack"      // 操作の成功
+	MessageKindError    = "error"    // 操作の失敗
+)
+
+// TableMessage はサーバーからソケット経由で送るメッセージ
+type TableMessage struct {
+	Kind      string          `json:"kind"`
+	RequestID string          `json:"request_id,omitempty"`
+	Event     *table.Event    `json:"event,omitempty"`
+	Snapshot  *table.Snapshot `json:"snapshot,omitempty"`
+	Code      string          `json:"code,omitempty"`    // 失敗時のエラーコード（HTTP のエラーレスポンスと同じ）
+	Error     string          `json:"error,omitempty"`   // Accept-Language で翻訳したエラーメッセージ
+	Details   string          `json:"details,omitempty"` // 元のエラーの内容
+}
+
+// newTableUpgrader は CORS と同じ allowedOrigins（"*" は全てのオリジン）からの接続を受け付ける Upgrader を返します。
+// ブラウザ以外のクライアント（Origin ヘッダなし）と同じホストのページからの接続も受け付けます。
+func newTableUpgrader(allowedOrigins []string) *websocket.Upgrader {
+	allowAll := false
+	allowed := make(map[string]bool)
+	for _, origin := range allowedOrigins {
+		if origin == "*" {
+			allowAll = true
+		}
+		allowed[strings.TrimSuffix(origin, "/")] = true
+	}
+	return &websocket.Upgrader{
+		ReadBufferSize:  1024,
+		WriteBufferSize: 1024,
+		CheckOrigin: func(r *http.Request) bool {
+			origin := r.Header.Get("Origin")
+			if allowAll || origin == "" || allowed[origin] {
+				return true
+			}
+			u, err := url.Parse(origin)
+			return err == nil && strings.EqualFold(u.Host, r.Host)
+		},
+	}
+}
+
+// TableSocketHandler はテーブルのイベントを配信し、操作を受け付ける WebSocket ハンドラ
+// 操作するには認証が必要で、認証したプレイヤーとして着席・操作します（匿名の接続は観戦のみ）。
+// クエリ: access_token（Authorization ヘッダを付けられないブラウザ向け）, player_id（認証したプレイヤーの ID。省略可）,
+// last_seq（再接続時に最後に受け取ったイベント番号）, locale（結果の文言とエラーメッセージの言語。省略時は Accept-Language に従う）
+// allowedOrigins（CORS と同じ設定）にないオリジンのページからの接続は 403 で拒否します。
+func TableSocketHandler(manager *table.Manager, allowedOrigins []string) http.HandlerFunc {
+	return tableSocketHandler(manager, newTableUpgrader(allowedOrigins), func(m TableMessage) interface{} { return m })
+}
+
+// tableSocketHandler は API のバージョンに依らない WebSocket ハンドラの本体です。
+// view は送信するメッセージを API のバージョンの表現に変換します。
+func tableSocketHandler(manager *table.Manager, upgrader *websocket.Upgrader, view func(TableMessage) interface{}) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		t, ok := manager.Get(mux.Vars(r)["id"])
+		if !ok {
+			writeError(w, r, errTableNotFound)
+			return
+		}
+		lang := requestLang(withLocale(r, r.URL.Query().Get("locale")))
+		playerID, err := playerFor(r, r.URL.Query().Get("player_id"))
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		var lastSeq uint64
+		resume := false
+		if v := r.URL.Query().Get("last_seq"); v != "" {
+			n, err := strconv.ParseUint(v, 10, 64)
+			if err != nil {
+				writeError(w, r, invalidRequest("last_seq must be a non-negative integer"))
+				return
+			}
+			lastSeq, resume = n, true
+		}
+
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			// Upgrade が失敗時のレスポンスを書き込み済み
+			return
+		}
+		defer conn.Close()
+
+		sub := t.Subscribe(lastSeq, resume)
+		defer sub.Close()
+
+		// プレイヤーの接続・切断を記録する。同じプレイヤーの他のソケットが開いている間は切断扱いにせず、
+		// 最後のソケットが閉じてから猶予が切れると自動プレイになる
+		if playerID != "" {
+			t.Reconnect(playerID)
+			defer t.Disconnect(playerID)
+		}
+
+		replies := make(chan TableMessage, 16)
+		done := make(chan struct{})
+		go writeTableMessages(conn, sub, replies, done, lang, view)
+
+		conn.SetReadLimit(socketMaxMessageSize)
+		conn.SetReadDeadline(time.Now().Add(socketPongWait))
+		conn.SetPongHandler(func(string) error {
+			return conn.SetReadDeadline(time.Now().Add(socketPongWait))
+		})
+
+		for {
+			var cmd TableCommand
+			if err := conn.ReadJSON(&cmd); err != nil {
+				close(done)
+				return
+			}
+			reply := TableMessage{Kind: MessageKindAck, RequestID: cmd.RequestID}
+			if err := applyTableCommand(t, playerID, cmd); err != nil {
+				_, resp := newErrorResponse(err, lang)
+				reply.Kind = MessageKindError
+				reply.Code, reply.Error, reply.Details = resp.Code, resp.Message, resp.Details
+			}
+			select {
+			case replies <- reply:
+			case <-done:
+				return
+			}
+		}
+	}
+}
+
+// writeTableMessages はソケットへの唯一の書き込み役として、スナップショット・イベント・応答・ping を送ります。
+// 精算結果の文言は lang に翻訳し、view で API のバージョンの表現に変換して送ります。
+func writeTableMessages(conn *websocket.Conn, sub *table.Subscription, replies <-chan TableMessage, done chan struct{}, lang i18n.Lang, view func(TableMessage) interface{}) {
+	ticker := time.NewTicker(socketPingPeriod)
+	defer ticker.Stop()
+	// 書き込みに失敗したら読み込み側も止める
+	defer conn.Close()
+
+	write := func(m TableMessage) bool {
+		conn.SetWriteDeadline(time.Now().Add(socketWriteWait))
+		return conn.WriteJSON(view(m)) == nil
+	}
+
+	if sub.Snapshot != nil {
+		for i := range sub.Snapshot.Seats {
+			i18n.Localize(&sub.Snapshot.Seats[i].Game, lang)
+		}
+		if !write(TableMessage{Kind: MessageKindSnapshot, Snapshot: sub.Snapshot}) {
+			return
+		}
+	}
+	for i := range sub.Backlog {
+		e := localizeEvent(sub.Backlog[i], lang)
+		if !write(TableMessage{Kind: MessageKindEvent, Event: &e}) {
+			return
+		}
+	}
+
+	for {
+		select {
+		case e, ok := <-sub.C:
+			if !ok {
+				// サーバーの停止でテーブルが閉じられたか、配信が追いつかず購読が切られた。
+				// 後者ならクライアントは last_seq を付けて再接続する
+				closeMsg := websocket.FormatCloseMessage(websocket.CloseTryAgainLater, "resubscribe")
+				if sub.TableClosed() {
+					closeMsg = websocket.FormatCloseMessage(websocket.CloseGoingAway, "server shutting down")
+				}
+				conn.SetWriteDeadline(time.Now().Add(socketWriteWait))
+				conn.WriteMessage(websocket.CloseMessage, closeMsg)
+				return
+			}
+			e = localizeEvent(e, lang)
+			if !write(TableMessage{Kind: MessageKindEvent, Event: &e}) {
+				return
+			}
+		case m := <-replies:
+			if !write(m) {
+				return
+			}
+		case <-ticker.C:
+			conn.SetWriteDeadline(time.Now().Add(socketWriteWait))
+			if err := conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		case <-done:
+			return
+		}
+	}
+}
+
+// localizeEvent は精算イベントのゲームを lang に翻訳した写しに差し替えます（イベントのゲームは購読者間で共有される）。
+func localizeEvent(e table.Event, lang i18n.Lang) table.Event {
+	if e.Game != nil {
+		g := *e.Game
+		i18n.Localize(&g, lang)
+		e.Game = &g
+	}
+	return e
+}
+
+// applyTableCommand はクライアントの操作をテーブルに適用します。
+func applyTableCommand(t *table.Table, playerID string, cmd TableCommand) error {
+	if playerID == "" {
+		return fmt.Errorf("%w: player_id is required to act at a table", services.ErrPlayerIDRequired)
+	}
+	switch cmd.Action {
+	case "join":
+		seat := -1
+		if cmd.Seat != nil {
+			seat = *cmd.Seat
+		}
+		_, err := t.Join(playerID, seat)
+		return err
+	case "leave":
+		return t.Leave(playerID)
+	case "bet":
+		return t.PlaceBet(playerID, cmd.Bet)
+	case "deal":
+		return t.Deal()
+	case "hit":
+		return t.Hit(playerID)
+	case "stand":
+		return t.Stand(playerID)
+	case "surrender":
+		return t.Surrender(playerID)
+	case "new_round":
+		return t.NewRound()
+	case "autopilot":
+		return t.SetAutopilot(playerID, cmd.Enabled)
+	default:
+		return fmt.Errorf("%w: %s", services.ErrUnknownAction, cmd.Action)
+	}
+}
@@ -0,0 +1,166 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"blackjack/api/game"
+	"blackjack/api/table"
+
+	"github.com/gorilla/mux"
+	"github.com/gorilla/websocket"
+)
+
+// fixedDeck は常に同じカードを配るデッキ
+type fixedDeck struct {
+	card game.Card
+}
+
+func (d fixedDeck) Deal() game.Card { return d.card }
+
+func newTableServer(t *testing.T) (*httptest.Server, *table.Table) {
+	t.Helper()
+	manager := table.NewManager(func() game.Deck { return fixedDeck{card: game.Card{Suit: game.Spade, Rank: "9"}} }, table.Options{})
+	tbl, err := manager.Create(game.GameConfig{DealerStandThreshold: 17})
+	if err != nil {
+		t.Fatalf("create table: %v", err)
+	}
+	router := mux.NewRouter()
+	router.HandleFunc("/api/tables/{id}/ws", TableSocketHandler(manager, []string{"https://blackjack.example"}))
+	router.Use(Authenticate(testTokens))
+	srv := httptest.NewServer(router)
+	t.Cleanup(srv.Close)
+	return srv, tbl
+}
+
+func dialTable(t *testing.T, srv *httptest.Server, tableID, query string) *websocket.Conn {
+	t.Helper()
+	url := "ws" + strings.TrimPrefix(srv.URL, "http") + "/api/tables/" + tableID + "/ws?" + query
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func readMessage(t *testing.T, conn *websocket.Conn) TableMessage {
+	t.Helper()
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	var m TableMessage
+	if err := conn.ReadJSON(&m); err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	return m
+}
+
+// readUntil は条件を満たすメッセージが来るまで読み進めます。
+func readUntil(t *testing.T, conn *websocket.Conn, pred func(TableMessage) bool) TableMessage {
+	t.Helper()
+	for i := 0; i < 50; i++ {
+		if m := readMessage(t, conn); pred(m) {
+			return m
+		}
+	}
+	t.Fatalf("expected message not received")
+	return TableMessage{}
+}
+
+func TestTableSocket_BroadcastsEventsAndAcceptsActions(t *testing.T) {
+	srv, tbl := newTableServer(t)
+
+	player := dialTable(t, srv, tbl.ID(), "access_token="+tokenFor(t, "alice"))
+	spectator := dialTable(t, srv, tbl.ID(), "")
+
+	// 接続直後はスナップショット
+	if m := readMessage(t, player); m.Kind != MessageKindSnapshot || m.Snapshot.ID != tbl.ID() {
+		t.Fatalf("expected snapshot, got %+v", m)
+	}
+	if m := readMessage(t, spectator); m.Kind != MessageKindSnapshot {
+		t.Fatalf("expected snapshot, got %+v", m)
+	}
+
+	player.WriteJSON(TableCommand{RequestID: "1", Action: "join"})
+	ack := readUntil(t, player, func(m TableMessage) bool { return m.RequestID == "1" })
+	if ack.Kind != MessageKindAck {
+		t.Fatalf("expected ack, got %+v", ack)
+	}
+
+	// 観戦者にも着席イベントが届く
+	m := readUntil(t, spectator, func(m TableMessage) bool { return m.Kind == MessageKindEvent })
+	if m.Event.Type != table.EventPlayerJoined || m.Event.PlayerID != "alice" {
+		t.Fatalf("expected player_joined event, got %+v", m.Event)
+	}
+
+	// 観戦者（認証なし）は操作できない
+	spectator.WriteJSON(TableCommand{RequestID: "2", Action: "deal"})
+	if m := readUntil(t, spectator, func(m TableMessage) bool { return m.RequestID == "2" }); m.Kind != MessageKindError || m.Code != "player_id_required" {
+		t.Fatalf("expected error for spectator action, got %+v", m)
+	}
+
+	player.WriteJSON(TableCommand{RequestID: "3", Action: "bet", Bet: 100})
+	player.WriteJSON(TableCommand{RequestID: "4", Action: "deal"})
+	m = readUntil(t, spectator, func(m TableMessage) bool {
+		return m.Kind == MessageKindEvent && m.Event.Type == table.EventTurnChanged
+	})
+	if m.Event.Seat != 0 {
+		t.Fatalf("expected alice's turn, got seat %d", m.Event.Seat)
+	}
+}
+
+func TestTableSocket_ResumesFromLastSeq(t *testing.T) {
+	srv, tbl := newTableServer(t)
+
+	tbl.Join("alice", 0)
+	tbl.PlaceBet("alice", 100)
+	// 2 件目（bet_placed）まで受け取った体で再接続する
+	tbl.Deal()
+
+	conn := dialTable(t, srv, tbl.ID(), "access_token="+tokenFor(t, "alice")+"&last_seq="+strconv.Itoa(2))
+	m := readMessage(t, conn)
+	if m.Kind != MessageKindEvent || m.Event.Seq != 3 || m.Event.Type != table.EventRoundStarted {
+		t.Fatalf("expected replay to start at seq 3 (round_started), got %+v", m)
+	}
+
+	// 未来の番号を指定した場合はスナップショットから再開する
+	conn2 := dialTable(t, srv, tbl.ID(), "last_seq=9999")
+	if m := readMessage(t, conn2); m.Kind != MessageKindSnapshot {
+		t.Fatalf("expected snapshot for unknown seq, got %+v", m)
+	}
+}
+
+func TestTableSocket_ChecksOrigin(t *testing.T) {
+	srv, tbl := newTableServer(t)
+	url := "ws" + strings.TrimPrefix(srv.URL, "http") + "/api/tables/" + tbl.ID() + "/ws"
+
+	// 許可していないオリジンのページからは接続できない
+	_, resp, err := websocket.DefaultDialer.Dial(url, http.Header{"Origin": {"https://evil.example"}})
+	if err == nil || resp == nil || resp.StatusCode != http.StatusForbidden {
+		t.Fatalf("expected 403 for a foreign origin, got %v, %v", resp, err)
+	}
+	for _, origin := range []string{"https://blackjack.example", srv.URL} {
+		conn, _, err := websocket.DefaultDialer.Dial(url, http.Header{"Origin": {origin}})
+		if err != nil {
+			t.Fatalf("origin %s: %v", origin, err)
+		}
+		conn.Close()
+	}
+}
+
+func TestTableSocket_ClosesWhenTableCloses(t *testing.T) {
+	srv, tbl := newTableServer(t)
+	conn := dialTable(t, srv, tbl.ID(), "")
+	readUntil(t, conn, func(m TableMessage) bool { return m.Kind == MessageKindSnapshot })
+
+	// サーバーの停止でテーブルを閉じたら、ソケットも閉じる
+	tbl.Close()
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	_, _, err := conn.ReadMessage()
+	if !websocket.IsCloseError(err, websocket.CloseGoingAway) {
+		t.Fatalf("expected a going away close, got %v", err)
+	}
+}
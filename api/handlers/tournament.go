@@ -0,0 +1,176 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"blackjack/api/game"
+	"blackjack/api/i18n"
+	"blackjack/api/services"
+	"blackjack/api/tournament"
+
+	"github.com/gorilla/mux"
+)
+
+// TournamentRegisterRequest はトーナメントへの参加登録のリクエストボディ
+// 参加登録とハンドの操作には認証が必要で、認証したプレイヤーとして行います。
+type TournamentRegisterRequest struct {
+	PlayerID string `json:"player_id,omitempty"` // 認証したプレイヤーの ID（省略可）
+}
+
+// TournamentPlayRequest はトーナメント内のハンドを進めるリクエストボディ
+// action: bet（bet 必須）, hit, stand, surrender, switch（ブラックジャック・スイッチのみ）
+type TournamentPlayRequest struct {
+	PlayerID string `json:"player_id,omitempty"` // 認証したプレイヤーの ID（省略可）
+	Action   string `json:"action"`
+	Bet      int    `json:"bet,omitempty"`
+	Locale   string `json:"locale,omitempty"` // 結果の文言とエラーメッセージの言語（"ja", "en"）。省略時は Accept-Language に従います
+}
+
+// TournamentPlayResponse は操作後のハンドとトーナメントの状態
+type TournamentPlayResponse struct {
+	Game       game.Game             `json:"game"`
+	Tournament tournament.Tournament `json:"tournament"`
+}
+
+// CreateTournamentHandler は参加受付中のトーナメントを作成するハンドラ
+// 作成には認証が必要で、認証したプレイヤーだけがトーナメントを開始できます。
+func CreateTournamentHandler(svc tournament.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		var config tournament.Config
+		if err := decodeJSON(w, r, &config); err != nil {
+			writeError(w, r, err)
+			return
+		}
+		creator, err := requirePlayer(r, "")
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		t, err := svc.Create(creator, config)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		json.NewEncoder(w).Encode(t)
+	}
+}
+
+// GetTournamentHandler はトーナメントの状態と順位表（終了後は結果）を返すハンドラ
+func GetTournamentHandler(svc tournament.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		t, err := svc.Get(mux.Vars(r)["id"])
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		json.NewEncoder(w).Encode(t)
+	}
+}
+
+// RegisterTournamentHandler はプレイヤーをトーナメントに参加登録するハンドラ
+func RegisterTournamentHandler(svc tournament.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		var req TournamentRegisterRequest
+		if err := decodeJSON(w, r, &req); err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		playerID, err := requirePlayer(r, req.PlayerID)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		t, err := svc.Register(mux.Vars(r)["id"], playerID)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		json.NewEncoder(w).Encode(t)
+	}
+}
+
+// StartTournamentHandler は参加受付を締め切って最初のラウンドを開始するハンドラ（トーナメントを作成したプレイヤーのみ）
+func StartTournamentHandler(svc tournament.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		playerID, err := requirePlayer(r, "")
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		t, err := svc.Start(mux.Vars(r)["id"], playerID)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		json.NewEncoder(w).Encode(t)
+	}
+}
+
+// PlayTournamentHandler はトーナメント内のハンドを 1 手進めるハンドラ
+func PlayTournamentHandler(svc tournament.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		var req TournamentPlayRequest
+		if err := decodeJSON(w, r, &req); err != nil {
+			writeError(w, r, err)
+			return
+		}
+		r = withLocale(r, req.Locale)
+
+		g, t, err := playTournament(svc, r, req)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		i18n.Localize(&g, requestLang(r))
+		json.NewEncoder(w).Encode(TournamentPlayResponse{Game: g, Tournament: t})
+	}
+}
+
+// playTournament はリクエストの操作で、認証したプレイヤーのトーナメント内のハンドを進め、ハンドと操作後のトーナメントを返します。
+func playTournament(svc tournament.Service, r *http.Request, req TournamentPlayRequest) (game.Game, tournament.Tournament, error) {
+	playerID, err := requirePlayer(r, req.PlayerID)
+	if err != nil {
+		return game.Game{}, tournament.Tournament{}, err
+	}
+	id := mux.Vars(r)["id"]
+	var g game.Game
+	switch req.Action {
+	case "bet":
+		g, err = svc.Bet(id, playerID, req.Bet)
+	case "hit":
+		g, err = svc.Hit(id, playerID)
+	case "stand":
+		g, err = svc.Stand(id, playerID)
+	case "surrender":
+		g, err = svc.Surrender(id, playerID)
+	case "switch":
+		g, err = svc.Switch(id, playerID)
+	default:
+		err = fmt.Errorf("%w: %s", services.ErrUnknownAction, req.Action)
+	}
+	if err != nil {
+		return game.Game{}, tournament.Tournament{}, err
+	}
+
+	t, err := svc.Get(id)
+	if err != nil {
+		return game.Game{}, tournament.Tournament{}, err
+	}
+	return g, t, nil
+}
@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"blackjack/api/game"
+	"blackjack/api/services"
+	"blackjack/api/tournament"
+
+	"github.com/gorilla/mux"
+)
+
+func newTournamentRouter(deck game.Deck) *mux.Router {
+	svc := tournament.NewService(services.NewGameService(deck))
+	router := mux.NewRouter()
+	router.HandleFunc("/api/tournaments", CreateTournamentHandler(svc)).Methods("POST")
+	router.HandleFunc("/api/tournaments/{id}", GetTournamentHandler(svc)).Methods("GET")
+	router.HandleFunc("/api/tournaments/{id}/register", RegisterTournamentHandler(svc)).Methods("POST")
+	router.HandleFunc("/api/tournaments/{id}/start", StartTournamentHandler(svc)).Methods("POST")
+	router.HandleFunc("/api/tournaments/{id}/play", PlayTournamentHandler(svc)).Methods("POST")
+	router.Use(Authenticate(testTokens))
+	return router
+}
+
+func doJSON(t *testing.T, h http.Handler, method, path string, body interface{}) *httptest.ResponseRecorder {
+	t.Helper()
+	return doJSONAs(t, h, "", method, path, body)
+}
+
+// doJSONAs は playerID のトークンを付けてリクエストを送ります（playerID が空なら匿名）。
+func doJSONAs(t *testing.T, h http.Handler, playerID, method, path string, body interface{}) *httptest.ResponseRecorder {
+	t.Helper()
+	var buf bytes.Buffer
+	if body != nil {
+		json.NewEncoder(&buf).Encode(body)
+	}
+	req := httptest.NewRequest(method, path, &buf)
+	if playerID != "" {
+		req.Header.Set("Authorization", "Bearer "+tokenFor(t, playerID))
+	}
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, req)
+	return rr
+}
+
+func TestTournamentHandlers_RegisterPlayAndView(t *testing.T) {
+	// 全てのカードが 9: プレイヤー 18、ディーラー 9+9=18 で引き分け
+	router := newTournamentRouter(fixedDeck{card: game.Card{Suit: game.Spade, Rank: "9"}})
+
+	rr := doJSONAs(t, router, "host", http.MethodPost, "/api/tournaments", tournament.Config{
+		StartingChips: 1000, HandsPerRound: 1, MinBet: 100, FinalTable: 2,
+		Game: game.GameConfig{DealerStandThreshold: 17},
+	})
+	if rr.Code != http.StatusOK {
+		t.Fatalf("create: expected status %d, got %d: %s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+	var created tournament.Tournament
+	json.Unmarshal(rr.Body.Bytes(), &created)
+	base := "/api/tournaments/" + created.ID
+
+	for _, p := range []string{"alice", "bob"} {
+		if rr := doJSONAs(t, router, p, http.MethodPost, base+"/register", TournamentRegisterRequest{}); rr.Code != http.StatusOK {
+			t.Fatalf("register %s: got %d: %s", p, rr.Code, rr.Body.String())
+		}
+	}
+	if rr := doJSONAs(t, router, "alice", http.MethodPost, base+"/start", nil); rr.Code != http.StatusForbidden {
+		t.Fatalf("start by a player who did not create it: expected status %d, got %d", http.StatusForbidden, rr.Code)
+	}
+	if rr := doJSONAs(t, router, "host", http.MethodPost, base+"/start", nil); rr.Code != http.StatusOK {
+		t.Fatalf("start: got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	// 他のプレイヤーとしては操作できない
+	rr = doJSONAs(t, router, "bob", http.MethodPost, base+"/play", TournamentPlayRequest{PlayerID: "alice", Action: "bet", Bet: 100})
+	if rr.Code != http.StatusForbidden {
+		t.Fatalf("expected status %d for another player's bet, got %d", http.StatusForbidden, rr.Code)
+	}
+
+	rr = doJSONAs(t, router, "alice", http.MethodPost, base+"/play", TournamentPlayRequest{PlayerID: "alice", Action: "bet", Bet: 100})
+	if rr.Code != http.StatusOK {
+		t.Fatalf("bet: got %d: %s", rr.Code, rr.Body.String())
+	}
+	var play TournamentPlayResponse
+	json.Unmarshal(rr.Body.Bytes(), &play)
+	if play.Game.Bet != 100 || play.Tournament.Standings[1].Chips != 900 || !play.Tournament.Standings[1].InHand {
+		t.Fatalf("expected alice's bet to be taken from her chips, got %+v", play)
+	}
+
+	rr = doJSONAs(t, router, "alice", http.MethodPost, base+"/play", TournamentPlayRequest{Action: "fold"})
+	if rr.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("expected status %d for unknown action, got %d", http.StatusUnprocessableEntity, rr.Code)
+	}
+
+	rr = doJSON(t, router, http.MethodGet, base, nil)
+	var view tournament.Tournament
+	json.Unmarshal(rr.Body.Bytes(), &view)
+	if view.Status != tournament.Running || view.Round != 1 || len(view.Standings) != 2 {
+		t.Fatalf("unexpected tournament view: %+v", view)
+	}
+
+	if rr := doJSON(t, router, http.MethodGet, "/api/tournaments/missing", nil); rr.Code != http.StatusNotFound {
+		t.Fatalf("expected status %d, got %d", http.StatusNotFound, rr.Code)
+	}
+}
@@ -0,0 +1,172 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"blackjack/api/game"
+	"blackjack/api/services"
+
+	"github.com/gorilla/mux"
+)
+
+// TrainerStartRequest はドリルセッション開始時のリクエストボディ
+type TrainerStartRequest struct {
+	Config   game.GameConfig `json:"config"`
+	PlayerID string          `json:"player_id,omitempty"` // 認証したプレイヤーの ID（省略可）。認証していれば回答結果をランキングに記録します
+}
+
+// TrainerStartResponse は開始したドリルセッションの ID を返す
+type TrainerStartResponse struct {
+	SessionID string `json:"session_id"`
+}
+
+// TrainerSpotResponse はドリルの1問
+type TrainerSpotResponse struct {
+	SpotID       string          `json:"spot_id"`
+	PlayerCards  []game.Card     `json:"player_cards"`
+	DealerUpcard game.Card       `json:"dealer_upcard"`
+	Config       game.GameConfig `json:"config"`
+}
+
+// TrainerAnswerRequest はドリルへの回答（answer は H/S/R のいずれか）
+type TrainerAnswerRequest struct {
+	SpotID string                 `json:"spot_id"`
+	Answer services.TrainerAnswer `json:"answer"`
+}
+
+// TrainerAnswerResponse は回答の採点結果
+type TrainerAnswerResponse struct {
+	Correct       bool                     `json:"correct"`
+	Answer        services.TrainerAnswer   `json:"answer"`
+	CorrectAnswer services.TrainerAnswer   `json:"correct_answer"`
+	Category      services.TrainerCategory `json:"category"`
+	EVLoss        float64                  `json:"ev_loss"`
+	Stats         TrainerStatsResponse     `json:"stats"`
+}
+
+// TrainerStatsResponse はドリルセッションの成績
+type TrainerStatsResponse struct {
+	Total      int                                                   `json:"total"`
+	Correct    int                                                   `json:"correct"`
+	Accuracy   float64                                               `json:"accuracy"`
+	Categories map[services.TrainerCategory]CategoryAccuracyResponse `json:"categories"`
+}
+
+// CategoryAccuracyResponse は分類ごとの正答率
+type CategoryAccuracyResponse struct {
+	Total    int     `json:"total"`
+	Correct  int     `json:"correct"`
+	Accuracy float64 `json:"accuracy"`
+}
+
+// accuracy は total に対する correct の割合を返します（total が 0 なら 0）。
+func accuracy(correct, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(correct) / float64(total)
+}
+
+func toTrainerStatsResponse(st services.TrainerStats) TrainerStatsResponse {
+	cats := make(map[services.TrainerCategory]CategoryAccuracyResponse, len(st.Categories))
+	for k, v := range st.Categories {
+		cats[k] = CategoryAccuracyResponse{Total: v.Total, Correct: v.Correct, Accuracy: accuracy(v.Correct, v.Total)}
+	}
+	return TrainerStatsResponse{
+		Total:      st.Total,
+		Correct:    st.Correct,
+		Accuracy:   accuracy(st.Correct, st.Total),
+		Categories: cats,
+	}
+}
+
+// TrainerStartHandler はドリルセッションを開始するハンドラ
+func TrainerStartHandler(trainer services.Trainer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		var req TrainerStartRequest
+		if err := decodeJSON(w, r, &req); err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		playerID, err := playerFor(r, req.PlayerID)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		id, err := trainer.StartSession(playerID, req.Config)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		json.NewEncoder(w).Encode(TrainerStartResponse{SessionID: id})
+	}
+}
+
+// TrainerSpotHandler は次の問題を出題するハンドラ
+func TrainerSpotHandler(trainer services.Trainer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		spot, err := trainer.NextSpot(mux.Vars(r)["id"])
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		json.NewEncoder(w).Encode(TrainerSpotResponse{
+			SpotID:       spot.ID,
+			PlayerCards:  spot.PlayerCards,
+			DealerUpcard: spot.DealerUpcard,
+			Config:       spot.Config,
+		})
+	}
+}
+
+// TrainerAnswerHandler は回答を採点するハンドラ
+func TrainerAnswerHandler(trainer services.Trainer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		var req TrainerAnswerRequest
+		if err := decodeJSON(w, r, &req); err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		res, err := trainer.Answer(mux.Vars(r)["id"], req.SpotID, req.Answer)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		json.NewEncoder(w).Encode(TrainerAnswerResponse{
+			Correct:       res.Correct,
+			Answer:        res.Answer,
+			CorrectAnswer: res.CorrectAnswer,
+			Category:      res.Category,
+			EVLoss:        res.EVLoss,
+			Stats:         toTrainerStatsResponse(res.Stats),
+		})
+	}
+}
+
+// TrainerStatsHandler はドリルセッションの成績を返すハンドラ
+func TrainerStatsHandler(trainer services.Trainer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		st, err := trainer.Stats(mux.Vars(r)["id"])
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		json.NewEncoder(w).Encode(toTrainerStatsResponse(st))
+	}
+}
@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"blackjack/api/game"
+	"blackjack/api/services"
+
+	"github.com/gorilla/mux"
+)
+
+// mockTrainer は固定の問題と採点結果を返すモック
+type mockTrainer struct{}
+
+func (m mockTrainer) StartSession(playerID string, config game.GameConfig) (string, error) {
+	return "session-1", nil
+}
+
+func (m mockTrainer) NextSpot(sessionID string) (services.TrainerSpot, error) {
+	if sessionID != "session-1" {
+		return services.TrainerSpot{}, services.ErrSessionNotFound
+	}
+	return services.TrainerSpot{
+		ID:           "spot-1",
+		PlayerCards:  []game.Card{{Suit: game.Spade, Rank: "10"}, {Suit: game.Heart, Rank: "6"}},
+		DealerUpcard: game.Card{Suit: game.Club, Rank: "10"},
+	}, nil
+}
+
+func (m mockTrainer) Answer(sessionID, spotID string, answer services.TrainerAnswer) (services.TrainerResult, error) {
+	return services.TrainerResult{
+		Correct:       answer == services.AnswerSurrender,
+		Answer:        answer,
+		CorrectAnswer: services.AnswerSurrender,
+		Category:      services.CategorySurrender,
+		Stats: services.TrainerStats{
+			Total:      4,
+			Correct:    3,
+			Categories: map[services.TrainerCategory]services.CategoryAccuracy{services.CategorySurrender: {Total: 4, Correct: 3}},
+		},
+	}, nil
+}
+
+func (m mockTrainer) Stats(sessionID string) (services.TrainerStats, error) {
+	return services.TrainerStats{}, nil
+}
+
+func newTrainerRouter() *mux.Router {
+	router := mux.NewRouter()
+	router.HandleFunc("/api/trainer/sessions/{id}/spot", TrainerSpotHandler(mockTrainer{}))
+	router.HandleFunc("/api/trainer/sessions/{id}/answer", TrainerAnswerHandler(mockTrainer{}))
+	return router
+}
+
+func TestTrainerSpotHandler(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/api/trainer/sessions/session-1/spot", nil)
+	rr := httptest.NewRecorder()
+	newTrainerRouter().ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, rr.Code)
+	}
+	var resp TrainerSpotResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if resp.SpotID != "spot-1" || len(resp.PlayerCards) != 2 {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+
+	// 未知のセッションは 404
+	req = httptest.NewRequest(http.MethodPost, "/api/trainer/sessions/unknown/spot", nil)
+	rr = httptest.NewRecorder()
+	newTrainerRouter().ServeHTTP(rr, req)
+	if rr.Code != http.StatusNotFound {
+		t.Fatalf("expected status %d, got %d", http.StatusNotFound, rr.Code)
+	}
+}
+
+func TestTrainerAnswerHandler(t *testing.T) {
+	body, _ := json.Marshal(TrainerAnswerRequest{SpotID: "spot-1", Answer: services.AnswerSurrender})
+	req := httptest.NewRequest(http.MethodPost, "/api/trainer/sessions/session-1/answer", bytes.NewReader(body))
+	rr := httptest.NewRecorder()
+	newTrainerRouter().ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, rr.Code)
+	}
+	var resp TrainerAnswerResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if !resp.Correct || resp.Stats.Accuracy != 0.75 {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+	if got := resp.Stats.Categories[services.CategorySurrender]; got.Accuracy != 0.75 {
+		t.Fatalf("unexpected surrender accuracy: %+v", got)
+	}
+}
@@ -0,0 +1,267 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"blackjack/api/game"
+	"blackjack/api/i18n"
+	"blackjack/api/services"
+	"blackjack/api/strategy"
+	"blackjack/api/table"
+	"blackjack/api/tournament"
+
+	"github.com/gorilla/mux"
+)
+
+// v2 のハンドラは v1 と同じサービスを呼び、結果を GameV2 に変換して返します。
+// v1 と違い、ゲームはサーバーが ID で保持し（行動のリクエストは game_id で指定します）、決着したゲームを成績に記録します。
+// 戦略のアドバイスはリクエストの GameV2 を game.Game に戻して評価します。ゲームを含まないエンドポイントは v1 のハンドラをそのまま使います。
+
+// NewGameV2Handler は新規ゲームを開始してサーバーに保持し、GameV2 で返すハンドラです。リクエストは v1 の NewGameRequest と同じです。
+func NewGameV2Handler(gameSvc services.GameStarter, sideBets services.SideBetService, store services.GameStore, recorder services.GameRecorder) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		var req NewGameRequest
+		if err := decodeJSON(w, r, &req); err != nil {
+			writeError(w, r, err)
+			return
+		}
+		r = withLocale(r, req.Locale)
+		playerID, err := playerFor(r, req.PlayerID)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		g, err := startGame(gameSvc, sideBets, store, recorder, r, playerID, req)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		i18n.Localize(&g, requestLang(r))
+		json.NewEncoder(w).Encode(NewGameV2(g))
+	}
+}
+
+// actionV2Handler は v2 のヒット・スタンド・サレンダーのハンドラです。保持しているゲームに apply で行動を適用し、action として採点します。
+func actionV2Handler(apply func(*game.Game, *game.GameConfig) error, action strategy.Action, store services.GameStore, grader services.DecisionGrader, recorder services.GameRecorder) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		var req ActionRequestV2
+		if err := decodeJSON(w, r, &req); err != nil {
+			writeError(w, r, err)
+			return
+		}
+		r = withLocale(r, req.Locale)
+		playerID, err := playerFor(r, req.PlayerID)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		g, grade, err := playAction(store, grader, recorder, req.GameID, playerID, apply, action, req.Grade, req.SessionID)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		i18n.Localize(&g, requestLang(r))
+		json.NewEncoder(w).Encode(ActionResponseV2{GameV2: NewGameV2(g), Grade: grade})
+	}
+}
+
+// HitV2Handler は v2 のヒットのハンドラです。
+func HitV2Handler(gameSvc services.Hitter, store services.GameStore, grader services.DecisionGrader, recorder services.GameRecorder) http.HandlerFunc {
+	return actionV2Handler(gameSvc.Hit, strategy.ActionHit, store, grader, recorder)
+}
+
+// StandV2Handler は v2 のスタンドのハンドラです。
+func StandV2Handler(gameSvc services.Stander, store services.GameStore, grader services.DecisionGrader, recorder services.GameRecorder) http.HandlerFunc {
+	return actionV2Handler(gameSvc.Stand, strategy.ActionStand, store, grader, recorder)
+}
+
+// SurrenderV2Handler は v2 のサレンダーのハンドラです。
+func SurrenderV2Handler(gameSvc services.Surrenderer, store services.GameStore, grader services.DecisionGrader, recorder services.GameRecorder) http.HandlerFunc {
+	return actionV2Handler(gameSvc.Surrender, strategy.ActionSurrender, store, grader, recorder)
+}
+
+// StandStreamV2Handler は v2 のスタンドのストリーミングのハンドラです。
+// リクエストは ActionRequestV2 で、settlement イベントで ActionResponseV2 を送ります。
+func StandStreamV2Handler(gameSvc services.Stander, store services.GameStore, grader services.DecisionGrader, recorder services.GameRecorder, interval time.Duration) http.HandlerFunc {
+	decode := func(w http.ResponseWriter, r *http.Request) (standStreamRequest, error) {
+		var req ActionRequestV2
+		if err := decodeJSON(w, r, &req); err != nil {
+			return standStreamRequest{}, err
+		}
+		play := func(playerID string, stand func(*game.Game, *game.GameConfig) error) (game.Game, *GradeResponse, error) {
+			return playAction(store, grader, recorder, req.GameID, playerID, stand, strategy.ActionStand, req.Grade, req.SessionID)
+		}
+		return standStreamRequest{PlayerID: req.PlayerID, Locale: req.Locale, play: play}, nil
+	}
+	settlement := func(g game.Game, grade *GradeResponse) interface{} {
+		return ActionResponseV2{GameV2: NewGameV2(g), Grade: grade}
+	}
+	return standStreamHandler(gameSvc, interval, decode, settlement)
+}
+
+// SwitchV2Handler は v2 のスイッチのハンドラです。
+func SwitchV2Handler(gameSvc services.Switcher, store services.GameStore, recorder services.GameRecorder) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		var req SwitchRequestV2
+		if err := decodeJSON(w, r, &req); err != nil {
+			writeError(w, r, err)
+			return
+		}
+		r = withLocale(r, req.Locale)
+
+		playerID, err := playerFor(r, "")
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		g, _, err := playAction(store, nil, recorder, req.GameID, playerID, gameSvc.Switch, "", false, "")
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		i18n.Localize(&g, requestLang(r))
+		json.NewEncoder(w).Encode(NewGameV2(g))
+	}
+}
+
+// StrategyV2Handler は v2 の戦略アドバイスのハンドラです。レスポンスは v1 の StrategyResponse と同じです。
+func StrategyV2Handler(strategyAdvisor services.StrategyAdvisor) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		var req StrategyRequestV2
+		if err := decodeJSON(w, r, &req); err != nil {
+			writeError(w, r, err)
+			return
+		}
+		if err := validateStrategyConfig(req.Config); err != nil {
+			writeError(w, r, err)
+			return
+		}
+		g, err := req.Game.Game()
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		payouts, err := strategyAdvisor.Advise(g, &req.Config)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		json.NewEncoder(w).Encode(StrategyResponse{
+			HitPayout:       payouts.HitPayout,
+			StandPayout:     payouts.StandPayout,
+			SurrenderPayout: payouts.SurrenderPayout,
+		})
+	}
+}
+
+// StrategyBatchV2Handler は v2 の一括の戦略アドバイスのハンドラです。レスポンスは v1 の StrategyBatchResponse と同じです。
+func StrategyBatchV2Handler(strategyAdvisor services.StrategyAdvisor) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		var req StrategyBatchRequestV2
+		if err := decodeJSON(w, r, &req); err != nil {
+			writeError(w, r, err)
+			return
+		}
+		if len(req.Positions) > maxBatchPositions {
+			writeError(w, r, invalidRequest(fmt.Sprintf("too many positions (max %d)", maxBatchPositions)))
+			return
+		}
+		// 局面 1 件ごとに 1 トークン使う（受け付けたときの 1 トークンに加えて）
+		if err := chargeRateLimit(r, len(req.Positions)-1); err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		positions := make([]services.AdvicePosition, len(req.Positions))
+		errs := make([]error, len(req.Positions))
+		for i, p := range req.Positions {
+			positions[i].Config = p.Config
+			positions[i].Game, errs[i] = p.Game.Game()
+		}
+		json.NewEncoder(w).Encode(adviseBatch(strategyAdvisor, positions, errs, requestLang(r)))
+	}
+}
+
+// PlayTournamentV2Handler は v2 のトーナメントのハンドを進めるハンドラです。リクエストは v1 と同じです。
+func PlayTournamentV2Handler(svc tournament.Service) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		var req TournamentPlayRequest
+		if err := decodeJSON(w, r, &req); err != nil {
+			writeError(w, r, err)
+			return
+		}
+		r = withLocale(r, req.Locale)
+
+		g, t, err := playTournament(svc, r, req)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		i18n.Localize(&g, requestLang(r))
+		json.NewEncoder(w).Encode(TournamentPlayResponseV2{Game: NewGameV2(g), Tournament: t})
+	}
+}
+
+// CreateTableV2Handler は v2 のテーブル作成のハンドラです。リクエストは v1 と同じです。
+func CreateTableV2Handler(manager *table.Manager) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		var req CreateTableRequest
+		if err := decodeJSON(w, r, &req); err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		t, err := manager.Create(tableConfigFor(r, req.Config))
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		json.NewEncoder(w).Encode(newTableSnapshotV2(t.Snapshot()))
+	}
+}
+
+// GetTableV2Handler は v2 のテーブルのスナップショットを返すハンドラです。
+func GetTableV2Handler(manager *table.Manager) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		t, ok := manager.Get(mux.Vars(r)["id"])
+		if !ok {
+			writeError(w, r, errTableNotFound)
+			return
+		}
+
+		json.NewEncoder(w).Encode(newTableSnapshotV2(t.Snapshot()))
+	}
+}
+
+// TableSocketV2Handler は v2 のテーブルの WebSocket ハンドラです。操作は v1 と同じで、メッセージを TableMessageV2 で送ります。
+func TableSocketV2Handler(manager *table.Manager, allowedOrigins []string) http.HandlerFunc {
+	return tableSocketHandler(manager, newTableUpgrader(allowedOrigins), func(m TableMessage) interface{} { return newTableMessageV2(m) })
+}
@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"blackjack/api/game"
+	"blackjack/api/services"
+)
+
+func TestNewGameV2Handler_KeepsGameOnServer(t *testing.T) {
+	g := game.Game{
+		PlayerHand: game.Hand{Cards: []game.Card{{Suit: game.Spade, Rank: "10"}, {Suit: game.Heart, Rank: "6"}}, Score: 16},
+		DealerHand: game.Hand{Cards: []game.Card{{Suit: game.Club, Rank: "9"}}, Score: 9},
+		Bet:        100,
+		State:      game.PlayerTurn,
+		Result:     game.Pending,
+	}
+	store := services.NewGameStore()
+	handler := NewGameV2Handler(mockGameService{expectedBet: 100, retGame: g}, defaultSideBets, store, nil)
+
+	body, _ := json.Marshal(NewGameRequest{Bet: 100, Config: &game.GameConfig{DealerStandThreshold: 16}})
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/api/v2/game/new", bytes.NewReader(body)))
+
+	var got GameV2
+	if err := json.Unmarshal(rr.Body.Bytes(), &got); err != nil || got.ID == "" {
+		t.Fatalf("expected a game with an id, got %s", rr.Body.String())
+	}
+	// 以後の行動は配ったときのゲームと設定で進める
+	stored, err := store.Get(got.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored.Game.PlayerHand.Score != 16 || stored.Config.DealerStandThreshold != 16 {
+		t.Fatalf("unexpected stored game: %+v", stored)
+	}
+
+	// 設定を省略したら既定の閾値で保持する
+	body, _ = json.Marshal(NewGameRequest{Bet: 100})
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/api/v2/game/new", bytes.NewReader(body)))
+	json.Unmarshal(rr.Body.Bytes(), &got)
+	if stored, err := store.Get(got.ID); err != nil || stored.Config.DealerStandThreshold != game.DefaultDealerStandThreshold {
+		t.Fatalf("expected the default threshold, got %+v, %v", stored.Config, err)
+	}
+}
+
+func TestHitV2Handler_RejectsUnknownGame(t *testing.T) {
+	handler := HitV2Handler(mockHitService{}, services.NewGameStore(), nil, nil)
+	for _, tc := range []struct {
+		gameID string
+		want   int
+	}{
+		{"", http.StatusBadRequest},
+		{"unknown", http.StatusNotFound},
+	} {
+		body, _ := json.Marshal(ActionRequestV2{GameID: tc.gameID})
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/api/v2/game/hit", bytes.NewReader(body)))
+		if rr.Code != tc.want {
+			t.Errorf("game_id %q: expected status %d, got %d", tc.gameID, tc.want, rr.Code)
+		}
+	}
+}
+
+func TestHitV2Handler_RejectsOtherPlayersGame(t *testing.T) {
+	store := services.NewGameStore()
+	g := game.Game{Bet: 100, State: game.PlayerTurn, Result: game.Pending}
+	id := storeGame(t, store, "alice", g, game.GameConfig{DealerStandThreshold: 17})
+	handler := HitV2Handler(mockHitService{}, store, nil, nil)
+
+	// 他のプレイヤーと匿名のリクエストはゲームを始めたプレイヤーではない
+	for _, playerID := range []string{"bob", ""} {
+		body, _ := json.Marshal(ActionRequestV2{GameID: id})
+		req := httptest.NewRequest(http.MethodPost, "/api/v2/game/hit", bytes.NewReader(body))
+		if playerID != "" {
+			req = asPlayer(req, playerID)
+		}
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		if rr.Code != http.StatusForbidden {
+			t.Errorf("player %q: expected status %d, got %d", playerID, http.StatusForbidden, rr.Code)
+		}
+	}
+	if stored, _ := store.Get(id); stored.Game.State != game.PlayerTurn {
+		t.Fatalf("expected alice's game to be unchanged, got %+v", stored.Game)
+	}
+
+	body, _ := json.Marshal(ActionRequestV2{GameID: id})
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, asPlayer(httptest.NewRequest(http.MethodPost, "/api/v2/game/hit", bytes.NewReader(body)), "alice"))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected alice to hit, got %d: %s", rr.Code, rr.Body.String())
+	}
+}
@@ -0,0 +1,139 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// API のバージョン
+// /api/v1/... と /api/v2/... は同じサービスを使い、ゲームの表現（v2 は GameV2）だけが異なります。
+const (
+	APIVersion1 = 1
+	APIVersion2 = 2
+	// DefaultAPIVersion はバージョンを指定しない /api/... のリクエストに使うバージョン（既存のクライアントのため v1）
+	DefaultAPIVersion = APIVersion1
+)
+
+// supportedVersions は提供中のバージョン
+var supportedVersions = []int{APIVersion1, APIVersion2}
+
+// VersionHeader はリクエストで希望するバージョンと、レスポンスで実際に使ったバージョンを示すヘッダ
+const VersionHeader = "API-Version"
+
+// errUnsupportedVersion は提供していないバージョンを要求されたことを表します。
+var errUnsupportedVersion = errors.New("unsupported API version")
+
+var (
+	// versionedPath はバージョンを含むパス（/api/ より後ろ）
+	versionedPath = regexp.MustCompile(`^v[0-9]+(/|$)`)
+	// versionMediaType は Accept でバージョンを指定するメディアタイプ（application/vnd.blackjack.v2+json）
+	versionMediaType = regexp.MustCompile(`^application/vnd\.blackjack\.v([0-9]+)\+json$`)
+)
+
+// NegotiateVersion はバージョンを含まない /api/... のリクエストを、要求されたバージョンの
+// /api/vN/... に振り分けるミドルウェアです。バージョンは API-Version ヘッダ（例: 2）、
+// Accept の application/vnd.blackjack.vN+json の順に調べ、どちらもなければ DefaultAPIVersion を使います。
+// 提供していないバージョンを要求された場合は 406 を返します。
+func NegotiateVersion(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rest, ok := strings.CutPrefix(r.URL.Path, "/api/")
+		if !ok || versionedPath.MatchString(rest) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		version, err := requestedVersion(r)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		w.Header().Add("Vary", VersionHeader+", Accept")
+
+		u := *r.URL
+		u.Path = fmt.Sprintf("/api/v%d/%s", version, rest)
+		u.RawPath = ""
+		versioned := r.Clone(r.Context())
+		versioned.URL = &u
+		next.ServeHTTP(w, versioned)
+	})
+}
+
+// requestedVersion はリクエストが要求するバージョンを返します。
+func requestedVersion(r *http.Request) (int, error) {
+	if v := strings.TrimSpace(r.Header.Get(VersionHeader)); v != "" {
+		n, err := strconv.Atoi(strings.TrimPrefix(strings.ToLower(v), "v"))
+		if err != nil || !isSupportedVersion(n) {
+			return 0, fmt.Errorf("%w: %s %q (supported: %s)", errUnsupportedVersion, VersionHeader, v, supportedVersionList())
+		}
+		return n, nil
+	}
+	for _, part := range strings.Split(r.Header.Get("Accept"), ",") {
+		mt, _, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		m := versionMediaType.FindStringSubmatch(mt)
+		if m == nil {
+			continue
+		}
+		n, _ := strconv.Atoi(m[1])
+		if !isSupportedVersion(n) {
+			return 0, fmt.Errorf("%w: Accept %q (supported: %s)", errUnsupportedVersion, mt, supportedVersionList())
+		}
+		return n, nil
+	}
+	return DefaultAPIVersion, nil
+}
+
+func isSupportedVersion(n int) bool {
+	for _, v := range supportedVersions {
+		if v == n {
+			return true
+		}
+	}
+	return false
+}
+
+func supportedVersionList() string {
+	s := make([]string, len(supportedVersions))
+	for i, v := range supportedVersions {
+		s[i] = strconv.Itoa(v)
+	}
+	return strings.Join(s, ", ")
+}
+
+// Version はレスポンスに API-Version ヘッダを付けるミドルウェアです（バージョンごとのサブルーターに適用する）。
+func Version(version int) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set(VersionHeader, strconv.Itoa(version))
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// Deprecated は非推奨のバージョンのレスポンスに Deprecation（RFC 9745）・Sunset（RFC 8594）と、
+// 後継のバージョンの同じエンドポイントを示す Link ヘッダを付けるミドルウェアです。
+func Deprecated(version, successor int, deprecatedAt, sunset time.Time) mux.MiddlewareFunc {
+	prefix := fmt.Sprintf("/api/v%d/", version)
+	successorPrefix := fmt.Sprintf("/api/v%d/", successor)
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			h := w.Header()
+			h.Set("Deprecation", "@"+strconv.FormatInt(deprecatedAt.Unix(), 10))
+			h.Set("Sunset", sunset.UTC().Format(http.TimeFormat))
+			if rest, ok := strings.CutPrefix(r.URL.Path, prefix); ok {
+				h.Add("Link", fmt.Sprintf(`<%s%s>; rel="successor-version"`, successorPrefix, rest))
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,130 @@
+package i18n
+
+import "blackjack/api/game"
+
+// catalog は言語ごとのメッセージ
+// キーは "error.<エラーコード>"、"result.<決着の理由>" の形式。日本語の決着の文言は game パッケージの既定の文言と同じ
+var catalog = map[Lang]map[string]string{
+	Japanese: {
+		"result.blackjack":        game.MessageBlackjackPlayerWin,
+		"result.player_bust":      game.MessagePlayerBustDealerWin,
+		"result.dealer_bust":      game.MessageDealerBustPlayerWin,
+		"result.player_win":       game.MessagePlayerWin,
+		"result.dealer_win":       game.MessageDealerWin,
+		"result.push":             game.MessagePush,
+		"result.surrender":        game.MessagePlayerSurrendered,
+		"result.bonus21":          game.MessageBonus21PlayerWin,
+		"result.charlie":          game.MessageCharliePlayerWin,
+		"result.dealer_blackjack": game.MessageDealerBlackjackDealerWin,
+		"result.tie_dealer_win":   game.MessageTieDealerWin,
+		"result.dealer22_push":    game.MessageDealer22Push,
+		"result.switch_settled":   game.MessageSwitchSettled,
+
+		"error.invalid_request":       "リクエストの形式が正しくありません",
+		"error.request_too_large":     "リクエストが大きすぎます",
+		"error.rate_limited":          "リクエストが多すぎます。しばらく待ってから再度お試しください",
+		"error.unauthenticated":       "ログインしてください",
+		"error.invalid_token":         "トークンが正しくないか、有効期限が切れています",
+		"error.invalid_credentials":   "名前またはパスワードが正しくありません",
+		"error.forbidden":             "他のプレイヤーとしては操作できません",
+		"error.unsupported_version":   "指定された API のバージョンには対応していません",
+		"error.not_found":             "対象が見つかりません",
+		"error.not_player_turn":       "プレイヤーの手番ではありません",
+		"error.game_finished":         "ゲームはすでに終了しています",
+		"error.name_taken":            "その名前はすでに使われています",
+		"error.surrender_not_allowed": "現在はサレンダーできません",
+		"error.switch_not_allowed":    "現在はスイッチできません",
+		"error.game_not_finished":     "ゲームがまだ終了していません",
+		"error.table_full":            "テーブルが満席です",
+		"error.seat_taken":            "その席はすでに使われています",
+		"error.not_seated":            "このテーブルに着席していません",
+		"error.already_seated":        "すでにこのテーブルに着席しています",
+		"error.wrong_phase":           "現在の進行状況ではその操作はできません",
+		"error.not_your_turn":         "あなたの手番ではありません",
+		"error.no_bets":               "ベットしている席がありません",
+		"error.not_registering":       "トーナメントは参加を受け付けていません",
+		"error.already_registered":    "すでに参加登録しています",
+		"error.not_registered":        "このトーナメントに参加登録していません",
+		"error.not_enough_players":    "開始するには2人以上の参加者が必要です",
+		"error.not_running":           "トーナメントは進行中ではありません",
+		"error.eliminated":            "すでに敗退しています",
+		"error.hand_in_progress":      "進行中のハンドがあります",
+		"error.no_hand_in_progress":   "進行中のハンドがありません",
+		"error.no_hands_left":         "このラウンドのハンドはすべてプレイ済みです",
+		"error.invalid_bet":           "掛け金は1以上にしてください",
+		"error.bet_out_of_range":      "掛け金がトーナメントの上限・下限の範囲外です",
+		"error.insufficient_chips":    "チップが足りません",
+		"error.invalid_config":        "設定が正しくありません",
+		"error.variant_mismatch":      "ゲームのルールと設定が一致しません",
+		"error.invalid_game_state":    "ゲームの状態が正しくありません",
+		"error.unknown_action":        "不明な操作です",
+		"error.invalid_side_bet":      "サイドベットが正しくありません",
+		"error.player_id_required":    "プレイヤーIDを指定してください",
+		"error.invalid_argument":      "入力値が正しくありません",
+		"error.invalid_seat":          "席番号が正しくありません",
+		"error.invalid_name":          "名前は3〜32文字の英数字・_・-で、guest- で始まらないものにしてください",
+		"error.invalid_password":      "パスワードは8〜72バイトにしてください",
+		"error.internal_error":        "サーバーでエラーが発生しました",
+	},
+	English: {
+		"result.blackjack":        "Blackjack! The player wins with a natural 21.",
+		"result.player_bust":      "The player busts. The dealer wins.",
+		"result.dealer_bust":      "The dealer busts. The player wins.",
+		"result.player_win":       "The player wins.",
+		"result.dealer_win":       "The dealer wins.",
+		"result.push":             "Push.",
+		"result.surrender":        "The player surrendered.",
+		"result.bonus21":          "Bonus 21! The player wins.",
+		"result.charlie":          "Charlie! The player wins without busting.",
+		"result.dealer_blackjack": "The dealer has blackjack. The dealer wins.",
+		"result.tie_dealer_win":   "Ties go to the dealer. The dealer wins.",
+		"result.dealer22_push":    "The dealer has 22. Push.",
+		"result.switch_settled":   "Both hands have been settled.",
+
+		"error.invalid_request":       "The request is malformed.",
+		"error.request_too_large":     "The request body is too large.",
+		"error.rate_limited":          "Too many requests. Please wait and try again.",
+		"error.unauthenticated":       "Please log in.",
+		"error.invalid_token":         "The token is invalid or has expired.",
+		"error.invalid_credentials":   "The name or password is incorrect.",
+		"error.forbidden":             "You cannot act as another player.",
+		"error.unsupported_version":   "The requested API version is not supported.",
+		"error.not_found":             "The requested resource was not found.",
+		"error.not_player_turn":       "It is not the player's turn.",
+		"error.game_finished":         "The game has already finished.",
+		"error.name_taken":            "That name is already taken.",
+		"error.surrender_not_allowed": "Surrender is not allowed right now.",
+		"error.switch_not_allowed":    "Switching is not allowed right now.",
+		"error.game_not_finished":     "The game has not finished yet.",
+		"error.table_full":            "The table is full.",
+		"error.seat_taken":            "That seat is already taken.",
+		"error.not_seated":            "You are not seated at this table.",
+		"error.already_seated":        "You are already seated at this table.",
+		"error.wrong_phase":           "That action is not allowed in the current phase.",
+		"error.not_your_turn":         "It is not your turn.",
+		"error.no_bets":               "No seat has placed a bet.",
+		"error.not_registering":       "The tournament is not accepting registrations.",
+		"error.already_registered":    "You are already registered.",
+		"error.not_registered":        "You are not registered in this tournament.",
+		"error.not_enough_players":    "At least 2 players are required to start.",
+		"error.not_running":           "The tournament is not running.",
+		"error.eliminated":            "You have been eliminated.",
+		"error.hand_in_progress":      "You already have a hand in progress.",
+		"error.no_hand_in_progress":   "You have no hand in progress.",
+		"error.no_hands_left":         "You have played all hands in this round.",
+		"error.invalid_bet":           "The bet must be positive.",
+		"error.bet_out_of_range":      "The bet is outside the tournament limits.",
+		"error.insufficient_chips":    "You do not have enough chips.",
+		"error.invalid_config":        "The configuration is invalid.",
+		"error.variant_mismatch":      "The game does not match the configured variant.",
+		"error.invalid_game_state":    "The game state is invalid.",
+		"error.unknown_action":        "Unknown action.",
+		"error.invalid_side_bet":      "The side bet is invalid.",
+		"error.player_id_required":    "A player ID is required.",
+		"error.invalid_argument":      "The input is invalid.",
+		"error.invalid_seat":          "The seat number is invalid.",
+		"error.invalid_name":          "Names must be 3-32 letters, digits, _ or - and must not start with guest-.",
+		"error.invalid_password":      "Passwords must be 8 to 72 bytes.",
+		"error.internal_error":        "An internal server error occurred.",
+	},
+}
@@ -0,0 +1,84 @@
+package i18n
+
+import (
+	"strconv"
+	"strings"
+
+	"blackjack/api/game"
+)
+
+// Lang はメッセージの言語を表します。
+type Lang string
+
+const (
+	Japanese Lang = "ja"
+	English  Lang = "en"
+
+	// Default は対応する言語が指定されなかったときの言語です。
+	Default = Japanese
+)
+
+// Supported は翻訳を用意している言語です。
+var Supported = []Lang{Japanese, English}
+
+// Parse は言語タグ（"en-US" など）を対応言語に変換します。対応していなければ false を返します。
+func Parse(tag string) (Lang, bool) {
+	primary := strings.ToLower(strings.TrimSpace(tag))
+	if i := strings.IndexAny(primary, "-_"); i >= 0 {
+		primary = primary[:i]
+	}
+	for _, l := range Supported {
+		if string(l) == primary {
+			return l, true
+		}
+	}
+	return "", false
+}
+
+// FromAcceptLanguage は Accept-Language ヘッダの q 値の順に、最初に対応している言語を返します。
+// 対応する言語がなければ Default を返します。q 値が同じ場合はヘッダ内の順序を優先します。
+func FromAcceptLanguage(header string) Lang {
+	best, bestQ := Default, 0.0
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(part, ";")
+		q := 1.0
+		for _, param := range fields[1:] {
+			name, value, ok := strings.Cut(strings.TrimSpace(param), "=")
+			if !ok || name != "q" {
+				continue
+			}
+			v, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				v = 0
+			}
+			q = v
+		}
+		if q <= bestQ {
+			continue
+		}
+		if l, ok := Parse(fields[0]); ok {
+			best, bestQ = l, q
+		}
+	}
+	return best
+}
+
+// Message は key のメッセージを lang で返します。
+// lang に訳がなければ Default の訳を、それもなければ key をそのまま返します。
+func Message(lang Lang, key string) string {
+	if msg, ok := catalog[lang][key]; ok {
+		return msg
+	}
+	if msg, ok := catalog[Default][key]; ok {
+		return msg
+	}
+	return key
+}
+
+// Localize は決着したゲームの ResultMessage を決着の理由から lang の文言に書き換えます。
+// 決着の理由がなければ何もしません。
+func Localize(g *game.Game, lang Lang) {
+	if g.ResultReason != "" {
+		g.ResultMessage = Message(lang, "result."+string(g.ResultReason))
+	}
+}
@@ -0,0 +1,84 @@
+package i18n
+
+import (
+	"testing"
+
+	"blackjack/api/game"
+)
+
+func TestFromAcceptLanguage(t *testing.T) {
+	cases := []struct {
+		header string
+		want   Lang
+	}{
+		{"", Japanese},
+		{"en-US,en;q=0.9", English},
+		{"fr-FR, en;q=0.5, ja;q=0.8", Japanese},
+		{"fr, de;q=0.9", Japanese},
+		{"ja;q=0, en-GB;q=0.3", English},
+		{"EN", English},
+	}
+	for _, c := range cases {
+		if got := FromAcceptLanguage(c.header); got != c.want {
+			t.Errorf("FromAcceptLanguage(%q) = %s, want %s", c.header, got, c.want)
+		}
+	}
+}
+
+func TestMessage_FallsBack(t *testing.T) {
+	if got := Message(English, "error.not_found"); got != "The requested resource was not found." {
+		t.Fatalf("unexpected english message: %s", got)
+	}
+	if got := Message("fr", "error.not_found"); got != catalog[Default]["error.not_found"] {
+		t.Fatalf("expected unsupported language to fall back to the default, got %s", got)
+	}
+	if got := Message(English, "missing.key"); got != "missing.key" {
+		t.Fatalf("expected unknown key to be returned as is, got %s", got)
+	}
+}
+
+// 全ての言語で同じキーが翻訳されていること
+func TestCatalog_Complete(t *testing.T) {
+	for key := range catalog[Default] {
+		for _, l := range Supported {
+			if _, ok := catalog[l][key]; !ok {
+				t.Errorf("missing %s translation for %s", l, key)
+			}
+		}
+	}
+}
+
+func TestLocalize(t *testing.T) {
+	g := game.Game{State: game.Finished, Result: game.DealerWin}
+	g.SetReason(game.ReasonPlayerBust)
+	if g.ResultMessage != game.MessagePlayerBustDealerWin {
+		t.Fatalf("expected the default japanese message, got %s", g.ResultMessage)
+	}
+	Localize(&g, English)
+	if g.ResultMessage != "The player busts. The dealer wins." || g.ResultReason != game.ReasonPlayerBust {
+		t.Fatalf("unexpected localized game: %+v", g)
+	}
+
+	pending := game.Game{State: game.PlayerTurn, Result: game.Pending}
+	Localize(&pending, English)
+	if pending.ResultMessage != "" {
+		t.Fatalf("expected pending games to stay without a message, got %s", pending.ResultMessage)
+	}
+}
+
+// 全ての決着の理由に翻訳があること
+func TestCatalog_CoversResultReasons(t *testing.T) {
+	reasons := []game.ResultReason{
+		game.ReasonBlackjack, game.ReasonPlayerBust, game.ReasonDealerBust, game.ReasonPlayerWin,
+		game.ReasonDealerWin, game.ReasonPush, game.ReasonSurrender, game.ReasonBonus21, game.ReasonCharlie,
+		game.ReasonDealerBlackjack, game.ReasonTieDealerWin, game.ReasonDealer22Push, game.ReasonSwitchSettled,
+	}
+	for _, r := range reasons {
+		if _, ok := catalog[Default]["result."+string(r)]; !ok {
+			t.Errorf("missing translation for result reason %s", r)
+		}
+		if catalog[Japanese]["result."+string(r)] != r.Message() {
+			t.Errorf("japanese catalog differs from the default message for %s", r)
+		}
+	}
+}
@@ -1,74 +1,390 @@
 package main
 
 import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
 	"log"
+	"net"
 	"net/http"
 	"os"
+	"os/signal"
+	"strings"
+	"sync/atomic"
+	"syscall"
+	"time"
 
+	"blackjack/api/auth"
+	"blackjack/api/config"
 	"blackjack/api/game"
+	"blackjack/api/grpcapi"
 	"blackjack/api/handlers"
+	"blackjack/api/ratelimit"
 	"blackjack/api/services"
+	"blackjack/api/table"
+	"blackjack/api/tournament"
 
 	"github.com/gorilla/mux"
+	"google.golang.org/grpc"
 )
 
-// CORSを許可するためのミドルウェア
-func corsMiddleware(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// 許可するオリジンを指定します。'*'は全てのオリジンを許可します。
-		// 本番環境では特定のドメイン（Next.jsアプリのURL）を指定することが推奨されます。
-		w.Header().Set("Access-Control-Allow-Origin", "*")
-		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
-
-		// OPTIONSリクエストの場合はここで処理を終了
-		if r.Method == "OPTIONS" {
-			w.WriteHeader(http.StatusOK)
-			return
+// corsMiddleware は allowedOrigins のオリジンからのクロスオリジンのリクエストを許可するミドルウェアを返します。
+// allowedOrigins に "*" があれば全てのオリジンを許可します。許可しないオリジンには CORS のヘッダを付けません。
+func corsMiddleware(allowedOrigins []string) func(http.Handler) http.Handler {
+	allowAll := false
+	allowed := make(map[string]bool)
+	for _, origin := range allowedOrigins {
+		if origin == "*" {
+			allowAll = true
 		}
+		allowed[strings.TrimSuffix(origin, "/")] = true
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			origin := r.Header.Get("Origin")
+			if allowAll {
+				w.Header().Set("Access-Control-Allow-Origin", "*")
+			} else {
+				// 許可するかどうかはオリジンによるので、キャッシュはオリジンごとに分ける
+				w.Header().Add("Vary", "Origin")
+				if allowed[origin] {
+					w.Header().Set("Access-Control-Allow-Origin", origin)
+				}
+			}
+			if w.Header().Get("Access-Control-Allow-Origin") != "" {
+				w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
+				w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, "+handlers.VersionHeader)
+				// バージョンと非推奨の通知をブラウザのクライアントからも読めるようにする
+				w.Header().Set("Access-Control-Expose-Headers", handlers.VersionHeader+", Deprecation, Sunset, Link, Retry-After")
+			}
+
+			// OPTIONSリクエストの場合はここで処理を終了
+			if r.Method == "OPTIONS" {
+				w.WriteHeader(http.StatusOK)
+				return
+			}
 
-		next.ServeHTTP(w, r)
-	})
+			next.ServeHTTP(w, r)
+		})
+	}
 }
 
 func main() {
-	// render.comが設定するPORT環境変数を取得。なければ8080を使う
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "8080"
+	// 設定は既定値・設定ファイル・環境変数・フラグの順に読み込み、誤りがあれば起動しない
+	cfg, err := config.Load(os.Args[1:], os.Getenv)
+	if err != nil {
+		log.Fatal(err)
 	}
+	if cfg.Auth.Secret == "" {
+		log.Println("AUTH_SECRET is not set; using a random secret (tokens will not survive a restart)")
+		cfg.Auth.Secret = randomSecret()
+	}
+
+	// SIGTERM（再デプロイ）と SIGINT で停止を始める
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	// gRPC サーバーは HTTP と並行して起動する。サービス・既定のルール・認証・レート制限は HTTP と共有する
+	deps := newDependencies(cfg)
+	grpcLis, err := net.Listen("tcp", ":"+cfg.GRPCPort)
+	if err != nil {
+		log.Fatal(err)
+	}
+	grpcServer := grpcapi.NewServer(deps.gameService, deps.strategyService, cfg.Game,
+		grpc.ChainUnaryInterceptor(grpcapi.Authenticate(deps.tokenService), grpcapi.RateLimit(deps.gameLimiter, deps.strategyLimiter)))
+	var grpcServing atomic.Bool
+	grpcServing.Store(true)
+	go func() {
+		log.Println("gRPC server starting on port " + cfg.GRPCPort)
+		if err := grpcServer.Serve(grpcLis); err != nil {
+			log.Println("gRPC server stopped:", err)
+		}
+		grpcServing.Store(false)
+	}()
+
+	// 停止中と gRPC サーバーの状態は準備状態（/ready）で返す
+	readiness := []handlers.ReadinessCheck{
+		{Name: "shutdown", Check: func(context.Context) error {
+			if ctx.Err() != nil {
+				return errors.New("server is shutting down")
+			}
+			return nil
+		}},
+		{Name: "grpc", Check: func(context.Context) error {
+			if !grpcServing.Load() {
+				return errors.New("gRPC server is not serving")
+			}
+			return nil
+		}},
+	}
+
+	lis, err := net.Listen("tcp", ":"+cfg.Port)
+	if err != nil {
+		log.Fatal(err)
+	}
+	// バージョンの振り分けと CORS のミドルウェアを適用したハンドラをタイムアウト付きのサーバーで動かす
+	srv := newServer(cfg, newHandler(cfg, deps, readiness...))
+
+	log.Println("Server starting on port " + cfg.Port)
+	grace := time.Duration(cfg.Timeouts.Shutdown)
+	if err := serveHTTP(ctx, srv, lis, grace); err != nil {
+		log.Println(err)
+	}
+	// Shutdown は WebSocket（ハイジャックした接続）を待たないので、テーブルを閉じてソケットとタイマーを止める
+	closeCtx, cancel := context.WithTimeout(context.Background(), grace)
+	if err := deps.tableManager.Close(closeCtx); err != nil {
+		log.Println("table sockets did not close in time:", err)
+	}
+	cancel()
+	stopGRPC(grpcServer, grace)
+	log.Println("Server stopped")
+}
+
+// newServer は設定のタイムアウトで handler を動かす HTTP サーバーを返します。
+func newServer(cfg config.Config, handler http.Handler) *http.Server {
+	return &http.Server{
+		Addr:              ":" + cfg.Port,
+		Handler:           handler,
+		ReadHeaderTimeout: time.Duration(cfg.Timeouts.HTTPReadHeader),
+		ReadTimeout:       time.Duration(cfg.Timeouts.HTTPRead),
+		WriteTimeout:      time.Duration(cfg.Timeouts.HTTPWrite),
+		IdleTimeout:       time.Duration(cfg.Timeouts.HTTPIdle),
+	}
+}
+
+// serveHTTP は ctx が終わるまで lis で srv を動かします。ctx が終わったら新しい接続の受け付けをやめ、
+// 処理中のリクエスト（ゲームの操作と成績の記録）が終わるのを最大 grace 待ってから戻ります。
+// 保存先はメモリなので、書き出す必要のあるデータはありません。
+// grace を過ぎても終わらない接続は切断します。WebSocket の接続はここでは閉じません（table.Manager の Close で閉じる）。
+func serveHTTP(ctx context.Context, srv *http.Server, lis net.Listener, grace time.Duration) error {
+	errc := make(chan error, 1)
+	go func() { errc <- srv.Serve(lis) }()
+
+	select {
+	case err := <-errc:
+		return err
+	case <-ctx.Done():
+	}
+
+	log.Println("Shutting down: draining in-flight requests")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), grace)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		srv.Close()
+		return fmt.Errorf("shutdown did not finish in %s: %w", grace, err)
+	}
+	if err := <-errc; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// stopGRPC は処理中の RPC が終わるのを最大 grace 待って gRPC サーバーを止めます。
+func stopGRPC(s *grpc.Server, grace time.Duration) {
+	stopped := make(chan struct{})
+	go func() {
+		s.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(grace):
+		s.Stop()
+	}
+}
 
+// v1 の非推奨の開始日と提供終了日（Deprecation / Sunset ヘッダで通知する）
+var (
+	v1DeprecatedAt = time.Date(2026, time.October, 19, 0, 0, 0, 0, time.UTC)
+	v1Sunset       = time.Date(2027, time.April, 30, 0, 0, 0, 0, time.UTC)
+)
+
+// readinessPosition は準備状態の確認で戦略を計算する局面（プレイヤー 16 対ディーラー 10）
+var readinessPosition = game.Game{
+	Bet:        1,
+	PlayerHand: game.Hand{Cards: []game.Card{{Suit: game.Spade, Rank: "10"}, {Suit: game.Heart, Rank: "6"}}},
+	DealerHand: game.Hand{Cards: []game.Card{{Suit: game.Club, Rank: "10"}, {Suit: game.Diamond, Rank: "7"}}},
+	State:      game.PlayerTurn,
+	Result:     game.Pending,
+}
+
+// randomSecret はトークンの署名に使うランダムな鍵を返します。
+func randomSecret() string {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		log.Fatal(err)
+	}
+	return hex.EncodeToString(b)
+}
+
+// dependencies は HTTP と gRPC のサーバーが共有する依存性と、停止の手順で閉じる依存性です。
+// 同じゲームサービス（シュー）と戦略の計算（メモ化）を使い、レート制限のバケットも共有します。
+type dependencies struct {
+	gameService     services.GameService
+	strategyService services.StrategyAdvisor
+	tokenService    auth.TokenService
+	gameLimiter     ratelimit.Limiter
+	strategyLimiter ratelimit.Limiter
+	tableManager    *table.Manager
+}
+
+// newDependencies は検証済みの cfg から共有する依存性を生成します（cfg.Auth.Secret は空でないこと）。
+func newDependencies(cfg config.Config) dependencies {
+	strategyService := services.NewStrategyService()
+	return dependencies{
+		gameService:     services.NewGameService(cfg.NewDeck()),
+		strategyService: strategyService,
+		tokenService:    auth.NewTokenService([]byte(cfg.Auth.Secret), time.Duration(cfg.Auth.TokenTTL)),
+		gameLimiter:     ratelimit.New(cfg.RateLimits.Game),
+		strategyLimiter: ratelimit.New(cfg.RateLimits.Strategy),
+		// テーブルの手番は一定時間で自動行動し、切断から一定時間で離席扱いにする
+		tableManager: table.NewManager(cfg.NewDeck, table.Options{
+			DecisionTimeout: time.Duration(cfg.Timeouts.TableDecision),
+			DisconnectGrace: time.Duration(cfg.Timeouts.TableDisconnectGrace),
+			Advisor:         strategyService,
+		}),
+	}
+}
+
+// newHandler はバージョンの振り分けと CORS を適用した、サーバー全体のハンドラを返します。
+func newHandler(cfg config.Config, deps dependencies, readiness ...handlers.ReadinessCheck) http.Handler {
+	return corsMiddleware(cfg.CORS.AllowedOrigins)(handlers.NegotiateVersion(newRouter(cfg, deps, readiness...)))
+}
+
+// newRouter は依存性を生成し、全てのエンドポイントを /api/v1 と /api/v2 に登録したルーターを返します。
+// gRPC サーバーと共有する依存性は deps を使います。
+// v1 と v2 は同じサービスを共有し、ゲームを含むエンドポイントだけ v2 では GameV2 の表現を使います。
+// 依存性は検証済みの cfg から生成します（cfg.Auth.Secret は空でないこと）。
+// 両方のバージョンで認証したプレイヤーと既定のルールをリクエストのコンテキストに記録し、
+// 状態を変える操作と戦略の計算にはレート制限をかけます（v1 と v2 で同じバケットを使う）。
+// 準備状態（/ready）では readiness とルーター自身の依存先（保存先と戦略の計算）を確かめます。
+// エンドポイントを追加・変更したら openapi/openapi.json も更新してください（main_test.go で検証します）。
+func newRouter(cfg config.Config, deps dependencies, readiness ...handlers.ReadinessCheck) *mux.Router {
 	// ルーターを作成
 	router := mux.NewRouter()
 
 	// 依存性の生成
-	gameService := services.NewGameService(&game.RandomDeck{})
-	strategyService := services.NewStrategyService()
+	gameService := deps.gameService
+	strategyService := deps.strategyService
+	riskOfRuinService := services.NewRiskOfRuinService()
+	sideBetService := services.NewSideBetService(cfg.SideBets)
+	decisionGrader := services.NewDecisionGrader(strategyService)
+	statsService := services.NewStatsService(strategyService)
+	leaderboardService := services.NewLeaderboardService()
+	trainerService := services.NewTrainerService(leaderboardService)
+	tableManager := deps.tableManager
+	tournamentService := tournament.NewService(gameService)
+	// 配ったゲームはサーバーが保持し、クライアントは ID で行動する（v1 と v2 で共有する）
+	gameStore := services.NewGameStore()
+	// 決着したゲームは成績とランキングの両方に記録する。ランキングにはクラシックをサーバーの既定のルールで遊んだゲームだけを載せる
+	gameRecorder := services.MultiRecorder(statsService, services.HouseRulesOnly(leaderboardService, cfg.Game))
+	// ディーラーのドローを SSE で配信する間隔
+	standStreamInterval := time.Duration(cfg.Timeouts.StandStreamInterval)
+	accountService := auth.NewAccountService(0)
+	tokenService := deps.tokenService
+	limitGame := handlers.RateLimit(deps.gameLimiter, cfg.TrustProxy)
+	limitStrategy := handlers.RateLimit(deps.strategyLimiter, cfg.TrustProxy)
+	limitAuth := handlers.RateLimit(ratelimit.New(cfg.RateLimits.Auth), cfg.TrustProxy)
+	defaultConfig := handlers.DefaultGameConfig(cfg.Game)
+	readiness = append(readiness[:len(readiness):len(readiness)],
+		// メモリの保存先は常に使える（設定の検証で memory 以外は受け付けない）
+		handlers.ReadinessCheck{Name: "storage", Check: func(context.Context) error { return nil }},
+		// 戦略の計算が既定のルールで動くことを確かめる（2 回目以降はメモ化された結果を返す）
+		handlers.ReadinessCheck{Name: "strategy", Check: func(context.Context) error {
+			_, err := strategyService.Advise(readinessPosition, &cfg.Game)
+			return err
+		}},
+	)
 
-	// ゲームエンドポイント
-	router.HandleFunc("/api/game/new", handlers.NewGameHandler(gameService)).Methods("POST")
+	v1 := router.PathPrefix("/api/v1").Subrouter()
+	v1.Use(handlers.Version(handlers.APIVersion1), handlers.Deprecated(handlers.APIVersion1, handlers.APIVersion2, v1DeprecatedAt, v1Sunset), handlers.Authenticate(tokenService), defaultConfig)
+	v2 := router.PathPrefix("/api/v2").Subrouter()
+	v2.Use(handlers.Version(handlers.APIVersion2), handlers.Authenticate(tokenService), defaultConfig)
 
+	// v1: ゲームを game.Game の表現で扱うエンドポイント（ゲームの状態はクライアントが持ち、成績には記録しない）
+	// ゲームエンドポイント
+	v1.Handle("/game/new", limitGame(handlers.NewGameHandler(gameService, sideBetService))).Methods("POST")
 	// ヒットエンドポイント
-	router.HandleFunc("/api/game/hit", handlers.HitHandler(gameService)).Methods("POST")
-
+	v1.Handle("/game/hit", limitGame(handlers.HitHandler(gameService, decisionGrader))).Methods("POST")
 	// スタンドエンドポイント
-	router.HandleFunc("/api/game/stand", handlers.StandHandler(gameService)).Methods("POST")
-
+	v1.Handle("/game/stand", limitGame(handlers.StandHandler(gameService, decisionGrader))).Methods("POST")
+	// スタンド（ディーラーのドローを SSE で 1 枚ずつ配信）エンドポイント
+	v1.Handle("/game/stand/stream", limitGame(handlers.StandStreamHandler(gameService, decisionGrader, standStreamInterval))).Methods("POST")
 	// サレンダーエンドポイント
-	router.HandleFunc("/api/game/surrender", handlers.SurrenderHandler(gameService)).Methods("POST")
+	v1.Handle("/game/surrender", limitGame(handlers.SurrenderHandler(gameService, decisionGrader))).Methods("POST")
+	// スイッチ（ブラックジャック・スイッチの2枚目の入れ替え）エンドポイント
+	v1.Handle("/game/switch", limitGame(handlers.SwitchHandler(gameService))).Methods("POST")
+	// マルチプレイヤーテーブルのエンドポイント（イベント配信と操作は WebSocket）
+	v1.Handle("/tables", limitGame(handlers.CreateTableHandler(tableManager))).Methods("POST")
+	v1.HandleFunc("/tables/{id}", handlers.GetTableHandler(tableManager)).Methods("GET")
+	v1.Handle("/tables/{id}/ws", limitGame(handlers.TableSocketHandler(tableManager, cfg.CORS.AllowedOrigins))).Methods("GET")
+	// トーナメントのハンドを進めるエンドポイント
+	v1.Handle("/tournaments/{id}/play", limitGame(handlers.PlayTournamentHandler(tournamentService))).Methods("POST")
+	// 戦略アドバイスエンドポイント
+	v1.Handle("/strategy/advise", limitStrategy(handlers.StrategyHandler(strategyService))).Methods("POST")
+	// 複数の局面をまとめて評価する戦略アドバイスエンドポイント
+	v1.Handle("/strategy/advise/batch", limitStrategy(handlers.StrategyBatchHandler(strategyService))).Methods("POST")
 
-	// ヘルスチェックエンドポイント
-	router.HandleFunc("/api/health", handlers.HealthHandler).Methods("GET")
+	// v2: 同じエンドポイントをゲームの GameV2 の表現で扱う（ゲームはサーバーが ID で保持し、決着したゲームを成績に記録する）
+	v2.Handle("/game/new", limitGame(handlers.NewGameV2Handler(gameService, sideBetService, gameStore, gameRecorder))).Methods("POST")
+	v2.Handle("/game/hit", limitGame(handlers.HitV2Handler(gameService, gameStore, decisionGrader, gameRecorder))).Methods("POST")
+	v2.Handle("/game/stand", limitGame(handlers.StandV2Handler(gameService, gameStore, decisionGrader, gameRecorder))).Methods("POST")
+	v2.Handle("/game/stand/stream", limitGame(handlers.StandStreamV2Handler(gameService, gameStore, decisionGrader, gameRecorder, standStreamInterval))).Methods("POST")
+	v2.Handle("/game/surrender", limitGame(handlers.SurrenderV2Handler(gameService, gameStore, decisionGrader, gameRecorder))).Methods("POST")
+	v2.Handle("/game/switch", limitGame(handlers.SwitchV2Handler(gameService, gameStore, gameRecorder))).Methods("POST")
+	v2.Handle("/tables", limitGame(handlers.CreateTableV2Handler(tableManager))).Methods("POST")
+	v2.HandleFunc("/tables/{id}", handlers.GetTableV2Handler(tableManager)).Methods("GET")
+	v2.Handle("/tables/{id}/ws", limitGame(handlers.TableSocketV2Handler(tableManager, cfg.CORS.AllowedOrigins))).Methods("GET")
+	v2.Handle("/tournaments/{id}/play", limitGame(handlers.PlayTournamentV2Handler(tournamentService))).Methods("POST")
+	v2.Handle("/strategy/advise", limitStrategy(handlers.StrategyV2Handler(strategyService))).Methods("POST")
+	v2.Handle("/strategy/advise/batch", limitStrategy(handlers.StrategyBatchV2Handler(strategyService))).Methods("POST")
 
-	// 戦略アドバイスエンドポイント
-	router.HandleFunc("/api/strategy/advise", handlers.StrategyHandler(strategyService)).Methods("POST")
+	// ゲームを含まないエンドポイントは両方のバージョンで同じ
+	for _, api := range []*mux.Router{v1, v2} {
+		// 登録・ログイン・ゲストのエンドポイント（トークンを発行する）
+		api.Handle("/auth/register", limitAuth(handlers.RegisterHandler(accountService, tokenService))).Methods("POST")
+		api.Handle("/auth/login", limitAuth(handlers.LoginHandler(accountService, tokenService))).Methods("POST")
+		api.Handle("/auth/guest", limitAuth(handlers.GuestHandler(accountService, tokenService))).Methods("POST")
+		api.HandleFunc("/auth/me", handlers.MeHandler).Methods("GET")
 
-	// ミドルウェアを適用したハンドラ
-	handlerWithCors := corsMiddleware(router)
+		// 採点セッション集計エンドポイント
+		api.HandleFunc("/grading/sessions/{id}", handlers.SessionMistakesHandler(decisionGrader)).Methods("GET")
 
-	log.Println("Server starting on port " + port)
-	// サーバーを起動
-	if err := http.ListenAndServe(":"+port, handlerWithCors); err != nil {
-		log.Fatal(err)
+		// ベーシックストラテジー・ドリルのエンドポイント
+		api.Handle("/trainer/sessions", limitGame(handlers.TrainerStartHandler(trainerService))).Methods("POST")
+		api.Handle("/trainer/sessions/{id}/spot", limitGame(handlers.TrainerSpotHandler(trainerService))).Methods("POST")
+		api.Handle("/trainer/sessions/{id}/answer", limitGame(handlers.TrainerAnswerHandler(trainerService))).Methods("POST")
+		api.HandleFunc("/trainer/sessions/{id}/stats", handlers.TrainerStatsHandler(trainerService)).Methods("GET")
+
+		// プレイヤー成績エンドポイント（本人のみ）
+		api.HandleFunc("/players/{id}/stats", handlers.PlayerStatsHandler(statsService)).Methods("GET")
+
+		// ランキングエンドポイント
+		api.HandleFunc("/leaderboard", handlers.LeaderboardHandler(leaderboardService)).Methods("GET")
+
+		// トーナメントのエンドポイント
+		api.Handle("/tournaments", limitGame(handlers.CreateTournamentHandler(tournamentService))).Methods("POST")
+		api.HandleFunc("/tournaments/{id}", handlers.GetTournamentHandler(tournamentService)).Methods("GET")
+		api.Handle("/tournaments/{id}/register", limitGame(handlers.RegisterTournamentHandler(tournamentService))).Methods("POST")
+		api.Handle("/tournaments/{id}/start", limitGame(handlers.StartTournamentHandler(tournamentService))).Methods("POST")
+
+		// ヘルスチェックエンドポイント
+		api.HandleFunc("/health", handlers.HealthHandler).Methods("GET")
+		// 準備状態（依存先の状態）エンドポイント。停止中や依存先の障害では 503
+		api.HandleFunc("/ready", handlers.ReadinessHandler(readiness)).Methods("GET")
+
+		// サイドベットのハウスエッジエンドポイント
+		api.Handle("/strategy/side-bets", limitStrategy(handlers.SideBetHouseEdgeHandler(sideBetService))).Methods("POST")
+
+		// 破産確率（リスク・オブ・ルイン）エンドポイント
+		api.Handle("/strategy/ror", limitStrategy(handlers.RiskOfRuinHandler(riskOfRuinService))).Methods("POST")
+
+		// OpenAPI ドキュメントのエンドポイント（両方のバージョンを記述した同じドキュメント）
+		api.HandleFunc("/openapi.json", handlers.OpenAPIHandler).Methods("GET")
 	}
+
+	return router
 }
@@ -0,0 +1,803 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"blackjack/api/auth"
+	"blackjack/api/config"
+	"blackjack/api/game"
+	"blackjack/api/handlers"
+	"blackjack/api/openapi"
+	"blackjack/api/ratelimit"
+	"blackjack/api/tournament"
+
+	"github.com/gorilla/mux"
+	"github.com/gorilla/websocket"
+)
+
+// testConfig はテストのサーバーの設定を返します。連続したリクエストはレート制限しません。
+func testConfig() config.Config {
+	cfg := config.Default()
+	cfg.Auth.Secret = "test secret for the api"
+	unlimited := ratelimit.Limit{Rate: 1000, Burst: 1000}
+	cfg.RateLimits = config.RateLimits{Game: unlimited, Strategy: unlimited, Auth: unlimited}
+	return cfg
+}
+
+// TestRouter_RoutesMatchOpenAPI はルーターのエンドポイントと OpenAPI ドキュメントの操作が一致することを確認します。
+func TestRouter_RoutesMatchOpenAPI(t *testing.T) {
+	spec, err := openapi.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var routes []string
+	err = newRouter(testConfig(), newDependencies(testConfig())).Walk(func(route *mux.Route, _ *mux.Router, _ []*mux.Route) error {
+		methods, err := route.GetMethods()
+		if err != nil {
+			// バージョンごとのサブルーター（PathPrefix）はエンドポイントではない
+			return nil
+		}
+		path, err := route.GetPathTemplate()
+		if err != nil {
+			return err
+		}
+		for _, m := range methods {
+			routes = append(routes, m+" "+path)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(routes)
+
+	if got, want := strings.Join(spec.Operations(), "\n"), strings.Join(routes, "\n"); got != want {
+		t.Errorf("OpenAPI operations do not match the router\ndocumented:\n%s\n\nrouted:\n%s", got, want)
+	}
+}
+
+// apiClient は OpenAPI の検証ミドルウェアを通したテストサーバーへのクライアントです。
+type apiClient struct {
+	t      *testing.T
+	server *httptest.Server
+	spec   *openapi.Spec
+	token  string // 空でなければ Authorization: Bearer で送る
+}
+
+// newAPIClient は全てのリクエストとレスポンスを OpenAPI ドキュメントで検証するテストサーバーを起動します。
+// ドキュメントに合わないリクエスト・レスポンスはテストの失敗になります。
+func newAPIClient(t *testing.T) *apiClient {
+	t.Helper()
+	return newAPIClientWithConfig(t, testConfig())
+}
+
+// newAPIClientWithConfig は newAPIClient と同じですが、サーバーの設定を cfg にします。
+func newAPIClientWithConfig(t *testing.T, cfg config.Config) *apiClient {
+	t.Helper()
+	spec, err := openapi.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	validate := openapi.Middleware(spec, func(r *http.Request, err error) {
+		t.Errorf("OpenAPI violation: %v", err)
+	})
+	// バージョンを含まないパスは振り分け後に検証する
+	server := httptest.NewServer(corsMiddleware(cfg.CORS.AllowedOrigins)(handlers.NegotiateVersion(validate(newRouter(cfg, newDependencies(cfg))))))
+	t.Cleanup(server.Close)
+	return &apiClient{t: t, server: server, spec: spec}
+}
+
+// as は name のプレイヤーを登録（登録済みならログイン）し、そのトークンを付けて送るクライアントを返します。
+func (c *apiClient) as(name string) *apiClient {
+	c.t.Helper()
+	req := handlers.AuthRequest{Name: name, Password: "password-" + name}
+	var resp handlers.AuthResponse
+	if status := c.do("POST", "/api/v2/auth/register", req, &resp); status == http.StatusConflict {
+		c.mustDo("POST", "/api/v2/auth/login", req, &resp)
+	} else if status != http.StatusOK {
+		c.t.Fatalf("register %s: status = %d, want 200", name, status)
+	}
+	player := *c
+	player.token = resp.Token
+	return &player
+}
+
+// wsURL は path の WebSocket の URL を返します（トークンは access_token クエリで送る）。
+func (c *apiClient) wsURL(path string) string {
+	url := "ws" + strings.TrimPrefix(c.server.URL, "http") + path
+	if c.token != "" {
+		url += "?access_token=" + c.token
+	}
+	return url
+}
+
+// do はリクエストを送り、ステータスコードを返してボディを out に読み込みます（out が nil なら読み捨てる）。
+func (c *apiClient) do(method, path string, body, out any) int {
+	c.t.Helper()
+	resp, data := c.send(method, path, body, nil)
+	if out != nil && resp.StatusCode == http.StatusOK && strings.HasPrefix(resp.Header.Get("Content-Type"), "application/json") {
+		if err := json.Unmarshal(data, out); err != nil {
+			c.t.Fatalf("%s %s: %v", method, path, err)
+		}
+	}
+	return resp.StatusCode
+}
+
+// send はヘッダを付けてリクエストを送り、レスポンスと読み込んだボディを返します。
+func (c *apiClient) send(method, path string, body any, header http.Header) (*http.Response, []byte) {
+	c.t.Helper()
+	var reader io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			c.t.Fatal(err)
+		}
+		reader = bytes.NewReader(b)
+	}
+	req, err := http.NewRequest(method, c.server.URL+path, reader)
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := c.server.Client().Do(req)
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	return resp, data
+}
+
+// mustDo は do と同じですが、200 以外ならテストを止めます。
+func (c *apiClient) mustDo(method, path string, body, out any) {
+	c.t.Helper()
+	if status := c.do(method, path, body, out); status != http.StatusOK {
+		c.t.Fatalf("%s %s: status = %d, want 200", method, path, status)
+	}
+}
+
+// playOut はゲームが決着するまでスタンドします（ハンドラのレスポンスは全てドキュメントで検証される）。
+func (c *apiClient) playOut(g game.Game, config game.GameConfig, playerID string) game.Game {
+	c.t.Helper()
+	if g.State != game.PlayerTurn {
+		return g
+	}
+	var resp handlers.ActionResponse
+	c.mustDo("POST", "/api/game/stand", handlers.StandRequest{Game: g, Config: config, PlayerID: playerID, SessionID: "s1", Grade: true}, &resp)
+	return resp.Game
+}
+
+func TestAPI_GameEndpointsConformToOpenAPI(t *testing.T) {
+	c := newAPIClient(t)
+	c.mustDo("GET", "/api/health", nil, nil)
+	c.mustDo("GET", "/api/openapi.json", nil, nil)
+
+	alice, bob := c.as("alice"), c.as("bob")
+	classic := game.GameConfig{DealerStandThreshold: 17}
+	for i := 0; i < 10; i++ {
+		var g game.Game
+		alice.mustDo("POST", "/api/game/new", handlers.NewGameRequest{
+			Bet: 10, PlayerID: "alice", SessionID: "s1", Locale: "en",
+			SideBets: &game.SideBets{PerfectPairs: 5, TwentyOnePlusThree: 5},
+		}, &g)
+		c.mustDo("POST", "/api/strategy/advise", handlers.StrategyRequest{Game: g, Config: classic}, nil)
+		c.mustDo("POST", "/api/strategy/advise/batch", handlers.StrategyBatchRequest{Positions: []handlers.StrategyRequest{{Game: g, Config: classic}, {Game: g}}}, nil)
+		if g.State == game.PlayerTurn {
+			var resp handlers.ActionResponse
+			alice.mustDo("POST", "/api/game/hit", handlers.HitRequest{Game: g, Config: classic, PlayerID: "alice", SessionID: "s1", Grade: true}, &resp)
+			g = resp.Game
+		}
+		g = alice.playOut(g, classic, "alice")
+		// 決着済みのゲームへの操作はエラーレスポンスになる
+		if status := c.do("POST", "/api/game/hit", handlers.HitRequest{Game: g, Config: classic}, nil); status != http.StatusConflict {
+			t.Errorf("hit on finished game: status = %d, want 409", status)
+		}
+	}
+
+	for i := 0; i < 5; i++ {
+		var g game.Game
+		c.mustDo("POST", "/api/game/new", handlers.NewGameRequest{Bet: 10, Config: &classic}, &g)
+		c.do("POST", "/api/game/surrender", handlers.SurrenderRequest{Game: g, Config: classic, Locale: "ja"}, nil)
+
+		c.mustDo("POST", "/api/game/new", handlers.NewGameRequest{Bet: 10, Config: &classic}, &g)
+		c.do("POST", "/api/game/stand/stream", handlers.StandRequest{Game: g, Config: classic}, nil)
+	}
+
+	variants := []game.GameConfig{
+		{DealerStandThreshold: 17, Variant: game.VariantSpanish21},
+		{DealerStandThreshold: 17, Variant: game.VariantDoubleExposure},
+		{DealerStandThreshold: 17, Variant: game.VariantSwitch},
+		{DealerStandThreshold: 17, CharlieCards: 5},
+	}
+	for _, config := range variants {
+		for i := 0; i < 5; i++ {
+			config := config
+			var g game.Game
+			bob.mustDo("POST", "/api/game/new", handlers.NewGameRequest{Bet: 10, Config: &config}, &g)
+			// 最初の手が 21 なら配った時点で 2 つ目の手に進み、もうスイッチできない
+			if slices.Contains(g.AllowedActions, game.ActionSwitch) {
+				bob.mustDo("POST", "/api/game/switch", handlers.SwitchRequest{Game: g, Config: config}, &g)
+			}
+			bob.playOut(g, config, "bob")
+		}
+	}
+
+	c.mustDo("GET", "/api/grading/sessions/s1", nil, nil)
+	// 成績は本人しか見られない
+	if status := alice.do("GET", "/api/players/bob/stats", nil, nil); status != http.StatusForbidden {
+		t.Errorf("another player's stats: status = %d, want 403", status)
+	}
+	if status := c.do("GET", "/api/players/alice/stats", nil, nil); status != http.StatusUnauthorized {
+		t.Errorf("anonymous stats: status = %d, want 401", status)
+	}
+	if status := c.do("POST", "/api/game/new", handlers.NewGameRequest{Bet: 10, PlayerID: "alice"}, nil); status != http.StatusUnauthorized {
+		t.Errorf("anonymous game with player_id: status = %d, want 401", status)
+	}
+	c.mustDo("GET", "/api/leaderboard?metric=roi&window=weekly&offset=0&limit=5", nil, nil)
+	c.mustDo("POST", "/api/strategy/side-bets", handlers.SideBetHouseEdgeRequest{}, nil)
+	c.mustDo("POST", "/api/strategy/side-bets", handlers.SideBetHouseEdgeRequest{Variant: game.VariantSpanish21}, nil)
+	c.mustDo("POST", "/api/strategy/ror", handlers.RiskOfRuinRequest{
+		Bankroll: 100, BetUnit: 1, TargetRuin: 0.05, Trials: 10, MaxHands: 100, Config: classic,
+	}, nil)
+}
+
+func TestAPI_TrainerEndpointsConformToOpenAPI(t *testing.T) {
+	c := newAPIClient(t)
+	var start handlers.TrainerStartResponse
+	c.as("alice").mustDo("POST", "/api/trainer/sessions", handlers.TrainerStartRequest{Config: game.GameConfig{DealerStandThreshold: 17}, PlayerID: "alice"}, &start)
+	base := "/api/trainer/sessions/" + start.SessionID
+	for i := 0; i < 3; i++ {
+		var spot handlers.TrainerSpotResponse
+		c.mustDo("POST", base+"/spot", nil, &spot)
+		c.mustDo("POST", base+"/answer", handlers.TrainerAnswerRequest{SpotID: spot.SpotID, Answer: "H"}, nil)
+	}
+	c.mustDo("GET", base+"/stats", nil, nil)
+	c.mustDo("GET", "/api/leaderboard?metric=trainer_accuracy", nil, nil)
+	if status := c.do("GET", "/api/trainer/sessions/unknown/stats", nil, nil); status != http.StatusNotFound {
+		t.Errorf("unknown session: status = %d, want 404", status)
+	}
+}
+
+func TestAPI_TableEndpointsConformToOpenAPI(t *testing.T) {
+	c := newAPIClient(t)
+	var snap struct {
+		ID string `json:"id"`
+	}
+	c.mustDo("POST", "/api/tables", handlers.CreateTableRequest{Config: game.GameConfig{DealerStandThreshold: 17}}, &snap)
+	c.mustDo("GET", "/api/tables/"+snap.ID, nil, nil)
+	if status := c.do("GET", "/api/tables/unknown", nil, nil); status != http.StatusNotFound {
+		t.Errorf("unknown table: status = %d, want 404", status)
+	}
+
+	// WebSocket のメッセージは TableMessage のスキーマで検証する
+	conn, _, err := websocket.DefaultDialer.Dial(c.as("alice").wsURL("/api/tables/"+snap.ID+"/ws"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	read := func() handlers.TableMessage {
+		t.Helper()
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := c.spec.ValidateJSON("TableMessage", data); err != nil {
+			t.Errorf("table message does not conform to OpenAPI: %v\n%s", err, data)
+		}
+		var m handlers.TableMessage
+		if err := json.Unmarshal(data, &m); err != nil {
+			t.Fatal(err)
+		}
+		return m
+	}
+	// 接続直後のスナップショット
+	read()
+
+	for _, cmd := range []handlers.TableCommand{
+		{RequestID: "1", Action: "join"},
+		{RequestID: "2", Action: "bet", Bet: 10},
+		{RequestID: "3", Action: "deal"},
+		{RequestID: "4", Action: "stand"},
+		{RequestID: "5", Action: "fly"},
+	} {
+		if err := conn.WriteJSON(cmd); err != nil {
+			t.Fatal(err)
+		}
+		for {
+			if m := read(); m.RequestID == cmd.RequestID {
+				break
+			}
+		}
+	}
+	c.mustDo("GET", "/api/tables/"+snap.ID, nil, nil)
+}
+
+func TestAPI_TournamentEndpointsConformToOpenAPI(t *testing.T) {
+	c := newAPIClient(t)
+	config := tournament.Config{
+		StartingChips: 100, HandsPerRound: 2, MinBet: 10, MaxBet: 50, FinalTable: 1,
+		Game: game.GameConfig{DealerStandThreshold: 17},
+	}
+	if status := c.do("POST", "/api/tournaments", config, nil); status != http.StatusUnauthorized {
+		t.Errorf("anonymous create: status = %d, want 401", status)
+	}
+	host := c.as("host")
+	var tr tournament.Tournament
+	host.mustDo("POST", "/api/tournaments", config, &tr)
+	base := "/api/tournaments/" + tr.ID
+	alice, bob := c.as("alice"), c.as("bob")
+	alice.mustDo("POST", base+"/register", handlers.TournamentRegisterRequest{PlayerID: "alice"}, nil)
+	bob.mustDo("POST", base+"/register", handlers.TournamentRegisterRequest{}, nil)
+	if status := bob.do("POST", base+"/register", handlers.TournamentRegisterRequest{}, nil); status != http.StatusConflict {
+		t.Errorf("duplicate registration: status = %d, want 409", status)
+	}
+	if status := bob.do("POST", base+"/start", nil, nil); status != http.StatusForbidden {
+		t.Errorf("start by a player who did not create it: status = %d, want 403", status)
+	}
+	host.mustDo("POST", base+"/start", nil, nil)
+
+	var resp handlers.TournamentPlayResponse
+	alice.mustDo("POST", base+"/play", handlers.TournamentPlayRequest{PlayerID: "alice", Action: "bet", Bet: 10, Locale: "en"}, &resp)
+	if resp.Game.State == game.PlayerTurn {
+		alice.mustDo("POST", base+"/play", handlers.TournamentPlayRequest{Action: "stand"}, nil)
+	}
+	c.mustDo("GET", base, nil, nil)
+	if status := c.do("GET", "/api/tournaments/unknown", nil, nil); status != http.StatusNotFound {
+		t.Errorf("unknown tournament: status = %d, want 404", status)
+	}
+}
+
+// standV2 は v2 のゲームがまだ決着していなければ、ゲームを始めたプレイヤーとしてスタンドします。
+func (c *apiClient) standV2(g handlers.GameV2) {
+	c.t.Helper()
+	if g.State == game.PlayerTurn {
+		c.mustDo("POST", "/api/v2/game/stand", handlers.ActionRequestV2{GameID: g.ID}, nil)
+	}
+}
+
+// playOutV2 は v2 のゲームが決着するまでスタンドします。
+func (c *apiClient) playOutV2(g handlers.GameV2) handlers.GameV2 {
+	c.t.Helper()
+	if g.State != game.PlayerTurn {
+		return g
+	}
+	var resp handlers.ActionResponseV2
+	c.mustDo("POST", "/api/v2/game/stand", handlers.ActionRequestV2{GameID: g.ID, PlayerID: "carol", SessionID: "s2", Grade: true}, &resp)
+	return resp.GameV2
+}
+
+func TestAPI_V2EndpointsConformToOpenAPI(t *testing.T) {
+	c := newAPIClient(t)
+	carol := c.as("carol")
+	configs := []game.GameConfig{
+		{DealerStandThreshold: 17},
+		{DealerStandThreshold: 17, Variant: game.VariantSpanish21},
+		{DealerStandThreshold: 17, Variant: game.VariantSwitch},
+	}
+	for _, config := range configs {
+		config := config
+		for i := 0; i < 5; i++ {
+			var g handlers.GameV2
+			carol.mustDo("POST", "/api/v2/game/new", handlers.NewGameRequest{Bet: 10, PlayerID: "carol", Config: &config}, &g)
+			wantHands := 1
+			if config.Variant == game.VariantSwitch {
+				wantHands = 2
+			}
+			if len(g.Hands) != wantHands {
+				t.Fatalf("%s: hands = %d, want %d", config.Variant, len(g.Hands), wantHands)
+			}
+			c.mustDo("POST", "/api/v2/strategy/advise", handlers.StrategyRequestV2{Game: g, Config: config}, nil)
+			c.mustDo("POST", "/api/v2/strategy/advise/batch", handlers.StrategyBatchRequestV2{Positions: []handlers.StrategyRequestV2{{Game: g, Config: config}, {Game: g}}}, nil)
+			// 最初の手が 21 なら配った時点で 2 つ目の手に進み、もうスイッチできない
+			if slices.Contains(g.AllowedActions, game.ActionSwitch) {
+				carol.mustDo("POST", "/api/v2/game/switch", handlers.SwitchRequestV2{GameID: g.ID}, &g)
+			}
+			if g.State == game.PlayerTurn {
+				var resp handlers.ActionResponseV2
+				carol.mustDo("POST", "/api/v2/game/hit", handlers.ActionRequestV2{GameID: g.ID, Grade: true, SessionID: "s2"}, &resp)
+				g = resp.GameV2
+			}
+			g = carol.playOutV2(g)
+			if config.Variant == game.VariantSwitch && g.State == game.Finished && g.Payout != g.Hands[0].Payout+g.Hands[1].Payout {
+				t.Errorf("payout %d is not the sum of the hands %+v", g.Payout, g.Hands)
+			}
+		}
+	}
+
+	var g handlers.GameV2
+	c.mustDo("POST", "/api/v2/game/new", handlers.NewGameRequest{Bet: 10}, &g)
+	c.do("POST", "/api/v2/game/surrender", handlers.ActionRequestV2{GameID: g.ID}, nil)
+	c.mustDo("POST", "/api/v2/game/new", handlers.NewGameRequest{Bet: 10}, &g)
+	c.do("POST", "/api/v2/game/stand/stream", handlers.ActionRequestV2{GameID: g.ID}, nil)
+
+	// サーバーが配っていないゲームは 404
+	if status := c.do("POST", "/api/v2/game/hit", handlers.ActionRequestV2{GameID: "unknown"}, nil); status != http.StatusNotFound {
+		t.Errorf("unknown game: status = %d, want 404", status)
+	}
+	// 決着したゲームは成績に記録される
+	carol.mustDo("GET", "/api/v2/players/carol/stats", nil, nil)
+	// ゲームを始めたプレイヤーしか行動できない
+	carol.mustDo("POST", "/api/v2/game/new", handlers.NewGameRequest{Bet: 10}, &g)
+	if status := c.as("dave").do("POST", "/api/v2/game/hit", handlers.ActionRequestV2{GameID: g.ID}, nil); status != http.StatusForbidden {
+		t.Errorf("hit on carol's game: status = %d, want 403", status)
+	}
+	if status := c.do("POST", "/api/v2/game/stand", handlers.ActionRequestV2{GameID: g.ID}, nil); status != http.StatusForbidden {
+		t.Errorf("anonymous stand on carol's game: status = %d, want 403", status)
+	}
+
+	var tr tournament.Tournament
+	host := c.as("host")
+	host.mustDo("POST", "/api/v2/tournaments", tournament.Config{
+		StartingChips: 100, HandsPerRound: 2, MinBet: 10, FinalTable: 1, Game: configs[0],
+	}, &tr)
+	for _, p := range []string{"alice", "bob"} {
+		c.as(p).mustDo("POST", "/api/v2/tournaments/"+tr.ID+"/register", handlers.TournamentRegisterRequest{}, nil)
+	}
+	host.mustDo("POST", "/api/v2/tournaments/"+tr.ID+"/start", nil, nil)
+	var play handlers.TournamentPlayResponseV2
+	c.as("alice").mustDo("POST", "/api/v2/tournaments/"+tr.ID+"/play", handlers.TournamentPlayRequest{Action: "bet", Bet: 10}, &play)
+	if len(play.Game.Hands) != 1 {
+		t.Errorf("tournament hand: hands = %d, want 1", len(play.Game.Hands))
+	}
+}
+
+func TestAPI_V2TableSocketConformsToOpenAPI(t *testing.T) {
+	c := newAPIClient(t)
+	var snap handlers.TableSnapshotV2
+	c.mustDo("POST", "/api/v2/tables", handlers.CreateTableRequest{Config: game.GameConfig{DealerStandThreshold: 17}}, &snap)
+
+	conn, _, err := websocket.DefaultDialer.Dial(c.as("alice").wsURL("/api/v2/tables/"+snap.ID+"/ws"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	settled := false
+	read := func() handlers.TableMessage {
+		t.Helper()
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := c.spec.ValidateJSON("TableMessageV2", data); err != nil {
+			t.Errorf("table message does not conform to OpenAPI: %v\n%s", err, data)
+		}
+		var m handlers.TableMessage
+		json.Unmarshal(data, &m)
+		if m.Event != nil && m.Event.Type == "settlement" {
+			settled = true
+		}
+		return m
+	}
+	read()
+	for _, cmd := range []handlers.TableCommand{
+		{RequestID: "1", Action: "join"},
+		{RequestID: "2", Action: "bet", Bet: 10},
+		{RequestID: "3", Action: "deal"},
+		{RequestID: "4", Action: "stand"},
+	} {
+		conn.WriteJSON(cmd)
+		for read().RequestID != cmd.RequestID {
+		}
+	}
+	// スタンドの応答より後に精算イベントが届くことがある
+	for !settled {
+		read()
+	}
+	c.mustDo("GET", "/api/v2/tables/"+snap.ID, nil, &snap)
+	if len(snap.Seats) != 1 || len(snap.Seats[0].Game.Hands) != 1 {
+		t.Errorf("snapshot seats = %+v, want 1 seat with 1 hand", snap.Seats)
+	}
+}
+
+func TestAPI_AuthEndpointsConformToOpenAPI(t *testing.T) {
+	c := newAPIClient(t)
+	alice := c.as("alice")
+	var me auth.Identity
+	alice.mustDo("GET", "/api/auth/me", nil, &me)
+	if me.PlayerID != "alice" || me.Guest {
+		t.Errorf("me = %+v, want alice", me)
+	}
+	if status := c.do("POST", "/api/auth/login", handlers.AuthRequest{Name: "alice", Password: "wrong password"}, nil); status != http.StatusUnauthorized {
+		t.Errorf("wrong password: status = %d, want 401", status)
+	}
+	if status := c.do("POST", "/api/auth/register", handlers.AuthRequest{Name: "alice", Password: "short"}, nil); status != http.StatusUnprocessableEntity {
+		t.Errorf("short password: status = %d, want 422", status)
+	}
+	bad := *c
+	bad.token = "not-a-token"
+	if status := bad.do("GET", "/api/health", nil, nil); status != http.StatusUnauthorized {
+		t.Errorf("invalid token: status = %d, want 401", status)
+	}
+
+	// ゲストも登録プレイヤーと同じように遊べ、（サーバーが保持する v2 のゲームの）成績が記録される
+	var guest handlers.AuthResponse
+	c.mustDo("POST", "/api/auth/guest", nil, &guest)
+	g := *c
+	g.token = guest.Token
+	var started handlers.GameV2
+	g.mustDo("POST", "/api/v2/game/new", handlers.NewGameRequest{Bet: 10}, &started)
+	g.standV2(started)
+	var stats handlers.PlayerStatsResponse
+	g.mustDo("GET", "/api/players/"+guest.Player.PlayerID+"/stats", nil, &stats)
+	if stats.Lifetime.HandsPlayed != 1 {
+		t.Errorf("guest hands played = %d, want 1", stats.Lifetime.HandsPlayed)
+	}
+}
+
+func TestAPI_RateLimitConformsToOpenAPI(t *testing.T) {
+	cfg := testConfig()
+	cfg.RateLimits.Strategy = ratelimit.Limit{Rate: 0.001, Burst: 1}
+	c := newAPIClientWithConfig(t, cfg)
+	var g game.Game
+	c.mustDo("POST", "/api/game/new", handlers.NewGameRequest{Bet: 10}, &g)
+	req := handlers.StrategyRequest{Game: g, Config: game.GameConfig{DealerStandThreshold: 17}}
+
+	c.mustDo("POST", "/api/strategy/advise", req, nil)
+	resp, _ := c.send("POST", "/api/strategy/advise", req, nil)
+	if resp.StatusCode != http.StatusTooManyRequests || resp.Header.Get("Retry-After") == "" {
+		t.Errorf("second advise: status = %d, Retry-After = %q, want 429 with Retry-After", resp.StatusCode, resp.Header.Get("Retry-After"))
+	}
+	// ゲームの操作は別の予算
+	c.mustDo("POST", "/api/game/new", handlers.NewGameRequest{Bet: 10}, nil)
+
+	// 大きすぎるボディは 413
+	big := handlers.NewGameRequest{Bet: 10, SessionID: strings.Repeat("x", handlers.MaxBodyBytes)}
+	if status := c.do("POST", "/api/game/new", big, nil); status != http.StatusRequestEntityTooLarge {
+		t.Errorf("large body: status = %d, want 413", status)
+	}
+}
+
+func TestCORSMiddleware_AllowList(t *testing.T) {
+	handler := corsMiddleware([]string{"https://app.example.com"})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	for _, tc := range []struct {
+		origin string
+		want   string
+	}{
+		{"https://app.example.com", "https://app.example.com"},
+		{"https://evil.example.com", ""},
+		{"", ""},
+	} {
+		req := httptest.NewRequest(http.MethodOptions, "/api/game/new", nil)
+		if tc.origin != "" {
+			req.Header.Set("Origin", tc.origin)
+		}
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		if got := rr.Header().Get("Access-Control-Allow-Origin"); got != tc.want {
+			t.Errorf("origin %q: Access-Control-Allow-Origin = %q, want %q", tc.origin, got, tc.want)
+		}
+		if (rr.Header().Get("Access-Control-Allow-Headers") != "") != (tc.want != "") {
+			t.Errorf("origin %q: Access-Control-Allow-Headers = %q", tc.origin, rr.Header().Get("Access-Control-Allow-Headers"))
+		}
+		if rr.Header().Get("Vary") != "Origin" {
+			t.Errorf("origin %q: Vary = %q, want Origin", tc.origin, rr.Header().Get("Vary"))
+		}
+	}
+
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/api/health", nil)
+	req.Header.Set("Origin", "https://evil.example.com")
+	corsMiddleware([]string{"*"})(handler).ServeHTTP(rr, req)
+	if got := rr.Header().Get("Access-Control-Allow-Origin"); got != "*" {
+		t.Errorf("allow all: Access-Control-Allow-Origin = %q, want *", got)
+	}
+}
+
+func TestAPI_LeaderboardCountsOnlyHouseRuleGames(t *testing.T) {
+	c := newAPIClient(t)
+	dave := c.as("dave")
+	play := func(config *game.GameConfig) {
+		var g handlers.GameV2
+		dave.mustDo("POST", "/api/v2/game/new", handlers.NewGameRequest{Bet: 10, Config: config}, &g)
+		dave.standV2(g)
+	}
+	ranked := func() int {
+		var lb handlers.LeaderboardResponse
+		c.mustDo("GET", "/api/leaderboard?metric=net_winnings&window=all_time", nil, &lb)
+		return lb.Total
+	}
+
+	// ディーラーの閾値を下げたゲームは成績には残るが、ランキングには載らない
+	play(&game.GameConfig{DealerStandThreshold: 12})
+	var stats handlers.PlayerStatsResponse
+	dave.mustDo("GET", "/api/players/dave/stats", nil, &stats)
+	if stats.Lifetime.HandsPlayed != 1 || ranked() != 0 {
+		t.Fatalf("hands played = %d, ranked = %d, want 1 and 0", stats.Lifetime.HandsPlayed, ranked())
+	}
+	// クラシック以外のバリエーションも載らない
+	play(&game.GameConfig{DealerStandThreshold: 17, Variant: game.VariantSpanish21})
+	if ranked() != 0 {
+		t.Fatalf("ranked = %d after a Spanish 21 game, want 0", ranked())
+	}
+
+	play(nil)
+	if ranked() != 1 {
+		t.Errorf("ranked = %d after a game under house rules, want 1", ranked())
+	}
+}
+
+func TestAPI_UsesDefaultGameConfig(t *testing.T) {
+	cfg := testConfig()
+	cfg.Game = game.GameConfig{DealerStandThreshold: 17, Variant: game.VariantSpanish21}
+	c := newAPIClientWithConfig(t, cfg)
+
+	var g game.Game
+	c.mustDo("POST", "/api/game/new", handlers.NewGameRequest{Bet: 10}, &g)
+	if g.Variant != game.VariantSpanish21 {
+		t.Errorf("new game without config: variant = %q, want %q", g.Variant, game.VariantSpanish21)
+	}
+	classic := game.GameConfig{DealerStandThreshold: 17}
+	var withConfig game.Game
+	c.mustDo("POST", "/api/game/new", handlers.NewGameRequest{Bet: 10, Config: &classic}, &withConfig)
+	if withConfig.Variant != "" {
+		t.Errorf("new game with config: variant = %q, want classic", withConfig.Variant)
+	}
+
+	// 準備状態の確認は既定のルールで戦略を計算する
+	c.mustDo("GET", "/api/ready", nil, nil)
+
+	// テーブルはクラシックのみなので、クラシックの既定のルールで設定を省略できる
+	c = newAPIClient(t)
+	c.mustDo("POST", "/api/v2/tables", handlers.CreateTableRequest{}, nil)
+}
+
+func TestAPI_ReadinessConformsToOpenAPI(t *testing.T) {
+	c := newAPIClient(t)
+	var ready handlers.ReadinessResponse
+	c.mustDo("GET", "/api/ready", nil, &ready)
+	if ready.Status != "ready" || len(ready.Checks) != 2 {
+		t.Errorf("readiness = %+v, want ready with storage and strategy", ready)
+	}
+
+	// 停止中などの依存先の障害は 503
+	spec, err := openapi.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	validate := openapi.Middleware(spec, func(r *http.Request, err error) {
+		t.Errorf("OpenAPI violation: %v", err)
+	})
+	draining := handlers.ReadinessCheck{Name: "shutdown", Check: func(context.Context) error { return errors.New("server is shutting down") }}
+	server := httptest.NewServer(handlers.NegotiateVersion(validate(newRouter(testConfig(), newDependencies(testConfig()), draining))))
+	defer server.Close()
+	resp, err := server.Client().Get(server.URL + "/api/v2/ready")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("draining: status = %d, want 503", resp.StatusCode)
+	}
+}
+
+func TestServeHTTP_DrainsInFlightRequests(t *testing.T) {
+	started := make(chan struct{})
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		time.Sleep(200 * time.Millisecond)
+		w.Write([]byte("done"))
+	})
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	served := make(chan error, 1)
+	go func() { served <- serveHTTP(ctx, newServer(testConfig(), handler), lis, 5*time.Second) }()
+
+	type result struct {
+		body string
+		err  error
+	}
+	results := make(chan result, 1)
+	go func() {
+		resp, err := http.Get("http://" + lis.Addr().String() + "/")
+		if err != nil {
+			results <- result{err: err}
+			return
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		results <- result{string(body), err}
+	}()
+
+	// 処理中のリクエストがある間に停止を始めても、そのリクエストは最後まで処理する
+	<-started
+	cancel()
+	if res := <-results; res.err != nil || res.body != "done" {
+		t.Errorf("in-flight request: got %q, %v", res.body, res.err)
+	}
+	if err := <-served; err != nil {
+		t.Errorf("serveHTTP: %v", err)
+	}
+	if _, err := http.Get("http://" + lis.Addr().String() + "/"); err == nil {
+		t.Error("server still accepts requests after shutdown")
+	}
+}
+
+func TestAPI_VersionNegotiation(t *testing.T) {
+	c := newAPIClient(t)
+	newGame := handlers.NewGameRequest{Bet: 10}
+
+	tests := []struct {
+		name        string
+		path        string
+		header      http.Header
+		wantVersion string
+		wantHands   bool // レスポンスが GameV2（hands を持つ）か
+	}{
+		{"default is v1", "/api/game/new", nil, "1", false},
+		{"explicit v1 path", "/api/v1/game/new", nil, "1", false},
+		{"explicit v2 path", "/api/v2/game/new", nil, "2", true},
+		{"API-Version header", "/api/game/new", http.Header{"Api-Version": {"2"}}, "2", true},
+		{"Accept media type", "/api/game/new", http.Header{"Accept": {"application/vnd.blackjack.v2+json"}}, "2", true},
+		{"path wins over header", "/api/v1/game/new", http.Header{"Api-Version": {"2"}}, "1", false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			resp, data := c.send("POST", tc.path, newGame, tc.header)
+			if resp.StatusCode != http.StatusOK {
+				t.Fatalf("status = %d, want 200: %s", resp.StatusCode, data)
+			}
+			if got := resp.Header.Get(handlers.VersionHeader); got != tc.wantVersion {
+				t.Errorf("API-Version = %q, want %q", got, tc.wantVersion)
+			}
+			var body map[string]any
+			json.Unmarshal(data, &body)
+			if _, ok := body["hands"]; ok != tc.wantHands {
+				t.Errorf("response has hands = %v, want %v: %s", ok, tc.wantHands, data)
+			}
+			deprecated := resp.Header.Get("Deprecation") != ""
+			if deprecated != (tc.wantVersion == "1") {
+				t.Errorf("Deprecation header = %q for version %s", resp.Header.Get("Deprecation"), tc.wantVersion)
+			}
+			if deprecated {
+				if got := resp.Header.Get("Link"); got != `</api/v2/game/new>; rel="successor-version"` {
+					t.Errorf("Link = %q", got)
+				}
+				if resp.Header.Get("Sunset") == "" {
+					t.Error("Sunset header is missing")
+				}
+			}
+		})
+	}
+
+	for _, header := range []http.Header{
+		{"Api-Version": {"3"}},
+		{"Api-Version": {"latest"}},
+		{"Accept": {"application/vnd.blackjack.v9+json"}},
+	} {
+		resp, data := c.send("POST", "/api/game/new", newGame, header)
+		var e handlers.ErrorResponse
+		json.Unmarshal(data, &e)
+		if resp.StatusCode != http.StatusNotAcceptable || e.Code != "unsupported_version" {
+			t.Errorf("%v: status = %d code = %q, want 406 unsupported_version", header, resp.StatusCode, e.Code)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package openapi
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// Middleware はリクエストとレスポンスをドキュメントで検証するミドルウェアを返します。
+// 検証に失敗しても処理は止めず、report にエラーを渡します（テストでは t.Error を渡す）。
+// レスポンスは書き込みをそのまま流しつつ写しを取り、ハンドラの終了後に検証します。
+// WebSocket へ切り替えたレスポンスはボディを検証しません。
+func Middleware(spec *Spec, report func(r *http.Request, err error)) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// CORS のプリフライトはドキュメントの対象外
+			if r.Method == http.MethodOptions {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			var body []byte
+			if r.Body != nil {
+				var err error
+				body, err = io.ReadAll(r.Body)
+				r.Body.Close()
+				if err != nil {
+					report(r, err)
+				}
+				r.Body = io.NopCloser(bytes.NewReader(body))
+			}
+			if err := spec.ValidateRequest(r, body); err != nil {
+				report(r, err)
+			}
+
+			rec := &recorder{ResponseWriter: w, status: http.StatusOK}
+			next.ServeHTTP(rec, r)
+			if rec.hijacked {
+				if !strings.EqualFold(r.Header.Get("Upgrade"), "websocket") {
+					report(r, errors.New("connection was hijacked without a websocket upgrade"))
+				}
+				return
+			}
+			if err := spec.ValidateResponse(r, rec.status, w.Header(), rec.body.Bytes()); err != nil {
+				report(r, err)
+			}
+		})
+	}
+}
+
+// recorder は書き込みを下位の ResponseWriter に流しつつ、ステータスとボディの写しを取ります。
+type recorder struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+	hijacked    bool
+	body        bytes.Buffer
+}
+
+func (rec *recorder) WriteHeader(status int) {
+	if !rec.wroteHeader {
+		rec.status, rec.wroteHeader = status, true
+	}
+	rec.ResponseWriter.WriteHeader(status)
+}
+
+func (rec *recorder) Write(b []byte) (int, error) {
+	rec.wroteHeader = true
+	rec.body.Write(b)
+	return rec.ResponseWriter.Write(b)
+}
+
+// Flush は SSE のハンドラのために下位の Flush を呼びます。
+func (rec *recorder) Flush() {
+	if f, ok := rec.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Hijack は WebSocket へ切り替えるハンドラのために下位の Hijack を呼びます。
+func (rec *recorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := rec.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("openapi: response writer does not support hijacking")
+	}
+	rec.hijacked = true
+	return h.Hijack()
+}
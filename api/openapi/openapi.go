@@ -0,0 +1,180 @@
+// Package openapi は API の OpenAPI 3 ドキュメントと、それに基づくリクエスト・レスポンスの検証を提供します。
+//
+// ドキュメント（openapi.json）は API の契約として手で管理します。ハンドラの構造体や game.Game の
+// JSON を変えたら、このドキュメントも合わせて更新してください。テストでは Middleware を通して
+// 全てのリクエストとレスポンスを検証するので、更新漏れや意図しない互換性の破壊はテストで検出されます。
+package openapi
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+//go:embed openapi.json
+var document []byte
+
+// Document は OpenAPI ドキュメントの JSON を返します。
+func Document() []byte {
+	return document
+}
+
+// Spec は検証に使う範囲の OpenAPI ドキュメントです。
+type Spec struct {
+	OpenAPI    string                          `json:"openapi"`
+	Paths      map[string]map[string]Operation `json:"paths"` // パス → 小文字のメソッド → 操作
+	Components struct {
+		Schemas   map[string]*Schema   `json:"schemas"`
+		Responses map[string]*Response `json:"responses"`
+	} `json:"components"`
+}
+
+// Operation は 1 つのエンドポイントの定義です。
+type Operation struct {
+	Summary     string               `json:"summary"`
+	Deprecated  bool                 `json:"deprecated,omitempty"`
+	Parameters  []Parameter          `json:"parameters,omitempty"`
+	RequestBody *RequestBody         `json:"requestBody,omitempty"`
+	Responses   map[string]*Response `json:"responses"` // ステータスコード（または default）→ レスポンス
+}
+
+// Parameter はパスまたはクエリのパラメータです。
+type Parameter struct {
+	Name     string  `json:"name"`
+	In       string  `json:"in"`
+	Required bool    `json:"required,omitempty"`
+	Schema   *Schema `json:"schema"`
+}
+
+// RequestBody はリクエストボディの定義です。
+type RequestBody struct {
+	Required bool                 `json:"required,omitempty"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+// Response はレスポンスの定義です。Ref が空でなければ components.responses を参照します。
+type Response struct {
+	Ref         string               `json:"$ref,omitempty"`
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+// MediaType は Content-Type ごとのボディの定義です。
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+// Load は埋め込みのドキュメントを読み込みます。
+func Load() (*Spec, error) {
+	return Parse(document)
+}
+
+// Parse は OpenAPI ドキュメントを読み込み、参照（$ref）が全て解決できることを確かめます。
+func Parse(data []byte) (*Spec, error) {
+	var s Spec
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("openapi: %w", err)
+	}
+	if !strings.HasPrefix(s.OpenAPI, "3.") {
+		return nil, fmt.Errorf("openapi: unsupported version %q", s.OpenAPI)
+	}
+	for _, path := range s.sortedPaths() {
+		for method, op := range s.Paths[path] {
+			for status, resp := range op.Responses {
+				if _, err := s.response(resp); err != nil {
+					return nil, fmt.Errorf("openapi: %s %s %s: %w", method, path, status, err)
+				}
+			}
+		}
+	}
+	var missing []string
+	walk := func(sc *Schema) {
+		sc.walk(func(sc *Schema) {
+			if sc.Ref != "" {
+				if _, err := s.schema(sc.Ref); err != nil {
+					missing = append(missing, sc.Ref)
+				}
+			}
+		})
+	}
+	for _, sc := range s.Components.Schemas {
+		walk(sc)
+	}
+	for _, ops := range s.Paths {
+		for _, op := range ops {
+			if op.RequestBody != nil {
+				for _, mt := range op.RequestBody.Content {
+					walk(mt.Schema)
+				}
+			}
+			for _, resp := range op.Responses {
+				for _, mt := range resp.Content {
+					walk(mt.Schema)
+				}
+			}
+		}
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return nil, fmt.Errorf("openapi: unresolved references: %s", strings.Join(missing, ", "))
+	}
+	return &s, nil
+}
+
+// HasOperation は method と path テンプレート（例: /api/v1/tables/{id}）の操作が定義されているかを返します。
+func (s *Spec) HasOperation(method, path string) bool {
+	_, ok := s.Paths[path][strings.ToLower(method)]
+	return ok
+}
+
+// Operations は定義されている全ての操作を "METHOD path" の形で返します。
+func (s *Spec) Operations() []string {
+	var ops []string
+	for _, path := range s.sortedPaths() {
+		for method := range s.Paths[path] {
+			ops = append(ops, strings.ToUpper(method)+" "+path)
+		}
+	}
+	sort.Strings(ops)
+	return ops
+}
+
+func (s *Spec) sortedPaths() []string {
+	paths := make([]string, 0, len(s.Paths))
+	for p := range s.Paths {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// schema は #/components/schemas/ への参照を解決します。
+func (s *Spec) schema(ref string) (*Schema, error) {
+	name, ok := strings.CutPrefix(ref, "#/components/schemas/")
+	if !ok {
+		return nil, fmt.Errorf("unsupported reference %q", ref)
+	}
+	sc, ok := s.Components.Schemas[name]
+	if !ok || sc == nil {
+		return nil, fmt.Errorf("unknown schema %q", ref)
+	}
+	return sc, nil
+}
+
+// response は #/components/responses/ への参照を解決します。
+func (s *Spec) response(r *Response) (*Response, error) {
+	if r == nil || r.Ref == "" {
+		return r, nil
+	}
+	name, ok := strings.CutPrefix(r.Ref, "#/components/responses/")
+	if !ok {
+		return nil, fmt.Errorf("unsupported reference %q", r.Ref)
+	}
+	resp, ok := s.Components.Responses[name]
+	if !ok || resp == nil {
+		return nil, fmt.Errorf("unknown response %q", r.Ref)
+	}
+	return resp, nil
+}
@@ -0,0 +1,207 @@
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"blackjack/api/game"
+)
+
+func mustLoad(t *testing.T) *Spec {
+	t.Helper()
+	s, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func sampleGame() game.Game {
+	g := game.Game{
+		PlayerHand: game.Hand{Cards: []game.Card{{Suit: game.Spade, Rank: "10"}, {Suit: game.Heart, Rank: "7"}}, Score: 17},
+		DealerHand: game.Hand{Cards: []game.Card{{Suit: game.Club, Rank: "9"}}, Score: 9},
+		State:      game.PlayerTurn,
+		Result:     game.Pending,
+		Bet:        10,
+	}
+	rules, _ := game.RulesFor(game.VariantClassic)
+	g.UpdateAllowedActions(rules)
+	return g
+}
+
+// TestValidateJSON_Game は game.Game の JSON がドキュメントに合い、互換性を壊す変更は検出されることを確認します。
+func TestValidateJSON_Game(t *testing.T) {
+	s := mustLoad(t)
+	data, err := json.Marshal(sampleGame())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.ValidateJSON("Game", data); err != nil {
+		t.Fatalf("game does not conform: %v", err)
+	}
+
+	broken := []struct {
+		name string
+		edit func(m map[string]any)
+		want string
+	}{
+		{"renamed field", func(m map[string]any) { m["playerHand"] = m["player_hand"]; delete(m, "player_hand") }, `$.playerHand: unknown property`},
+		{"missing field", func(m map[string]any) { delete(m, "allowed_actions") }, `missing required property "allowed_actions"`},
+		{"wrong type", func(m map[string]any) { m["bet"] = "10" }, `$.bet: expected integer, got string`},
+		{"unknown enum", func(m map[string]any) { m["state"] = "Dealing" }, `$.state: "Dealing" is not one of`},
+		{"null array", func(m map[string]any) { m["allowed_actions"] = nil }, `$.allowed_actions: must not be null`},
+		{"nested", func(m map[string]any) {
+			m["dealer_hand"].(map[string]any)["cards"].([]any)[0].(map[string]any)["rank"] = "1"
+		}, `$.dealer_hand.cards[0].rank: "1" is not one of`},
+	}
+	for _, tc := range broken {
+		t.Run(tc.name, func(t *testing.T) {
+			var m map[string]any
+			json.Unmarshal(data, &m)
+			tc.edit(m)
+			b, _ := json.Marshal(m)
+			err := s.ValidateJSON("Game", b)
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Errorf("error = %v, want containing %q", err, tc.want)
+			}
+		})
+	}
+}
+
+// TestActionResponse_MatchesGame は ActionResponse（ゲームを展開したもの）がゲームと同じプロパティを持つことを確認します。
+func TestActionResponse_MatchesGame(t *testing.T) {
+	s := mustLoad(t)
+	for _, tc := range []struct{ game, response string }{
+		{"Game", "ActionResponse"},
+		{"GameV2", "ActionResponseV2"},
+	} {
+		g, a := s.Components.Schemas[tc.game], s.Components.Schemas[tc.response]
+		for name, p := range g.Properties {
+			if !reflect.DeepEqual(a.Properties[name], p) {
+				t.Errorf("%s.%s differs from %s.%s", tc.response, name, tc.game, name)
+			}
+		}
+		var extra []string
+		for name := range a.Properties {
+			if _, ok := g.Properties[name]; !ok {
+				extra = append(extra, name)
+			}
+		}
+		sort.Strings(extra)
+		if !reflect.DeepEqual(extra, []string{"grade"}) {
+			t.Errorf("%s extra properties = %v, want [grade]", tc.response, extra)
+		}
+		if !reflect.DeepEqual(a.Required, g.Required) {
+			t.Errorf("%s required = %v, want %v", tc.response, a.Required, g.Required)
+		}
+	}
+}
+
+func TestFindOperation(t *testing.T) {
+	s := mustLoad(t)
+	tests := []struct {
+		method, path string
+		want         string
+		params       map[string]string
+	}{
+		{"POST", "/api/v1/game/stand", "/api/v1/game/stand", map[string]string{}},
+		{"POST", "/api/v2/game/stand/stream", "/api/v2/game/stand/stream", map[string]string{}},
+		{"POST", "/api/v1/tournaments/t1/play", "/api/v1/tournaments/{id}/play", map[string]string{"id": "t1"}},
+		{"GET", "/api/v2/tables/abc", "/api/v2/tables/{id}", map[string]string{"id": "abc"}},
+	}
+	for _, tc := range tests {
+		got, _, params, err := s.FindOperation(tc.method, tc.path)
+		if err != nil {
+			t.Errorf("%s %s: %v", tc.method, tc.path, err)
+			continue
+		}
+		if got != tc.want || !reflect.DeepEqual(params, tc.params) {
+			t.Errorf("%s %s = %s %v, want %s %v", tc.method, tc.path, got, params, tc.want, tc.params)
+		}
+	}
+	for _, tc := range []struct{ method, path string }{
+		{"GET", "/api/v1/game/new"},
+		{"POST", "/api/game/new"},
+		{"GET", "/api/v1/tables//ws"},
+	} {
+		if _, _, _, err := s.FindOperation(tc.method, tc.path); err == nil {
+			t.Errorf("%s %s: expected ErrUndocumented", tc.method, tc.path)
+		}
+	}
+}
+
+func TestValidateRequest(t *testing.T) {
+	s := mustLoad(t)
+	tests := []struct {
+		name string
+		path string
+		body string
+		want string
+	}{
+		{"valid", "/api/v1/game/new", `{"bet": 10, "config": {"dealer_stand_threshold": 17, "variant": "spanish21"}}`, ""},
+		{"missing required", "/api/v1/game/new", `{"config": {"dealer_stand_threshold": 17}}`, `missing required property "bet"`},
+		{"unknown variant", "/api/v1/game/new", `{"bet": 10, "config": {"dealer_stand_threshold": 17, "variant": "pontoon"}}`, `$.config.variant`},
+		{"typo", "/api/v1/game/new", `{"bet": 10, "sidebets": {}}`, `$.sidebets: unknown property`},
+		{"bad query", "/api/v1/leaderboard?limit=ten", ``, `query parameter "limit" must be an integer`},
+		{"no body", "/api/v1/game/new", ``, `request body is required`},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			method := "POST"
+			if strings.HasPrefix(tc.path, "/api/v1/leaderboard") {
+				method = "GET"
+			}
+			r := httptest.NewRequest(method, tc.path, nil)
+			err := s.ValidateRequest(r, []byte(tc.body))
+			switch {
+			case tc.want == "" && err != nil:
+				t.Errorf("unexpected error: %v", err)
+			case tc.want != "" && (err == nil || !strings.Contains(err.Error(), tc.want)):
+				t.Errorf("error = %v, want containing %q", err, tc.want)
+			}
+		})
+	}
+}
+
+func TestMiddleware_ReportsNonConformingResponse(t *testing.T) {
+	s := mustLoad(t)
+	var reported []error
+	mw := Middleware(s, func(r *http.Request, err error) { reported = append(reported, err) })
+
+	handler := mw(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"state": "ok"}`))
+	}))
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/health", nil))
+	if w.Body.String() != `{"state": "ok"}` {
+		t.Errorf("body was not passed through: %q", w.Body.String())
+	}
+	if len(reported) != 1 || !strings.Contains(reported[0].Error(), `missing required property "status"`) {
+		t.Errorf("reported = %v, want missing status", reported)
+	}
+
+	// エラーレスポンスは default（ErrorResponse）で検証する
+	reported = nil
+	handler = mw(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusConflict)
+		w.Write([]byte(`{"code": "game_finished", "message": "終了しています"}`))
+	}))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("POST", "/api/v1/game/hit", strings.NewReader(`{}`)))
+	if len(reported) != 1 || !strings.Contains(reported[0].Error(), `missing required property "game"`) {
+		t.Errorf("reported = %v, want only the request error", reported)
+	}
+}
+
+func TestParse_RejectsUnresolvedReference(t *testing.T) {
+	doc := `{"openapi": "3.0.3", "paths": {}, "components": {"schemas": {"A": {"$ref": "#/components/schemas/B"}}}}`
+	if _, err := Parse([]byte(doc)); err == nil || !strings.Contains(err.Error(), "#/components/schemas/B") {
+		t.Errorf("err = %v, want unresolved reference", err)
+	}
+}
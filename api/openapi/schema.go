@@ -0,0 +1,251 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Schema は OpenAPI 3.0 のスキーマのうち、このドキュメントで使う部分です。
+// type, format, enum, nullable, properties, required, additionalProperties, items,
+// minItems, maxItems, allOf, $ref に対応します。
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Enum                 []any              `json:"enum,omitempty"`
+	Nullable             bool               `json:"nullable,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *Additional        `json:"additionalProperties,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	MinItems             *int               `json:"minItems,omitempty"`
+	MaxItems             *int               `json:"maxItems,omitempty"`
+	AllOf                []*Schema          `json:"allOf,omitempty"`
+}
+
+// Additional は additionalProperties の値（真偽値またはスキーマ）です。
+type Additional struct {
+	Allowed bool
+	Schema  *Schema
+}
+
+// UnmarshalJSON は真偽値とスキーマのどちらも受け付けます。
+func (a *Additional) UnmarshalJSON(data []byte) error {
+	if b := bytes.TrimSpace(data); bytes.Equal(b, []byte("true")) || bytes.Equal(b, []byte("false")) {
+		a.Allowed, a.Schema = string(b) == "true", nil
+		return nil
+	}
+	a.Allowed = true
+	return json.Unmarshal(data, &a.Schema)
+}
+
+// walk はスキーマと入れ子のスキーマを全て辿ります。
+func (sc *Schema) walk(fn func(*Schema)) {
+	if sc == nil {
+		return
+	}
+	fn(sc)
+	for _, p := range sc.Properties {
+		p.walk(fn)
+	}
+	if sc.AdditionalProperties != nil {
+		sc.AdditionalProperties.Schema.walk(fn)
+	}
+	sc.Items.walk(fn)
+	for _, s := range sc.AllOf {
+		s.walk(fn)
+	}
+}
+
+// ValidationError は値がスキーマに合わない箇所を表します。Path は $.player_hand.cards[0] の形です。
+type ValidationError struct {
+	Path    string
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	return e.Path + ": " + e.Message
+}
+
+// ValidateJSON は JSON の data が components.schemas の name に合うかを検証し、合わない箇所を全て返します。
+func (s *Spec) ValidateJSON(name string, data []byte) error {
+	sc, err := s.schema("#/components/schemas/" + name)
+	if err != nil {
+		return err
+	}
+	return s.validateJSON(sc, data)
+}
+
+func (s *Spec) validateJSON(sc *Schema, data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return &ValidationError{Path: "$", Message: "invalid JSON: " + err.Error()}
+	}
+	var errs []error
+	s.validate(sc, v, "$", &errs)
+	return errors.Join(errs...)
+}
+
+// validate は decode 済みの値 v を検証し、エラーを errs に追加します。
+func (s *Spec) validate(sc *Schema, v any, path string, errs *[]error) {
+	fail := func(format string, args ...any) {
+		*errs = append(*errs, &ValidationError{Path: path, Message: fmt.Sprintf(format, args...)})
+	}
+	if sc.Ref != "" {
+		target, err := s.schema(sc.Ref)
+		if err != nil {
+			fail("%v", err)
+			return
+		}
+		s.validate(target, v, path, errs)
+		return
+	}
+	if v == nil {
+		if !sc.Nullable && (sc.Type != "" || sc.Ref != "" || len(sc.AllOf) > 0) {
+			fail("must not be null")
+		}
+		return
+	}
+	for _, sub := range sc.AllOf {
+		s.validate(sub, v, path, errs)
+	}
+
+	switch sc.Type {
+	case "":
+		// 型の指定がなければ何でもよい
+	case "object":
+		obj, ok := v.(map[string]any)
+		if !ok {
+			fail("expected object, got %s", kindOf(v))
+			return
+		}
+		for _, name := range sc.Required {
+			if _, ok := obj[name]; !ok {
+				fail("missing required property %q", name)
+			}
+		}
+		keys := make([]string, 0, len(obj))
+		for k := range obj {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			child := path + "." + k
+			if p, ok := sc.Properties[k]; ok {
+				s.validate(p, obj[k], child, errs)
+				continue
+			}
+			switch {
+			case sc.AdditionalProperties == nil:
+			case !sc.AdditionalProperties.Allowed:
+				*errs = append(*errs, &ValidationError{Path: child, Message: "unknown property"})
+			case sc.AdditionalProperties.Schema != nil:
+				s.validate(sc.AdditionalProperties.Schema, obj[k], child, errs)
+			}
+		}
+	case "array":
+		arr, ok := v.([]any)
+		if !ok {
+			fail("expected array, got %s", kindOf(v))
+			return
+		}
+		if sc.MinItems != nil && len(arr) < *sc.MinItems {
+			fail("must have at least %d items, got %d", *sc.MinItems, len(arr))
+		}
+		if sc.MaxItems != nil && len(arr) > *sc.MaxItems {
+			fail("must have at most %d items, got %d", *sc.MaxItems, len(arr))
+		}
+		if sc.Items != nil {
+			for i, item := range arr {
+				s.validate(sc.Items, item, path+"["+strconv.Itoa(i)+"]", errs)
+			}
+		}
+	case "string":
+		if _, ok := v.(string); !ok {
+			fail("expected string, got %s", kindOf(v))
+			return
+		}
+	case "integer":
+		n, ok := v.(json.Number)
+		if !ok {
+			fail("expected integer, got %s", kindOf(v))
+			return
+		}
+		if _, err := n.Int64(); err != nil {
+			if f, err := n.Float64(); err != nil || f != math.Trunc(f) {
+				fail("expected integer, got %s", n)
+				return
+			}
+		}
+	case "number":
+		if _, ok := v.(json.Number); !ok {
+			fail("expected number, got %s", kindOf(v))
+			return
+		}
+	case "boolean":
+		if _, ok := v.(bool); !ok {
+			fail("expected boolean, got %s", kindOf(v))
+			return
+		}
+	default:
+		fail("unsupported schema type %q", sc.Type)
+		return
+	}
+
+	if len(sc.Enum) > 0 && !inEnum(sc.Enum, v) {
+		fail("%s is not one of %s", formatValue(v), formatEnum(sc.Enum))
+	}
+}
+
+// inEnum は v が enum のいずれかと等しいかを返します（このドキュメントの enum は文字列のみ）。
+func inEnum(enum []any, v any) bool {
+	for _, e := range enum {
+		if e == v {
+			return true
+		}
+	}
+	return false
+}
+
+func formatEnum(enum []any) string {
+	parts := make([]string, len(enum))
+	for i, e := range enum {
+		parts[i] = formatValue(e)
+	}
+	return "[" + strings.Join(parts, ", ") + "]"
+}
+
+func formatValue(v any) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(b)
+}
+
+// kindOf はエラーメッセージ用に JSON の型名を返します。
+func kindOf(v any) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case map[string]any:
+		return "object"
+	case []any:
+		return "array"
+	case string:
+		return "string"
+	case json.Number:
+		return "number"
+	case bool:
+		return "boolean"
+	}
+	return fmt.Sprintf("%T", v)
+}
@@ -0,0 +1,195 @@
+package openapi
+
+import (
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// ErrUndocumented はドキュメントにない操作へのリクエストを表します。
+var ErrUndocumented = errors.New("operation is not documented")
+
+// FindOperation は実際のリクエストパスに一致するパステンプレートと操作を返します。
+// テンプレートの {name} は 1 つのパス要素に一致します。固定の要素が多いテンプレートを優先します。
+func (s *Spec) FindOperation(method, path string) (string, *Operation, map[string]string, error) {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	best, bestFixed := "", -1
+	var bestParams map[string]string
+	for _, tmpl := range s.sortedPaths() {
+		params, fixed, ok := matchPath(tmpl, segments)
+		if !ok || fixed <= bestFixed {
+			continue
+		}
+		if _, ok := s.Paths[tmpl][strings.ToLower(method)]; !ok {
+			continue
+		}
+		best, bestFixed, bestParams = tmpl, fixed, params
+	}
+	if best == "" {
+		return "", nil, nil, fmt.Errorf("%w: %s %s", ErrUndocumented, method, path)
+	}
+	op := s.Paths[best][strings.ToLower(method)]
+	return best, &op, bestParams, nil
+}
+
+// matchPath はテンプレートがパス要素に一致するかと、パスパラメータ・固定要素の数を返します。
+func matchPath(tmpl string, segments []string) (map[string]string, int, bool) {
+	parts := strings.Split(strings.Trim(tmpl, "/"), "/")
+	if len(parts) != len(segments) {
+		return nil, 0, false
+	}
+	params := map[string]string{}
+	fixed := 0
+	for i, p := range parts {
+		if strings.HasPrefix(p, "{") && strings.HasSuffix(p, "}") {
+			if segments[i] == "" {
+				return nil, 0, false
+			}
+			params[p[1:len(p)-1]] = segments[i]
+			continue
+		}
+		if p != segments[i] {
+			return nil, 0, false
+		}
+		fixed++
+	}
+	return params, fixed, true
+}
+
+// ValidateRequest はリクエストのパラメータとボディがドキュメントに合うかを検証します。
+// body は読み取り済みのリクエストボディです。
+func (s *Spec) ValidateRequest(r *http.Request, body []byte) error {
+	tmpl, op, params, err := s.FindOperation(r.Method, r.URL.Path)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	query := r.URL.Query()
+	for _, p := range op.Parameters {
+		var (
+			value   string
+			present bool
+		)
+		switch p.In {
+		case "path":
+			value, present = params[p.Name]
+		case "query":
+			present = query.Has(p.Name)
+			value = query.Get(p.Name)
+		default:
+			continue
+		}
+		if !present {
+			if p.Required {
+				errs = append(errs, fmt.Errorf("%s parameter %q is required", p.In, p.Name))
+			}
+			continue
+		}
+		if err := checkParameter(p, value); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if op.RequestBody != nil {
+		switch {
+		case len(body) == 0:
+			if op.RequestBody.Required {
+				errs = append(errs, errors.New("request body is required"))
+			}
+		default:
+			// Content-Type のないリクエストは JSON として扱う（ハンドラも Content-Type を見ない）
+			contentType := r.Header.Get("Content-Type")
+			if contentType == "" {
+				contentType = "application/json"
+			}
+			mt, err := mediaType(op.RequestBody.Content, contentType)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("request: %w", err))
+			} else if mt.Schema != nil {
+				if err := s.validateJSON(mt.Schema, body); err != nil {
+					errs = append(errs, fmt.Errorf("request body: %w", err))
+				}
+			}
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("%s %s: %w", r.Method, tmpl, err)
+	}
+	return nil
+}
+
+// checkParameter はパラメータの値が型に合うかを検証します。
+func checkParameter(p Parameter, value string) error {
+	if p.Schema == nil {
+		return nil
+	}
+	switch p.Schema.Type {
+	case "integer":
+		if _, err := strconv.ParseInt(value, 10, 64); err != nil {
+			return fmt.Errorf("%s parameter %q must be an integer, got %q", p.In, p.Name, value)
+		}
+	case "number":
+		if _, err := strconv.ParseFloat(value, 64); err != nil {
+			return fmt.Errorf("%s parameter %q must be a number, got %q", p.In, p.Name, value)
+		}
+	case "boolean":
+		if _, err := strconv.ParseBool(value); err != nil {
+			return fmt.Errorf("%s parameter %q must be a boolean, got %q", p.In, p.Name, value)
+		}
+	}
+	return nil
+}
+
+// ValidateResponse はレスポンスのステータスコード・Content-Type・ボディがドキュメントに合うかを検証します。
+func (s *Spec) ValidateResponse(r *http.Request, status int, header http.Header, body []byte) error {
+	tmpl, op, _, err := s.FindOperation(r.Method, r.URL.Path)
+	if err != nil {
+		return err
+	}
+	resp, ok := op.Responses[strconv.Itoa(status)]
+	if !ok {
+		resp, ok = op.Responses["default"]
+	}
+	if !ok {
+		return fmt.Errorf("%s %s: status %d is not documented", r.Method, tmpl, status)
+	}
+	resp, err = s.response(resp)
+	if err != nil {
+		return fmt.Errorf("%s %s: %w", r.Method, tmpl, err)
+	}
+	if len(resp.Content) == 0 {
+		return nil
+	}
+	mt, err := mediaType(resp.Content, header.Get("Content-Type"))
+	if err != nil {
+		return fmt.Errorf("%s %s %d: %w", r.Method, tmpl, status, err)
+	}
+	if mt.Schema == nil || !isJSON(header.Get("Content-Type")) {
+		return nil
+	}
+	if err := s.validateJSON(mt.Schema, body); err != nil {
+		return fmt.Errorf("%s %s %d response body: %w", r.Method, tmpl, status, err)
+	}
+	return nil
+}
+
+// mediaType は Content-Type に対応するボディの定義を返します。
+func mediaType(content map[string]MediaType, contentType string) (MediaType, error) {
+	mt, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return MediaType{}, fmt.Errorf("invalid Content-Type %q", contentType)
+	}
+	m, ok := content[mt]
+	if !ok {
+		return MediaType{}, fmt.Errorf("Content-Type %q is not documented", mt)
+	}
+	return m, nil
+}
+
+func isJSON(contentType string) bool {
+	mt, _, _ := mime.ParseMediaType(contentType)
+	return mt == "application/json"
+}
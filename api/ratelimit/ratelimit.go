@@ -0,0 +1,115 @@
+// Package ratelimit はキー（IP アドレスやプレイヤー）ごとのトークンバケットによるレート制限を提供します。
+package ratelimit
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// sweepInterval は満杯に戻ったバケットを捨てる間隔
+const sweepInterval = time.Minute
+
+// Limit はトークンバケットの設定です。
+type Limit struct {
+	Rate  float64 `json:"rate"`  // 1 秒あたりに補充するトークン数（1 リクエストで 1 トークン、重いリクエストはその分使う）
+	Burst int     `json:"burst"` // バケットの容量（続けて受け付けられるリクエスト数）
+}
+
+// Limiter はキーごとのレート制限を行うインタフェース
+type Limiter interface {
+	// Allow はキーのバケットからトークンを 1 つ使います。
+	// トークンが足りなければ false と、次のトークンが補充されるまでの時間を返します。
+	Allow(key string) (bool, time.Duration)
+	// AllowN はキーのバケットからトークンを n 個使います。残量が 1 以上あれば受け付け、足りない分は前借りします
+	// （残量が負になり、返済されるまで以後のリクエストは受け付けない）。そのため Burst より大きい n も受け付けられます。
+	// 残量が 1 未満なら何も使わず、false と次のトークンが補充されるまでの時間を返します。
+	AllowN(key string, n int) (bool, time.Duration)
+	// AllowAll は keys のすべてのバケットから AllowN と同じようにトークンを n 個ずつ使います。
+	// どれか 1 つでも残量が 1 未満なら、どのバケットからも使わずに false と、すべてのバケットに補充されるまでの時間を返します。
+	AllowAll(keys []string, n int) (bool, time.Duration)
+}
+
+// bucket はあるキーのトークンバケット。tokens は last の時点の残量
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+type limiter struct {
+	limit     Limit
+	now       func() time.Time
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+// New は limit のトークンバケットでキーごとにレート制限する Limiter を生成します。
+// バケットは最初は満杯で、満杯に戻ったバケットは定期的に捨てるので、キーの数だけメモリを使い続けることはありません。
+func New(limit Limit) Limiter {
+	if limit.Rate <= 0 || limit.Burst < 1 {
+		panic("rate limit must have a positive rate and burst")
+	}
+	return &limiter{limit: limit, now: time.Now, buckets: make(map[string]*bucket)}
+}
+
+func (l *limiter) Allow(key string) (bool, time.Duration) {
+	return l.AllowN(key, 1)
+}
+
+func (l *limiter) AllowN(key string, n int) (bool, time.Duration) {
+	return l.AllowAll([]string{key}, n)
+}
+
+func (l *limiter) AllowAll(keys []string, n int) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	if now.Sub(l.lastSweep) >= sweepInterval {
+		l.sweep(now)
+	}
+
+	// 先にすべてのバケットの残量を確かめ、1 つでも足りなければどれからも使わない
+	buckets := make([]*bucket, len(keys))
+	var wait time.Duration
+	for i, key := range keys {
+		b, ok := l.buckets[key]
+		if !ok {
+			b = &bucket{tokens: float64(l.limit.Burst), last: now}
+			l.buckets[key] = b
+		}
+		b.tokens = l.refill(b, now)
+		b.last = now
+		if b.tokens < 1 {
+			wait = max(wait, time.Duration(math.Ceil((1-b.tokens)/l.limit.Rate*float64(time.Second))))
+		}
+		buckets[i] = b
+	}
+	if wait > 0 {
+		return false, wait
+	}
+	for _, b := range buckets {
+		b.tokens -= float64(n)
+	}
+	return true, 0
+}
+
+// refill は now の時点のバケットの残量を返します。
+func (l *limiter) refill(b *bucket, now time.Time) float64 {
+	elapsed := now.Sub(b.last).Seconds()
+	if elapsed < 0 {
+		elapsed = 0
+	}
+	return math.Min(float64(l.limit.Burst), b.tokens+elapsed*l.limit.Rate)
+}
+
+// sweep は満杯に戻った（新しく作るのと変わらない）バケットを捨てます。
+func (l *limiter) sweep(now time.Time) {
+	for key, b := range l.buckets {
+		if l.refill(b, now) >= float64(l.limit.Burst) {
+			delete(l.buckets, key)
+		}
+	}
+	l.lastSweep = now
+}
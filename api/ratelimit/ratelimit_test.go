@@ -0,0 +1,100 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+)
+
+// newTestLimiter は手動で時刻を進められる Limiter を返します。
+func newTestLimiter(limit Limit) (*limiter, *time.Time) {
+	now := time.Date(2026, time.October, 19, 12, 0, 0, 0, time.UTC)
+	l := New(limit).(*limiter)
+	l.now = func() time.Time { return now }
+	return l, &now
+}
+
+func TestLimiter_BurstThenRefill(t *testing.T) {
+	l, now := newTestLimiter(Limit{Rate: 2, Burst: 3})
+
+	for i := 0; i < 3; i++ {
+		if ok, _ := l.Allow("a"); !ok {
+			t.Fatalf("request %d within burst was rejected", i+1)
+		}
+	}
+	ok, wait := l.Allow("a")
+	if ok || wait != 500*time.Millisecond {
+		t.Fatalf("over burst: got %v, %v, want false, 500ms", ok, wait)
+	}
+
+	// 0.5 秒で 1 トークン補充される
+	*now = now.Add(500 * time.Millisecond)
+	if ok, _ := l.Allow("a"); !ok {
+		t.Error("request after refill was rejected")
+	}
+	if ok, _ := l.Allow("a"); ok {
+		t.Error("second request after a single refill was allowed")
+	}
+}
+
+func TestLimiter_KeysAreIndependent(t *testing.T) {
+	l, _ := newTestLimiter(Limit{Rate: 1, Burst: 1})
+	if ok, _ := l.Allow("a"); !ok {
+		t.Fatal("first request was rejected")
+	}
+	if ok, _ := l.Allow("a"); ok {
+		t.Error("second request for the same key was allowed")
+	}
+	if ok, _ := l.Allow("b"); !ok {
+		t.Error("request for another key was rejected")
+	}
+}
+
+func TestLimiter_SweepsFullBuckets(t *testing.T) {
+	l, now := newTestLimiter(Limit{Rate: 1, Burst: 2})
+	l.Allow("idle")
+	l.Allow("busy")
+	*now = now.Add(sweepInterval)
+	l.Allow("busy")
+	if _, ok := l.buckets["idle"]; ok {
+		t.Error("refilled bucket was not swept")
+	}
+	if _, ok := l.buckets["busy"]; !ok {
+		t.Error("bucket in use was swept")
+	}
+}
+
+func TestLimiter_AllowNBorrowsTokens(t *testing.T) {
+	l, now := newTestLimiter(Limit{Rate: 2, Burst: 3})
+	// Burst を超える重いリクエストも受け付け、前借りした分は返済されるまで待たせる
+	if ok, _ := l.AllowN("a", 10); !ok {
+		t.Fatal("heavy request with a full bucket was rejected")
+	}
+	ok, wait := l.Allow("a")
+	if ok || wait != 4*time.Second {
+		t.Fatalf("after borrowing: got %v, %v, want false, 4s", ok, wait)
+	}
+	*now = now.Add(4 * time.Second)
+	if ok, _ := l.Allow("a"); !ok {
+		t.Error("request after repaying was rejected")
+	}
+}
+
+func TestLimiter_AllowAllTakesNothingWhenRefused(t *testing.T) {
+	l, _ := newTestLimiter(Limit{Rate: 1, Burst: 1})
+	l.Allow("player")
+
+	ok, wait := l.AllowAll([]string{"ip", "player"}, 1)
+	if ok || wait != time.Second {
+		t.Fatalf("with one empty bucket: got %v, %v, want false, 1s", ok, wait)
+	}
+	// 断ったときは、足りていた ip のバケットからも使っていない
+	if ok, _ := l.Allow("ip"); !ok {
+		t.Error("ip bucket was charged for a refused request")
+	}
+	if ok, _ := l.AllowAll([]string{"other", "another"}, 1); !ok {
+		t.Error("request with full buckets was rejected")
+	}
+	if ok, _ := l.Allow("another"); ok {
+		t.Error("allowed request did not charge every bucket")
+	}
+}
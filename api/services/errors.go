@@ -0,0 +1,31 @@
+package services
+
+import (
+	"errors"
+
+	"blackjack/api/game"
+)
+
+// サービス層が返すエラー
+// 詳細を付ける場合は fmt.Errorf("%w: ...", Err...) で包むので、判定には errors.Is を使ってください。
+// ゲームのルールに由来するものは game パッケージのエラーと同一で、どちらで判定しても一致します。
+var (
+	ErrInvalidGameState    = game.ErrInvalidState
+	ErrNotPlayerTurn       = game.ErrNotPlayerTurn
+	ErrGameFinished        = game.ErrGameFinished
+	ErrSurrenderNotAllowed = game.ErrSurrenderNotAllowed
+	ErrSwitchNotAllowed    = game.ErrSwitchNotAllowed
+	ErrUnknownAction       = game.ErrUnknownAction
+	ErrInvalidConfig       = game.ErrInvalidConfig
+	ErrInvalidSideBet      = game.ErrInvalidSideBet
+
+	ErrInvalidBet       = errors.New("bet must be positive")
+	ErrVariantMismatch  = errors.New("invalid state: game variant does not match config")
+	ErrGameNotFinished  = errors.New("invalid state: game is not finished")
+	ErrPlayerIDRequired = errors.New("player id is required")
+	ErrPlayerNotFound   = errors.New("player not found")
+	ErrSessionNotFound  = errors.New("trainer session not found")
+	ErrGameNotFound     = errors.New("game not found")
+	ErrSpotNotFound     = errors.New("spot not found or already answered")
+	ErrInvalidArgument  = errors.New("invalid argument")
+)
@@ -1,14 +1,11 @@
 package services
 
-import (
-	"errors"
-
-	"blackjack/api/game"
-)
+import "blackjack/api/game"
 
 // GameStarter は新規ゲーム開始のみを表す最小インタフェース
 type GameStarter interface {
-	NewGame(bet int) (game.Game, error)
+	// NewGame は config のバリエーションでゲームを開始する。config が nil ならクラシック
+	NewGame(bet int, config *game.GameConfig) (game.Game, error)
 }
 
 // Hitter はヒット（カードを引く）処理のみを表す最小インタフェース
@@ -26,12 +23,18 @@ type Surrenderer interface {
 	Surrender(*game.Game, *game.GameConfig) error
 }
 
+// Switcher はブラックジャック・スイッチの2枚目の入れ替えのみを表す最小インタフェース
+type Switcher interface {
+	Switch(*game.Game, *game.GameConfig) error
+}
+
 // GameService はブラックジャックに必要な全ての処理を提供するインターフェース
 type GameService interface {
 	GameStarter
 	Hitter
 	Stander
 	Surrenderer
+	Switcher
 }
 
 type gameService struct {
@@ -48,13 +51,29 @@ func NewGameService(deck game.Deck) GameService {
 
 // NewGame は掛け金を受け取り、新しいゲームを初期化して返します。
 // bet が 1 未満の場合はエラーを返します。
-func (s *gameService) NewGame(bet int) (game.Game, error) {
+// ブラックジャック・スイッチでは bet は1つの手あたりの掛け金で、Bet には2つの手の合計が入ります。
+func (s *gameService) NewGame(bet int, config *game.GameConfig) (game.Game, error) {
+	rules, err := rulesOf(config)
+	if err != nil {
+		return game.Game{}, err
+	}
 	if bet <= 0 {
-		return game.Game{}, errors.New("bet must be positive")
+		return game.Game{}, ErrInvalidBet
+	}
+	if rules.Hands == 2 {
+		g := s.newSwitchGame(bet, rules)
+		if err := s.skipTwentyOne(&g, config, rules); err != nil {
+			return game.Game{}, err
+		}
+		g.UpdateAllowedActions(rules)
+		return g, nil
 	}
 
-	playerCards := []game.Card{s.deck.Deal(), s.deck.Deal()}
-	dealerCards := []game.Card{s.deck.Deal()}
+	playerCards := []game.Card{s.deal(rules), s.deal(rules)}
+	dealerCards := make([]game.Card, 0, rules.InitialDealerCards)
+	for i := 0; i < rules.InitialDealerCards; i++ {
+		dealerCards = append(dealerCards, s.deal(rules))
+	}
 
 	playerScore := game.CalculateScore(playerCards)
 	dealerScore := game.CalculateScore(dealerCards)
@@ -70,115 +89,160 @@ func (s *gameService) NewGame(bet int) (game.Game, error) {
 		Result:        game.Pending,
 		ResultMessage: "",
 		Payout:        0,
+		Variant:       gameVariant(rules),
 	}
 
 	// ブラックジャック判定
-	if playerScore == 21 {
+	settleNaturals(&g, rules)
+	g.UpdateAllowedActions(rules)
+
+	return g, nil
+}
+
+// SettleBlackjack は配られた直後のプレイヤー手札が21（ブラックジャック）なら、
+// その場でプレイヤーの勝ち（2.5 倍の払い戻し）としてゲームを終了し true を返します。
+func SettleBlackjack(g *game.Game) bool {
+	return settleNaturals(g, game.ClassicRules())
+}
+
+// settleNaturals は配られた直後の 21 を精算します。
+// プレイヤーの 21 はルールの倍率で払い戻し、ディーラーの2枚が見えるルールではディーラーの 21 はその場で負けになります。
+// 両方が 21 の場合はプレイヤーの勝ちです。
+func settleNaturals(g *game.Game, rules game.VariantRules) bool {
+	if !rules.NaturalsSettleImmediately {
+		return false
+	}
+	switch {
+	case len(g.PlayerHand.Cards) == 2 && g.PlayerHand.Score == 21:
 		g.State = game.Finished
 		g.Result = game.PlayerWin
-		g.ResultMessage = game.MessageBlackjackPlayerWin
-		g.Payout = bet * 5 / 2 // 2.5 倍
+		g.SetReason(game.ReasonBlackjack)
+		g.Payout = rules.BlackjackPayout(g.Bet)
+		return true
+	case rules.InitialDealerCards == 2 && len(g.DealerHand.Cards) == 2 && g.DealerHand.Score == 21:
+		g.State = game.Finished
+		g.Result = game.DealerWin
+		g.SetReason(game.ReasonDealerBlackjack)
+		g.Payout = 0
+		return true
 	}
-
-	return g, nil
+	return false
 }
 
 // Stand はプレイヤーターン終了後、ディーラーが設定された閾値以上になるまでカードを引き、
 // 最終結果を判定して Game を返します。
 // g.State が PlayerTurn でない場合はエラーを返します。
+// ブラックジャック・スイッチでは行動中の手を終え、2つ目の手があればそちらに移ります。
 func (s *gameService) Stand(g *game.Game, config *game.GameConfig) error {
-	// 基本整合性
-	if err := g.ValidateCore(); err != nil {
+	rules, err := beginAction(g, config, game.ActionStand)
+	if err != nil {
 		return err
 	}
-	// アクション固有の前提
-	if g.State != game.PlayerTurn {
-		return errors.New("invalid state: game is not in player turn")
-	}
-	if g.Result != game.Pending {
-		return errors.New("invalid state: game already finished")
-	}
-	if len(g.DealerHand.Cards) != 1 {
-		return errors.New("invalid state: dealer must have exactly 1 card")
+	defer g.UpdateAllowedActions(rules)
+	if rules.Hands == 2 {
+		return s.finishSwitchHand(g, config, rules)
 	}
 
-	// ディーラーは設定された閾値以上またはバースト（score==0）で止まる
+	s.playDealer(g, config, rules)
+	SettleHandWithRules(g, rules)
+	return nil
+}
+
+// playDealer はディーラーが設定された閾値以上またはバースト（score==0）になるまで引きます。
+func (s *gameService) playDealer(g *game.Game, config *game.GameConfig, rules game.VariantRules) {
 	for g.DealerHand.Score < config.DealerStandThreshold && g.DealerHand.Score != 0 {
-		card := s.deck.Deal()
+		card := s.deal(rules)
 		g.DealerHand.Cards = append(g.DealerHand.Cards, card)
 		g.DealerHand.Score = game.CalculateScore(g.DealerHand.Cards)
 	}
+}
 
-	dealerScore := g.DealerHand.Score
-	playerScore := g.PlayerHand.Score
+// SettleHand はディーラーの手札が確定した後、プレイヤーの手札と比較して結果と払い戻しを決定し、ゲームを終了します。
+// プレイヤーがバーストしている場合は、ディーラーの手札に関係なくディーラーの勝ちになります。
+func SettleHand(g *game.Game) {
+	SettleHandWithRules(g, game.ClassicRules())
+}
+
+// SettleHandWithRules はバリエーションのルールに従って SettleHand と同じ精算を行います。
+func SettleHandWithRules(g *game.Game, rules game.VariantRules) {
+	result, payout, reason := settleAgainstDealer(g.PlayerHand, g.DealerHand, g.Bet, rules)
+	g.State = game.Finished
+	g.Result = result
+	g.SetReason(reason)
+	g.Payout = payout
+}
 
-	// 結果判定
-	var (
-		result game.Result
-		payout int
-		msg    string
-	)
+// settleAgainstDealer は1つの手をディーラーの手札と比較し、結果・払い戻し・決着の理由を返します。
+func settleAgainstDealer(player, dealer game.Hand, bet int, rules game.VariantRules) (game.Result, int, game.ResultReason) {
+	playerScore := player.Score
+	dealerScore := dealer.Score
+
+	win := func(reason game.ResultReason) (game.Result, int, game.ResultReason) {
+		if payout, ok := rules.Bonus21Payout(player, bet); ok {
+			return game.PlayerWin, payout, game.ReasonBonus21
+		}
+		return game.PlayerWin, bet * 2, reason
+	}
 
 	switch {
 	case playerScore == 0:
-		result = game.DealerWin
-		payout = 0
-		msg = game.MessagePlayerBustDealerWin
+		return game.DealerWin, 0, game.ReasonPlayerBust
+	case rules.IsCharlie(player):
+		return win(game.ReasonCharlie)
+	case dealerScore == 0 && rules.Dealer22Pushes && hardTotal(dealer.Cards) == 22:
+		return game.Push, bet, game.ReasonDealer22Push
 	case dealerScore == 0:
-		result = game.PlayerWin
-		payout = g.Bet * 2
-		msg = game.MessageDealerBustPlayerWin
+		return win(game.ReasonDealerBust)
 	case dealerScore < playerScore:
-		result = game.PlayerWin
-		payout = g.Bet * 2
-		msg = game.MessagePlayerWin
+		return win(game.ReasonPlayerWin)
 	case dealerScore > playerScore:
-		result = game.DealerWin
-		payout = 0
-		msg = game.MessageDealerWin
+		return game.DealerWin, 0, game.ReasonDealerWin
+	case rules.Player21AlwaysWins && playerScore == 21:
+		return win(game.ReasonPlayerWin)
+	case rules.TiesLose:
+		return game.DealerWin, 0, game.ReasonTieDealerWin
 	default:
-		result = game.Push
-		payout = g.Bet
-		msg = game.MessagePush
+		return game.Push, bet, game.ReasonPush
 	}
-
-	g.State = game.Finished
-	g.Result = result
-	g.ResultMessage = msg
-	g.Payout = payout
-
-	return nil
 }
 
 func (s *gameService) Hit(g *game.Game, config *game.GameConfig) error {
-	// 基本整合性
-	if err := g.ValidateCore(); err != nil {
+	rules, err := beginAction(g, config, game.ActionHit)
+	if err != nil {
 		return err
 	}
-	// アクション固有の前提
-	if g.State != game.PlayerTurn {
-		return errors.New("invalid state: game is not in player turn")
-	}
-	if g.Result != game.Pending {
-		return errors.New("invalid state: game already finished")
-	}
+	defer g.UpdateAllowedActions(rules)
 
 	// 1 枚カードを配る
-	card := s.deck.Deal()
+	card := s.deal(rules)
 	g.PlayerHand.Cards = append(g.PlayerHand.Cards, card)
 	g.PlayerHand.Score = game.CalculateScore(g.PlayerHand.Cards)
 
 	playerScore := g.PlayerHand.Score
 
+	// スイッチではバーストや 21、チャーリーでも、もう1つの手が残っていれば続ける
+	if rules.Hands == 2 {
+		if playerScore == 0 || playerScore == 21 || rules.IsCharlie(g.PlayerHand) {
+			return s.finishSwitchHand(g, config, rules)
+		}
+		return nil
+	}
+
 	// バーストチェック
 	if playerScore == 0 {
 		g.State = game.Finished
 		g.Result = game.DealerWin
-		g.ResultMessage = game.MessagePlayerBustDealerWin
+		g.SetReason(game.ReasonPlayerBust)
 		g.Payout = 0
 		return nil
 	}
 
+	// N枚チャーリーはディーラーの手に関わらず勝ちなので、ディーラーは引かずに精算する
+	if rules.IsCharlie(g.PlayerHand) {
+		SettleHandWithRules(g, rules)
+		return nil
+	}
+
 	// 21 ちょうどの場合は自動的にスタンド相当の処理を行う
 	if playerScore == 21 {
 		return s.Stand(g, config)
@@ -192,26 +256,187 @@ func (s *gameService) Hit(g *game.Game, config *game.GameConfig) error {
 // 掛け金の半分を失い、ゲームを終了します。
 // プレイヤーは最初の2枚のカードを受け取った後にのみサレンダーできます。
 func (s *gameService) Surrender(g *game.Game, config *game.GameConfig) error {
-	// 基本整合性
-	if err := g.ValidateCore(); err != nil {
+	rules, err := beginAction(g, config, game.ActionSurrender)
+	if err != nil {
 		return err
 	}
-	// アクション固有の前提
-	if g.State != game.PlayerTurn {
-		return errors.New("invalid state: game is not in player turn")
-	}
-	if g.Result != game.Pending {
-		return errors.New("invalid state: game already finished")
-	}
-	if len(g.PlayerHand.Cards) != 2 {
-		return errors.New("invalid state: surrender is only allowed with initial 2 cards")
-	}
+	defer g.UpdateAllowedActions(rules)
 
 	// サレンダー処理
 	g.State = game.Finished
 	g.Result = game.Surrender
-	g.ResultMessage = game.MessagePlayerSurrendered
+	g.SetReason(game.ReasonSurrender)
 	g.Payout = g.Bet / 2 // 掛け金の半分を返却
 
 	return nil
 }
+
+// Switch はブラックジャック・スイッチで、2つの手の2枚目のカードを入れ替えます。
+// どちらの手でも行動する前に1度だけ行えます。
+func (s *gameService) Switch(g *game.Game, config *game.GameConfig) error {
+	rules, err := beginAction(g, config, game.ActionSwitch)
+	if err != nil {
+		return err
+	}
+	defer g.UpdateAllowedActions(rules)
+
+	sw := g.Switch
+
+	sw.Hands[0].Cards[1], sw.Hands[1].Cards[1] = sw.Hands[1].Cards[1], sw.Hands[0].Cards[1]
+	for i := range sw.Hands {
+		sw.Hands[i].Score = game.CalculateScore(sw.Hands[i].Cards)
+	}
+	sw.Switched = true
+	g.PlayerHand = copyHand(sw.Hands[0])
+	return s.skipTwentyOne(g, config, rules)
+}
+
+// newSwitchGame はブラックジャック・スイッチのゲームを配ります。
+// 初手の 21 はその場では精算せず、通常の 21 として等倍で払い戻します。
+func (s *gameService) newSwitchGame(bet int, rules game.VariantRules) game.Game {
+	var hands [2]game.Hand
+	for i := range hands {
+		cards := []game.Card{s.deal(rules), s.deal(rules)}
+		hands[i] = game.Hand{Cards: cards, Score: game.CalculateScore(cards)}
+	}
+	dealerCards := []game.Card{s.deal(rules)}
+
+	return game.Game{
+		PlayerHand: copyHand(hands[0]),
+		DealerHand: game.Hand{Cards: dealerCards, Score: game.CalculateScore(dealerCards)},
+		State:      game.PlayerTurn,
+		Bet:        bet * 2,
+		Result:     game.Pending,
+		Variant:    rules.Variant,
+		Switch: &game.SwitchHands{
+			Hands:   [2]game.Hand{copyHand(hands[0]), copyHand(hands[1])},
+			HandBet: bet,
+			Results: [2]game.Result{game.Pending, game.Pending},
+		},
+	}
+}
+
+// skipTwentyOne は行動中の手が 21 なら、行動の余地がないのでその手を終えます。
+// 配った直後・入れ替えた後・2つ目の手に移ったときに、どちらの手にも同じように適用します（1つ目の手が 21 なら入れ替えもできません）。
+func (s *gameService) skipTwentyOne(g *game.Game, config *game.GameConfig, rules game.VariantRules) error {
+	if g.PlayerHand.Score == 21 {
+		return s.finishSwitchHand(g, config, rules)
+	}
+	return nil
+}
+
+// finishSwitchHand は行動中の手を終えます。1つ目の手なら2つ目の手に移り、
+// 2つ目の手なら（バーストしていない手があれば）ディーラーが引いて両方の手を精算します。
+func (s *gameService) finishSwitchHand(g *game.Game, config *game.GameConfig, rules game.VariantRules) error {
+	sw := g.Switch
+	sw.Hands[sw.Active] = copyHand(g.PlayerHand)
+	if sw.Active == 0 {
+		sw.Active = 1
+		g.PlayerHand = copyHand(sw.Hands[1])
+		return s.skipTwentyOne(g, config, rules)
+	}
+
+	if sw.Hands[0].Score != 0 || sw.Hands[1].Score != 0 {
+		s.playDealer(g, config, rules)
+	}
+	g.Payout = 0
+	for i, h := range sw.Hands {
+		result, payout, reason := settleAgainstDealer(h, g.DealerHand, sw.HandBet, rules)
+		sw.Results[i] = result
+		sw.Reasons[i] = reason
+		sw.Payouts[i] = payout
+		g.Payout += payout
+	}
+
+	g.State = game.Finished
+	g.SetReason(game.ReasonSwitchSettled)
+	switch {
+	case g.Payout > g.Bet:
+		g.Result = game.PlayerWin
+	case g.Payout < g.Bet:
+		g.Result = game.DealerWin
+	default:
+		g.Result = game.Push
+	}
+	return nil
+}
+
+// CheckAction はゲームを変更せずに、プレイヤーの行動 action の前提を検証します。
+// 行動する前に採点するなど、行動の結果を待たずに前提だけ確かめたい場合に使います。
+func CheckAction(g game.Game, config *game.GameConfig, action game.Action) error {
+	_, err := checkAction(&g, config, action)
+	return err
+}
+
+// checkAction はプレイヤーの行動 action の前提を検証し、ゲームのルールを返します。
+func checkAction(g *game.Game, config *game.GameConfig, action game.Action) (game.VariantRules, error) {
+	// 基本整合性
+	if err := g.ValidateCore(); err != nil {
+		return game.VariantRules{}, err
+	}
+	rules, err := rulesOf(config)
+	if err != nil {
+		return game.VariantRules{}, err
+	}
+	if gameVariant(rules) != g.Variant {
+		return game.VariantRules{}, ErrVariantMismatch
+	}
+	// アクション固有の前提（AllowedActions と同じルールエンジンで判定する）
+	if err := rules.CheckAction(g, action); err != nil {
+		return game.VariantRules{}, err
+	}
+	return rules, nil
+}
+
+// beginAction はプレイヤーの行動 action の前提を検証し、ゲームのルールを返します。
+// スイッチでは PlayerHand を行動中の手として扱うので、ここで2つの手に反映します。
+func beginAction(g *game.Game, config *game.GameConfig, action game.Action) (game.VariantRules, error) {
+	rules, err := checkAction(g, config, action)
+	if err != nil {
+		return game.VariantRules{}, err
+	}
+	if rules.Hands == 2 {
+		g.Switch.Hands[g.Switch.Active] = copyHand(g.PlayerHand)
+	}
+	return rules, nil
+}
+
+// deal はルールのデッキに含まれるカードが出るまで引きます。
+// 一様なデッキから除外ランクを引き直すことは、除外ランクを抜いたデッキから引くことと同じです。
+func (s *gameService) deal(rules game.VariantRules) game.Card {
+	for {
+		c := s.deck.Deal()
+		if rules.AllowsCard(c) {
+			return c
+		}
+	}
+}
+
+// rulesOf は設定のルールを返します。config が nil ならクラシックです。
+func rulesOf(config *game.GameConfig) (game.VariantRules, error) {
+	if config == nil {
+		return game.ClassicRules(), nil
+	}
+	return config.Rules()
+}
+
+// gameVariant は Game.Variant に記録する値を返します（クラシックは空）。
+func gameVariant(rules game.VariantRules) game.Variant {
+	if rules.Variant == game.VariantClassic {
+		return ""
+	}
+	return rules.Variant
+}
+
+// hardTotal は A を 1 として数えた手札の合計を返します。
+func hardTotal(cards []game.Card) int {
+	total := 0
+	for _, c := range cards {
+		total += game.RankToScore(c.Rank)
+	}
+	return total
+}
+
+func copyHand(h game.Hand) game.Hand {
+	return game.Hand{Cards: append([]game.Card(nil), h.Cards...), Score: h.Score}
+}
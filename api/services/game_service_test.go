@@ -30,7 +30,7 @@ func (m *MockDeck) Deal() game.Card {
 
 func TestGameService_NewGame_InvalidBet(t *testing.T) {
 	svc := NewGameService(&mockDeck{})
-	_, err := svc.NewGame(0)
+	_, err := svc.NewGame(0, nil)
 	if err == nil {
 		t.Fatalf("expected error for non-positive bet, got nil")
 	}
@@ -74,7 +74,7 @@ func TestGameService_NewGame_ValidBet(t *testing.T) {
 		deck := &mockDeck{cards: tc.deckCards}
 		svc := NewGameService(deck)
 
-		g, err := svc.NewGame(bet)
+		g, err := svc.NewGame(bet, nil)
 		if err != nil {
 			t.Fatalf("%s: unexpected error: %v", tc.name, err)
 		}
@@ -290,3 +290,42 @@ func TestGameService_Surrender(t *testing.T) {
 		t.Fatalf("expected result message '%s', got '%s'", game.MessagePlayerSurrendered, g.ResultMessage)
 	}
 }
+
+func TestGameService_AllowedActionsMatchValidation(t *testing.T) {
+	config := &game.GameConfig{DealerStandThreshold: 17}
+	// プレイヤー 9-5、ディーラー 9。ヒットで 2 を引いて 16
+	svc := NewGameService(&mockDeck{cards: cardsOf("9", "5", "9", "2", "K")})
+	g, err := svc.NewGame(100, config)
+	if err != nil {
+		t.Fatalf("new game: %v", err)
+	}
+	if len(g.AllowedActions) != 3 {
+		t.Fatalf("expected hit/stand/surrender after the deal, got %v", g.AllowedActions)
+	}
+	if err := svc.Hit(&g, config); err != nil {
+		t.Fatalf("hit: %v", err)
+	}
+
+	// AllowedActions に含まれない行動はサービス層でも拒否される
+	allowed := map[game.Action]bool{}
+	for _, a := range g.AllowedActions {
+		allowed[a] = true
+	}
+	if allowed[game.ActionSurrender] || !allowed[game.ActionHit] || !allowed[game.ActionStand] {
+		t.Fatalf("unexpected actions after hitting: %v", g.AllowedActions)
+	}
+	snapshot := g
+	if err := svc.Surrender(&snapshot, config); err == nil {
+		t.Fatalf("expected surrender outside allowed_actions to be rejected")
+	}
+	if err := svc.Switch(&snapshot, config); err == nil {
+		t.Fatalf("expected switch outside allowed_actions to be rejected")
+	}
+
+	if err := svc.Stand(&g, config); err != nil {
+		t.Fatalf("stand: %v", err)
+	}
+	if g.AllowedActions == nil || len(g.AllowedActions) != 0 {
+		t.Fatalf("expected no actions once the game is finished, got %#v", g.AllowedActions)
+	}
+}
@@ -0,0 +1,144 @@
+package services
+
+import (
+	"sync"
+	"time"
+
+	"blackjack/api/game"
+)
+
+const (
+	// storedGameTTL はこの期間操作のないゲームを捨てる
+	storedGameTTL = time.Hour
+	// maxStoredGames は保持するゲームの上限（超えたら最後の操作が最も古いゲームから捨てる）
+	maxStoredGames = 100000
+)
+
+// StoredGame はサーバーが配って保持しているゲーム
+// 行動はこのゲームと配ったときのルールに対して行うので、クライアントはゲームの状態を送りません。
+type StoredGame struct {
+	ID        string
+	PlayerID  string // ゲームを始めたプレイヤー（匿名なら空）
+	SessionID string // 成績を集計するセッション
+	Game      game.Game
+	Config    game.GameConfig
+	Recorded  bool // 決着を成績に記録したか（同じゲームを二度記録しない）
+}
+
+// GameStore はサーバーが配ったゲームを ID で保持するインタフェース
+type GameStore interface {
+	// Create は ID を割り当ててゲームを保持し、保持した内容を返す
+	Create(sg StoredGame) (StoredGame, error)
+	// Get はゲームの写しを返す。未知のゲームは ErrGameNotFound を返す
+	Get(id string) (StoredGame, error)
+	// Update はゲームの写しを fn で変更して保存する。fn がエラーを返したら何も変更しない。
+	// 同じゲームへの Update は 1 つずつ実行する
+	Update(id string, fn func(sg *StoredGame) error) (StoredGame, error)
+}
+
+// storedEntry は保持しているゲームと最後に操作した時刻。mu は同じゲームへの Update を直列にする
+type storedEntry struct {
+	mu       sync.Mutex
+	game     StoredGame
+	lastSeen time.Time
+}
+
+type memoryGameStore struct {
+	now   func() time.Time
+	games map[string]*storedEntry
+	mu    sync.Mutex
+}
+
+// NewGameStore はゲームをメモリに保持する GameStore を生成します。
+// ゲームは storedGameTTL の間操作がなければ捨て、maxStoredGames を超えて保持しません。
+func NewGameStore() GameStore {
+	return &memoryGameStore{now: time.Now, games: make(map[string]*storedEntry)}
+}
+
+// Create は ID を割り当て、Game.ID にも同じ ID を設定して保持します。
+func (s *memoryGameStore) Create(sg StoredGame) (StoredGame, error) {
+	id, err := newID()
+	if err != nil {
+		return StoredGame{}, err
+	}
+	sg.ID = id
+	sg.Game = sg.Game.Clone()
+	sg.Game.ID = id
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	s.makeRoom(now)
+	s.games[id] = &storedEntry{game: sg, lastSeen: now}
+	return cloneStoredGame(sg), nil
+}
+
+// Get はゲームの写しを返します。
+func (s *memoryGameStore) Get(id string) (StoredGame, error) {
+	e, err := s.entry(id)
+	if err != nil {
+		return StoredGame{}, err
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return cloneStoredGame(e.game), nil
+}
+
+// Update はゲームの写しを fn で変更し、成功したときだけ保存します。
+func (s *memoryGameStore) Update(id string, fn func(sg *StoredGame) error) (StoredGame, error) {
+	e, err := s.entry(id)
+	if err != nil {
+		return StoredGame{}, err
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	sg := cloneStoredGame(e.game)
+	if err := fn(&sg); err != nil {
+		return StoredGame{}, err
+	}
+	// ID と持ち主は変えさせない
+	sg.ID, sg.PlayerID, sg.Game.ID = e.game.ID, e.game.PlayerID, e.game.ID
+	e.game = sg
+	return cloneStoredGame(sg), nil
+}
+
+// entry は期限内のゲームを返し、最後に操作した時刻を更新します。
+func (s *memoryGameStore) entry(id string) (*storedEntry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.games[id]
+	now := s.now()
+	if !ok || now.Sub(e.lastSeen) >= storedGameTTL {
+		return nil, ErrGameNotFound
+	}
+	e.lastSeen = now
+	return e, nil
+}
+
+// makeRoom は新しいゲームを 1 つ追加できるように、期限切れのゲームを捨てます。
+// それでも上限に達していれば、最後の操作が最も古いゲームを捨てます。s.mu を保持して呼びます。
+func (s *memoryGameStore) makeRoom(now time.Time) {
+	if len(s.games) < maxStoredGames {
+		return
+	}
+	var oldestID string
+	var oldest time.Time
+	for id, e := range s.games {
+		if now.Sub(e.lastSeen) >= storedGameTTL {
+			delete(s.games, id)
+			continue
+		}
+		if oldestID == "" || e.lastSeen.Before(oldest) {
+			oldestID, oldest = id, e.lastSeen
+		}
+	}
+	if len(s.games) >= maxStoredGames {
+		delete(s.games, oldestID)
+	}
+}
+
+// cloneStoredGame はゲームの状態を共有しない写しを返します。
+func cloneStoredGame(sg StoredGame) StoredGame {
+	sg.Game = sg.Game.Clone()
+	return sg
+}
@@ -0,0 +1,73 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"blackjack/api/game"
+)
+
+func TestGameStore_CreateGetUpdate(t *testing.T) {
+	store := NewGameStore()
+	cards := []game.Card{{Suit: game.Spade, Rank: "10"}, {Suit: game.Heart, Rank: "6"}}
+	sg, err := store.Create(StoredGame{PlayerID: "p1", Game: game.Game{PlayerHand: game.Hand{Cards: cards}, Bet: 100}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sg.ID == "" || sg.Game.ID != sg.ID {
+		t.Fatalf("expected an id on the stored game, got %q and %q", sg.ID, sg.Game.ID)
+	}
+
+	// 返した写しを変更しても保持しているゲームは変わらない
+	sg.Game.PlayerHand.Cards[0].Rank = "A"
+	got, err := store.Get(sg.ID)
+	if err != nil || got.Game.PlayerHand.Cards[0].Rank != "10" {
+		t.Fatalf("expected the stored game to be unchanged, got %+v, %v", got.Game.PlayerHand, err)
+	}
+
+	// fn が失敗したら何も変えない
+	fail := errors.New("fail")
+	if _, err := store.Update(sg.ID, func(sg *StoredGame) error {
+		sg.Game.Bet = 1
+		return fail
+	}); !errors.Is(err, fail) {
+		t.Fatalf("expected the error from fn, got %v", err)
+	}
+	// ID と持ち主は変えられない
+	got, err = store.Update(sg.ID, func(s *StoredGame) error {
+		s.Game.Bet = 200
+		s.PlayerID, s.ID = "p2", "other"
+		return nil
+	})
+	if err != nil || got.Game.Bet != 200 || got.PlayerID != "p1" || got.ID != sg.ID {
+		t.Fatalf("unexpected update: %+v, %v", got, err)
+	}
+
+	if _, err := store.Get("unknown"); !errors.Is(err, ErrGameNotFound) {
+		t.Fatalf("expected ErrGameNotFound, got %v", err)
+	}
+}
+
+func TestGameStore_EvictsIdleAndOldestGames(t *testing.T) {
+	now := time.Unix(0, 0)
+	store := &memoryGameStore{now: func() time.Time { return now }, games: make(map[string]*storedEntry)}
+
+	idle, _ := store.Create(StoredGame{})
+	now = now.Add(storedGameTTL)
+	if _, err := store.Get(idle.ID); !errors.Is(err, ErrGameNotFound) {
+		t.Fatalf("expected an idle game to expire, got %v", err)
+	}
+
+	first, _ := store.Create(StoredGame{})
+	for i := 0; i < maxStoredGames; i++ {
+		now = now.Add(time.Millisecond)
+		store.Create(StoredGame{})
+	}
+	if len(store.games) > maxStoredGames {
+		t.Fatalf("expected at most %d games, got %d", maxStoredGames, len(store.games))
+	}
+	if _, err := store.Get(first.ID); !errors.Is(err, ErrGameNotFound) {
+		t.Fatalf("expected the oldest game to be evicted, got %v", err)
+	}
+}
@@ -0,0 +1,148 @@
+package services
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"blackjack/api/game"
+	"blackjack/api/strategy"
+)
+
+// 浮動小数点誤差をミスとして数えないための閾値
+const mistakeEpsilon = 1e-9
+
+const (
+	// gradingSessionTTL はこの期間採点のないセッションの集計を捨てる
+	gradingSessionTTL = 24 * time.Hour
+	// maxGradingSessions は保持するセッションの上限（超えたら最後の採点が最も古いセッションから捨てる）
+	maxGradingSessions = 10000
+)
+
+// DecisionGrade はプレイヤーの1回の行動を最適戦略と比較した採点結果
+// 期待値はすべて金額ベース（ベット額でスケール済み）
+type DecisionGrade struct {
+	Action        strategy.Action
+	OptimalAction strategy.Action
+	ChosenEV      float64
+	OptimalEV     float64
+	EVLoss        float64 // OptimalEV - ChosenEV（最適行動なら 0）
+	Session       SessionMistakeSummary
+}
+
+// SessionMistakeSummary はセッション内の採点結果の集計
+type SessionMistakeSummary struct {
+	SessionID   string
+	Decisions   int     // 採点した行動の数
+	Mistakes    int     // 最適でなかった行動の数
+	MistakeCost float64 // EV ロスの合計（金額）
+}
+
+// DecisionGrader は行動を採点し、セッションごとのミスのコストを集計するインタフェース
+type DecisionGrader interface {
+	// Grade は行動前のゲーム状態と選んだ行動を採点し、セッションに記録する
+	Grade(sessionID string, g game.Game, config *game.GameConfig, action strategy.Action) (DecisionGrade, error)
+	// SessionSummary はセッションの集計を返す。未知のセッションは空の集計を返す
+	SessionSummary(sessionID string) SessionMistakeSummary
+}
+
+// gradingSession はセッションの集計と最後に採点した時刻
+type gradingSession struct {
+	summary  SessionMistakeSummary
+	lastSeen time.Time
+}
+
+type decisionGrader struct {
+	advisor  StrategyAdvisor
+	now      func() time.Time
+	sessions map[string]*gradingSession
+	mu       sync.Mutex
+}
+
+// NewDecisionGrader は StrategyAdvisor を用いて行動を採点するサービスを生成します。
+// セッションの集計は gradingSessionTTL の間採点がなければ捨て、maxGradingSessions を超えて保持しません。
+func NewDecisionGrader(advisor StrategyAdvisor) DecisionGrader {
+	if advisor == nil {
+		panic("advisor must not be nil")
+	}
+	return &decisionGrader{advisor: advisor, now: time.Now, sessions: make(map[string]*gradingSession)}
+}
+
+// Grade は最適行動の期待値と選んだ行動の期待値の差を EV ロスとして返します。
+// sessionID が空の場合はセッションへの記録を行いません。
+func (s *decisionGrader) Grade(sessionID string, g game.Game, config *game.GameConfig, action strategy.Action) (DecisionGrade, error) {
+	payouts, err := s.advisor.Advise(g, config)
+	if err != nil {
+		return DecisionGrade{}, err
+	}
+	chosen, ok := payouts.PayoutFor(action)
+	if !ok {
+		return DecisionGrade{}, fmt.Errorf("%w: unsupported action for grading", ErrUnknownAction)
+	}
+
+	grade := DecisionGrade{
+		Action:        action,
+		OptimalAction: payouts.BestAction(),
+		ChosenEV:      chosen,
+		OptimalEV:     payouts.BestPayout,
+		EVLoss:        payouts.BestPayout - chosen,
+	}
+	if grade.EVLoss < mistakeEpsilon {
+		grade.EVLoss = 0
+	}
+
+	if sessionID == "" {
+		return grade, nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	session, ok := s.sessions[sessionID]
+	if !ok {
+		s.makeRoom(now)
+		session = &gradingSession{summary: SessionMistakeSummary{SessionID: sessionID}}
+		s.sessions[sessionID] = session
+	}
+	session.lastSeen = now
+	session.summary.Decisions++
+	if grade.EVLoss > 0 {
+		session.summary.Mistakes++
+		session.summary.MistakeCost += grade.EVLoss
+	}
+	grade.Session = session.summary
+	return grade, nil
+}
+
+// SessionSummary はセッションの集計を返します。
+func (s *decisionGrader) SessionSummary(sessionID string) SessionMistakeSummary {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	session, ok := s.sessions[sessionID]
+	if !ok || s.now().Sub(session.lastSeen) >= gradingSessionTTL {
+		return SessionMistakeSummary{SessionID: sessionID}
+	}
+	return session.summary
+}
+
+// makeRoom は新しいセッションを 1 つ追加できるように、期限切れのセッションを捨てます。
+// それでも上限に達していれば、最後の採点が最も古いセッションを捨てます。s.mu を保持して呼びます。
+func (s *decisionGrader) makeRoom(now time.Time) {
+	if len(s.sessions) < maxGradingSessions {
+		return
+	}
+	var oldestID string
+	var oldest time.Time
+	for id, session := range s.sessions {
+		if now.Sub(session.lastSeen) >= gradingSessionTTL {
+			delete(s.sessions, id)
+			continue
+		}
+		if oldestID == "" || session.lastSeen.Before(oldest) {
+			oldestID, oldest = id, session.lastSeen
+		}
+	}
+	if len(s.sessions) >= maxGradingSessions {
+		delete(s.sessions, oldestID)
+	}
+}
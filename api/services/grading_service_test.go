@@ -0,0 +1,119 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"testing"
+	"time"
+
+	"blackjack/api/game"
+	"blackjack/api/strategy"
+)
+
+// fixedAdvisor は固定の期待払い戻しを返す StrategyAdvisor
+type fixedAdvisor struct {
+	payouts strategy.StrategyExpectedPayouts
+}
+
+func (f fixedAdvisor) Advise(g game.Game, config *game.GameConfig) (strategy.StrategyExpectedPayouts, error) {
+	return f.payouts, nil
+}
+
+func TestDecisionGrader_Grade(t *testing.T) {
+	advisor := fixedAdvisor{payouts: strategy.StrategyExpectedPayouts{
+		HitPayout:       80,
+		StandPayout:     60,
+		SurrenderPayout: 50,
+		BestPayout:      80,
+	}}
+	grader := NewDecisionGrader(advisor)
+	config := &game.GameConfig{DealerStandThreshold: 17}
+
+	// 最適行動（ヒット）は EV ロス 0
+	grade, err := grader.Grade("s1", game.Game{}, config, strategy.ActionHit)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if grade.OptimalAction != strategy.ActionHit || grade.EVLoss != 0 {
+		t.Fatalf("expected optimal hit with no loss, got %+v", grade)
+	}
+
+	// スタンドは 20 のロス
+	grade, err = grader.Grade("s1", game.Game{}, config, strategy.ActionStand)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if math.Abs(grade.EVLoss-20) > 1e-9 {
+		t.Fatalf("expected ev loss 20, got %f", grade.EVLoss)
+	}
+	if grade.Session.Decisions != 2 || grade.Session.Mistakes != 1 || math.Abs(grade.Session.MistakeCost-20) > 1e-9 {
+		t.Fatalf("unexpected session summary: %+v", grade.Session)
+	}
+
+	// セッション ID なしは記録されない
+	if _, err := grader.Grade("", game.Game{}, config, strategy.ActionSurrender); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := grader.SessionSummary("s1"); got.Decisions != 2 {
+		t.Fatalf("expected 2 decisions in s1, got %d", got.Decisions)
+	}
+	if got := grader.SessionSummary("unknown"); got.Decisions != 0 || got.SessionID != "unknown" {
+		t.Fatalf("expected empty summary for unknown session, got %+v", got)
+	}
+}
+
+func TestDecisionGrader_Grade_WithStrategyService(t *testing.T) {
+	grader := NewDecisionGrader(NewStrategyService())
+
+	// ハード20 vs 7 でヒットするのは明確なミス
+	g := game.Game{
+		PlayerHand: game.Hand{Cards: []game.Card{{Suit: game.Spade, Rank: "K"}, {Suit: game.Heart, Rank: "Q"}}},
+		DealerHand: game.Hand{Cards: []game.Card{{Suit: game.Diamond, Rank: "7"}}},
+		State:      game.PlayerTurn,
+		Result:     game.Pending,
+		Bet:        100,
+	}
+	grade, err := grader.Grade("s", g, &game.GameConfig{DealerStandThreshold: 17}, strategy.ActionHit)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if grade.OptimalAction != strategy.ActionStand || grade.EVLoss <= 0 {
+		t.Fatalf("expected hitting hard 20 to be a mistake, got %+v", grade)
+	}
+}
+
+func TestDecisionGrader_EvictsIdleAndOldestSessions(t *testing.T) {
+	grader := NewDecisionGrader(fixedAdvisor{payouts: strategy.StrategyExpectedPayouts{HitPayout: 80, StandPayout: 60, BestPayout: 80}}).(*decisionGrader)
+	now := time.Date(2026, time.October, 19, 12, 0, 0, 0, time.UTC)
+	grader.now = func() time.Time { return now }
+	config := &game.GameConfig{DealerStandThreshold: 17}
+
+	if _, err := grader.Grade("idle", game.Game{}, config, strategy.ActionHit); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// 期限を過ぎたセッションは空の集計になる
+	now = now.Add(gradingSessionTTL)
+	if got := grader.SessionSummary("idle"); got.Decisions != 0 {
+		t.Fatalf("expected idle session to expire, got %+v", got)
+	}
+
+	// 上限に達したら期限切れのセッションから捨て、それでも足りなければ最も古いセッションを捨てる
+	for i := 0; i <= maxGradingSessions; i++ {
+		now = now.Add(time.Millisecond)
+		if _, err := grader.Grade(fmt.Sprintf("s%d", i), game.Game{}, config, strategy.ActionHit); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if len(grader.sessions) != maxGradingSessions {
+		t.Fatalf("expected %d sessions, got %d", maxGradingSessions, len(grader.sessions))
+	}
+	if _, ok := grader.sessions["idle"]; ok {
+		t.Fatal("expected the expired session to be evicted")
+	}
+	if got := grader.SessionSummary("s0"); got.Decisions != 0 {
+		t.Fatalf("expected the oldest session to be evicted, got %+v", got)
+	}
+	if got := grader.SessionSummary("s1"); got.Decisions != 1 {
+		t.Fatalf("expected s1 to be kept, got %+v", got)
+	}
+}
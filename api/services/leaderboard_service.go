@@ -0,0 +1,290 @@
+package services
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"blackjack/api/game"
+)
+
+// LeaderboardMetric はランキングの指標
+type LeaderboardMetric string
+
+const (
+	MetricNetWinnings      LeaderboardMetric = "net_winnings"
+	MetricROI              LeaderboardMetric = "roi"
+	MetricLongestWinStreak LeaderboardMetric = "longest_win_streak"
+	MetricTrainerAccuracy  LeaderboardMetric = "trainer_accuracy"
+)
+
+// LeaderboardWindow はランキングの集計期間（UTC 基準）
+type LeaderboardWindow string
+
+const (
+	WindowDaily   LeaderboardWindow = "daily"  // 当日
+	WindowWeekly  LeaderboardWindow = "weekly" // 当週（ISO 週、月曜始まり）
+	WindowAllTime LeaderboardWindow = "all_time"
+)
+
+// ページサイズの上限
+const maxLeaderboardLimit = 100
+
+// LeaderboardEntry はランキングの1行。同じ値のプレイヤーは同順位になる
+type LeaderboardEntry struct {
+	Rank     int
+	PlayerID string
+	Value    float64
+}
+
+// LeaderboardPage はランキングの1ページ
+type LeaderboardPage struct {
+	Metric  LeaderboardMetric
+	Window  LeaderboardWindow
+	Total   int // ランキングに載っているプレイヤーの総数
+	Offset  int
+	Entries []LeaderboardEntry
+}
+
+// TrainerRecorder はドリルの回答結果を受け取るインタフェース
+type TrainerRecorder interface {
+	RecordTrainerAnswer(playerID string, correct bool) error
+}
+
+// LeaderboardService はゲームの決着とドリルの回答を集計し、ランキングを提供するインタフェース
+type LeaderboardService interface {
+	GameRecorder
+	TrainerRecorder
+	// Leaderboard は指標と期間を指定してランキングの1ページを返す
+	Leaderboard(metric LeaderboardMetric, window LeaderboardWindow, offset, limit int) (LeaderboardPage, error)
+}
+
+// leaderboardAgg は1つの集計期間における1プレイヤーの集計値
+type leaderboardAgg struct {
+	net              int
+	wagered          int
+	currentWinStreak int
+	longestWinStreak int
+	trainerTotal     int
+	trainerCorrect   int
+}
+
+// leaderboardMetrics はランキングの全ての指標
+var leaderboardMetrics = []LeaderboardMetric{MetricNetWinnings, MetricROI, MetricLongestWinStreak, MetricTrainerAccuracy}
+
+type rankingKey struct {
+	bucket string
+	metric LeaderboardMetric
+}
+
+// rankedPlayer はランキングの索引の1行
+type rankedPlayer struct {
+	playerID string
+	value    float64
+}
+
+// before は a が b より上位かを返します（値の降順、同じ値ならプレイヤー ID の昇順）。
+func (a rankedPlayer) before(b rankedPlayer) bool {
+	if a.value != b.value {
+		return a.value > b.value
+	}
+	return a.playerID < b.playerID
+}
+
+type leaderboardService struct {
+	now func() time.Time
+	// 集計期間のキー（"all", "d:2006-01-02", "w:2006-W01"）ごとのプレイヤー集計
+	buckets map[string]map[string]*leaderboardAgg
+	// 集計期間と指標ごとの並べ替え済みランキング。集計を更新したプレイヤーの行だけを並べ直す
+	rankings map[rankingKey][]rankedPlayer
+	mu       sync.Mutex
+}
+
+// NewLeaderboardService はランキング用のサービスを生成します。
+func NewLeaderboardService() LeaderboardService {
+	return newLeaderboardService(time.Now)
+}
+
+func newLeaderboardService(now func() time.Time) *leaderboardService {
+	return &leaderboardService{
+		now:      now,
+		buckets:  make(map[string]map[string]*leaderboardAgg),
+		rankings: make(map[rankingKey][]rankedPlayer),
+	}
+}
+
+// RecordGame は決着したゲームを当日・当週・通算の集計に加えます。config は使用しません。
+func (s *leaderboardService) RecordGame(playerID, sessionID string, g game.Game, config *game.GameConfig) error {
+	if playerID == "" {
+		return ErrPlayerIDRequired
+	}
+	if g.State != game.Finished {
+		return ErrGameNotFinished
+	}
+
+	s.update(playerID, func(a *leaderboardAgg) {
+		a.net += g.Payout - g.Bet
+		a.wagered += g.Bet
+		switch g.Result {
+		case game.PlayerWin:
+			a.currentWinStreak++
+			if a.currentWinStreak > a.longestWinStreak {
+				a.longestWinStreak = a.currentWinStreak
+			}
+		case game.DealerWin, game.Surrender:
+			a.currentWinStreak = 0
+		}
+	})
+	return nil
+}
+
+// RecordTrainerAnswer はドリルの回答を当日・当週・通算の集計に加えます。
+func (s *leaderboardService) RecordTrainerAnswer(playerID string, correct bool) error {
+	if playerID == "" {
+		return ErrPlayerIDRequired
+	}
+	s.update(playerID, func(a *leaderboardAgg) {
+		a.trainerTotal++
+		if correct {
+			a.trainerCorrect++
+		}
+	})
+	return nil
+}
+
+// Leaderboard は並べ替え済みのランキングからページを切り出して返します。
+func (s *leaderboardService) Leaderboard(metric LeaderboardMetric, window LeaderboardWindow, offset, limit int) (LeaderboardPage, error) {
+	switch metric {
+	case MetricNetWinnings, MetricROI, MetricLongestWinStreak, MetricTrainerAccuracy:
+	default:
+		return LeaderboardPage{}, fmt.Errorf("%w: unknown leaderboard metric: %s", ErrInvalidArgument, metric)
+	}
+	if offset < 0 {
+		return LeaderboardPage{}, fmt.Errorf("%w: offset must be non-negative", ErrInvalidArgument)
+	}
+	if limit <= 0 || limit > maxLeaderboardLimit {
+		return LeaderboardPage{}, fmt.Errorf("%w: limit must be between 1 and %d", ErrInvalidArgument, maxLeaderboardLimit)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	bucket, err := bucketKey(window, s.now())
+	if err != nil {
+		return LeaderboardPage{}, err
+	}
+	ranking := s.rankings[rankingKey{bucket: bucket, metric: metric}]
+
+	page := LeaderboardPage{Metric: metric, Window: window, Total: len(ranking), Offset: offset}
+	for i := offset; i < len(ranking) && i < offset+limit; i++ {
+		// 同じ値のプレイヤーは、その値の先頭の行と同じ順位
+		v := ranking[i].value
+		first := sort.Search(i, func(j int) bool { return ranking[j].value <= v })
+		page.Entries = append(page.Entries, LeaderboardEntry{Rank: first + 1, PlayerID: ranking[i].playerID, Value: v})
+	}
+	return page, nil
+}
+
+// update は現在の当日・当週・通算のバケットに対して集計を更新します。
+func (s *leaderboardService) update(playerID string, fn func(a *leaderboardAgg)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	daily, _ := bucketKey(WindowDaily, now)
+	weekly, _ := bucketKey(WindowWeekly, now)
+	allTime, _ := bucketKey(WindowAllTime, now)
+	current := []string{daily, weekly, allTime}
+
+	// 期間の過ぎたバケットは二度と参照されないので捨てる
+	for key := range s.buckets {
+		if key != daily && key != weekly && key != allTime {
+			delete(s.buckets, key)
+		}
+	}
+	for key := range s.rankings {
+		if key.bucket != daily && key.bucket != weekly && key.bucket != allTime {
+			delete(s.rankings, key)
+		}
+	}
+
+	for _, key := range current {
+		players, ok := s.buckets[key]
+		if !ok {
+			players = make(map[string]*leaderboardAgg)
+			s.buckets[key] = players
+		}
+		agg, ok := players[playerID]
+		if !ok {
+			agg = &leaderboardAgg{}
+			players[playerID] = agg
+		}
+		before := *agg
+		fn(agg)
+		for _, metric := range leaderboardMetrics {
+			s.rerank(rankingKey{bucket: key, metric: metric}, playerID, &before, agg)
+		}
+	}
+}
+
+// rerank はプレイヤーの集計が before から after に変わったとき、ランキング key の行を並べ直します（呼び出し側でロックを取ること）。
+// 指標の値が変わらなければ何もしないので、ドリルの回答でゲームの指標のランキングは変わりません。
+func (s *leaderboardService) rerank(key rankingKey, playerID string, before, after *leaderboardAgg) {
+	oldValue, wasRanked := before.value(key.metric)
+	newValue, isRanked := after.value(key.metric)
+	if wasRanked == isRanked && oldValue == newValue {
+		return
+	}
+
+	ranking := s.rankings[key]
+	if wasRanked {
+		old := rankedPlayer{playerID: playerID, value: oldValue}
+		i := sort.Search(len(ranking), func(i int) bool { return !ranking[i].before(old) })
+		ranking = slices.Delete(ranking, i, i+1)
+	}
+	if isRanked {
+		row := rankedPlayer{playerID: playerID, value: newValue}
+		i := sort.Search(len(ranking), func(i int) bool { return !ranking[i].before(row) })
+		ranking = slices.Insert(ranking, i, row)
+	}
+	s.rankings[key] = ranking
+}
+
+// value は指標の値を返します。対象外（ゲームやドリルの記録がない）なら false を返します。
+func (a *leaderboardAgg) value(metric LeaderboardMetric) (float64, bool) {
+	switch metric {
+	case MetricNetWinnings:
+		return float64(a.net), a.wagered > 0
+	case MetricROI:
+		if a.wagered == 0 {
+			return 0, false
+		}
+		return float64(a.net) / float64(a.wagered), true
+	case MetricLongestWinStreak:
+		return float64(a.longestWinStreak), a.wagered > 0
+	case MetricTrainerAccuracy:
+		if a.trainerTotal == 0 {
+			return 0, false
+		}
+		return float64(a.trainerCorrect) / float64(a.trainerTotal), true
+	default:
+		return 0, false
+	}
+}
+
+// bucketKey は期間と時刻から集計バケットのキーを返します。
+func bucketKey(window LeaderboardWindow, t time.Time) (string, error) {
+	t = t.UTC()
+	switch window {
+	case WindowDaily:
+		return "d:" + t.Format("2006-01-02"), nil
+	case WindowWeekly:
+		year, week := t.ISOWeek()
+		return fmt.Sprintf("w:%04d-W%02d", year, week), nil
+	case WindowAllTime:
+		return "all", nil
+	default:
+		return "", fmt.Errorf("%w: unknown leaderboard window: %s", ErrInvalidArgument, window)
+	}
+}
@@ -0,0 +1,203 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"blackjack/api/game"
+)
+
+// fakeClock はテスト用に時刻を進められる時計
+type fakeClock struct {
+	t time.Time
+}
+
+func (c *fakeClock) Now() time.Time { return c.t }
+
+func settledGame(result game.Result, bet, payout int) game.Game {
+	return game.Game{State: game.Finished, Result: result, Bet: bet, Payout: payout}
+}
+
+func TestLeaderboardService_RanksByMetric(t *testing.T) {
+	clock := &fakeClock{t: time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)}
+	svc := newLeaderboardService(clock.Now)
+
+	// alice: +100, +100 (2連勝) / bob: +150 (BJ), -100 / carol: -100
+	svc.RecordGame("alice", "", settledGame(game.PlayerWin, 100, 200), nil)
+	svc.RecordGame("alice", "", settledGame(game.PlayerWin, 100, 200), nil)
+	svc.RecordGame("bob", "", settledGame(game.PlayerWin, 100, 250), nil)
+	svc.RecordGame("bob", "", settledGame(game.DealerWin, 100, 0), nil)
+	svc.RecordGame("carol", "", settledGame(game.DealerWin, 100, 0), nil)
+
+	page, err := svc.Leaderboard(MetricNetWinnings, WindowAllTime, 0, 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if page.Total != 3 || page.Entries[0].PlayerID != "alice" || page.Entries[0].Value != 200 || page.Entries[2].PlayerID != "carol" {
+		t.Fatalf("unexpected net winnings ranking: %+v", page.Entries)
+	}
+
+	page, _ = svc.Leaderboard(MetricROI, WindowAllTime, 0, 10)
+	if page.Entries[0].PlayerID != "alice" || page.Entries[0].Value != 1.0 || page.Entries[1].Value != 0.25 {
+		t.Fatalf("unexpected roi ranking: %+v", page.Entries)
+	}
+
+	page, _ = svc.Leaderboard(MetricLongestWinStreak, WindowAllTime, 0, 10)
+	if page.Entries[0].PlayerID != "alice" || page.Entries[0].Value != 2 {
+		t.Fatalf("unexpected win streak ranking: %+v", page.Entries)
+	}
+
+	// ページング
+	page, _ = svc.Leaderboard(MetricNetWinnings, WindowAllTime, 1, 1)
+	if page.Total != 3 || len(page.Entries) != 1 || page.Entries[0].PlayerID != "bob" || page.Entries[0].Rank != 2 {
+		t.Fatalf("unexpected page: %+v", page)
+	}
+	page, _ = svc.Leaderboard(MetricNetWinnings, WindowAllTime, 5, 10)
+	if len(page.Entries) != 0 {
+		t.Fatalf("expected empty page beyond total, got %+v", page.Entries)
+	}
+}
+
+func TestLeaderboardService_TiesShareRank(t *testing.T) {
+	svc := newLeaderboardService(time.Now)
+	svc.RecordTrainerAnswer("a", true)
+	svc.RecordTrainerAnswer("b", true)
+	svc.RecordTrainerAnswer("c", false)
+
+	page, _ := svc.Leaderboard(MetricTrainerAccuracy, WindowAllTime, 0, 10)
+	if page.Entries[0].Rank != 1 || page.Entries[1].Rank != 1 || page.Entries[2].Rank != 3 {
+		t.Fatalf("expected ranks 1,1,3, got %+v", page.Entries)
+	}
+
+	// ゲーム記録のないプレイヤーは収支ランキングに載らない
+	page, _ = svc.Leaderboard(MetricNetWinnings, WindowAllTime, 0, 10)
+	if page.Total != 0 {
+		t.Fatalf("expected no entries, got %+v", page.Entries)
+	}
+}
+
+func TestLeaderboardService_ReranksUpdatedPlayers(t *testing.T) {
+	svc := newLeaderboardService(time.Now)
+	ranking := func() []LeaderboardEntry {
+		page, _ := svc.Leaderboard(MetricNetWinnings, WindowAllTime, 0, 10)
+		return page.Entries
+	}
+	svc.RecordGame("alice", "", settledGame(game.PlayerWin, 100, 200), nil)
+	svc.RecordGame("bob", "", settledGame(game.PlayerWin, 100, 200), nil)
+	svc.RecordGame("carol", "", settledGame(game.DealerWin, 100, 0), nil)
+	if got := ranking(); got[0].PlayerID != "alice" || got[1].Rank != 1 || got[2].PlayerID != "carol" {
+		t.Fatalf("unexpected ranking: %+v", got)
+	}
+
+	// carol が 2 勝して +100 で並び、bob が負けて最下位に下がる
+	svc.RecordGame("carol", "", settledGame(game.PlayerWin, 100, 300), nil)
+	svc.RecordGame("bob", "", settledGame(game.DealerWin, 100, 0), nil)
+	got := ranking()
+	if len(got) != 3 || got[0].PlayerID != "alice" || got[1].PlayerID != "carol" || got[1].Rank != 1 || got[1].Value != 100 ||
+		got[2].PlayerID != "bob" || got[2].Rank != 3 || got[2].Value != 0 {
+		t.Fatalf("unexpected ranking after updates: %+v", got)
+	}
+
+	// ドリルの回答はゲームの指標のランキングを変えない
+	svc.RecordTrainerAnswer("bob", true)
+	if after := ranking(); len(after) != 3 || after[2].PlayerID != "bob" {
+		t.Fatalf("unexpected ranking after a trainer answer: %+v", after)
+	}
+	if page, _ := svc.Leaderboard(MetricTrainerAccuracy, WindowAllTime, 0, 10); page.Total != 1 || page.Entries[0].PlayerID != "bob" {
+		t.Fatalf("unexpected trainer ranking: %+v", page.Entries)
+	}
+}
+
+func TestLeaderboardService_Windows(t *testing.T) {
+	// 2026-10-19 は月曜日
+	clock := &fakeClock{t: time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)}
+	svc := newLeaderboardService(clock.Now)
+	svc.RecordGame("alice", "", settledGame(game.PlayerWin, 100, 200), nil)
+
+	// 翌日: 当日のランキングは空、当週と通算には残る
+	clock.t = clock.t.Add(24 * time.Hour)
+	svc.RecordGame("bob", "", settledGame(game.PlayerWin, 100, 200), nil)
+
+	daily, _ := svc.Leaderboard(MetricNetWinnings, WindowDaily, 0, 10)
+	weekly, _ := svc.Leaderboard(MetricNetWinnings, WindowWeekly, 0, 10)
+	allTime, _ := svc.Leaderboard(MetricNetWinnings, WindowAllTime, 0, 10)
+	if daily.Total != 1 || daily.Entries[0].PlayerID != "bob" {
+		t.Fatalf("unexpected daily ranking: %+v", daily.Entries)
+	}
+	if weekly.Total != 2 || allTime.Total != 2 {
+		t.Fatalf("expected 2 players weekly and all-time, got %d / %d", weekly.Total, allTime.Total)
+	}
+
+	// 翌週: 当週のランキングはリセットされる
+	clock.t = clock.t.Add(7 * 24 * time.Hour)
+	weekly, _ = svc.Leaderboard(MetricNetWinnings, WindowWeekly, 0, 10)
+	if weekly.Total != 0 {
+		t.Fatalf("expected empty weekly ranking next week, got %+v", weekly.Entries)
+	}
+}
+
+func TestLeaderboardService_InvalidQuery(t *testing.T) {
+	svc := newLeaderboardService(time.Now)
+	if _, err := svc.Leaderboard("unknown", WindowAllTime, 0, 10); err == nil {
+		t.Fatalf("expected error for unknown metric")
+	}
+	if _, err := svc.Leaderboard(MetricROI, "monthly", 0, 10); err == nil {
+		t.Fatalf("expected error for unknown window")
+	}
+	if _, err := svc.Leaderboard(MetricROI, WindowAllTime, 0, maxLeaderboardLimit+1); err == nil {
+		t.Fatalf("expected error for too large limit")
+	}
+}
+
+func TestMultiRecorder_DeliversToAll(t *testing.T) {
+	a := newLeaderboardService(time.Now)
+	b := newLeaderboardService(time.Now)
+	rec := MultiRecorder(a, b)
+
+	if err := rec.RecordGame("p", "", settledGame(game.PlayerWin, 100, 200), nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, svc := range []*leaderboardService{a, b} {
+		page, _ := svc.Leaderboard(MetricNetWinnings, WindowAllTime, 0, 10)
+		if page.Total != 1 {
+			t.Fatalf("expected game delivered to every recorder")
+		}
+	}
+}
+
+func TestHouseRulesOnly_SkipsGamesOutsideHouseRules(t *testing.T) {
+	svc := newLeaderboardService(time.Now)
+	house := game.GameConfig{DealerStandThreshold: 17}
+	rec := HouseRulesOnly(svc, house)
+
+	dealt := settledGame(game.PlayerWin, 100, 200)
+	dealt.ID = "g1"
+	for _, tc := range []struct {
+		name   string
+		g      game.Game
+		config *game.GameConfig
+	}{
+		{"not dealt by the server", settledGame(game.PlayerWin, 100, 200), &house},
+		{"without config", dealt, nil},
+		{"other dealer threshold", dealt, &game.GameConfig{DealerStandThreshold: 12}},
+		{"charlie", dealt, &game.GameConfig{DealerStandThreshold: 17, CharlieCards: 3}},
+		{"spanish 21", dealt, &game.GameConfig{DealerStandThreshold: 17, Variant: game.VariantSpanish21}},
+		{"switch", dealt, &game.GameConfig{DealerStandThreshold: 17, Variant: game.VariantSwitch}},
+		{"double exposure", dealt, &game.GameConfig{DealerStandThreshold: 17, Variant: game.VariantDoubleExposure}},
+	} {
+		if err := rec.RecordGame("p", "", tc.g, tc.config); err != nil {
+			t.Fatalf("%s: unexpected error: %v", tc.name, err)
+		}
+		if page, _ := svc.Leaderboard(MetricNetWinnings, WindowAllTime, 0, 10); page.Total != 0 {
+			t.Fatalf("%s: expected the game not to be ranked", tc.name)
+		}
+	}
+
+	classic := game.GameConfig{DealerStandThreshold: 17, Variant: game.VariantClassic}
+	if err := rec.RecordGame("p", "", dealt, &classic); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if page, _ := svc.Leaderboard(MetricNetWinnings, WindowAllTime, 0, 10); page.Total != 1 {
+		t.Fatalf("expected a dealt game under house rules to be ranked")
+	}
+}
@@ -0,0 +1,48 @@
+package services
+
+import "blackjack/api/game"
+
+// GameRecorder は決着したゲームを受け取るインタフェース
+type GameRecorder interface {
+	// RecordGame は決着したゲームとその設定を記録する。sessionID は空でもよい
+	RecordGame(playerID, sessionID string, g game.Game, config *game.GameConfig) error
+}
+
+// MultiRecorder は決着したゲームを複数の GameRecorder に配信する GameRecorder を返します。
+// 全ての recorder に配信し、最初に発生したエラーを返します。
+func MultiRecorder(recorders ...GameRecorder) GameRecorder {
+	return multiRecorder(recorders)
+}
+
+type multiRecorder []GameRecorder
+
+func (m multiRecorder) RecordGame(playerID, sessionID string, g game.Game, config *game.GameConfig) error {
+	var first error
+	for _, r := range m {
+		if err := r.RecordGame(playerID, sessionID, g, config); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
+
+// HouseRulesOnly は、サーバーが配って保持したゲーム（ID のあるゲーム）のうち、クラシックのブラックジャックで
+// ディーラーの閾値と N枚チャーリーがサーバーの既定のルール house と同じゲームだけを recorder に渡す GameRecorder を返します。
+// ランキングはプレイヤー同士を比べるので、プレイヤーに有利なルールを選んだゲームを載せないために使います。
+// バリエーションは期待値も損益の振れ幅も違い同じ表で比べられないので、クラシック以外のゲームも渡しません。
+func HouseRulesOnly(recorder GameRecorder, house game.GameConfig) GameRecorder {
+	return houseRulesRecorder{recorder: recorder, house: house}
+}
+
+type houseRulesRecorder struct {
+	recorder GameRecorder
+	house    game.GameConfig
+}
+
+func (h houseRulesRecorder) RecordGame(playerID, sessionID string, g game.Game, config *game.GameConfig) error {
+	if g.ID == "" || config == nil || (config.Variant != "" && config.Variant != game.VariantClassic) ||
+		config.DealerStandThreshold != h.house.DealerStandThreshold || config.CharlieCards != h.house.CharlieCards {
+		return nil
+	}
+	return h.recorder.RecordGame(playerID, sessionID, g, config)
+}
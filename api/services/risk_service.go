@@ -0,0 +1,86 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"blackjack/api/game"
+	"blackjack/api/strategy"
+)
+
+const (
+	defaultRuinTrials   = 1000
+	defaultRuinMaxHands = 10000
+	maxRuinTrials       = 20000
+	maxRuinMaxHands     = 100000
+	// maxRuinDraws は 1 回の計算で引く乱数（試行回数 × ハンド数）の上限
+	maxRuinDraws = 10_000_000
+)
+
+// RiskOfRuinEstimator は資金とベット単位から破産確率を見積もるインタフェース
+type RiskOfRuinEstimator interface {
+	// HandStatistics は最適戦略をとった場合の1ハンドあたりの純損益（ベット単位）の平均と分散を返す
+	HandStatistics(config *game.GameConfig) (mean, variance float64, err error)
+	// EstimateRiskOfRuin は破産確率・N0・必要資金を計算して返す。ctx が終わったらシミュレーションを中断する
+	EstimateRiskOfRuin(ctx context.Context, in strategy.RiskOfRuinInput) (strategy.RiskOfRuinResult, error)
+}
+
+type riskOfRuinService struct {
+	calc *strategy.Calculator
+	// newRand は計算ごとの乱数生成器を返す（rand.Rand はスレッドセーフではないので、計算どうしで共有しない）
+	newRand func() *rand.Rand
+}
+
+// NewRiskOfRuinService は破産確率計算用のサービスを生成します。
+func NewRiskOfRuinService() RiskOfRuinEstimator {
+	return &riskOfRuinService{
+		calc:    strategy.NewCalculator(),
+		newRand: func() *rand.Rand { return rand.New(rand.NewSource(time.Now().UnixNano())) },
+	}
+}
+
+// HandStatistics は初期配布からの払い戻し分布を計算し、平均と分散に変換して返します。
+func (s *riskOfRuinService) HandStatistics(config *game.GameConfig) (float64, float64, error) {
+	if config.DealerStandThreshold < 1 || config.DealerStandThreshold > 21 {
+		return 0, 0, fmt.Errorf("%w: dealer stand threshold must be between 1 and 21", ErrInvalidConfig)
+	}
+	dist := s.calc.CalculateInitialOutcomeDistribution(config)
+	mean, variance := strategy.OutcomeStatistics(dist)
+	return mean, variance, nil
+}
+
+// EstimateRiskOfRuin は入力を検証し、試行回数などの既定値を補ってから計算します。
+// 試行回数 × ハンド数は maxRuinDraws までです。
+func (s *riskOfRuinService) EstimateRiskOfRuin(ctx context.Context, in strategy.RiskOfRuinInput) (strategy.RiskOfRuinResult, error) {
+	if in.Bankroll <= 0 {
+		return strategy.RiskOfRuinResult{}, fmt.Errorf("%w: bankroll must be positive", ErrInvalidArgument)
+	}
+	if in.BetUnit <= 0 {
+		return strategy.RiskOfRuinResult{}, fmt.Errorf("%w: bet unit must be positive", ErrInvalidArgument)
+	}
+	if in.Variance <= 0 {
+		return strategy.RiskOfRuinResult{}, fmt.Errorf("%w: variance must be positive", ErrInvalidArgument)
+	}
+	if in.TargetRuin <= 0 || in.TargetRuin >= 1 {
+		return strategy.RiskOfRuinResult{}, fmt.Errorf("%w: target ruin must be between 0 and 1", ErrInvalidArgument)
+	}
+	if in.Trials == 0 {
+		in.Trials = defaultRuinTrials
+	}
+	if in.MaxHands == 0 {
+		in.MaxHands = defaultRuinMaxHands
+	}
+	if in.Trials < 0 || in.Trials > maxRuinTrials {
+		return strategy.RiskOfRuinResult{}, fmt.Errorf("%w: trials out of range", ErrInvalidArgument)
+	}
+	if in.MaxHands < 0 || in.MaxHands > maxRuinMaxHands {
+		return strategy.RiskOfRuinResult{}, fmt.Errorf("%w: max hands out of range", ErrInvalidArgument)
+	}
+	if in.Trials*in.MaxHands > maxRuinDraws {
+		return strategy.RiskOfRuinResult{}, fmt.Errorf("%w: trials * max hands must be at most %d", ErrInvalidArgument, maxRuinDraws)
+	}
+
+	return strategy.CalculateRiskOfRuin(ctx, in, s.newRand())
+}
@@ -0,0 +1,92 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"testing"
+
+	"blackjack/api/game"
+	"blackjack/api/strategy"
+)
+
+func newTestRiskOfRuinService() *riskOfRuinService {
+	return &riskOfRuinService{calc: strategy.NewCalculator(), newRand: func() *rand.Rand { return rand.New(rand.NewSource(1)) }}
+}
+
+func TestRiskOfRuinService_HandStatistics(t *testing.T) {
+	svc := newTestRiskOfRuinService()
+
+	mean, variance, err := svc.HandStatistics(&game.GameConfig{DealerStandThreshold: 17})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if mean < -1 || mean > 1.5 || variance <= 0 {
+		t.Fatalf("unexpected statistics: mean=%f variance=%f", mean, variance)
+	}
+
+	if _, _, err := svc.HandStatistics(&game.GameConfig{DealerStandThreshold: 0}); err == nil {
+		t.Fatalf("expected error for invalid dealer stand threshold")
+	}
+}
+
+func TestRiskOfRuinService_EstimateRiskOfRuin(t *testing.T) {
+	svc := newTestRiskOfRuinService()
+
+	res, err := svc.EstimateRiskOfRuin(context.Background(), strategy.RiskOfRuinInput{
+		Mean:       0.02,
+		Variance:   1.3,
+		Bankroll:   5000,
+		BetUnit:    100,
+		TargetRuin: 0.05,
+		Trials:     200,
+		MaxHands:   2000,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res.AnalyticRuin <= 0 || res.AnalyticRuin >= 1 {
+		t.Fatalf("analytic ruin out of range: %f", res.AnalyticRuin)
+	}
+	if res.SimulatedRuin < 0 || res.SimulatedRuin > 1 {
+		t.Fatalf("simulated ruin out of range: %f", res.SimulatedRuin)
+	}
+	if res.RequiredBankroll <= 0 {
+		t.Fatalf("expected positive required bankroll, got %f", res.RequiredBankroll)
+	}
+}
+
+func TestRiskOfRuinService_EstimateRiskOfRuin_InvalidInput(t *testing.T) {
+	svc := newTestRiskOfRuinService()
+	valid := strategy.RiskOfRuinInput{Mean: 0.02, Variance: 1.3, Bankroll: 5000, BetUnit: 100, TargetRuin: 0.05}
+
+	cases := []struct {
+		name  string
+		alter func(in *strategy.RiskOfRuinInput)
+	}{
+		{"zero bankroll", func(in *strategy.RiskOfRuinInput) { in.Bankroll = 0 }},
+		{"zero bet unit", func(in *strategy.RiskOfRuinInput) { in.BetUnit = 0 }},
+		{"zero variance", func(in *strategy.RiskOfRuinInput) { in.Variance = 0 }},
+		{"target ruin 1", func(in *strategy.RiskOfRuinInput) { in.TargetRuin = 1 }},
+		{"too many trials", func(in *strategy.RiskOfRuinInput) { in.Trials = maxRuinTrials + 1 }},
+		{"too many draws", func(in *strategy.RiskOfRuinInput) { in.Trials, in.MaxHands = maxRuinTrials, maxRuinMaxHands }},
+	}
+	for _, tc := range cases {
+		in := valid
+		tc.alter(&in)
+		if _, err := svc.EstimateRiskOfRuin(context.Background(), in); err == nil {
+			t.Fatalf("%s: expected error, got nil", tc.name)
+		}
+	}
+}
+
+func TestRiskOfRuinService_EstimateRiskOfRuin_Canceled(t *testing.T) {
+	svc := newTestRiskOfRuinService()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := svc.EstimateRiskOfRuin(ctx, strategy.RiskOfRuinInput{Mean: 0.02, Variance: 1.3, Bankroll: 5000, BetUnit: 100, TargetRuin: 0.05})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+}
@@ -0,0 +1,54 @@
+package services
+
+import (
+	"fmt"
+
+	"blackjack/api/game"
+)
+
+// SideBetService はサーバーの配当表でサイドベットを精算するインタフェース
+type SideBetService interface {
+	// SettleSideBets は配られた直後のゲームでサイドベットを精算し、結果を g.SideBets に設定する
+	SettleSideBets(g *game.Game, bets game.SideBets) error
+	// Paytables は精算に使う配当表を返す
+	Paytables() game.SideBetPaytables
+}
+
+type sideBetService struct {
+	paytables game.SideBetPaytables
+}
+
+// NewSideBetService は配当表 paytables で精算する SideBetService を生成します。
+// 配当表は起動時に検証済み（config.Config.Validate）であることを前提とします。
+func NewSideBetService(paytables game.SideBetPaytables) SideBetService {
+	return &sideBetService{paytables: paytables}
+}
+
+// Paytables は精算に使う配当表を返します。
+func (s *sideBetService) Paytables() game.SideBetPaytables {
+	return s.paytables
+}
+
+// SettleSideBets は配られた直後のゲームでサイドベットを精算し、結果を g.SideBets に設定します。
+// 配当はサーバーの配当表に従います。サイドベットは本体の勝敗とは独立しており、プレイヤーの初手2枚とディーラーのアップカードだけで決まります。
+func (s *sideBetService) SettleSideBets(g *game.Game, bets game.SideBets) error {
+	if err := bets.Validate(); err != nil {
+		return err
+	}
+	if len(g.PlayerHand.Cards) < 2 || len(g.DealerHand.Cards) < 1 {
+		return fmt.Errorf("%w: side bets are settled on the initial deal", ErrInvalidGameState)
+	}
+	first, second := g.PlayerHand.Cards[0], g.PlayerHand.Cards[1]
+	upcard := g.DealerHand.Cards[0]
+
+	g.SideBets = nil
+	if bets.PerfectPairs > 0 {
+		outcome := game.EvaluatePerfectPairs(first, second)
+		g.SideBets = append(g.SideBets, game.SettleSideBet(game.SideBetPerfectPairs, bets.PerfectPairs, outcome, s.paytables.PerfectPairs.Odds(outcome)))
+	}
+	if bets.TwentyOnePlusThree > 0 {
+		outcome := game.EvaluateTwentyOnePlusThree(first, second, upcard)
+		g.SideBets = append(g.SideBets, game.SettleSideBet(game.SideBetTwentyOnePlusThree, bets.TwentyOnePlusThree, outcome, s.paytables.TwentyOnePlusThree.Odds(outcome)))
+	}
+	return nil
+}
@@ -0,0 +1,38 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"blackjack/api/game"
+)
+
+func TestSideBetService_PaysFromConfiguredPaytables(t *testing.T) {
+	paytables := game.DefaultSideBetPaytables()
+	paytables.PerfectPairs.Perfect = 30
+	svc := NewSideBetService(paytables)
+
+	// 同じスートのペアと、アップカードとのスーテッド・トリプス
+	seven := game.Card{Suit: game.Spade, Rank: "7"}
+	g := game.Game{
+		PlayerHand: game.Hand{Cards: []game.Card{seven, seven}, Score: 14},
+		DealerHand: game.Hand{Cards: []game.Card{seven}, Score: 7},
+		Bet:        100,
+	}
+	if err := svc.SettleSideBets(&g, game.SideBets{PerfectPairs: 10, TwentyOnePlusThree: 5}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(g.SideBets) != 2 {
+		t.Fatalf("expected 2 side bets, got %+v", g.SideBets)
+	}
+	if pp := g.SideBets[0]; pp.Outcome != game.PairPerfect || pp.Odds != 30 || pp.Payout != 310 {
+		t.Errorf("expected perfect pair paying the configured 30:1, got %+v", pp)
+	}
+	if tot := g.SideBets[1]; tot.Outcome != game.HandSuitedTrips || tot.Payout != 505 {
+		t.Errorf("expected suited trips paying 100:1, got %+v", tot)
+	}
+
+	if err := svc.SettleSideBets(&game.Game{}, game.SideBets{PerfectPairs: 10}); !errors.Is(err, ErrInvalidGameState) {
+		t.Errorf("expected ErrInvalidGameState before the deal, got %v", err)
+	}
+}
@@ -0,0 +1,208 @@
+package services
+
+import (
+	"fmt"
+	"sync"
+
+	"blackjack/api/game"
+)
+
+// StatsSummary はある期間（セッションまたは通算）の成績
+type StatsSummary struct {
+	HandsPlayed       int
+	Wins              int
+	Losses            int
+	Pushes            int
+	Surrenders        int
+	Blackjacks        int // Wins のうちブラックジャックの数
+	TotalWagered      int
+	NetResult         int     // 払い戻し - 掛け金 の合計
+	ExpectedNet       float64 // 最適戦略をとった場合の期待損益の合計
+	LongestWinStreak  int
+	LongestLossStreak int
+
+	currentWinStreak  int
+	currentLossStreak int
+}
+
+// SessionStats はセッションごとの成績
+type SessionStats struct {
+	SessionID string
+	Summary   StatsSummary
+}
+
+// PlayerStats はプレイヤーの通算成績とセッションごとの成績
+type PlayerStats struct {
+	PlayerID string
+	Lifetime StatsSummary
+	Sessions []SessionStats // 最初にプレイした順
+}
+
+// StatsService は決着したゲームを集計し、プレイヤーの成績を提供するインタフェース
+type StatsService interface {
+	GameRecorder
+	// PlayerStats はプレイヤーの成績を返す。記録がなければエラーを返す
+	PlayerStats(playerID string) (PlayerStats, error)
+}
+
+type playerRecord struct {
+	lifetime     StatsSummary
+	sessions     map[string]*StatsSummary
+	sessionOrder []string
+}
+
+type statsService struct {
+	advisor StrategyAdvisor
+	players map[string]*playerRecord
+	mu      sync.RWMutex
+}
+
+// NewStatsService は StrategyAdvisor で期待損益を計算する成績サービスを生成します。
+func NewStatsService(advisor StrategyAdvisor) StatsService {
+	if advisor == nil {
+		panic("advisor must not be nil")
+	}
+	return &statsService{advisor: advisor, players: make(map[string]*playerRecord)}
+}
+
+// RecordGame は決着したゲームを通算とセッションの両方に集計します。
+func (s *statsService) RecordGame(playerID, sessionID string, g game.Game, config *game.GameConfig) error {
+	if playerID == "" {
+		return ErrPlayerIDRequired
+	}
+	if err := g.ValidateCore(); err != nil {
+		return err
+	}
+	if g.State != game.Finished {
+		return ErrGameNotFinished
+	}
+	expected, err := s.expectedNet(g, config)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	rec, ok := s.players[playerID]
+	if !ok {
+		rec = &playerRecord{sessions: make(map[string]*StatsSummary)}
+		s.players[playerID] = rec
+	}
+	rec.lifetime.add(g, expected)
+	if sessionID != "" {
+		sess, ok := rec.sessions[sessionID]
+		if !ok {
+			sess = &StatsSummary{}
+			rec.sessions[sessionID] = sess
+			rec.sessionOrder = append(rec.sessionOrder, sessionID)
+		}
+		sess.add(g, expected)
+	}
+	return nil
+}
+
+// PlayerStats はプレイヤーの成績のコピーを返します。
+func (s *statsService) PlayerStats(playerID string) (PlayerStats, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	rec, ok := s.players[playerID]
+	if !ok {
+		return PlayerStats{}, ErrPlayerNotFound
+	}
+	stats := PlayerStats{PlayerID: playerID, Lifetime: rec.lifetime}
+	for _, id := range rec.sessionOrder {
+		stats.Sessions = append(stats.Sessions, SessionStats{SessionID: id, Summary: *rec.sessions[id]})
+	}
+	return stats, nil
+}
+
+// 配られた直後の状態から、最適戦略をとった場合の期待損益を求める
+// ブラックジャックは戦略に関係なく決まった倍率の勝ちなので、クラシックのゲームでは config を必要としない
+func (s *statsService) expectedNet(g game.Game, config *game.GameConfig) (float64, error) {
+	rules, err := rulesOf(config)
+	if err != nil {
+		return 0, err
+	}
+	if isBlackjack(g) && rules.NaturalsSettleImmediately {
+		return float64(rules.BlackjackPayout(g.Bet) - g.Bet), nil
+	}
+	if config == nil {
+		return 0, fmt.Errorf("%w: config is required to compute expected result", ErrInvalidArgument)
+	}
+	if rules.InitialDealerCards == 2 && len(g.DealerHand.Cards) == 2 && g.DealerHand.Score == 21 && len(g.PlayerHand.Cards) == 2 {
+		// ディーラーの2枚が見えるルールでの初手の負けは戦略に関係ない
+		return -float64(g.Bet), nil
+	}
+
+	// スイッチは2つの手それぞれの期待損益の合計（入れ替えの判断は含まない）
+	hands := []game.Hand{g.PlayerHand}
+	bet := g.Bet
+	if g.Switch != nil {
+		hands = g.Switch.Hands[:]
+		bet = g.Switch.HandBet
+	}
+	expected := 0.0
+	for _, h := range hands {
+		payouts, err := s.advisor.Advise(initialGame(h, g.DealerHand, bet, rules), config)
+		if err != nil {
+			return 0, err
+		}
+		expected += payouts.BestPayout - float64(bet)
+	}
+	return expected, nil
+}
+
+// initialGame は決着したゲームの手札から配られた直後（プレイヤー2枚・ディーラーは表向きの初期枚数）の状態を復元します。
+func initialGame(player, dealer game.Hand, bet int, rules game.VariantRules) game.Game {
+	playerCards := append([]game.Card(nil), player.Cards[:2]...)
+	dealerCards := append([]game.Card(nil), dealer.Cards[:rules.InitialDealerCards]...)
+	return game.Game{
+		PlayerHand: game.Hand{Cards: playerCards, Score: game.CalculateScore(playerCards)},
+		DealerHand: game.Hand{Cards: dealerCards, Score: game.CalculateScore(dealerCards)},
+		State:      game.PlayerTurn,
+		Result:     game.Pending,
+		Bet:        bet,
+		Variant:    gameVariant(rules),
+	}
+}
+
+// isBlackjack は初手2枚で21になって勝ったゲームかを判定します。
+// スイッチの 21 は入れ替えで作れて等倍で払われる（g.Bet も2つの手の合計）ので、ブラックジャックとして数えません。
+func isBlackjack(g game.Game) bool {
+	return g.Switch == nil && g.Result == game.PlayerWin && len(g.PlayerHand.Cards) == 2 && game.CalculateScore(g.PlayerHand.Cards) == 21
+}
+
+func (st *StatsSummary) add(g game.Game, expectedNet float64) {
+	st.HandsPlayed++
+	st.TotalWagered += g.Bet
+	st.NetResult += g.Payout - g.Bet
+	st.ExpectedNet += expectedNet
+
+	switch g.Result {
+	case game.PlayerWin:
+		st.Wins++
+		if isBlackjack(g) {
+			st.Blackjacks++
+		}
+		st.currentWinStreak++
+		st.currentLossStreak = 0
+	case game.DealerWin, game.Surrender:
+		if g.Result == game.Surrender {
+			st.Surrenders++
+		} else {
+			st.Losses++
+		}
+		st.currentLossStreak++
+		st.currentWinStreak = 0
+	case game.Push:
+		// 引き分けは連勝・連敗を途切れさせない
+		st.Pushes++
+	}
+
+	if st.currentWinStreak > st.LongestWinStreak {
+		st.LongestWinStreak = st.currentWinStreak
+	}
+	if st.currentLossStreak > st.LongestLossStreak {
+		st.LongestLossStreak = st.currentLossStreak
+	}
+}
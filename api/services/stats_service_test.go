@@ -0,0 +1,151 @@
+package services
+
+import (
+	"math"
+	"testing"
+
+	"blackjack/api/game"
+	"blackjack/api/strategy"
+)
+
+func finishedGame(player, dealer []game.Card, result game.Result, bet, payout int) game.Game {
+	return game.Game{
+		PlayerHand: game.Hand{Cards: player, Score: game.CalculateScore(player)},
+		DealerHand: game.Hand{Cards: dealer, Score: game.CalculateScore(dealer)},
+		State:      game.Finished,
+		Result:     result,
+		Bet:        bet,
+		Payout:     payout,
+	}
+}
+
+func TestStatsService_RecordGame(t *testing.T) {
+	// 期待払い戻しは常にベットと同額（期待損益 0）とする
+	advisor := fixedAdvisor{payouts: strategy.StrategyExpectedPayouts{BestPayout: 100}}
+	svc := NewStatsService(advisor)
+	config := &game.GameConfig{DealerStandThreshold: 17}
+
+	ten := game.Card{Suit: game.Spade, Rank: "10"}
+	nine := game.Card{Suit: game.Heart, Rank: "9"}
+	ace := game.Card{Suit: game.Club, Rank: "A"}
+	seven := game.Card{Suit: game.Diamond, Rank: "7"}
+
+	games := []game.Game{
+		finishedGame([]game.Card{ten, nine}, []game.Card{seven, ten}, game.PlayerWin, 100, 200),
+		finishedGame([]game.Card{ace, ten}, []game.Card{seven}, game.PlayerWin, 100, 250),
+		finishedGame([]game.Card{ten, seven}, []game.Card{ten, nine}, game.DealerWin, 100, 0),
+		finishedGame([]game.Card{ten, seven}, []game.Card{ten, seven}, game.Push, 100, 100),
+		finishedGame([]game.Card{ten, seven}, []game.Card{ten}, game.Surrender, 100, 50),
+	}
+	for i, g := range games {
+		sessionID := "s1"
+		if i >= 3 {
+			sessionID = "s2"
+		}
+		if err := svc.RecordGame("p1", sessionID, g, config); err != nil {
+			t.Fatalf("game %d: unexpected error: %v", i, err)
+		}
+	}
+
+	stats, err := svc.PlayerStats("p1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	lt := stats.Lifetime
+	if lt.HandsPlayed != 5 || lt.Wins != 2 || lt.Blackjacks != 1 || lt.Losses != 1 || lt.Pushes != 1 || lt.Surrenders != 1 {
+		t.Fatalf("unexpected counts: %+v", lt)
+	}
+	if lt.TotalWagered != 500 || lt.NetResult != 100 {
+		t.Fatalf("expected wagered 500 and net 100, got %d / %d", lt.TotalWagered, lt.NetResult)
+	}
+	// ブラックジャックのみ期待損益 +150、それ以外は 0
+	if math.Abs(lt.ExpectedNet-150) > 1e-9 {
+		t.Fatalf("expected net 150, got %f", lt.ExpectedNet)
+	}
+	// 勝ち, 勝ち, 負け, 引き分け, サレンダー（負け扱い）
+	if lt.LongestWinStreak != 2 || lt.LongestLossStreak != 2 {
+		t.Fatalf("unexpected streaks: win=%d loss=%d", lt.LongestWinStreak, lt.LongestLossStreak)
+	}
+
+	if len(stats.Sessions) != 2 || stats.Sessions[0].SessionID != "s1" || stats.Sessions[0].Summary.HandsPlayed != 3 {
+		t.Fatalf("unexpected sessions: %+v", stats.Sessions)
+	}
+}
+
+func TestStatsService_RecordGame_SwitchedTwentyOneIsNotBlackjack(t *testing.T) {
+	advisor := fixedAdvisor{payouts: strategy.StrategyExpectedPayouts{BestPayout: 100}}
+	svc := NewStatsService(advisor)
+	config := &game.GameConfig{DealerStandThreshold: 17, Variant: game.VariantSwitch}
+
+	// 2枚目を入れ替えて A・K と 10・9 を作り、どちらも等倍で勝った
+	aceKing := []game.Card{{Suit: game.Spade, Rank: "A"}, {Suit: game.Heart, Rank: "K"}}
+	tenNine := []game.Card{{Suit: game.Club, Rank: "10"}, {Suit: game.Diamond, Rank: "9"}}
+	dealer := []game.Card{{Suit: game.Spade, Rank: "7"}, {Suit: game.Club, Rank: "10"}}
+	g := finishedGame(aceKing, dealer, game.PlayerWin, 200, 400)
+	g.Variant = game.VariantSwitch
+	g.Switch = &game.SwitchHands{
+		Hands:   [2]game.Hand{{Cards: aceKing, Score: 21}, {Cards: tenNine, Score: 19}},
+		HandBet: 100,
+	}
+	if err := svc.RecordGame("p1", "", g, config); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	stats, err := svc.PlayerStats("p1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	lt := stats.Lifetime
+	if lt.Wins != 1 || lt.Blackjacks != 0 {
+		t.Fatalf("expected a win without a blackjack, got %+v", lt)
+	}
+	// 期待損益は2つの手それぞれの期待損益（ここでは 0）の合計
+	if lt.ExpectedNet != 0 || lt.NetResult != 200 {
+		t.Fatalf("expected net 0 and actual net 200, got %f / %d", lt.ExpectedNet, lt.NetResult)
+	}
+}
+
+func TestStatsService_RecordGame_Spanish21BonusInExpectedNet(t *testing.T) {
+	config := &game.GameConfig{DealerStandThreshold: 17, Variant: game.VariantSpanish21}
+	dealer := cardsOf("9", "8")
+
+	// どちらも 13 だが、6-7 は 8 を引けば 6-7-8 のボーナス配当になるので期待損益が高い
+	expected := func(player []game.Card) float64 {
+		svc := NewStatsService(NewStrategyService())
+		g := finishedGame(append(player, cardsOf("2")...), dealer, game.DealerWin, 100, 0)
+		g.Variant = game.VariantSpanish21
+		if err := svc.RecordGame("p1", "", g, config); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		stats, err := svc.PlayerStats("p1")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return stats.Lifetime.ExpectedNet
+	}
+	sevens, plain := expected(cardsOf("6", "7")), expected(cardsOf("4", "9"))
+	if sevens <= plain {
+		t.Fatalf("expected 6-7 to be worth more than 4-9, got %f and %f", sevens, plain)
+	}
+}
+
+func TestStatsService_RecordGame_InvalidInput(t *testing.T) {
+	svc := NewStatsService(fixedAdvisor{})
+	config := &game.GameConfig{DealerStandThreshold: 17}
+	cards := []game.Card{{Suit: game.Spade, Rank: "10"}, {Suit: game.Heart, Rank: "7"}}
+	dealer := []game.Card{{Suit: game.Club, Rank: "9"}}
+
+	if err := svc.RecordGame("", "", finishedGame(cards, dealer, game.DealerWin, 100, 0), config); err == nil {
+		t.Fatalf("expected error for missing player id")
+	}
+
+	pending := finishedGame(cards, dealer, game.Pending, 100, 0)
+	pending.State = game.PlayerTurn
+	if err := svc.RecordGame("p1", "", pending, config); err == nil {
+		t.Fatalf("expected error for unfinished game")
+	}
+
+	if _, err := svc.PlayerStats("unknown"); err == nil {
+		t.Fatalf("expected error for unknown player")
+	}
+}
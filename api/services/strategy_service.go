@@ -1,6 +1,9 @@
 package services
 
 import (
+	"fmt"
+	"sync"
+
 	"blackjack/api/game"
 	"blackjack/api/strategy"
 )
@@ -37,10 +40,24 @@ func (s *strategyService) Advise(g game.Game, config *game.GameConfig) (strategy
 		}
 	}
 
-	// ディーラー手札（アップカードのみ使用）
-	dealerUpcard := g.DealerHand.Cards[0]
-	dealerSum := game.RankToScore(dealerUpcard.Rank)
-	dealerHasAce := dealerUpcard.Rank == "A"
+	rules, err := rulesOf(config)
+	if err != nil {
+		return strategy.StrategyExpectedPayouts{}, err
+	}
+
+	// ディーラー手札（表向きのカードのみ使用。通常はアップカード1枚、ダブル・エクスポージャーは2枚）
+	visible := rules.InitialDealerCards
+	if visible > len(g.DealerHand.Cards) {
+		visible = len(g.DealerHand.Cards)
+	}
+	dealerSum := 0
+	dealerHasAce := false
+	for _, c := range g.DealerHand.Cards[:visible] {
+		dealerSum += game.RankToScore(c.Rank)
+		if c.Rank == "A" {
+			dealerHasAce = true
+		}
+	}
 
 	hasHit := len(g.PlayerHand.Cards) > 2
 
@@ -48,15 +65,77 @@ func (s *strategyService) Advise(g game.Game, config *game.GameConfig) (strategy
 		Player: strategy.StrategyHand{Sum: playerSum, HasAce: playerHasAce},
 		Dealer: strategy.StrategyHand{Sum: dealerSum, HasAce: dealerHasAce},
 		HasHit: hasHit,
+		Cards:  len(g.PlayerHand.Cards),
+		// スパニッシュ21 の 6-7-8 / 7-7-7 は初手2枚のランクとスートで決まる
+		Bonus21: strategy.Bonus21DrawFor(g.PlayerHand.Cards),
 	}
 
 	payouts := s.calc.CalculateAllExpectedPayouts(st, config)
 
 	// 実際の払戻額を返すために、サービス層でスケーリング
+	// スイッチでは行動中の手の掛け金でスケーリングする
 	betF := float64(g.Bet)
+	if g.Switch != nil {
+		betF = float64(g.Switch.HandBet)
+	}
 	payouts.HitPayout *= betF
 	payouts.StandPayout *= betF
 	payouts.SurrenderPayout *= betF
 	payouts.BestPayout *= betF
 	return payouts, nil
 }
+
+// AdvicePosition は一括評価する 1 つの局面
+type AdvicePosition struct {
+	Game   game.Game
+	Config game.GameConfig
+}
+
+// AdviceResult は 1 つの局面の評価結果。Err が nil でなければ Payouts は使わないでください。
+type AdviceResult struct {
+	Payouts strategy.StrategyExpectedPayouts
+	Err     error
+}
+
+// AdviseBatch は positions を最大 workers 個の goroutine で並行に評価し、positions と同じ順序で結果を返します。
+// advisor は並行に呼び出すので、スレッドセーフである必要があります（NewStrategyService のサービスは
+// 1 つの Calculator のメモ化テーブルを共有します）。1 つの局面のエラーやパニックはその局面の Err になり、
+// 他の局面の評価は続けます。workers が 1 未満なら 1 として扱います。
+func AdviseBatch(advisor StrategyAdvisor, positions []AdvicePosition, workers int) []AdviceResult {
+	results := make([]AdviceResult, len(positions))
+	if workers < 1 {
+		workers = 1
+	}
+	if workers > len(positions) {
+		workers = len(positions)
+	}
+
+	next := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				results[i] = adviseOne(advisor, positions[i])
+			}
+		}()
+	}
+	for i := range positions {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+	return results
+}
+
+// adviseOne は 1 つの局面を評価します。評価中のパニックはエラーとして返します。
+func adviseOne(advisor StrategyAdvisor, p AdvicePosition) (result AdviceResult) {
+	defer func() {
+		if r := recover(); r != nil {
+			result = AdviceResult{Err: fmt.Errorf("advise panicked: %v", r)}
+		}
+	}()
+	payouts, err := advisor.Advise(p.Game, &p.Config)
+	return AdviceResult{Payouts: payouts, Err: err}
+}
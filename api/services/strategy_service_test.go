@@ -1,9 +1,12 @@
 package services
 
 import (
+	"errors"
+	"strings"
 	"testing"
 
 	"blackjack/api/game"
+	"blackjack/api/strategy"
 )
 
 func TestStrategyService_Advise_ConvertsGameToStrategyState(t *testing.T) {
@@ -50,4 +53,60 @@ func TestStrategyService_Advise_InvalidInput(t *testing.T) {
 	if _, err := svc.Advise(g2, config); err == nil {
 		t.Fatalf("expected error for insufficient player cards")
 	}
-}
\ No newline at end of file
+}
+// panickyAdvisor は掛け金 0 のゲームでパニックし、それ以外は本物のサービスで計算する
+type panickyAdvisor struct{ StrategyAdvisor }
+
+func (a panickyAdvisor) Advise(g game.Game, config *game.GameConfig) (strategy.StrategyExpectedPayouts, error) {
+	if g.Bet == 0 {
+		panic("boom")
+	}
+	return a.StrategyAdvisor.Advise(g, config)
+}
+
+func TestAdviseBatch_MatchesAdviseInOrder(t *testing.T) {
+	svc := NewStrategyService()
+	var positions []AdvicePosition
+	for _, up := range []game.Rank{"2", "5", "7", "10", "A"} {
+		for _, second := range []game.Rank{"2", "6", "9"} {
+			positions = append(positions, AdvicePosition{
+				Game: game.Game{
+					PlayerHand: game.Hand{Cards: []game.Card{{Suit: game.Spade, Rank: "10"}, {Suit: game.Heart, Rank: second}}},
+					DealerHand: game.Hand{Cards: []game.Card{{Suit: game.Club, Rank: up}}},
+					State:      game.PlayerTurn,
+					Result:     game.Pending,
+					Bet:        10,
+				},
+				Config: game.GameConfig{DealerStandThreshold: 17},
+			})
+		}
+	}
+	positions[3].Game.PlayerHand.Cards = nil // 局面のエラー
+	positions[7].Game.Bet = 0                // パニック
+
+	results := AdviseBatch(panickyAdvisor{svc}, positions, 4)
+	if len(results) != len(positions) {
+		t.Fatalf("results = %d, want %d", len(results), len(positions))
+	}
+	for i, res := range results {
+		switch i {
+		case 3:
+			if !errors.Is(res.Err, ErrInvalidGameState) {
+				t.Errorf("result[%d].Err = %v, want ErrInvalidGameState", i, res.Err)
+			}
+		case 7:
+			if res.Err == nil || !strings.Contains(res.Err.Error(), "boom") {
+				t.Errorf("result[%d].Err = %v, want recovered panic", i, res.Err)
+			}
+		default:
+			want, err := svc.Advise(positions[i].Game, &positions[i].Config)
+			if res.Err != nil || err != nil || res.Payouts != want {
+				t.Errorf("result[%d] = %+v, want %+v", i, res, want)
+			}
+		}
+	}
+
+	if got := AdviseBatch(svc, nil, 4); len(got) != 0 {
+		t.Errorf("empty batch = %v", got)
+	}
+}
@@ -0,0 +1,449 @@
+package services
+
+import (
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"blackjack/api/game"
+	"blackjack/api/strategy"
+)
+
+// TrainerAnswer はドリルでの回答（H/S/R）
+// ダブルダウン・スプリットはこのゲームでは選べないため、回答にも使えません。
+type TrainerAnswer string
+
+const (
+	AnswerHit       TrainerAnswer = "H"
+	AnswerStand     TrainerAnswer = "S"
+	AnswerSurrender TrainerAnswer = "R"
+)
+
+// TrainerCategory は出題の分類
+type TrainerCategory string
+
+const (
+	CategoryHard      TrainerCategory = "hard"
+	CategorySoft      TrainerCategory = "soft"
+	CategoryPairs     TrainerCategory = "pairs"
+	CategorySurrender TrainerCategory = "surrender" // 正解がサレンダーの問題
+)
+
+// 間違えた問題の重みの上限（間違えるたびに倍、正解するたびに半分になる）
+const (
+	minSpotWeight = 1.0
+	maxSpotWeight = 16.0
+)
+
+// maxPendingSpots は 1 つのセッションで回答を待つ問題の上限（超えたら最も古い問題を取り消す）
+const maxPendingSpots = 20
+
+// TrainerSpot はドリルの1問（初手2枚とディーラーのアップカード）
+type TrainerSpot struct {
+	ID           string
+	PlayerCards  []game.Card
+	DealerUpcard game.Card
+	Config       game.GameConfig
+}
+
+// CategoryAccuracy は分類ごとの正答数
+type CategoryAccuracy struct {
+	Total   int
+	Correct int
+}
+
+// TrainerStats はドリルセッションの成績
+type TrainerStats struct {
+	Total      int
+	Correct    int
+	Categories map[TrainerCategory]CategoryAccuracy
+}
+
+// TrainerResult は回答の採点結果
+type TrainerResult struct {
+	Correct       bool
+	Answer        TrainerAnswer
+	CorrectAnswer TrainerAnswer
+	Category      TrainerCategory // 出題時に示すと正解が分かってしまうため、採点時に返す
+	EVLoss        float64         // ベット1単位あたりの期待払い戻しの損失
+	Stats         TrainerStats
+}
+
+// Trainer はベーシックストラテジーのドリルを提供するインタフェース
+type Trainer interface {
+	// StartSession は新しいドリルセッションを開始し、セッション ID を返す
+	// playerID を指定すると、回答結果がランキングなどに記録される
+	StartSession(playerID string, config game.GameConfig) (string, error)
+	// NextSpot は次の問題を出題する。よく間違える問題ほど出題されやすい
+	NextSpot(sessionID string) (TrainerSpot, error)
+	// Answer は出題済みの問題に回答し、採点結果を返す
+	Answer(sessionID, spotID string, answer TrainerAnswer) (TrainerResult, error)
+	// Stats はセッションの成績を返す
+	Stats(sessionID string) (TrainerStats, error)
+}
+
+// 出題の種類（カテゴリとは別に、手札の形だけを表す）
+type handKind int
+
+const (
+	kindHard handKind = iota
+	kindSoft
+	kindPair
+)
+
+// spotKey は出題を手札の形とアップカードで同一視するためのキー
+// Value はハードなら合計、ソフトならエース以外のカード、ペアならカード1枚の値
+type spotKey struct {
+	Kind   handKind
+	Value  int
+	Upcard int
+}
+
+type trainerSession struct {
+	playerID string
+	config   game.GameConfig
+	rules    game.VariantRules
+	weights  map[spotKey]float64
+	pending  map[string]spotKey
+	// pendingOrder は回答を待つ問題の ID を出題順に並べたもの
+	pendingOrder []string
+	stats        TrainerStats
+}
+
+type trainerService struct {
+	calc     *strategy.Calculator
+	keys     []spotKey
+	sessions map[string]*trainerSession
+	recorder TrainerRecorder
+	rng      *rand.Rand
+	mu       sync.Mutex
+}
+
+// NewTrainerService はドリル用のサービスを生成します。
+// recorder が nil でなければ、プレイヤーに紐づくセッションの回答結果を記録します。
+func NewTrainerService(recorder TrainerRecorder) Trainer {
+	return newTrainerService(recorder, rand.New(rand.NewSource(time.Now().UnixNano())))
+}
+
+func newTrainerService(recorder TrainerRecorder, rng *rand.Rand) *trainerService {
+	return &trainerService{
+		calc:     strategy.NewCalculator(),
+		keys:     allSpotKeys(),
+		sessions: make(map[string]*trainerSession),
+		recorder: recorder,
+		rng:      rng,
+	}
+}
+
+// ブラックジャックを除く全ての初手の形 × アップカードを列挙する
+func allSpotKeys() []spotKey {
+	var keys []spotKey
+	for up := 1; up <= 10; up++ {
+		for total := 5; total <= 19; total++ {
+			keys = append(keys, spotKey{Kind: kindHard, Value: total, Upcard: up})
+		}
+		for other := 2; other <= 9; other++ {
+			keys = append(keys, spotKey{Kind: kindSoft, Value: other, Upcard: up})
+		}
+		for v := 1; v <= 10; v++ {
+			keys = append(keys, spotKey{Kind: kindPair, Value: v, Upcard: up})
+		}
+	}
+	return keys
+}
+
+// StartSession は設定を検証してセッションを作成します。
+// ルールのバリエーションと N枚チャーリーはゲームと同じく config.Rules で検証します。
+// 問題はアップカード 1 枚で出すので、ディーラーの2枚が見えるルールには対応しません。
+func (s *trainerService) StartSession(playerID string, config game.GameConfig) (string, error) {
+	if config.DealerStandThreshold < 1 || config.DealerStandThreshold > 21 {
+		return "", fmt.Errorf("%w: dealer stand threshold must be between 1 and 21", ErrInvalidConfig)
+	}
+	rules, err := config.Rules()
+	if err != nil {
+		return "", err
+	}
+	if rules.InitialDealerCards != 1 {
+		return "", fmt.Errorf("%w: the trainer does not support variant %s", ErrInvalidConfig, rules.Variant)
+	}
+	id, err := newID()
+	if err != nil {
+		return "", err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sessions[id] = &trainerSession{
+		playerID: playerID,
+		config:   config,
+		rules:    rules,
+		weights:  make(map[spotKey]float64),
+		pending:  make(map[string]spotKey),
+		stats:    TrainerStats{Categories: make(map[TrainerCategory]CategoryAccuracy)},
+	}
+	return id, nil
+}
+
+// NextSpot は重み付きで問題を選び、具体的なカードに変換して返します。
+func (s *trainerService) NextSpot(sessionID string) (TrainerSpot, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sess, ok := s.sessions[sessionID]
+	if !ok {
+		return TrainerSpot{}, ErrSessionNotFound
+	}
+
+	key := s.pickSpot(sess)
+	id, err := newID()
+	if err != nil {
+		return TrainerSpot{}, err
+	}
+	sess.addPending(id, key)
+
+	playerCards, upcard := s.cardsFor(key, sess.rules)
+	return TrainerSpot{
+		ID:           id,
+		PlayerCards:  playerCards,
+		DealerUpcard: upcard,
+		Config:       sess.config,
+	}, nil
+}
+
+// Answer はチャート（Calculator の最適行動）と比較して採点し、出題の重みを更新します。
+func (s *trainerService) Answer(sessionID, spotID string, answer TrainerAnswer) (TrainerResult, error) {
+	action, ok := answerToAction(answer)
+	if !ok {
+		return TrainerResult{}, fmt.Errorf("%w: answer must be one of H, S, R (double down and split are not offered)", ErrInvalidArgument)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sess, found := s.sessions[sessionID]
+	if !found {
+		return TrainerResult{}, ErrSessionNotFound
+	}
+	key, found := sess.pending[spotID]
+	if !found {
+		return TrainerResult{}, ErrSpotNotFound
+	}
+	sess.removePending(spotID)
+
+	payouts := s.calc.CalculateAllExpectedPayouts(key.state(), &sess.config)
+	correctAnswer, category := s.chartAnswer(key, &sess.config)
+	correct := answer == correctAnswer
+
+	chosen, _ := payouts.PayoutFor(action)
+	evLoss := payouts.BestPayout - chosen
+	if evLoss < mistakeEpsilon {
+		evLoss = 0
+	}
+
+	// 間違えた問題は重みを上げ、正解した問題は下げる
+	w := sess.weights[key]
+	if w == 0 {
+		w = minSpotWeight
+	}
+	if correct {
+		w /= 2
+	} else {
+		w *= 2
+	}
+	sess.weights[key] = clampWeight(w)
+
+	sess.stats.Total++
+	acc := sess.stats.Categories[category]
+	acc.Total++
+	if correct {
+		sess.stats.Correct++
+		acc.Correct++
+	}
+	sess.stats.Categories[category] = acc
+
+	if s.recorder != nil && sess.playerID != "" {
+		if err := s.recorder.RecordTrainerAnswer(sess.playerID, correct); err != nil {
+			return TrainerResult{}, err
+		}
+	}
+
+	return TrainerResult{
+		Correct:       correct,
+		Answer:        answer,
+		CorrectAnswer: correctAnswer,
+		Category:      category,
+		EVLoss:        evLoss,
+		Stats:         copyStats(sess.stats),
+	}, nil
+}
+
+// Stats はセッションの成績のコピーを返します。
+func (s *trainerService) Stats(sessionID string) (TrainerStats, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sess, ok := s.sessions[sessionID]
+	if !ok {
+		return TrainerStats{}, ErrSessionNotFound
+	}
+	return copyStats(sess.stats), nil
+}
+
+// 重みに比例した確率で出題を選ぶ（呼び出し側でロックを取ること）
+func (s *trainerService) pickSpot(sess *trainerSession) spotKey {
+	total := 0.0
+	for _, k := range s.keys {
+		total += sess.weight(k)
+	}
+	r := s.rng.Float64() * total
+	for _, k := range s.keys {
+		r -= sess.weight(k)
+		if r < 0 {
+			return k
+		}
+	}
+	return s.keys[len(s.keys)-1]
+}
+
+// チャートの正解と出題カテゴリを返す
+func (s *trainerService) chartAnswer(key spotKey, config *game.GameConfig) (TrainerAnswer, TrainerCategory) {
+	best := s.calc.CalculateAllExpectedPayouts(key.state(), config).BestAction()
+	answer := actionToAnswer(best)
+	switch {
+	case answer == AnswerSurrender:
+		return answer, CategorySurrender
+	case key.Kind == kindPair:
+		return answer, CategoryPairs
+	case key.Kind == kindSoft:
+		return answer, CategorySoft
+	default:
+		return answer, CategoryHard
+	}
+}
+
+// 出題キーを具体的なカードに変換する（スートや絵札はランダム。ルールのデッキにないカードは使わない）
+func (s *trainerService) cardsFor(key spotKey, rules game.VariantRules) ([]game.Card, game.Card) {
+	var a, b int
+	switch key.Kind {
+	case kindPair:
+		a, b = key.Value, key.Value
+	case kindSoft:
+		a, b = 1, key.Value
+	default:
+		// 合計が key.Value になる、ペアでもエースでもない2枚を選ぶ
+		var candidates [][2]int
+		for x := 2; x <= 9; x++ {
+			y := key.Value - x
+			if y > x && y <= 10 {
+				candidates = append(candidates, [2]int{x, y})
+			}
+		}
+		c := candidates[s.rng.Intn(len(candidates))]
+		a, b = c[0], c[1]
+	}
+	return []game.Card{s.randomCard(a, rules), s.randomCard(b, rules)}, s.randomCard(key.Upcard, rules)
+}
+
+func (s *trainerService) randomCard(value int, rules game.VariantRules) game.Card {
+	suits := []game.Suit{game.Spade, game.Heart, game.Diamond, game.Club}
+	suit := suits[s.rng.Intn(len(suits))]
+	switch value {
+	case 1:
+		return game.Card{Suit: suit, Rank: "A"}
+	case 10:
+		var tens []game.Rank
+		for _, rank := range []game.Rank{"10", "J", "Q", "K"} {
+			if rules.AllowsCard(game.Card{Suit: suit, Rank: rank}) {
+				tens = append(tens, rank)
+			}
+		}
+		return game.Card{Suit: suit, Rank: tens[s.rng.Intn(len(tens))]}
+	default:
+		return game.Card{Suit: suit, Rank: game.Rank(string(rune('0' + value)))}
+	}
+}
+
+func (k spotKey) state() strategy.StrategyState {
+	var player strategy.StrategyHand
+	switch k.Kind {
+	case kindPair:
+		player = strategy.StrategyHand{Sum: 2 * k.Value, HasAce: k.Value == 1}
+	case kindSoft:
+		player = strategy.StrategyHand{Sum: 1 + k.Value, HasAce: true}
+	default:
+		player = strategy.StrategyHand{Sum: k.Value}
+	}
+	return strategy.StrategyState{
+		Player: player,
+		Dealer: strategy.StrategyHand{Sum: k.Upcard, HasAce: k.Upcard == 1},
+	}
+}
+
+// addPending は回答を待つ問題を追加します。上限を超えたら最も古い問題を取り消します。
+func (sess *trainerSession) addPending(id string, key spotKey) {
+	if len(sess.pendingOrder) >= maxPendingSpots {
+		delete(sess.pending, sess.pendingOrder[0])
+		sess.pendingOrder = sess.pendingOrder[1:]
+	}
+	sess.pending[id] = key
+	sess.pendingOrder = append(sess.pendingOrder, id)
+}
+
+// removePending は回答した問題を取り除きます。
+func (sess *trainerSession) removePending(id string) {
+	delete(sess.pending, id)
+	for i, pending := range sess.pendingOrder {
+		if pending == id {
+			sess.pendingOrder = append(sess.pendingOrder[:i], sess.pendingOrder[i+1:]...)
+			return
+		}
+	}
+}
+
+func (sess *trainerSession) weight(k spotKey) float64 {
+	if w, ok := sess.weights[k]; ok {
+		return w
+	}
+	return minSpotWeight
+}
+
+func clampWeight(w float64) float64 {
+	if w < minSpotWeight {
+		return minSpotWeight
+	}
+	if w > maxSpotWeight {
+		return maxSpotWeight
+	}
+	return w
+}
+
+func copyStats(st TrainerStats) TrainerStats {
+	cats := make(map[TrainerCategory]CategoryAccuracy, len(st.Categories))
+	for k, v := range st.Categories {
+		cats[k] = v
+	}
+	st.Categories = cats
+	return st
+}
+
+func answerToAction(a TrainerAnswer) (strategy.Action, bool) {
+	switch a {
+	case AnswerHit:
+		return strategy.ActionHit, true
+	case AnswerStand:
+		return strategy.ActionStand, true
+	case AnswerSurrender:
+		return strategy.ActionSurrender, true
+	default:
+		return "", false
+	}
+}
+
+func actionToAnswer(a strategy.Action) TrainerAnswer {
+	switch a {
+	case strategy.ActionHit:
+		return AnswerHit
+	case strategy.ActionSurrender:
+		return AnswerSurrender
+	default:
+		return AnswerStand
+	}
+}
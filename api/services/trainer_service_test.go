@@ -0,0 +1,180 @@
+package services
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+	"time"
+
+	"blackjack/api/game"
+)
+
+func TestTrainerService_SpotAndAnswer(t *testing.T) {
+	svc := newTrainerService(nil, rand.New(rand.NewSource(1)))
+	config := game.GameConfig{DealerStandThreshold: 17}
+
+	sessionID, err := svc.StartSession("", config)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	spot, err := svc.NextSpot(sessionID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(spot.PlayerCards) != 2 {
+		t.Fatalf("expected 2 player cards, got %d", len(spot.PlayerCards))
+	}
+	if score := game.CalculateScore(spot.PlayerCards); score == 21 {
+		t.Fatalf("blackjack should never be dealt as a spot: %+v", spot.PlayerCards)
+	}
+
+	res, err := svc.Answer(sessionID, spot.ID, AnswerHit)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res.Correct != (res.CorrectAnswer == AnswerHit) {
+		t.Fatalf("H graded %v against correct answer %s", res.Correct, res.CorrectAnswer)
+	}
+	if res.Stats.Total != 1 || res.Stats.Categories[res.Category].Total != 1 {
+		t.Fatalf("unexpected stats: %+v", res.Stats)
+	}
+
+	// 同じ問題には二度回答できない
+	if _, err := svc.Answer(sessionID, spot.ID, res.CorrectAnswer); err == nil {
+		t.Fatalf("expected error for already answered spot")
+	}
+}
+
+func TestTrainerService_ChartMatchesKnownSpots(t *testing.T) {
+	svc := newTrainerService(nil, rand.New(rand.NewSource(1)))
+	config := &game.GameConfig{DealerStandThreshold: 17}
+
+	cases := []struct {
+		name     string
+		key      spotKey
+		expected TrainerAnswer
+		category TrainerCategory
+	}{
+		{"hard 19 vs 10", spotKey{Kind: kindHard, Value: 19, Upcard: 10}, AnswerStand, CategoryHard},
+		{"hard 9 vs 5", spotKey{Kind: kindHard, Value: 9, Upcard: 5}, AnswerHit, CategoryHard},
+		{"soft 13 vs 6", spotKey{Kind: kindSoft, Value: 2, Upcard: 6}, AnswerHit, CategorySoft},
+		{"pair of 10 vs 6", spotKey{Kind: kindPair, Value: 10, Upcard: 6}, AnswerStand, CategoryPairs},
+	}
+	for _, tc := range cases {
+		answer, category := svc.chartAnswer(tc.key, config)
+		if answer != tc.expected || category != tc.category {
+			t.Fatalf("%s: expected %s/%s, got %s/%s", tc.name, tc.expected, tc.category, answer, category)
+		}
+	}
+}
+
+func TestTrainerService_MissedSpotsAreWeighted(t *testing.T) {
+	svc := newTrainerService(nil, rand.New(rand.NewSource(1)))
+	sessionID, _ := svc.StartSession("", game.GameConfig{DealerStandThreshold: 17})
+
+	spot, _ := svc.NextSpot(sessionID)
+	key := svc.sessions[sessionID].pending[spot.ID]
+
+	if _, err := svc.Answer(sessionID, spot.ID, wrongAnswer(svc, sessionID, key)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if w := svc.sessions[sessionID].weight(key); w != 2*minSpotWeight {
+		t.Fatalf("expected weight %f after a miss, got %f", 2*minSpotWeight, w)
+	}
+}
+
+func TestTrainerService_InvalidInput(t *testing.T) {
+	svc := newTrainerService(nil, rand.New(rand.NewSource(1)))
+
+	if _, err := svc.StartSession("", game.GameConfig{DealerStandThreshold: 0}); err == nil {
+		t.Fatalf("expected error for invalid config")
+	}
+	if _, err := svc.NextSpot("unknown"); err == nil {
+		t.Fatalf("expected error for unknown session")
+	}
+
+	sessionID, _ := svc.StartSession("", game.GameConfig{DealerStandThreshold: 17})
+	spot, _ := svc.NextSpot(sessionID)
+	for _, answer := range []TrainerAnswer{"X", "D", "P"} {
+		if _, err := svc.Answer(sessionID, spot.ID, answer); !errors.Is(err, ErrInvalidArgument) {
+			t.Fatalf("answer %s: expected ErrInvalidArgument, got %v", answer, err)
+		}
+	}
+
+	// 未知のバリエーション・小さすぎるチャーリー・ディーラーの2枚が見えるルールは受け付けない
+	for _, config := range []game.GameConfig{
+		{DealerStandThreshold: 17, Variant: "unknown"},
+		{DealerStandThreshold: 17, CharlieCards: 2},
+		{DealerStandThreshold: 17, Variant: game.VariantDoubleExposure},
+	} {
+		if _, err := svc.StartSession("", config); !errors.Is(err, ErrInvalidConfig) {
+			t.Fatalf("%+v: expected ErrInvalidConfig, got %v", config, err)
+		}
+	}
+}
+
+func TestTrainerService_RecordsAnswersForPlayer(t *testing.T) {
+	lb := newLeaderboardService(time.Now)
+	svc := newTrainerService(lb, rand.New(rand.NewSource(1)))
+	sessionID, _ := svc.StartSession("alice", game.GameConfig{DealerStandThreshold: 17})
+
+	spot, _ := svc.NextSpot(sessionID)
+	key := svc.sessions[sessionID].pending[spot.ID]
+	if _, err := svc.Answer(sessionID, spot.ID, wrongAnswer(svc, sessionID, key)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	page, _ := lb.Leaderboard(MetricTrainerAccuracy, WindowAllTime, 0, 10)
+	if page.Total != 1 || page.Entries[0].PlayerID != "alice" || page.Entries[0].Value != 0 {
+		t.Fatalf("expected alice with accuracy 0, got %+v", page.Entries)
+	}
+}
+
+// wrongAnswer はセッションの出題キーの正解ではない回答を返します。
+func wrongAnswer(svc *trainerService, sessionID string, key spotKey) TrainerAnswer {
+	correct, _ := svc.chartAnswer(key, &svc.sessions[sessionID].config)
+	if correct == AnswerHit {
+		return AnswerStand
+	}
+	return AnswerHit
+}
+
+func TestTrainerService_Spanish21SpotsHaveNoTens(t *testing.T) {
+	svc := newTrainerService(nil, rand.New(rand.NewSource(1)))
+	sessionID, err := svc.StartSession("", game.GameConfig{DealerStandThreshold: 17, Variant: game.VariantSpanish21})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for i := 0; i < 200; i++ {
+		spot, err := svc.NextSpot(sessionID)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		for _, c := range append(spot.PlayerCards, spot.DealerUpcard) {
+			if c.Rank == "10" {
+				t.Fatalf("spanish 21 spot dealt a 10: %+v", spot)
+			}
+		}
+	}
+}
+
+func TestTrainerService_CapsPendingSpots(t *testing.T) {
+	svc := newTrainerService(nil, rand.New(rand.NewSource(1)))
+	sessionID, _ := svc.StartSession("", game.GameConfig{DealerStandThreshold: 17})
+
+	first, _ := svc.NextSpot(sessionID)
+	for i := 0; i < maxPendingSpots; i++ {
+		if _, err := svc.NextSpot(sessionID); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if n := len(svc.sessions[sessionID].pending); n != maxPendingSpots {
+		t.Fatalf("expected %d pending spots, got %d", maxPendingSpots, n)
+	}
+	// 最も古い問題は取り消される
+	if _, err := svc.Answer(sessionID, first.ID, AnswerHit); !errors.Is(err, ErrSpotNotFound) {
+		t.Fatalf("expected ErrSpotNotFound for the oldest spot, got %v", err)
+	}
+}
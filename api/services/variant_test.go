@@ -0,0 +1,193 @@
+package services
+
+import (
+	"testing"
+
+	"blackjack/api/game"
+)
+
+func cardsOf(ranks ...game.Rank) []game.Card {
+	cards := make([]game.Card, len(ranks))
+	for i, r := range ranks {
+		cards[i] = game.Card{Suit: game.Heart, Rank: r}
+	}
+	return cards
+}
+
+func TestGameService_Spanish21(t *testing.T) {
+	config := &game.GameConfig{DealerStandThreshold: 17, Variant: game.VariantSpanish21}
+	// 10 は引き直される。プレイヤー 6-7、ディーラー K。ヒットで 8 → 6-7-8 の 21
+	// ディーラーは A で 21 になるが、プレイヤーの 21 が勝ち、同スートの 6-7-8 として 2:1 のボーナス配当になる
+	deck := &mockDeck{cards: cardsOf("10", "6", "7", "10", "K", "8", "A")}
+	svc := NewGameService(deck)
+
+	g, err := svc.NewGame(100, config)
+	if err != nil {
+		t.Fatalf("new game: %v", err)
+	}
+	if g.Variant != game.VariantSpanish21 || g.PlayerHand.Score != 13 || g.DealerHand.Cards[0].Rank != "K" {
+		t.Fatalf("expected tens to be skipped, got %+v", g)
+	}
+	if err := svc.Hit(&g, config); err != nil {
+		t.Fatalf("hit: %v", err)
+	}
+	if g.DealerHand.Score != 21 || g.Result != game.PlayerWin || g.Payout != 300 {
+		t.Fatalf("expected 6-7-8 to beat dealer 21 with a 2:1 suited bonus, got %+v", g)
+	}
+	if g.ResultMessage != game.MessageBonus21PlayerWin {
+		t.Fatalf("unexpected message: %s", g.ResultMessage)
+	}
+}
+
+func TestGameService_DoubleExposure(t *testing.T) {
+	config := &game.GameConfig{DealerStandThreshold: 17, Variant: game.VariantDoubleExposure}
+
+	t.Run("ties lose", func(t *testing.T) {
+		svc := NewGameService(&mockDeck{cards: cardsOf("10", "8", "9", "9")})
+		g, _ := svc.NewGame(100, config)
+		if len(g.DealerHand.Cards) != 2 {
+			t.Fatalf("expected both dealer cards to be dealt, got %+v", g.DealerHand)
+		}
+		if err := svc.Stand(&g, config); err != nil {
+			t.Fatalf("stand: %v", err)
+		}
+		if g.Result != game.DealerWin || g.Payout != 0 || g.ResultMessage != game.MessageTieDealerWin {
+			t.Fatalf("expected the tie to lose, got %+v", g)
+		}
+	})
+
+	t.Run("dealer blackjack settles at deal", func(t *testing.T) {
+		svc := NewGameService(&mockDeck{cards: cardsOf("10", "8", "A", "K")})
+		g, _ := svc.NewGame(100, config)
+		if g.State != game.Finished || g.Result != game.DealerWin {
+			t.Fatalf("expected dealer blackjack to win immediately, got %+v", g)
+		}
+	})
+
+	t.Run("blackjack pays even money and no surrender", func(t *testing.T) {
+		svc := NewGameService(&mockDeck{cards: cardsOf("A", "K", "9", "9", "10", "6", "5", "5")})
+		g, _ := svc.NewGame(100, config)
+		if g.Result != game.PlayerWin || g.Payout != 200 {
+			t.Fatalf("expected blackjack to pay 1:1, got %+v", g)
+		}
+		g, _ = svc.NewGame(100, config)
+		if err := svc.Surrender(&g, config); err == nil {
+			t.Fatalf("expected surrender to be rejected")
+		}
+	})
+}
+
+func TestGameService_BlackjackSwitch(t *testing.T) {
+	config := &game.GameConfig{DealerStandThreshold: 17, Variant: game.VariantSwitch}
+	// 手1: 10-5、手2: 6-K、ディーラー 6。入れ替えで 10-K (20) と 6-5 (11)
+	// 手2 はヒットで 9 → 20、ディーラーは 6 → 6 → K で 22（引き分け）
+	deck := &mockDeck{cards: cardsOf("10", "5", "6", "K", "6", "9", "6", "K")}
+	svc := NewGameService(deck)
+
+	g, err := svc.NewGame(100, config)
+	if err != nil {
+		t.Fatalf("new game: %v", err)
+	}
+	if g.Bet != 200 || g.Switch == nil || g.Switch.HandBet != 100 {
+		t.Fatalf("expected two hands of 100, got %+v", g)
+	}
+	if err := svc.Switch(&g, config); err != nil {
+		t.Fatalf("switch: %v", err)
+	}
+	if g.PlayerHand.Score != 20 || g.Switch.Hands[1].Score != 11 {
+		t.Fatalf("expected second cards to be swapped, got %+v", g.Switch.Hands)
+	}
+	if err := svc.Switch(&g, config); err == nil {
+		t.Fatalf("expected a second switch to be rejected")
+	}
+
+	if err := svc.Stand(&g, config); err != nil {
+		t.Fatalf("stand first hand: %v", err)
+	}
+	if g.State != game.PlayerTurn || g.Switch.Active != 1 || g.PlayerHand.Score != 11 {
+		t.Fatalf("expected play to move to the second hand, got %+v", g)
+	}
+	if err := svc.Hit(&g, config); err != nil {
+		t.Fatalf("hit second hand: %v", err)
+	}
+	if err := svc.Stand(&g, config); err != nil {
+		t.Fatalf("stand second hand: %v", err)
+	}
+
+	if g.DealerHand.Score != 0 || len(g.DealerHand.Cards) != 3 {
+		t.Fatalf("expected dealer to bust with 22, got %+v", g.DealerHand)
+	}
+	if g.Switch.Results != [2]game.Result{game.Push, game.Push} || g.Payout != 200 || g.Result != game.Push {
+		t.Fatalf("expected dealer 22 to push both hands, got %+v", g)
+	}
+	if g.Switch.Reasons != [2]game.ResultReason{game.ReasonDealer22Push, game.ReasonDealer22Push} || g.ResultReason != game.ReasonSwitchSettled {
+		t.Fatalf("expected dealer 22 push reasons, got %+v and %s", g.Switch.Reasons, g.ResultReason)
+	}
+}
+
+func TestGameService_BlackjackSwitchSkipsTwentyOne(t *testing.T) {
+	config := &game.GameConfig{DealerStandThreshold: 17, Variant: game.VariantSwitch}
+
+	// 配った1つ目の手が 21（A-K）なら2つ目の手（5-6）から行動する
+	svc := NewGameService(&mockDeck{cards: cardsOf("A", "K", "5", "6", "9")})
+	g, err := svc.NewGame(100, config)
+	if err != nil {
+		t.Fatalf("new game: %v", err)
+	}
+	if g.State != game.PlayerTurn || g.Switch.Active != 1 || g.PlayerHand.Score != 11 {
+		t.Fatalf("expected a dealt 21 on the first hand to move play to the second hand, got %+v", g)
+	}
+
+	// 入れ替えで1つ目の手が 21（10-A）になったら2つ目の手（6-5）に移る
+	svc = NewGameService(&mockDeck{cards: cardsOf("10", "5", "6", "A", "9")})
+	g, _ = svc.NewGame(100, config)
+	if err := svc.Switch(&g, config); err != nil {
+		t.Fatalf("switch: %v", err)
+	}
+	if g.State != game.PlayerTurn || g.Switch.Active != 1 || g.PlayerHand.Score != 11 {
+		t.Fatalf("expected a 21 made by switching to move play to the second hand, got %+v", g)
+	}
+
+	// 入れ替えで両方の手が 21（A-K と K-A）になったらそのまま精算する
+	svc = NewGameService(&mockDeck{cards: cardsOf("A", "A", "K", "K", "9", "8")})
+	g, _ = svc.NewGame(100, config)
+	if err := svc.Switch(&g, config); err != nil {
+		t.Fatalf("switch: %v", err)
+	}
+	if g.State != game.Finished || g.Switch.Results != [2]game.Result{game.PlayerWin, game.PlayerWin} {
+		t.Fatalf("expected both 21s to be settled, got %+v", g)
+	}
+}
+
+func TestGameService_VariantMismatch(t *testing.T) {
+	svc := NewGameService(&mockDeck{cards: cardsOf("10", "5", "6")})
+	g, _ := svc.NewGame(100, nil)
+	if err := svc.Stand(&g, &game.GameConfig{DealerStandThreshold: 17, Variant: game.VariantSpanish21}); err == nil {
+		t.Fatalf("expected error when config variant differs from the game")
+	}
+}
+
+func TestGameService_Charlie(t *testing.T) {
+	config := &game.GameConfig{DealerStandThreshold: 17, CharlieCards: 5}
+	// プレイヤー 2-3、ディーラー K。2, 4, 3 と引いて 5 枚 14 でチャーリー。ディーラーは引かない
+	svc := NewGameService(&mockDeck{cards: cardsOf("2", "3", "K", "2", "4", "3", "A")})
+	g, _ := svc.NewGame(100, config)
+	for i := 0; i < 3; i++ {
+		if g.State == game.Finished {
+			t.Fatalf("expected the game to continue before the fifth card, got %+v", g)
+		}
+		if err := svc.Hit(&g, config); err != nil {
+			t.Fatalf("hit: %v", err)
+		}
+	}
+	if g.State != game.Finished || g.Result != game.PlayerWin || g.Payout != 200 {
+		t.Fatalf("expected a five-card charlie to win, got %+v", g)
+	}
+	if g.ResultReason != game.ReasonCharlie || g.ResultMessage != game.MessageCharliePlayerWin || len(g.DealerHand.Cards) != 1 {
+		t.Fatalf("expected the dealer not to draw, got %+v", g)
+	}
+
+	if err := svc.Hit(&game.Game{State: game.PlayerTurn}, &game.GameConfig{DealerStandThreshold: 17, CharlieCards: 1}); err == nil {
+		t.Fatalf("expected invalid charlie config to be rejected")
+	}
+}
@@ -13,6 +13,7 @@ type Calculator struct {
 	dealerMemo             map[dealerMemoKey]map[int]float64
 	standPayoutMemo        map[standPayoutKey]float64
 	allExpectedPayoutsMemo map[strategyStateKey]StrategyExpectedPayouts
+	outcomeMemo            map[outcomeMemoKey]OutcomeDistribution
 	mu                     sync.RWMutex
 }
 
@@ -38,6 +39,7 @@ func NewCalculator() *Calculator {
 		dealerMemo:             make(map[dealerMemoKey]map[int]float64),
 		standPayoutMemo:        make(map[standPayoutKey]float64),
 		allExpectedPayoutsMemo: make(map[strategyStateKey]StrategyExpectedPayouts),
+		outcomeMemo:            make(map[outcomeMemoKey]OutcomeDistribution),
 	}
 }
 
@@ -52,6 +54,7 @@ func (c *Calculator) GetDealerScoreDistribution(dealerHand StrategyHand, config
 	}
 	c.mu.RUnlock()
 
+	rules := rulesFor(config)
 	currentScore := calculateScore(dealerHand)
 
 	if currentScore >= config.DealerStandThreshold {
@@ -62,7 +65,12 @@ func (c *Calculator) GetDealerScoreDistribution(dealerHand StrategyHand, config
 		return result
 	}
 	if currentScore == 0 {
-		result := map[int]float64{0: 1.0}
+		// ディーラーの 22 が引き分けになるルールでは、バーストのうち 22 を区別する
+		bust := 0
+		if rules.Dealer22Pushes && dealerHand.Sum == 22 {
+			bust = 22
+		}
+		result := map[int]float64{bust: 1.0}
 		c.mu.Lock()
 		c.dealerMemo[key] = result
 		c.mu.Unlock()
@@ -70,7 +78,7 @@ func (c *Calculator) GetDealerScoreDistribution(dealerHand StrategyHand, config
 	}
 
 	result := make(map[int]float64)
-	for card, prob := range cardProbabilities {
+	for card, prob := range cardDistribution(rules) {
 		nextSum := dealerHand.Sum + card
 		nextHasAce := dealerHand.HasAce || (card == 1)
 		nextHand := StrategyHand{Sum: nextSum, HasAce: nextHasAce}
@@ -106,13 +114,10 @@ func (c *Calculator) CalculateStandExpectedPayout(playerScore int, dealerHand St
 	dealerDist := c.GetDealerScoreDistribution(dealerHand, config)
 
 	// ディーラーのスコア分布とプレイヤーのスコアを比較して、期待払い戻しを計算
+	rules := rulesFor(config)
 	expectedPayout := 0.0
 	for dealerScore, prob := range dealerDist {
-		if dealerScore < playerScore {
-			expectedPayout += 2.0 * prob // 勝ち
-		} else if dealerScore == playerScore {
-			expectedPayout += 1.0 * prob // 引き分け
-		} // 負けは0.0
+		expectedPayout += standPayout(playerScore, dealerScore, rules) * prob
 	}
 
 	// キャッシュに結果を保存
@@ -126,6 +131,8 @@ func (c *Calculator) CalculateStandExpectedPayout(playerScore int, dealerHand St
 func (c *Calculator) CalculateAllExpectedPayouts(state StrategyState, config *game.GameConfig) StrategyExpectedPayouts {
 
 	// キャッシュがあれば、計算せずにそれを再利用
+	rules := rulesFor(config)
+	state = normalizeCards(state, rules)
 	key := strategyStateKey{state: state, config: *config}
 	c.mu.RLock()
 	if v, ok := c.allExpectedPayoutsMemo[key]; ok {
@@ -136,6 +143,16 @@ func (c *Calculator) CalculateAllExpectedPayouts(state StrategyState, config *ga
 
 	var expectedPayouts StrategyExpectedPayouts
 
+	// N枚チャーリーとボーナスの対象になる 21 は確定で勝ち（gameService.Hit がその場で精算するので他の行動はとれない）
+	if payout, ok := settledPayout(state, rules); ok {
+		expectedPayouts.StandPayout = payout
+		expectedPayouts.BestPayout = payout
+		c.mu.Lock()
+		c.allExpectedPayoutsMemo[key] = expectedPayouts
+		c.mu.Unlock()
+		return expectedPayouts
+	}
+
 	// プレイヤーのスコアを計算
 	playerScore := calculateScore(state.Player)
 
@@ -143,7 +160,7 @@ func (c *Calculator) CalculateAllExpectedPayouts(state StrategyState, config *ga
 	if playerScore == 0 {
 		expectedPayouts.StandPayout = 0
 		expectedPayouts.HitPayout = 0
-		if !state.HasHit {
+		if !state.HasHit && rules.AllowSurrender {
 			expectedPayouts.SurrenderPayout = 0.5
 			expectedPayouts.BestPayout = 0.5
 		} else {
@@ -160,8 +177,8 @@ func (c *Calculator) CalculateAllExpectedPayouts(state StrategyState, config *ga
 	standPayout := c.CalculateStandExpectedPayout(playerScore, state.Dealer, config)
 	expectedPayouts.StandPayout = standPayout
 
-	// サレンダーの期待値を計算 (ヒットしている場合はサレンダーができないので、期待利得を0としておく)
-	if !state.HasHit {
+	// サレンダーの期待値を計算 (ヒットしている場合やサレンダーのないルールではサレンダーができないので、期待利得を0としておく)
+	if !state.HasHit && rules.AllowSurrender {
 		expectedPayouts.SurrenderPayout = 0.5
 	} else {
 		expectedPayouts.SurrenderPayout = 0.0
@@ -169,11 +186,7 @@ func (c *Calculator) CalculateAllExpectedPayouts(state StrategyState, config *ga
 
 	// ヒットの期待値を計算
 	hit := 0.0
-	for card, prob := range cardProbabilities {
-		nextSum := state.Player.Sum + card
-		nextHasAce := state.Player.HasAce || (card == 1)
-		nextHand := StrategyHand{Sum: nextSum, HasAce: nextHasAce}
-		nextState := StrategyState{Player: nextHand, Dealer: state.Dealer, HasHit: true}
+	for nextState, prob := range hitStates(state, rules) {
 		hit += c.CalculateAllExpectedPayouts(nextState, config).BestPayout * prob
 	}
 	expectedPayouts.HitPayout = hit
@@ -194,3 +207,30 @@ func (c *Calculator) CalculateAllExpectedPayouts(state StrategyState, config *ga
 	c.mu.Unlock()
 	return expectedPayouts
 }
+
+// ヒット後の手札の枚数（枚数が結果に影響しないルールでは正規化で 0 に戻る）
+func nextCards(state StrategyState) int {
+	if state.Cards == 0 {
+		return 0
+	}
+	return state.Cards + 1
+}
+
+// スタンドしたプレイヤーのスコアとディーラーの最終スコアから払い戻し倍率を返す
+// スパニッシュ21 のボーナス配当は手札の枚数と構成によるので、呼び出し側で settledPayout として扱う
+func standPayout(playerScore, dealerScore int, rules game.VariantRules) float64 {
+	switch {
+	case dealerScore == 22:
+		return 1.0 // ディーラーの 22 は引き分け（Dealer22Pushes のときのみ現れる）
+	case dealerScore < playerScore:
+		return 2.0 // 勝ち（ディーラーのバーストは 0）
+	case dealerScore > playerScore:
+		return 0.0
+	case rules.Player21AlwaysWins && playerScore == 21:
+		return 2.0
+	case rules.TiesLose:
+		return 0.0
+	default:
+		return 1.0 // 引き分け
+	}
+}
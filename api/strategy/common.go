@@ -1,16 +1,210 @@
 package strategy
 
+import (
+	"sync"
+
+	"blackjack/api/game"
+)
+
 var cardProbabilities = map[int]float64{
 	1: 1.0 / 13.0, 2: 1.0 / 13.0, 3: 1.0 / 13.0, 4: 1.0 / 13.0,
 	5: 1.0 / 13.0, 6: 1.0 / 13.0, 7: 1.0 / 13.0, 8: 1.0 / 13.0,
 	9: 1.0 / 13.0, 10: 4.0 / 13.0,
 }
 
+// 設定のバリエーションのルールを返す。不明なバリエーションはクラシックとして扱う（検証は呼び出し側で行う）
+func rulesFor(config *game.GameConfig) game.VariantRules {
+	rules, err := config.Rules()
+	if err != nil {
+		return game.ClassicRules()
+	}
+	return rules
+}
+
+// バリエーションごとの点数の出現確率のキャッシュ
+var cardDistributions sync.Map // game.Variant -> map[int]float64
+
+// ルールのデッキにおける点数ごとの出現確率を返す
+// 除外ランクのないデッキでは cardProbabilities と同じ分布になる
+func cardDistribution(rules game.VariantRules) map[int]float64 {
+	if len(rules.ExcludedRanks) == 0 {
+		return cardProbabilities
+	}
+	if d, ok := cardDistributions.Load(rules.Variant); ok {
+		return d.(map[int]float64)
+	}
+	counts := make(map[int]int)
+	total := 0
+	for _, c := range game.AllCards() {
+		if rules.AllowsCard(c) {
+			counts[game.RankToScore(c.Rank)]++
+			total++
+		}
+	}
+	dist := make(map[int]float64, len(counts))
+	for score, n := range counts {
+		dist[score] = float64(n) / float64(total)
+	}
+	cardDistributions.Store(rules.Variant, dist)
+	return dist
+}
+
 // 戦略計算用の状態 game.goのGameStateより簡素
+// Cards はプレイヤーの手札の枚数で、N枚チャーリーとスパニッシュ21 のボーナスのルールでのみ参照する（0 なら HasHit から推定する）
+// Bonus21 はスパニッシュ21 の 6-7-8 / 7-7-7 のボーナスの状態で、初手2枚では Bonus21DrawFor で求める
 type StrategyState struct {
-	Player StrategyHand
-	Dealer StrategyHand
-	HasHit bool
+	Player  StrategyHand
+	Dealer  StrategyHand
+	HasHit  bool
+	Cards   int
+	Bonus21 Bonus21Draw
+}
+
+// Bonus21Draw は 6-7-8 / 7-7-7 の 21 を作れる初手2枚の、3枚目に必要な点数とスート
+// 3枚目で作った後の状態では Payout にその 21 の払い戻し倍率を持つ
+type Bonus21Draw struct {
+	Need   int  // 3枚目に必要な点数（0 なら作れない）
+	Suited bool // 2枚が同じスート
+	Spades bool // 2枚ともスペード
+	Payout float64
+}
+
+// Bonus21DrawFor は初手2枚から 6-7-8 / 7-7-7 を作るための3枚目の条件を返す（作れなければゼロ値）
+func Bonus21DrawFor(cards []game.Card) Bonus21Draw {
+	if len(cards) != 2 {
+		return Bonus21Draw{}
+	}
+	need := sevensNeed(game.RankToScore(cards[0].Rank), game.RankToScore(cards[1].Rank))
+	if need == 0 {
+		return Bonus21Draw{}
+	}
+	suited := cards[0].Suit == cards[1].Suit
+	return Bonus21Draw{Need: need, Suited: suited, Spades: suited && cards[0].Suit == game.Spade}
+}
+
+// 2枚の点数から 6-7-8 / 7-7-7 を完成させる3枚目の点数を返す（作れなければ 0）
+func sevensNeed(a, b int) int {
+	if a > b {
+		a, b = b, a
+	}
+	switch [2]int{a, b} {
+	case [2]int{6, 7}:
+		return 8
+	case [2]int{6, 8}, [2]int{7, 7}:
+		return 7
+	case [2]int{7, 8}:
+		return 6
+	}
+	return 0
+}
+
+// 2枚の点数から、スートの組み合わせごとの Bonus21Draw とその確率を返す（スートは4種類が等確率）
+func bonus21Draws(a, b int, rules game.VariantRules) map[Bonus21Draw]float64 {
+	need := sevensNeed(a, b)
+	if !rules.Bonus21 || need == 0 {
+		return map[Bonus21Draw]float64{{}: 1.0}
+	}
+	return map[Bonus21Draw]float64{
+		{Need: need}:                             3.0 / 4.0,
+		{Need: need, Suited: true}:               3.0 / 16.0,
+		{Need: need, Suited: true, Spades: true}: 1.0 / 16.0,
+	}
+}
+
+// 3枚目の点数が Need のときの、3枚目のスートごとの払い戻し倍率とその確率を返す
+func (d Bonus21Draw) completions() map[float64]float64 {
+	mixed := sevensPayout(false, false)
+	if !d.Suited {
+		return map[float64]float64{mixed: 1.0}
+	}
+	return map[float64]float64{mixed: 3.0 / 4.0, sevensPayout(true, d.Spades): 1.0 / 4.0}
+}
+
+func sevensPayout(suited, spades bool) float64 {
+	return 1.0 + float64(game.Bonus21SevensRatio(suited, spades))/2.0
+}
+
+// メモ化のキーが同じ局面で一致するように、手札の枚数を正規化する
+// 枚数が結果に影響しないルールでは 0 にそろえ、影響するルールでは
+// それ以上の枚数を区別しない枚数（チャーリーの規定枚数、ボーナスは 7 枚）にまとめる
+func normalizeCards(state StrategyState, rules game.VariantRules) StrategyState {
+	limit := rules.CharlieCards
+	if rules.Bonus21 && limit < 7 {
+		limit = 7
+	}
+	if !rules.Bonus21 {
+		state.Bonus21 = Bonus21Draw{}
+	} else if state.HasHit {
+		// 3枚目の条件は初手2枚でのみ意味を持つ
+		state.Bonus21 = Bonus21Draw{Payout: state.Bonus21.Payout}
+	}
+	if limit == 0 {
+		state.Cards = 0
+		return state
+	}
+	if state.Cards == 0 {
+		state.Cards = 2
+		if state.HasHit {
+			state.Cards = 3
+		}
+	}
+	if state.Cards > limit {
+		state.Cards = limit
+	}
+	return state
+}
+
+// チャーリーの規定枚数に達してバーストしていない状態か（state は正規化済みであること）
+func isCharlie(state StrategyState, rules game.VariantRules) bool {
+	return rules.CharlieCards > 0 && state.Cards >= rules.CharlieCards && calculateScore(state.Player) != 0
+}
+
+// スパニッシュ21 のボーナス配当の対象になる 21 の払い戻し倍率を返す（state は正規化済みであること）
+// ボーナスのあるルールではプレイヤーの 21 はディーラーに関係なく勝つ（Player21AlwaysWins）ので、倍率は手札だけで決まる
+func bonus21Payout(state StrategyState, rules game.VariantRules) (float64, bool) {
+	if !rules.Bonus21 || calculateScore(state.Player) != 21 {
+		return 0, false
+	}
+	best := state.Bonus21.Payout
+	if cards := game.Bonus21CardsRatio(state.Cards); cards > 0 {
+		best = max(best, 1.0+float64(cards)/2.0)
+	}
+	return best, best > 0
+}
+
+// 行動をとれずに勝ちが決まる状態の払い戻し倍率を返す（state は正規化済みであること）
+// N枚チャーリーは gameService.Hit がその場で精算し、ボーナスの対象になる 21 は自動でスタンドする
+func settledPayout(state StrategyState, rules game.VariantRules) (float64, bool) {
+	if payout, ok := bonus21Payout(state, rules); ok {
+		return payout, true
+	}
+	if isCharlie(state, rules) {
+		return 2.0, true
+	}
+	return 0, false
+}
+
+// ヒットした後の状態とその確率を返す
+// 6-7-8 / 7-7-7 を完成させる3枚目は、スートごとの払い戻し倍率に分けて返す
+func hitStates(state StrategyState, rules game.VariantRules) map[StrategyState]float64 {
+	next := make(map[StrategyState]float64)
+	for card, prob := range cardDistribution(rules) {
+		nextState := StrategyState{
+			Player: StrategyHand{Sum: state.Player.Sum + card, HasAce: state.Player.HasAce || (card == 1)},
+			Dealer: state.Dealer,
+			HasHit: true,
+			Cards:  nextCards(state),
+		}
+		if !state.HasHit && card == state.Bonus21.Need {
+			for payout, p := range state.Bonus21.completions() {
+				nextState.Bonus21 = Bonus21Draw{Payout: payout}
+				next[nextState] += prob * p
+			}
+			continue
+		}
+		next[nextState] += prob
+	}
+	return next
 }
 
 // 戦略計算用の手札 game.goのHandより簡素
@@ -38,3 +232,38 @@ func calculateScore(sHand StrategyHand) int {
 	}
 	return sHand.Sum
 }
+
+// プレイヤーがとりうる行動
+type Action string
+
+const (
+	ActionHit       Action = "hit"
+	ActionStand     Action = "stand"
+	ActionSurrender Action = "surrender"
+)
+
+// 指定した行動の期待払い戻しを返す。未対応の行動なら false を返す
+func (p StrategyExpectedPayouts) PayoutFor(action Action) (float64, bool) {
+	switch action {
+	case ActionHit:
+		return p.HitPayout, true
+	case ActionStand:
+		return p.StandPayout, true
+	case ActionSurrender:
+		return p.SurrenderPayout, true
+	default:
+		return 0, false
+	}
+}
+
+// 最適行動を返す。期待値が並んだ場合はスタンド→ヒット→サレンダーの順に優先する
+func (p StrategyExpectedPayouts) BestAction() Action {
+	best, action := p.StandPayout, ActionStand
+	if p.HitPayout > best {
+		best, action = p.HitPayout, ActionHit
+	}
+	if p.SurrenderPayout > best {
+		action = ActionSurrender
+	}
+	return action
+}
@@ -0,0 +1,121 @@
+package strategy
+
+import "blackjack/api/game"
+
+// OutcomeDistribution は払い戻し倍率（0, 0.5, 1, 2, 2.5 など）ごとの発生確率を表す
+type OutcomeDistribution map[float64]float64
+
+type outcomeMemoKey struct {
+	state  StrategyState
+	config game.GameConfig
+}
+
+// 最適行動をとり続けた場合の、ある状態からの払い戻し倍率の分布を計算する
+// 最適行動の選び方は StrategyExpectedPayouts.BestAction に従う
+func (c *Calculator) CalculateOutcomeDistribution(state StrategyState, config *game.GameConfig) OutcomeDistribution {
+
+	// キャッシュがあれば、計算せずにそれを再利用
+	rules := rulesFor(config)
+	state = normalizeCards(state, rules)
+	key := outcomeMemoKey{state: state, config: *config}
+	c.mu.RLock()
+	if v, ok := c.outcomeMemo[key]; ok {
+		c.mu.RUnlock()
+		return v
+	}
+	c.mu.RUnlock()
+
+	payouts := c.CalculateAllExpectedPayouts(state, config)
+	playerScore := calculateScore(state.Player)
+
+	result := make(OutcomeDistribution)
+	if playerScore == 0 && state.HasHit {
+		// バースト済み
+		result[0] = 1.0
+	} else if payout, ok := settledPayout(state, rules); ok {
+		// N枚チャーリーとボーナスの対象になる 21
+		result[payout] = 1.0
+	} else {
+		switch payouts.BestAction() {
+		case ActionStand:
+			dealerDist := c.GetDealerScoreDistribution(state.Dealer, config)
+			for dealerScore, prob := range dealerDist {
+				result[standPayout(playerScore, dealerScore, rules)] += prob
+			}
+		case ActionHit:
+			for nextState, prob := range hitStates(state, rules) {
+				for payout, subProb := range c.CalculateOutcomeDistribution(nextState, config) {
+					result[payout] += prob * subProb
+				}
+			}
+		case ActionSurrender:
+			result[0.5] = 1.0
+		}
+	}
+
+	// キャッシュに結果を保存
+	c.mu.Lock()
+	c.outcomeMemo[key] = result
+	c.mu.Unlock()
+	return result
+}
+
+// 初期配布（プレイヤー2枚・ディーラー1枚）から最適行動をとった場合の、1ハンドあたりの払い戻し倍率の分布を計算する
+// 初手21はブラックジャックとして即座にルールの倍率（クラシックは 2.5 倍）が払い戻される（gameService.NewGame と同じ扱い）
+// ディーラーの2枚が見えるルールでは、ディーラーの初手21はその場で負けになる
+// スイッチは1つの手ごとの分布で、入れ替えの判断は含まない
+// スパニッシュ21 の 6-7-8 / 7-7-7 のボーナスは、初手2枚のスートの組み合わせ（等確率）ごとに分けて求める
+func (c *Calculator) CalculateInitialOutcomeDistribution(config *game.GameConfig) OutcomeDistribution {
+	rules := rulesFor(config)
+	cards := cardDistribution(rules)
+	blackjack := float64(rules.BlackjackPayoutNum) / float64(rules.BlackjackPayoutDen)
+
+	// ディーラーの初期手札の分布
+	dealerHands := make(map[StrategyHand]float64)
+	for up, pu := range cards {
+		if rules.InitialDealerCards == 1 {
+			dealerHands[StrategyHand{Sum: up, HasAce: up == 1}] += pu
+			continue
+		}
+		for hole, ph := range cards {
+			dealerHands[StrategyHand{Sum: up + hole, HasAce: up == 1 || hole == 1}] += pu * ph
+		}
+	}
+
+	result := make(OutcomeDistribution)
+	for first, p1 := range cards {
+		for second, p2 := range cards {
+			player := StrategyHand{Sum: first + second, HasAce: first == 1 || second == 1}
+			for dealer, pd := range dealerHands {
+				prob := p1 * p2 * pd
+				if rules.NaturalsSettleImmediately && calculateScore(player) == 21 {
+					result[blackjack] += prob
+					continue
+				}
+				if rules.NaturalsSettleImmediately && rules.InitialDealerCards == 2 && calculateScore(dealer) == 21 {
+					result[0] += prob
+					continue
+				}
+				for draw, pb := range bonus21Draws(first, second, rules) {
+					state := StrategyState{Player: player, Dealer: dealer, HasHit: false, Cards: 2, Bonus21: draw}
+					for payout, subProb := range c.CalculateOutcomeDistribution(state, config) {
+						result[payout] += prob * pb * subProb
+					}
+				}
+			}
+		}
+	}
+	return result
+}
+
+// OutcomeStatistics は払い戻し倍率の分布から、1ハンドあたりの純損益（払い戻し-ベット、ベット単位）の平均と分散を返す
+func OutcomeStatistics(dist OutcomeDistribution) (mean, variance float64) {
+	for payout, prob := range dist {
+		mean += (payout - 1.0) * prob
+	}
+	for payout, prob := range dist {
+		d := payout - 1.0 - mean
+		variance += d * d * prob
+	}
+	return mean, variance
+}
@@ -0,0 +1,99 @@
+package strategy
+
+import (
+	"context"
+	"math"
+	"math/rand"
+)
+
+// RiskOfRuinInput はリスク・オブ・ルイン（破産確率）計算の入力
+// Mean / Variance はベット単位（1ハンド1ユニット）での純損益の平均と分散
+type RiskOfRuinInput struct {
+	Mean       float64
+	Variance   float64
+	Bankroll   float64 // 資金（金額）
+	BetUnit    float64 // 1ハンドあたりのベット額（金額）
+	TargetRuin float64 // 必要資金を求める際の目標破産確率 (0, 1)
+	Trials     int     // シミュレーションの試行回数
+	MaxHands   int     // 1試行あたりの最大ハンド数（これを超えて生き残れば破産しなかったとみなす）
+}
+
+// RiskOfRuinResult はリスク・オブ・ルイン計算の結果
+// 期待値が 0 以下の場合など、有限値にならない項目は +Inf になる
+type RiskOfRuinResult struct {
+	AnalyticRuin     float64 // 拡散近似による破産確率
+	SimulatedRuin    float64 // シミュレーションによる破産確率
+	N0               float64 // 期待値が標準偏差1つ分に追いつくまでのハンド数
+	RequiredBankroll float64 // 目標破産確率を達成するための必要資金（金額）
+}
+
+// 拡散近似による破産確率 exp(-2 * mean * bankroll / variance) を返す（bankroll はユニット数）
+// 期待値が 0 以下なら、いずれ必ず破産するので 1 を返す
+func AnalyticRiskOfRuin(mean, variance, bankrollUnits float64) float64 {
+	if bankrollUnits <= 0 {
+		return 1.0
+	}
+	if mean <= 0 {
+		return 1.0
+	}
+	if variance <= 0 {
+		return 0.0
+	}
+	return math.Exp(-2.0 * mean * bankrollUnits / variance)
+}
+
+// N0 は期待値の累積が標準偏差1つ分と等しくなるハンド数 variance / mean^2 を返す
+func N0(mean, variance float64) float64 {
+	if mean == 0 {
+		return math.Inf(1)
+	}
+	return variance / (mean * mean)
+}
+
+// 目標破産確率を達成するために必要な資金（ユニット数）を返す
+// 期待値が 0 以下なら、どれだけ資金があっても達成できないので +Inf を返す
+func RequiredBankrollUnits(mean, variance, targetRuin float64) float64 {
+	if mean <= 0 {
+		return math.Inf(1)
+	}
+	return -variance * math.Log(targetRuin) / (2.0 * mean)
+}
+
+// 1ハンドの損益を正規分布 N(mean, variance) で近似し、破産確率をモンテカルロ法で求める
+// ctx が終わったら（クライアントの切断など）試行の途中でやめて ctx のエラーを返す
+func SimulateRiskOfRuin(ctx context.Context, mean, variance, bankrollUnits float64, trials, maxHands int, rng *rand.Rand) (float64, error) {
+	if trials <= 0 {
+		return 0.0, nil
+	}
+	sd := math.Sqrt(variance)
+	ruined := 0
+	for i := 0; i < trials; i++ {
+		if err := ctx.Err(); err != nil {
+			return 0, err
+		}
+		bankroll := bankrollUnits
+		for h := 0; h < maxHands && bankroll > 0; h++ {
+			bankroll += mean + sd*rng.NormFloat64()
+		}
+		if bankroll <= 0 {
+			ruined++
+		}
+	}
+	return float64(ruined) / float64(trials), nil
+}
+
+// CalculateRiskOfRuin は入力から解析値・シミュレーション値・N0・必要資金をまとめて計算する
+// シミュレーションが ctx の終了で中断された場合は ctx のエラーを返す
+func CalculateRiskOfRuin(ctx context.Context, in RiskOfRuinInput, rng *rand.Rand) (RiskOfRuinResult, error) {
+	units := in.Bankroll / in.BetUnit
+	simulated, err := SimulateRiskOfRuin(ctx, in.Mean, in.Variance, units, in.Trials, in.MaxHands, rng)
+	if err != nil {
+		return RiskOfRuinResult{}, err
+	}
+	return RiskOfRuinResult{
+		AnalyticRuin:     AnalyticRiskOfRuin(in.Mean, in.Variance, units),
+		SimulatedRuin:    simulated,
+		N0:               N0(in.Mean, in.Variance),
+		RequiredBankroll: RequiredBankrollUnits(in.Mean, in.Variance, in.TargetRuin) * in.BetUnit,
+	}, nil
+}
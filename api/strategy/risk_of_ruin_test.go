@@ -0,0 +1,84 @@
+package strategy
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+
+	"blackjack/api/game"
+)
+
+func TestCalculateInitialOutcomeDistribution_SumsToOne(t *testing.T) {
+	config := &game.GameConfig{DealerStandThreshold: 17}
+	calc := NewCalculator()
+
+	dist := calc.CalculateInitialOutcomeDistribution(config)
+	total := 0.0
+	for _, prob := range dist {
+		total += prob
+	}
+	if math.Abs(total-1.0) > 1e-9 {
+		t.Fatalf("expected probabilities to sum to 1, got %f", total)
+	}
+
+	mean, variance := OutcomeStatistics(dist)
+	if mean < -1 || mean > 1.5 {
+		t.Fatalf("mean out of range: %f", mean)
+	}
+	if variance <= 0 {
+		t.Fatalf("expected positive variance, got %f", variance)
+	}
+}
+
+func TestAnalyticRiskOfRuin(t *testing.T) {
+	// mean=0.01, variance=1.3, bankroll=100 ユニット -> exp(-2*0.01*100/1.3)
+	got := AnalyticRiskOfRuin(0.01, 1.3, 100)
+	want := math.Exp(-2.0 / 1.3)
+	if math.Abs(got-want) > 1e-12 {
+		t.Fatalf("expected %f, got %f", want, got)
+	}
+
+	// 期待値が負なら必ず破産
+	if AnalyticRiskOfRuin(-0.01, 1.3, 100) != 1.0 {
+		t.Fatalf("expected ruin 1.0 for negative mean")
+	}
+}
+
+func TestRequiredBankrollUnits_RoundTrip(t *testing.T) {
+	mean, variance := 0.01, 1.3
+	units := RequiredBankrollUnits(mean, variance, 0.05)
+	if got := AnalyticRiskOfRuin(mean, variance, units); math.Abs(got-0.05) > 1e-9 {
+		t.Fatalf("expected ruin 0.05 at required bankroll, got %f", got)
+	}
+	if !math.IsInf(RequiredBankrollUnits(-0.01, variance, 0.05), 1) {
+		t.Fatalf("expected +Inf for negative mean")
+	}
+	if n0 := N0(mean, variance); math.Abs(n0-13000) > 1e-6 {
+		t.Fatalf("expected N0 13000, got %f", n0)
+	}
+}
+
+func TestSimulateRiskOfRuin_CloseToAnalytic(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	mean, variance, units := 0.05, 1.0, 20.0
+
+	sim, err := SimulateRiskOfRuin(context.Background(), mean, variance, units, 2000, 5000, rng)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	analytic := AnalyticRiskOfRuin(mean, variance, units)
+	if math.Abs(sim-analytic) > 0.05 {
+		t.Fatalf("simulated ruin %f too far from analytic %f", sim, analytic)
+	}
+}
+
+func TestSimulateRiskOfRuin_StopsWhenContextIsDone(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := SimulateRiskOfRuin(ctx, 0.05, 1.0, 20, 1000, 10000, rand.New(rand.NewSource(1))); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+}
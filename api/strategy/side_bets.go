@@ -0,0 +1,78 @@
+package strategy
+
+import "blackjack/api/game"
+
+// サイドベットのハウスエッジ
+// デッキは game.RandomDeck と同じく無限デッキ（各カードがバリエーションのデッキのカードから独立に等確率で選ばれる。
+// クラシックなら 52 種類、スパニッシュ21 は 10 を除いた 48 種類）とし、全ての組み合わせを列挙して厳密に求めます。
+
+// SideBetOutcomeProbabilities は役ごとの出現確率です。SideBetLose も含みます。
+type SideBetOutcomeProbabilities map[game.SideBetOutcome]float64
+
+// PerfectPairsProbabilities はバリエーションのデッキでのパーフェクトペアの役ごとの確率を返します。
+func PerfectPairsProbabilities(rules game.VariantRules) SideBetOutcomeProbabilities {
+	cards := deckCards(rules)
+	counts := make(map[game.SideBetOutcome]int)
+	for _, a := range cards {
+		for _, b := range cards {
+			counts[game.EvaluatePerfectPairs(a, b)]++
+		}
+	}
+	return toProbabilities(counts, len(cards)*len(cards))
+}
+
+// TwentyOnePlusThreeProbabilities はバリエーションのデッキでの 21+3 の役ごとの確率を返します。
+func TwentyOnePlusThreeProbabilities(rules game.VariantRules) SideBetOutcomeProbabilities {
+	cards := deckCards(rules)
+	counts := make(map[game.SideBetOutcome]int)
+	for _, a := range cards {
+		for _, b := range cards {
+			for _, c := range cards {
+				counts[game.EvaluateTwentyOnePlusThree(a, b, c)]++
+			}
+		}
+	}
+	return toProbabilities(counts, len(cards)*len(cards)*len(cards))
+}
+
+// deckCards はバリエーションのデッキに含まれるカードを返します。
+func deckCards(rules game.VariantRules) []game.Card {
+	var cards []game.Card
+	for _, c := range game.AllCards() {
+		if rules.AllowsCard(c) {
+			cards = append(cards, c)
+		}
+	}
+	return cards
+}
+
+// toProbabilities は組み合わせの数を確率に変換します（誤差が溜まらないよう最後に割る）。
+func toProbabilities(counts map[game.SideBetOutcome]int, total int) SideBetOutcomeProbabilities {
+	probs := make(SideBetOutcomeProbabilities, len(counts))
+	for outcome, n := range counts {
+		probs[outcome] = float64(n) / float64(total)
+	}
+	return probs
+}
+
+// PerfectPairsHouseEdge はバリエーションのデッキと配当表に対するパーフェクトペアのハウスエッジ（掛け金 1 あたりの期待損失）を返します。
+func PerfectPairsHouseEdge(paytable game.PerfectPairsPaytable, rules game.VariantRules) float64 {
+	return houseEdge(PerfectPairsProbabilities(rules), paytable.Odds)
+}
+
+// TwentyOnePlusThreeHouseEdge はバリエーションのデッキと配当表に対する 21+3 のハウスエッジ（掛け金 1 あたりの期待損失）を返します。
+func TwentyOnePlusThreeHouseEdge(paytable game.TwentyOnePlusThreePaytable, rules game.VariantRules) float64 {
+	return houseEdge(TwentyOnePlusThreeProbabilities(rules), paytable.Odds)
+}
+
+// houseEdge は 1 - 期待払い戻し（掛け金を含む）を返します。
+func houseEdge(probs SideBetOutcomeProbabilities, odds func(game.SideBetOutcome) int) float64 {
+	expected := 0.0
+	for outcome, p := range probs {
+		if outcome == game.SideBetLose {
+			continue
+		}
+		expected += p * float64(odds(outcome)+1)
+	}
+	return 1 - expected
+}
@@ -0,0 +1,54 @@
+package strategy
+
+import (
+	"math"
+	"testing"
+
+	"blackjack/api/game"
+)
+
+func TestPerfectPairsHouseEdge(t *testing.T) {
+	// 無限デッキ: 同スート 1/52, 同色別スート 1/52, 色違い 2/52
+	paytable := game.PerfectPairsPaytable{Mixed: 5, Colored: 10, Perfect: 30}
+	expected := 1 - (31.0/52 + 11.0/52 + 2*6.0/52)
+	if got := PerfectPairsHouseEdge(paytable, game.ClassicRules()); math.Abs(got-expected) > 1e-12 {
+		t.Fatalf("expected house edge %v, got %v", expected, got)
+	}
+
+	// スパニッシュ21 のデッキ（10 を除く 48 枚）: 同スート 1/48, 同色別スート 1/48, 色違い 2/48
+	spanish, _ := game.RulesFor(game.VariantSpanish21)
+	expected = 1 - (31.0/48 + 11.0/48 + 2*6.0/48)
+	if got := PerfectPairsHouseEdge(paytable, spanish); math.Abs(got-expected) > 1e-12 {
+		t.Fatalf("expected Spanish 21 house edge %v, got %v", expected, got)
+	}
+}
+
+func TestTwentyOnePlusThreeHouseEdge(t *testing.T) {
+	// 無限デッキ: フラッシュ 1/16, スリーカード 1/169, ストレート 12 通り × 並び 6 / 13^3
+	pFlush := 1.0 / 16
+	pTrips := 1.0 / 169
+	pStraight := 72.0 / 2197
+	probs := map[game.SideBetOutcome]float64{
+		game.HandSuitedTrips:   pTrips * pFlush,
+		game.HandStraightFlush: pStraight * pFlush,
+		game.HandThreeOfAKind:  pTrips * (1 - pFlush),
+		game.HandStraight:      pStraight * (1 - pFlush),
+		game.HandFlush:         pFlush * (1 - pTrips - pStraight),
+	}
+	paytable := game.DefaultTwentyOnePlusThreePaytable
+	expected := 1.0
+	for outcome, p := range probs {
+		expected -= p * float64(paytable.Odds(outcome)+1)
+	}
+	if got := TwentyOnePlusThreeHouseEdge(paytable, game.ClassicRules()); math.Abs(got-expected) > 1e-12 {
+		t.Fatalf("expected house edge %v, got %v", expected, got)
+	}
+
+	total := 0.0
+	for _, p := range TwentyOnePlusThreeProbabilities(game.ClassicRules()) {
+		total += p
+	}
+	if math.Abs(total-1) > 1e-12 {
+		t.Fatalf("expected probabilities to sum to 1, got %v", total)
+	}
+}
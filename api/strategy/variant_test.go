@@ -0,0 +1,147 @@
+package strategy
+
+import (
+	"math"
+	"testing"
+
+	"blackjack/api/game"
+)
+
+func TestVariant_DoubleExposureTiesLose(t *testing.T) {
+	calc := NewCalculator()
+	dealer := StrategyHand{Sum: 18}
+	classic := calc.CalculateStandExpectedPayout(18, dealer, &game.GameConfig{DealerStandThreshold: 17})
+	de := calc.CalculateStandExpectedPayout(18, dealer, &game.GameConfig{DealerStandThreshold: 17, Variant: game.VariantDoubleExposure})
+	if classic != 1.0 || de != 0.0 {
+		t.Fatalf("expected tie to push in classic and lose in double exposure, got %v and %v", classic, de)
+	}
+
+	payouts := calc.CalculateAllExpectedPayouts(StrategyState{Player: StrategyHand{Sum: 16}, Dealer: StrategyHand{Sum: 20}},
+		&game.GameConfig{DealerStandThreshold: 17, Variant: game.VariantDoubleExposure})
+	if payouts.SurrenderPayout != 0 {
+		t.Fatalf("expected no surrender in double exposure, got %v", payouts.SurrenderPayout)
+	}
+}
+
+func TestVariant_SwitchDealer22Pushes(t *testing.T) {
+	calc := NewCalculator()
+	dealer := StrategyHand{Sum: 12}
+	config := &game.GameConfig{DealerStandThreshold: 17, Variant: game.VariantSwitch}
+
+	dist := calc.GetDealerScoreDistribution(dealer, config)
+	if dist[22] <= 0 {
+		t.Fatalf("expected dealer 22 to be tracked separately, got %v", dist)
+	}
+	classic := calc.CalculateStandExpectedPayout(18, dealer, &game.GameConfig{DealerStandThreshold: 17})
+	switched := calc.CalculateStandExpectedPayout(18, dealer, config)
+	if math.Abs(classic-switched-dist[22]) > 1e-12 {
+		t.Fatalf("expected each dealer 22 to turn a win (2) into a push (1): classic %v, switch %v, p22 %v", classic, switched, dist[22])
+	}
+}
+
+func TestVariant_Spanish21(t *testing.T) {
+	rules, _ := game.RulesFor(game.VariantSpanish21)
+	dist := cardDistribution(rules)
+	total := 0.0
+	for _, p := range dist {
+		total += p
+	}
+	if math.Abs(total-1) > 1e-12 || math.Abs(dist[10]-0.25) > 1e-12 {
+		t.Fatalf("expected 10-value cards to be 3 of 12 ranks, got %v", dist)
+	}
+
+	calc := NewCalculator()
+	payout := calc.CalculateStandExpectedPayout(21, StrategyHand{Sum: 21}, &game.GameConfig{DealerStandThreshold: 17, Variant: game.VariantSpanish21})
+	if payout != 2.0 {
+		t.Fatalf("expected player 21 to beat dealer 21, got %v", payout)
+	}
+}
+
+func TestVariant_Spanish21Bonus(t *testing.T) {
+	calc := NewCalculator()
+	config := &game.GameConfig{DealerStandThreshold: 17, Variant: game.VariantSpanish21}
+	dealer := StrategyHand{Sum: 10}
+
+	// 5 枚以上の 21 はディーラーに関係なくボーナス配当で勝つ
+	for cards, want := range map[int]float64{4: 2.0, 5: 2.5, 6: 3.0, 7: 4.0, 9: 4.0} {
+		got := calc.CalculateAllExpectedPayouts(StrategyState{Player: StrategyHand{Sum: 21}, Dealer: dealer, HasHit: true, Cards: cards}, config)
+		if math.Abs(got.BestPayout-want) > 1e-12 {
+			t.Errorf("%d-card 21: expected %v, got %v", cards, want, got.BestPayout)
+		}
+	}
+
+	// スペードの 6-7 は 8 を引けば 3/4 でスート混合（2.5）、1/4 でスペード（4）の 6-7-8
+	sevens := StrategyState{Player: StrategyHand{Sum: 13}, Dealer: dealer, Cards: 2, Bonus21: Bonus21DrawFor([]game.Card{
+		{Suit: game.Spade, Rank: "6"}, {Suit: game.Spade, Rank: "7"},
+	})}
+	plain := StrategyState{Player: StrategyHand{Sum: 13}, Dealer: dealer, Cards: 2}
+	diff := calc.CalculateAllExpectedPayouts(sevens, config).HitPayout - calc.CalculateAllExpectedPayouts(plain, config).HitPayout
+	rules, _ := game.RulesFor(game.VariantSpanish21)
+	if want := cardDistribution(rules)[8] * (0.75*2.5 + 0.25*4 - 2.0); math.Abs(diff-want) > 1e-12 {
+		t.Fatalf("expected the 6-7-8 bonus to add %v to hitting, got %v", want, diff)
+	}
+
+	// 初手の分布にもボーナス配当が現れる
+	dist := calc.CalculateInitialOutcomeDistribution(config)
+	for _, payout := range []float64{2.5, 3.0, 4.0} {
+		if dist[payout] <= 0 {
+			t.Errorf("expected payout %v in the initial distribution, got %v", payout, dist)
+		}
+	}
+}
+
+func TestBonus21DrawFor(t *testing.T) {
+	tests := []struct {
+		cards []game.Card
+		want  Bonus21Draw
+	}{
+		{[]game.Card{{Suit: game.Spade, Rank: "8"}, {Suit: game.Spade, Rank: "6"}}, Bonus21Draw{Need: 7, Suited: true, Spades: true}},
+		{[]game.Card{{Suit: game.Heart, Rank: "7"}, {Suit: game.Heart, Rank: "7"}}, Bonus21Draw{Need: 7, Suited: true}},
+		{[]game.Card{{Suit: game.Club, Rank: "7"}, {Suit: game.Heart, Rank: "8"}}, Bonus21Draw{Need: 6}},
+		{[]game.Card{{Suit: game.Spade, Rank: "9"}, {Suit: game.Spade, Rank: "4"}}, Bonus21Draw{}},
+		{[]game.Card{{Suit: game.Spade, Rank: "6"}, {Suit: game.Spade, Rank: "7"}, {Suit: game.Spade, Rank: "2"}}, Bonus21Draw{}},
+	}
+	for _, tt := range tests {
+		if got := Bonus21DrawFor(tt.cards); got != tt.want {
+			t.Errorf("Bonus21DrawFor(%v) = %+v, want %+v", tt.cards, got, tt.want)
+		}
+	}
+}
+
+func TestCharlie_CardCountInMemoKey(t *testing.T) {
+	calc := NewCalculator()
+	config := &game.GameConfig{DealerStandThreshold: 17, CharlieCards: 5}
+	dealer := StrategyHand{Sum: 10}
+
+	// 4 枚 12 はヒットすればバーストしない限りチャーリー、2 枚 12 はそうではない
+	four := calc.CalculateAllExpectedPayouts(StrategyState{Player: StrategyHand{Sum: 12}, Dealer: dealer, HasHit: true, Cards: 4}, config)
+	two := calc.CalculateAllExpectedPayouts(StrategyState{Player: StrategyHand{Sum: 12}, Dealer: dealer, Cards: 2}, config)
+	if math.Abs(four.HitPayout-2*(1-4.0/13.0)) > 1e-12 {
+		t.Fatalf("expected hitting into a charlie to pay 2 unless busting, got %v", four.HitPayout)
+	}
+	if two.HitPayout >= four.HitPayout {
+		t.Fatalf("expected card count to change the hit EV: two cards %v, four cards %v", two.HitPayout, four.HitPayout)
+	}
+
+	charlie := calc.CalculateAllExpectedPayouts(StrategyState{Player: StrategyHand{Sum: 12}, Dealer: dealer, HasHit: true, Cards: 5}, config)
+	if charlie.BestPayout != 2.0 || charlie.BestAction() != ActionStand {
+		t.Fatalf("expected a charlie to be a sure win, got %+v", charlie)
+	}
+
+	// チャーリーのないルールでは枚数は無視され、同じ局面として扱われる
+	classic := &game.GameConfig{DealerStandThreshold: 17}
+	a := calc.CalculateAllExpectedPayouts(StrategyState{Player: StrategyHand{Sum: 12}, Dealer: dealer, HasHit: true, Cards: 4}, classic)
+	b := calc.CalculateAllExpectedPayouts(StrategyState{Player: StrategyHand{Sum: 12}, Dealer: dealer, HasHit: true}, classic)
+	if a != b {
+		t.Fatalf("expected card count to be ignored without charlie: %+v vs %+v", a, b)
+	}
+}
+
+func TestCharlie_ImprovesInitialEV(t *testing.T) {
+	calc := NewCalculator()
+	classic, _ := OutcomeStatistics(calc.CalculateInitialOutcomeDistribution(&game.GameConfig{DealerStandThreshold: 17}))
+	charlie, _ := OutcomeStatistics(calc.CalculateInitialOutcomeDistribution(&game.GameConfig{DealerStandThreshold: 17, CharlieCards: 5}))
+	if charlie <= classic {
+		t.Fatalf("expected a five-card charlie to improve the player's EV: classic %v, charlie %v", classic, charlie)
+	}
+}
@@ -0,0 +1,22 @@
+package table
+
+import "time"
+
+// Clock はテーブルのタイマーが使う時計です。テストでは時刻を手動で進める実装に差し替えます。
+type Clock interface {
+	Now() time.Time
+	// AfterFunc は d 経過後に f を別の goroutine で呼び出すタイマーを開始します。
+	AfterFunc(d time.Duration, f func()) Timer
+}
+
+// Timer は Clock.AfterFunc が返すタイマーです。
+type Timer interface {
+	// Stop はタイマーを止めます。既に発火済みか停止済みなら false を返します。
+	Stop() bool
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time { return time.Now() }
+
+func (realClock) AfterFunc(d time.Duration, f func()) Timer { return time.AfterFunc(d, f) }
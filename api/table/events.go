@@ -0,0 +1,181 @@
+package table
+
+import (
+	"errors"
+	"time"
+
+	"blackjack/api/game"
+)
+
+// EventType はテーブルで発生したイベントの種類
+type EventType string
+
+const (
+	EventPlayerJoined EventType = "player_joined"
+	EventPlayerLeft   EventType = "player_left"
+	EventBetPlaced    EventType = "bet_placed"
+	EventRoundStarted EventType = "round_started"
+	EventCardDealt    EventType = "card_dealt" // 初期配布のカード（Seat が DealerSeat ならディーラー）
+	EventPlayerAction EventType = "player_action"
+	EventTurnChanged  EventType = "turn_changed"
+	EventDealerDraw   EventType = "dealer_draw"
+	EventSettlement   EventType = "settlement"
+	EventRoundOver    EventType = "round_over"
+	EventRoundReset   EventType = "round_reset"
+
+	EventTimerStarted       EventType = "timer_started" // 手番の持ち時間の開始（Deadline が期限）
+	EventTimerExpired       EventType = "timer_expired" // 持ち時間切れ。続けて自動行動の player_action が届く
+	EventAutopilotChanged   EventType = "autopilot_changed"
+	EventPlayerDisconnected EventType = "player_disconnected" // Deadline は猶予の期限
+	EventPlayerReconnected  EventType = "player_reconnected"
+	EventPlayerSatOut       EventType = "player_sat_out" // 猶予切れで離席扱いになった
+)
+
+// DealerSeat はディーラーに関するイベントの席番号
+const DealerSeat = -1
+
+// 再接続時の再送のために保持するイベント数
+const maxEventLog = 512
+
+// 購読者ごとの送信バッファ。溢れた購読者は切断され、再接続で追いつく
+const subscriberBuffer = 64
+
+// ErrResumeGap は再開位置のイベントが既に破棄されていて再送できないことを表します。
+var ErrResumeGap = errors.New("events after the given sequence are no longer available")
+
+// Event はテーブルで発生したイベントです。Seq はテーブル内で 1 から始まる連番です。
+type Event struct {
+	Seq      uint64     `json:"seq"`
+	Type     EventType  `json:"type"`
+	Seat     int        `json:"seat"` // 関係する席がなければ DealerSeat
+	PlayerID string     `json:"player_id,omitempty"`
+	Action   string     `json:"action,omitempty"`
+	Auto     bool       `json:"auto,omitempty"` // 持ち時間切れ・離席による自動行動
+	Bet      int        `json:"bet,omitempty"`
+	Card     *game.Card `json:"card,omitempty"`
+	Hand     *game.Hand `json:"hand,omitempty"` // カードを受け取った後の手札
+	Game     *game.Game `json:"game,omitempty"` // 精算結果
+	Deadline *time.Time `json:"deadline,omitempty"`
+	Enabled  *bool      `json:"enabled,omitempty"` // オートパイロットの状態
+}
+
+// Subscription はテーブルのイベント購読です。
+// Snapshot が nil でなければ、クライアントはスナップショットから状態を再構築し、以降 C のイベントを適用します。
+// Snapshot が nil なら Backlog（取りこぼしたイベント）を適用してから C のイベントを適用します。
+type Subscription struct {
+	Snapshot *Snapshot
+	Backlog  []Event
+	C        <-chan Event // 送信が追いつかなくなるか、テーブルが閉じられると close される
+	id       int
+	table    *Table
+	released bool
+}
+
+// Close は購読を解除します。
+func (s *Subscription) Close() {
+	s.table.mu.Lock()
+	defer s.table.mu.Unlock()
+	if ch, ok := s.table.subs[s.id]; ok {
+		delete(s.table.subs, s.id)
+		close(ch)
+	}
+	if !s.released {
+		s.released = true
+		s.table.open.Done()
+	}
+}
+
+// TableClosed はテーブルが閉じられたかどうかを返します。C が close された理由（送信の遅れか停止か）の判別に使います。
+func (s *Subscription) TableClosed() bool {
+	s.table.mu.Lock()
+	defer s.table.mu.Unlock()
+	return s.table.closed
+}
+
+// Subscribe はイベントの購読を開始します。
+// resume が true なら lastSeq より後のイベントを Backlog として返します。再送できない場合や resume が false の場合は
+// 現在のスナップショットを返します。
+func (t *Table) Subscribe(lastSeq uint64, resume bool) *Subscription {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	ch := make(chan Event, subscriberBuffer)
+	t.nextSubID++
+	id := t.nextSubID
+	t.open.Add(1)
+	sub := &Subscription{C: ch, id: id, table: t}
+	if t.closed {
+		close(ch)
+		return sub
+	}
+	t.subs[id] = ch
+
+	if resume {
+		if backlog, err := t.eventsAfter(lastSeq); err == nil {
+			sub.Backlog = backlog
+			return sub
+		}
+	}
+	snap := t.snapshot()
+	sub.Snapshot = &snap
+	return sub
+}
+
+// EventsAfter は lastSeq より後のイベントを返します。
+func (t *Table) EventsAfter(lastSeq uint64) ([]Event, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.eventsAfter(lastSeq)
+}
+
+func (t *Table) eventsAfter(lastSeq uint64) ([]Event, error) {
+	if lastSeq > t.seq {
+		return nil, ErrResumeGap
+	}
+	if len(t.log) > 0 && lastSeq+1 < t.log[0].Seq {
+		return nil, ErrResumeGap
+	}
+	var events []Event
+	for _, e := range t.log {
+		if e.Seq > lastSeq {
+			events = append(events, e)
+		}
+	}
+	return events, nil
+}
+
+// emit はイベントに連番を振って記録し、購読者に配信します（呼び出し側でロックを取ること）。
+func (t *Table) emit(e Event) {
+	t.seq++
+	e.Seq = t.seq
+	t.log = append(t.log, e)
+	if len(t.log) > maxEventLog {
+		t.log = t.log[len(t.log)-maxEventLog:]
+	}
+	for id, ch := range t.subs {
+		select {
+		case ch <- e:
+		default:
+			// 受信が追いつかない購読者は切断する
+			delete(t.subs, id)
+			close(ch)
+		}
+	}
+}
+
+func cardPtr(c game.Card) *game.Card {
+	return &c
+}
+
+func handPtr(h game.Hand) *game.Hand {
+	h = copyHand(h)
+	return &h
+}
+
+func gamePtr(g game.Game) *game.Game {
+	g.PlayerHand = copyHand(g.PlayerHand)
+	g.DealerHand = copyHand(g.DealerHand)
+	// 精算済みのゲームなので、とれる行動は空になる（テーブルはクラシックのルールのみ）
+	g.UpdateAllowedActions(game.ClassicRules())
+	return &g
+}
@@ -0,0 +1,86 @@
+package table
+
+import (
+	"testing"
+
+	"blackjack/api/game"
+)
+
+func TestTable_EventsAreSequencedAndDelivered(t *testing.T) {
+	tbl := newTestTable(card("10"), card("9"), card("8"), card("K"))
+	sub := tbl.Subscribe(0, false)
+	defer sub.Close()
+	if sub.Snapshot == nil || sub.Snapshot.Seq != 0 {
+		t.Fatalf("expected initial snapshot at seq 0, got %+v", sub.Snapshot)
+	}
+
+	tbl.Join("alice", -1)
+	tbl.PlaceBet("alice", 100)
+	tbl.Deal()
+	tbl.Stand("alice")
+
+	var got []EventType
+	var last uint64
+	for len(sub.C) > 0 {
+		e := <-sub.C
+		if e.Seq != last+1 {
+			t.Fatalf("expected seq %d, got %d", last+1, e.Seq)
+		}
+		last = e.Seq
+		got = append(got, e.Type)
+	}
+	want := []EventType{
+		EventPlayerJoined, EventBetPlaced, EventRoundStarted,
+		EventCardDealt, EventCardDealt, EventCardDealt, EventTurnChanged,
+		EventPlayerAction, EventDealerDraw, EventSettlement, EventRoundOver,
+	}
+	if len(got) != len(want) {
+		t.Fatalf("expected events %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("event %d: expected %s, got %s", i, want[i], got[i])
+		}
+	}
+}
+
+func TestTable_SubscribeResume(t *testing.T) {
+	tbl := newTestTable()
+	tbl.Join("alice", -1)
+	tbl.Join("bob", -1)
+
+	sub := tbl.Subscribe(1, true)
+	defer sub.Close()
+	if sub.Snapshot != nil || len(sub.Backlog) != 1 || sub.Backlog[0].PlayerID != "bob" {
+		t.Fatalf("expected backlog with bob's join, got %+v", sub)
+	}
+
+	if _, err := tbl.EventsAfter(10); err != ErrResumeGap {
+		t.Fatalf("expected ErrResumeGap for future seq, got %v", err)
+	}
+
+	// 古すぎるイベントは再送できない
+	for i := 0; i < maxEventLog; i++ {
+		tbl.Leave("bob")
+		tbl.Join("bob", -1)
+	}
+	if _, err := tbl.EventsAfter(1); err != ErrResumeGap {
+		t.Fatalf("expected ErrResumeGap for trimmed events, got %v", err)
+	}
+	if s := tbl.Subscribe(1, true); s.Snapshot == nil {
+		t.Fatalf("expected snapshot when resume is impossible")
+	}
+}
+
+func TestTable_SlowSubscriberIsDropped(t *testing.T) {
+	tbl := New("t", &scriptedShoe{}, game.GameConfig{DealerStandThreshold: 17})
+	sub := tbl.Subscribe(0, false)
+	for i := 0; i <= subscriberBuffer; i++ {
+		tbl.Join("p", -1)
+		tbl.Leave("p")
+	}
+	// バッファを溢れさせた購読者のチャネルは close される
+	for range sub.C {
+	}
+	sub.Close()
+}
@@ -0,0 +1,107 @@
+package table
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+
+	"blackjack/api/game"
+)
+
+// idleTableTTL は接続のないままこの期間が過ぎたテーブルを捨てる
+const idleTableTTL = 30 * time.Minute
+
+// Manager はサーバー上のテーブルを ID で管理します。
+// 接続のないまま idleTableTTL が過ぎたテーブルは、次にテーブルを作成するときに捨てます。
+type Manager struct {
+	newShoe func() game.Deck
+	opts    Options
+	clock   Clock
+	tables  map[string]*Table
+	mu      sync.RWMutex
+}
+
+// NewManager はテーブルごとのシューを生成する関数と、全テーブル共通の持ち時間の設定を受け取り、Manager を生成します。
+func NewManager(newShoe func() game.Deck, opts Options) *Manager {
+	if newShoe == nil {
+		panic("newShoe must not be nil")
+	}
+	clock := opts.Clock
+	if clock == nil {
+		clock = realClock{}
+	}
+	return &Manager{newShoe: newShoe, opts: opts, clock: clock, tables: make(map[string]*Table)}
+}
+
+// Create は新しいテーブルを作成します。
+func (m *Manager) Create(config game.GameConfig) (*Table, error) {
+	if config.DealerStandThreshold < 1 || config.DealerStandThreshold > 21 {
+		return nil, fmt.Errorf("%w: dealer stand threshold must be between 1 and 21", game.ErrInvalidConfig)
+	}
+	// テーブルはクラシックのルールのみ対応（N枚チャーリーも未対応）
+	if rules, err := config.Rules(); err != nil || rules.Variant != game.VariantClassic {
+		return nil, fmt.Errorf("%w: tables only support the classic variant", game.ErrInvalidConfig)
+	}
+	if config.CharlieCards != 0 {
+		return nil, fmt.Errorf("%w: tables do not support the charlie rule", game.ErrInvalidConfig)
+	}
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return nil, err
+	}
+	t := NewWithOptions(hex.EncodeToString(b), m.newShoe(), config, m.opts)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.removeIdle()
+	m.tables[t.ID()] = t
+	return t, nil
+}
+
+// Get は ID に対応するテーブルを返します。
+func (m *Manager) Get(id string) (*Table, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	t, ok := m.tables[id]
+	return t, ok
+}
+
+// Close はサーバーの停止時に全てのテーブルを閉じて捨て（Table.Close）、
+// 開いている WebSocket が購読を解除するのを ctx が終わるまで待ちます。待ちきれなければ ctx のエラーを返します。
+func (m *Manager) Close(ctx context.Context) error {
+	m.mu.Lock()
+	tables := m.tables
+	m.tables = make(map[string]*Table)
+	m.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		for _, t := range tables {
+			t.Close()
+		}
+		for _, t := range tables {
+			t.open.Wait()
+		}
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// removeIdle は接続のないまま idleTableTTL が過ぎたテーブルを閉じて捨てます。m.mu を保持して呼びます。
+func (m *Manager) removeIdle() {
+	now := m.clock.Now()
+	for id, t := range m.tables {
+		if since, ok := t.idleSince(); ok && now.Sub(since) >= idleTableTTL {
+			t.Close()
+			delete(m.tables, id)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package table
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"blackjack/api/game"
+)
+
+func TestManager_RemovesIdleTables(t *testing.T) {
+	clock := newFakeClock()
+	m := NewManager(func() game.Deck { return &scriptedShoe{} }, Options{Clock: clock})
+	config := game.GameConfig{DealerStandThreshold: 17}
+
+	unused, _ := m.Create(config)
+	played, _ := m.Create(config)
+	played.Join("alice", 0)
+	played.Reconnect("alice")
+
+	// 接続のあるテーブルは残し、接続のないまま期限を過ぎたテーブルは次の作成で捨てる
+	clock.Advance(idleTableTTL)
+	m.Create(config)
+	if _, ok := m.Get(unused.ID()); ok {
+		t.Errorf("expected the table nobody connected to to be removed")
+	}
+	if _, ok := m.Get(played.ID()); !ok {
+		t.Fatalf("expected the connected table to be kept")
+	}
+
+	// 最後の接続が切れてから期限を数える
+	played.Disconnect("alice")
+	clock.Advance(idleTableTTL - time.Second)
+	m.Create(config)
+	if _, ok := m.Get(played.ID()); !ok {
+		t.Fatalf("table removed before it was idle for %v", idleTableTTL)
+	}
+	clock.Advance(time.Second)
+	m.Create(config)
+	if _, ok := m.Get(played.ID()); ok {
+		t.Errorf("expected the table to be removed after the last connection closed")
+	}
+}
+
+func TestManager_CloseStopsTimersAndSubscriptions(t *testing.T) {
+	clock := newFakeClock()
+	// alice 10+8=18, ディーラー 9
+	m := NewManager(func() game.Deck { return &scriptedShoe{cards: []game.Card{card("10"), card("9"), card("8")}} },
+		Options{DecisionTimeout: 10 * time.Second, DisconnectGrace: 30 * time.Second, Clock: clock})
+	tbl, _ := m.Create(game.GameConfig{DealerStandThreshold: 17})
+	tbl.Reconnect("alice")
+	tbl.Join("alice", 0)
+	tbl.PlaceBet("alice", 100)
+	tbl.Deal()
+	sub := tbl.Subscribe(0, false)
+
+	// WebSocket と同じく、C が close されたら購読を解除する
+	go func() {
+		for range sub.C {
+		}
+		tbl.Disconnect("alice")
+		sub.Close()
+	}()
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := m.Close(ctx); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	if _, ok := m.Get(tbl.ID()); ok {
+		t.Errorf("expected the closed table to be removed")
+	}
+
+	// 持ち時間も切断の猶予も進まない
+	clock.Advance(time.Minute)
+	snap := tbl.Snapshot()
+	if snap.Phase != PlayerTurns || snap.Seats[0].Disconnected || snap.Seats[0].SittingOut {
+		t.Fatalf("expected the closed table to stop its timers, got %+v", snap)
+	}
+	// 閉じた後の購読はすぐに終わる
+	late := tbl.Subscribe(0, false)
+	defer late.Close()
+	if _, ok := <-late.C; ok || !late.TableClosed() {
+		t.Errorf("expected a subscription to a closed table to be closed")
+	}
+}
@@ -0,0 +1,504 @@
+package table
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"blackjack/api/game"
+	"blackjack/api/services"
+)
+
+// MaxSeats は1テーブルの最大席数
+const MaxSeats = 7
+
+// Phase はテーブル全体の進行状況を表します。
+type Phase string
+
+const (
+	Betting     Phase = "Betting"     // 着席・ベット受付中
+	PlayerTurns Phase = "PlayerTurns" // 席順にプレイヤーが行動中
+	RoundOver   Phase = "RoundOver"   // ディーラーの行動と精算が完了
+)
+
+var (
+	ErrTableFull     = errors.New("table is full")
+	ErrSeatTaken     = errors.New("seat is already taken")
+	ErrInvalidSeat   = errors.New("invalid seat number")
+	ErrNotSeated     = errors.New("player is not seated at this table")
+	ErrAlreadySeated = errors.New("player is already seated at this table")
+	ErrWrongPhase    = errors.New("action is not allowed in the current phase")
+	ErrNotYourTurn   = errors.New("it is not this player's turn")
+	ErrNoBets        = errors.New("no seat has placed a bet")
+)
+
+// Seat は1つの席を表します。Game は席の手札と精算結果を持ち、DealerHand は共有のディーラー手札の写しです。
+type Seat struct {
+	Number   int       `json:"number"`
+	PlayerID string    `json:"player_id"`
+	Game     game.Game `json:"game"`
+	Done     bool      `json:"done"` // このラウンドの行動を終えたか
+	// Autopilot の席は持ち時間切れのとき最適戦略の行動をとる（それ以外はスタンド）
+	Autopilot    bool `json:"autopilot"`
+	Disconnected bool `json:"disconnected"` // 接続が切れて再接続を待っている
+	SittingOut   bool `json:"sitting_out"`  // 切断の猶予が切れ、手番は自動で行動する
+}
+
+// Snapshot はクライアントに返すテーブルの状態
+type Snapshot struct {
+	ID         string    `json:"id"`
+	Phase      Phase     `json:"phase"`
+	Seats      []Seat    `json:"seats"`
+	DealerHand game.Hand `json:"dealer_hand"`
+	TurnSeat   int       `json:"turn_seat"` // 行動中の席番号（行動中の席がなければ -1）
+	// TurnDeadline は手番の持ち時間の期限（持ち時間がなければ null）
+	TurnDeadline *time.Time      `json:"turn_deadline"`
+	Config       game.GameConfig `json:"config"`
+	Seq          uint64          `json:"seq"` // このスナップショットに反映済みの最新イベント番号
+}
+
+// Table は最大 7 席が1つのシューと1人のディーラーを共有するテーブルです。
+// 全ての操作はスレッドセーフです。
+type Table struct {
+	id     string
+	config game.GameConfig
+	shoe   game.Deck
+	svc    services.GameService // サレンダーなど、単独ゲームと同じルールの再利用に使う
+	seats  [MaxSeats]*Seat
+	dealer game.Hand
+	phase  Phase
+	turn   int
+
+	// 持ち時間と切断の猶予
+	opts          Options
+	clock         Clock
+	decisionTimer Timer
+	decisionGen   uint64
+	deadline      *time.Time
+	grace         map[string]*graceTimer
+	graceGen      uint64
+	conns         map[string]int // プレイヤーごとの開いている接続の数
+	idleAt        time.Time      // 最後の接続が切れた時刻（接続されたことがなければ作成した時刻）
+
+	// イベント配信
+	seq       uint64
+	log       []Event
+	subs      map[int]chan Event
+	nextSubID int
+	open      sync.WaitGroup // Close されていない購読（開いている WebSocket）
+	closed    bool           // Close 済み。購読もタイマーも新たに始めない
+
+	mu sync.Mutex
+}
+
+// New はシューと設定を受け取り、持ち時間の制限のないテーブルを生成します。
+func New(id string, shoe game.Deck, config game.GameConfig) *Table {
+	return NewWithOptions(id, shoe, config, Options{})
+}
+
+// NewWithOptions は持ち時間や自動行動の設定を指定してテーブルを生成します。
+func NewWithOptions(id string, shoe game.Deck, config game.GameConfig, opts Options) *Table {
+	if shoe == nil {
+		panic("shoe must not be nil")
+	}
+	clock := opts.Clock
+	if clock == nil {
+		clock = realClock{}
+	}
+	return &Table{
+		id:     id,
+		config: config,
+		shoe:   shoe,
+		svc:    services.NewGameService(shoe),
+		phase:  Betting,
+		turn:   -1,
+		subs:   make(map[int]chan Event),
+		opts:   opts,
+		clock:  clock,
+		grace:  make(map[string]*graceTimer),
+		conns:  make(map[string]int),
+		idleAt: clock.Now(),
+	}
+}
+
+// ID はテーブルの ID を返します。
+func (t *Table) ID() string {
+	return t.id
+}
+
+// Close はサーバーの停止時にテーブルを閉じます。持ち時間と切断の猶予のタイマーを止め、
+// 全ての購読の C を close します（WebSocket はそれを見てソケットを閉じる）。以後の購読はすぐに close されます。
+func (t *Table) Close() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.closed = true
+	t.stopDecisionTimer()
+	for playerID := range t.grace {
+		t.stopGrace(playerID)
+	}
+	for id, ch := range t.subs {
+		delete(t.subs, id)
+		close(ch)
+	}
+}
+
+// Join はプレイヤーを指定した席に着席させます。seat が負なら空いている最小の席に着席させます。
+// 着席した席番号を返します。
+func (t *Table) Join(playerID string, seat int) (int, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if playerID == "" {
+		return 0, services.ErrPlayerIDRequired
+	}
+	if t.seatOf(playerID) != nil {
+		return 0, ErrAlreadySeated
+	}
+	if seat < 0 {
+		seat = -1
+		for i, s := range t.seats {
+			if s == nil {
+				seat = i
+				break
+			}
+		}
+		if seat < 0 {
+			return 0, ErrTableFull
+		}
+	}
+	if seat >= MaxSeats {
+		return 0, ErrInvalidSeat
+	}
+	if t.seats[seat] != nil {
+		return 0, ErrSeatTaken
+	}
+	t.seats[seat] = &Seat{Number: seat, PlayerID: playerID}
+	t.emit(Event{Type: EventPlayerJoined, Seat: seat, PlayerID: playerID})
+	return seat, nil
+}
+
+// Leave はプレイヤーを席から外します。ラウンド中は離席できません。
+func (t *Table) Leave(playerID string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	s := t.seatOf(playerID)
+	if s == nil {
+		return ErrNotSeated
+	}
+	if t.phase == PlayerTurns {
+		return ErrWrongPhase
+	}
+	t.removeSeat(s)
+	return nil
+}
+
+// removeSeat は席を外します（呼び出し側でロックを取ること）。
+func (t *Table) removeSeat(s *Seat) {
+	t.stopGrace(s.PlayerID)
+	t.seats[s.Number] = nil
+	t.emit(Event{Type: EventPlayerLeft, Seat: s.Number, PlayerID: s.PlayerID})
+}
+
+// PlaceBet はベット受付中にプレイヤーの掛け金を設定します。
+func (t *Table) PlaceBet(playerID string, bet int) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.phase != Betting {
+		return ErrWrongPhase
+	}
+	s := t.seatOf(playerID)
+	if s == nil {
+		return ErrNotSeated
+	}
+	if bet <= 0 {
+		return services.ErrInvalidBet
+	}
+	s.Game = game.Game{Bet: bet}
+	t.emit(Event{Type: EventBetPlaced, Seat: s.Number, PlayerID: playerID, Bet: bet})
+	return nil
+}
+
+// Deal はベットした席に席順で2枚ずつ、ディーラーに1枚配ってラウンドを開始します。
+// ブラックジャックの席はその場で精算され、行動を終えた扱いになります。
+func (t *Table) Deal() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.phase != Betting {
+		return ErrWrongPhase
+	}
+	var active []*Seat
+	for _, s := range t.seats {
+		if s != nil && s.Game.Bet > 0 {
+			active = append(active, s)
+		}
+	}
+	if len(active) == 0 {
+		return ErrNoBets
+	}
+
+	t.emit(Event{Type: EventRoundStarted, Seat: DealerSeat})
+
+	// 実際のテーブルと同じく、1 枚ずつ席順に2周配る
+	for _, s := range active {
+		c := t.shoe.Deal()
+		s.Game.PlayerHand = game.Hand{Cards: []game.Card{c}}
+		s.Game.PlayerHand.Score = game.CalculateScore(s.Game.PlayerHand.Cards)
+		t.emit(Event{Type: EventCardDealt, Seat: s.Number, PlayerID: s.PlayerID, Card: cardPtr(c), Hand: handPtr(s.Game.PlayerHand)})
+	}
+	c := t.shoe.Deal()
+	t.dealer = game.Hand{Cards: []game.Card{c}}
+	t.dealer.Score = game.CalculateScore(t.dealer.Cards)
+	t.emit(Event{Type: EventCardDealt, Seat: DealerSeat, Card: cardPtr(c), Hand: handPtr(t.dealer)})
+	for _, s := range active {
+		c := t.shoe.Deal()
+		s.Game.PlayerHand.Cards = append(s.Game.PlayerHand.Cards, c)
+		s.Game.PlayerHand.Score = game.CalculateScore(s.Game.PlayerHand.Cards)
+		t.emit(Event{Type: EventCardDealt, Seat: s.Number, PlayerID: s.PlayerID, Card: cardPtr(c), Hand: handPtr(s.Game.PlayerHand)})
+	}
+	for _, s := range active {
+		s.Game.DealerHand = copyHand(t.dealer)
+		s.Game.State = game.PlayerTurn
+		s.Game.Result = game.Pending
+		s.Game.Payout = 0
+		if services.SettleBlackjack(&s.Game) {
+			s.Done = true
+			t.emitSettlement(s)
+		}
+	}
+
+	t.phase = PlayerTurns
+	t.turn = -1
+	t.advance()
+	return nil
+}
+
+// Hit は手番のプレイヤーに1枚配ります。バーストまたは21で手番は次の席に移ります。
+func (t *Table) Hit(playerID string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	s, err := t.currentSeat(playerID)
+	if err != nil {
+		return err
+	}
+	t.hit(s, false)
+	return nil
+}
+
+// hit は手番の席に1枚配ります（呼び出し側でロックと手番の確認を行うこと）。auto は自動行動かどうか。
+func (t *Table) hit(s *Seat, auto bool) {
+	t.stopDecisionTimer()
+	c := t.shoe.Deal()
+	s.Game.PlayerHand.Cards = append(s.Game.PlayerHand.Cards, c)
+	s.Game.PlayerHand.Score = game.CalculateScore(s.Game.PlayerHand.Cards)
+	t.emit(Event{Type: EventPlayerAction, Seat: s.Number, PlayerID: s.PlayerID, Action: "hit", Auto: auto, Card: cardPtr(c), Hand: handPtr(s.Game.PlayerHand)})
+
+	switch s.Game.PlayerHand.Score {
+	case 0:
+		// バーストはディーラーを待たずに負けが確定する
+		services.SettleHand(&s.Game)
+		t.emitSettlement(s)
+		t.finishTurn(s)
+	case 21:
+		t.finishTurn(s)
+	default:
+		// 手番が続くので次の判断の持ち時間を開始する
+		t.beginDecision(s)
+	}
+}
+
+// Stand は手番のプレイヤーの行動を終え、手番を次の席に移します。
+func (t *Table) Stand(playerID string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	s, err := t.currentSeat(playerID)
+	if err != nil {
+		return err
+	}
+	t.stand(s, false)
+	return nil
+}
+
+func (t *Table) stand(s *Seat, auto bool) {
+	t.stopDecisionTimer()
+	t.emit(Event{Type: EventPlayerAction, Seat: s.Number, PlayerID: s.PlayerID, Action: "stand", Auto: auto})
+	t.finishTurn(s)
+}
+
+// Surrender は手番のプレイヤーをサレンダーさせます。条件は単独ゲームと同じ（最初の2枚のみ）です。
+func (t *Table) Surrender(playerID string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	s, err := t.currentSeat(playerID)
+	if err != nil {
+		return err
+	}
+	return t.surrender(s, false)
+}
+
+func (t *Table) surrender(s *Seat, auto bool) error {
+	if err := t.svc.Surrender(&s.Game, &t.config); err != nil {
+		return err
+	}
+	t.stopDecisionTimer()
+	t.emit(Event{Type: EventPlayerAction, Seat: s.Number, PlayerID: s.PlayerID, Action: "surrender", Auto: auto})
+	t.emitSettlement(s)
+	t.finishTurn(s)
+	return nil
+}
+
+// NewRound は精算の終わったテーブルを次のラウンドのベット受付に戻します。
+// 着席はそのまま残りますが、ラウンド中に離席扱いになった席は外します。
+func (t *Table) NewRound() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.phase != RoundOver {
+		return ErrWrongPhase
+	}
+	for _, s := range t.seats {
+		if s == nil {
+			continue
+		}
+		if s.SittingOut {
+			t.removeSeat(s)
+			continue
+		}
+		s.Game = game.Game{}
+		s.Done = false
+	}
+	t.dealer = game.Hand{}
+	t.phase = Betting
+	t.turn = -1
+	t.emit(Event{Type: EventRoundReset, Seat: DealerSeat})
+	return nil
+}
+
+// Snapshot は現在のテーブル状態のコピーを返します。
+func (t *Table) Snapshot() Snapshot {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.snapshot()
+}
+
+func (t *Table) snapshot() Snapshot {
+	snap := Snapshot{
+		ID:         t.id,
+		Phase:      t.phase,
+		Seats:      []Seat{},
+		DealerHand: copyHand(t.dealer),
+		TurnSeat:   t.turn,
+		Config:     t.config,
+		Seq:        t.seq,
+	}
+	if t.deadline != nil {
+		d := *t.deadline
+		snap.TurnDeadline = &d
+	}
+	for _, s := range t.seats {
+		if s == nil {
+			continue
+		}
+		c := *s
+		c.Game.PlayerHand = copyHand(s.Game.PlayerHand)
+		c.Game.DealerHand = copyHand(s.Game.DealerHand)
+		// 行動できるのは手番の席だけ。判定は単独ゲームと同じルールエンジンで行う
+		c.Game.AllowedActions = []game.Action{}
+		if t.phase == PlayerTurns && s.Number == t.turn {
+			c.Game.UpdateAllowedActions(game.ClassicRules())
+		}
+		snap.Seats = append(snap.Seats, c)
+	}
+	return snap
+}
+
+// currentSeat は playerID が手番の席であることを確認して返します（呼び出し側でロックを取ること）。
+func (t *Table) currentSeat(playerID string) (*Seat, error) {
+	if t.phase != PlayerTurns {
+		return nil, ErrWrongPhase
+	}
+	s := t.seatOf(playerID)
+	if s == nil {
+		return nil, ErrNotSeated
+	}
+	if s.Number != t.turn {
+		return nil, ErrNotYourTurn
+	}
+	return s, nil
+}
+
+// finishTurn は席の行動を終え、次の席に手番を移します。
+func (t *Table) finishTurn(s *Seat) {
+	s.Done = true
+	t.advance()
+}
+
+// advance は現在の手番より後ろで、まだ行動していない席に手番を移します。
+// 全ての席が行動を終えていれば、ディーラーの行動と精算を行います。
+func (t *Table) advance() {
+	for i := t.turn + 1; i < MaxSeats; i++ {
+		s := t.seats[i]
+		if s != nil && s.Game.Bet > 0 && !s.Done {
+			t.turn = i
+			t.emit(Event{Type: EventTurnChanged, Seat: i, PlayerID: s.PlayerID})
+			t.beginDecision(s)
+			return
+		}
+	}
+	t.turn = -1
+	t.playDealer()
+}
+
+// playDealer はディーラーが1度だけカードを引き、勝負の残っている全ての席を精算します。
+func (t *Table) playDealer() {
+	// 勝負の残っている席（スタンドした席）がなければディーラーは引かない
+	needDealer := false
+	for _, s := range t.seats {
+		if s != nil && s.Game.Bet > 0 && s.Game.State == game.PlayerTurn {
+			needDealer = true
+		}
+	}
+	if needDealer {
+		for t.dealer.Score < t.config.DealerStandThreshold && t.dealer.Score != 0 {
+			c := t.shoe.Deal()
+			t.dealer.Cards = append(t.dealer.Cards, c)
+			t.dealer.Score = game.CalculateScore(t.dealer.Cards)
+			t.emit(Event{Type: EventDealerDraw, Seat: DealerSeat, Card: cardPtr(c), Hand: handPtr(t.dealer)})
+		}
+	}
+
+	for _, s := range t.seats {
+		if s == nil || s.Game.Bet == 0 {
+			continue
+		}
+		s.Game.DealerHand = copyHand(t.dealer)
+		if s.Game.State == game.PlayerTurn {
+			services.SettleHand(&s.Game)
+			t.emitSettlement(s)
+		}
+	}
+	t.phase = RoundOver
+	t.emit(Event{Type: EventRoundOver, Seat: DealerSeat, Hand: handPtr(t.dealer)})
+}
+
+func (t *Table) emitSettlement(s *Seat) {
+	t.emit(Event{Type: EventSettlement, Seat: s.Number, PlayerID: s.PlayerID, Game: gamePtr(s.Game)})
+}
+
+func (t *Table) seatOf(playerID string) *Seat {
+	for _, s := range t.seats {
+		if s != nil && s.PlayerID == playerID {
+			return s
+		}
+	}
+	return nil
+}
+
+func copyHand(h game.Hand) game.Hand {
+	return game.Hand{Cards: append([]game.Card(nil), h.Cards...), Score: h.Score}
+}
@@ -0,0 +1,162 @@
+package table
+
+import (
+	"testing"
+
+	"blackjack/api/game"
+)
+
+// scriptedShoe はテスト用に決められたカードを順番に配るシューです
+type scriptedShoe struct {
+	cards []game.Card
+}
+
+func (s *scriptedShoe) Deal() game.Card {
+	c := s.cards[0]
+	s.cards = s.cards[1:]
+	return c
+}
+
+func card(rank game.Rank) game.Card {
+	return game.Card{Suit: game.Spade, Rank: rank}
+}
+
+func newTestTable(cards ...game.Card) *Table {
+	return New("t1", &scriptedShoe{cards: cards}, game.GameConfig{DealerStandThreshold: 17})
+}
+
+func TestTable_FullRound(t *testing.T) {
+	// 配る順: alice1, bob1, carol1, dealer, alice2, bob2, carol2, その後のヒット・ディーラーのドロー
+	tbl := newTestTable(
+		card("10"), card("A"), card("10"), // 1枚目
+		card("9"),                       // ディーラー
+		card("8"), card("K"), card("6"), // 2枚目: alice 18, bob BJ, carol 16
+		card("Q"), // carol ヒット → バースト
+		card("8"), // ディーラー 9+8=17
+	)
+	for i, p := range []string{"alice", "bob", "carol"} {
+		if _, err := tbl.Join(p, i); err != nil {
+			t.Fatalf("join %s: %v", p, err)
+		}
+		if err := tbl.PlaceBet(p, 100); err != nil {
+			t.Fatalf("bet %s: %v", p, err)
+		}
+	}
+	if err := tbl.Deal(); err != nil {
+		t.Fatalf("deal: %v", err)
+	}
+
+	snap := tbl.Snapshot()
+	if snap.TurnSeat != 0 {
+		t.Fatalf("expected alice (seat 0) to act first, got %d", snap.TurnSeat)
+	}
+	if got := snap.Seats[0].Game.AllowedActions; len(got) != 3 || got[0] != game.ActionHit {
+		t.Fatalf("expected alice to be offered hit/stand/surrender, got %v", got)
+	}
+	if got := snap.Seats[2].Game.AllowedActions; len(got) != 0 {
+		t.Fatalf("expected no actions for carol before her turn, got %v", got)
+	}
+	if snap.Seats[1].Game.Result != game.PlayerWin || snap.Seats[1].Game.Payout != 250 || !snap.Seats[1].Done {
+		t.Fatalf("expected bob's blackjack to be settled at deal, got %+v", snap.Seats[1])
+	}
+
+	// 手番でないプレイヤーは行動できない
+	if err := tbl.Hit("carol"); err != ErrNotYourTurn {
+		t.Fatalf("expected ErrNotYourTurn, got %v", err)
+	}
+
+	if err := tbl.Stand("alice"); err != nil {
+		t.Fatalf("alice stand: %v", err)
+	}
+	// bob はブラックジャックで行動済みなので carol の手番
+	if got := tbl.Snapshot().TurnSeat; got != 2 {
+		t.Fatalf("expected carol (seat 2) to act, got %d", got)
+	}
+	if err := tbl.Hit("carol"); err != nil {
+		t.Fatalf("carol hit: %v", err)
+	}
+
+	snap = tbl.Snapshot()
+	if snap.Phase != RoundOver {
+		t.Fatalf("expected round over, got %s", snap.Phase)
+	}
+	if snap.DealerHand.Score != 17 || len(snap.DealerHand.Cards) != 2 {
+		t.Fatalf("expected dealer to draw once to 17, got %+v", snap.DealerHand)
+	}
+	if snap.Seats[0].Game.Result != game.PlayerWin || snap.Seats[0].Game.Payout != 200 {
+		t.Fatalf("expected alice 18 to beat dealer 17, got %+v", snap.Seats[0].Game)
+	}
+	if snap.Seats[2].Game.Result != game.DealerWin || snap.Seats[2].Game.Payout != 0 {
+		t.Fatalf("expected carol to bust, got %+v", snap.Seats[2].Game)
+	}
+	if snap.Seats[0].Game.DealerHand.Score != 17 {
+		t.Fatalf("expected seat game to carry the final dealer hand")
+	}
+
+	// 次のラウンド
+	if err := tbl.NewRound(); err != nil {
+		t.Fatalf("new round: %v", err)
+	}
+	if snap := tbl.Snapshot(); snap.Phase != Betting || len(snap.Seats) != 3 || snap.Seats[0].Game.Bet != 0 {
+		t.Fatalf("unexpected state after new round: %+v", snap)
+	}
+}
+
+func TestTable_DealerSkipsDrawWhenNoSeatStands(t *testing.T) {
+	tbl := newTestTable(card("10"), card("5"), card("6"), card("K"))
+	tbl.Join("alice", -1)
+	tbl.PlaceBet("alice", 100)
+	tbl.Deal()
+
+	// alice 16 でヒットしてバースト
+	if err := tbl.Hit("alice"); err != nil {
+		t.Fatalf("hit: %v", err)
+	}
+	snap := tbl.Snapshot()
+	if snap.Phase != RoundOver || len(snap.DealerHand.Cards) != 1 {
+		t.Fatalf("expected dealer not to draw, got %+v", snap)
+	}
+}
+
+func TestTable_SurrenderUsesSingleGameRules(t *testing.T) {
+	tbl := newTestTable(card("10"), card("9"), card("6"), card("2"), card("9"))
+	tbl.Join("alice", -1)
+	tbl.PlaceBet("alice", 100)
+	tbl.Deal()
+
+	// ヒット後はサレンダーできない
+	tbl.Hit("alice")
+	if err := tbl.Surrender("alice"); err == nil {
+		t.Fatalf("expected surrender to be rejected after hit")
+	}
+	if err := tbl.Stand("alice"); err != nil {
+		t.Fatalf("stand: %v", err)
+	}
+	if got := tbl.Snapshot().Seats[0].Game.Result; got != game.Push {
+		t.Fatalf("expected 18 vs 18 to push, got %s", got)
+	}
+}
+
+func TestTable_SeatingRules(t *testing.T) {
+	tbl := newTestTable()
+	for i := 0; i < MaxSeats; i++ {
+		if _, err := tbl.Join(string(rune('a'+i)), -1); err != nil {
+			t.Fatalf("join %d: %v", i, err)
+		}
+	}
+	if _, err := tbl.Join("extra", -1); err != ErrTableFull {
+		t.Fatalf("expected ErrTableFull, got %v", err)
+	}
+	if _, err := tbl.Join("a", -1); err != ErrAlreadySeated {
+		t.Fatalf("expected ErrAlreadySeated, got %v", err)
+	}
+	if err := tbl.Leave("a"); err != nil {
+		t.Fatalf("leave: %v", err)
+	}
+	if _, err := tbl.Join("extra", 0); err != nil {
+		t.Fatalf("expected to take freed seat: %v", err)
+	}
+	if err := tbl.Deal(); err != ErrNoBets {
+		t.Fatalf("expected ErrNoBets, got %v", err)
+	}
+}
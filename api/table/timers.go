@@ -0,0 +1,235 @@
+package table
+
+import (
+	"time"
+
+	"blackjack/api/services"
+	"blackjack/api/strategy"
+)
+
+// Options はテーブルの持ち時間と自動行動の設定です。ゼロ値なら持ち時間の制限はありません。
+type Options struct {
+	// DecisionTimeout は 1 回の判断にかけられる時間。過ぎると自動で行動する（0 以下なら無制限）
+	DecisionTimeout time.Duration
+	// DisconnectGrace は切断から自動プレイ（離席扱い）に切り替わるまでの猶予（0 以下なら即座に切り替わる）
+	DisconnectGrace time.Duration
+	// Advisor はオートパイロットの席の行動を決める。nil ならオートパイロットでもスタンドする
+	Advisor services.StrategyAdvisor
+	// Clock はタイマーに使う時計。nil なら実時間
+	Clock Clock
+}
+
+// graceTimer は切断した席の猶予タイマーです。gen は停止済みのタイマーが発火した場合に無視するための番号です。
+type graceTimer struct {
+	timer Timer
+	gen   uint64
+}
+
+// SetAutopilot は席のオートパイロットを切り替えます。
+// オートパイロットの席は持ち時間切れのとき、スタンドではなく最適戦略の行動をとります。
+func (t *Table) SetAutopilot(playerID string, enabled bool) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	s := t.seatOf(playerID)
+	if s == nil {
+		return ErrNotSeated
+	}
+	if s.Autopilot == enabled {
+		return nil
+	}
+	s.Autopilot = enabled
+	t.emit(Event{Type: EventAutopilotChanged, Seat: s.Number, PlayerID: playerID, Enabled: boolPtr(enabled)})
+	return nil
+}
+
+// Disconnect はプレイヤーの接続が切れたことを記録し、猶予タイマーを開始します。
+// 猶予内に Reconnect されなければ席は離席扱いになり、手番は待たずに自動で行動します。
+// ベット受付中・精算後に猶予が切れた場合はそのまま席を外します。
+// 同じプレイヤーの接続が Reconnect で他にも開いていれば、最後の接続が切れるまで何もしません。
+func (t *Table) Disconnect(playerID string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if n := t.conns[playerID]; n > 0 {
+		if n > 1 {
+			t.conns[playerID] = n - 1
+			return nil
+		}
+		delete(t.conns, playerID)
+		if len(t.conns) == 0 {
+			t.idleAt = t.clock.Now()
+		}
+	}
+
+	s := t.seatOf(playerID)
+	if s == nil {
+		return ErrNotSeated
+	}
+	// 停止時に閉じたソケットは猶予を始めない
+	if s.Disconnected || t.closed {
+		return nil
+	}
+	s.Disconnected = true
+
+	if t.opts.DisconnectGrace <= 0 {
+		t.emit(Event{Type: EventPlayerDisconnected, Seat: s.Number, PlayerID: playerID})
+		t.sitOut(s)
+		return nil
+	}
+	deadline := t.clock.Now().Add(t.opts.DisconnectGrace)
+	t.emit(Event{Type: EventPlayerDisconnected, Seat: s.Number, PlayerID: playerID, Deadline: &deadline})
+
+	t.graceGen++
+	gen := t.graceGen
+	t.grace[playerID] = &graceTimer{
+		gen:   gen,
+		timer: t.clock.AfterFunc(t.opts.DisconnectGrace, func() { t.onGraceExpired(playerID, gen) }),
+	}
+	return nil
+}
+
+// Reconnect はプレイヤーの接続（再接続）を記録し、猶予タイマーと離席扱いを解除します。
+// 接続ごとに呼び、接続が切れたら同じ回数だけ Disconnect を呼んでください（着席前の接続も数えます）。
+func (t *Table) Reconnect(playerID string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.conns[playerID]++
+	s := t.seatOf(playerID)
+	if s == nil {
+		return ErrNotSeated
+	}
+	t.stopGrace(playerID)
+	if !s.Disconnected && !s.SittingOut {
+		return nil
+	}
+	s.Disconnected = false
+	s.SittingOut = false
+	t.emit(Event{Type: EventPlayerReconnected, Seat: s.Number, PlayerID: playerID})
+	return nil
+}
+
+// idleSince は接続も購読も 1 つもなければ、最後の接続が切れた時刻と true を返します。
+func (t *Table) idleSince() (time.Time, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if len(t.conns) > 0 || len(t.subs) > 0 {
+		return time.Time{}, false
+	}
+	return t.idleAt, true
+}
+
+func (t *Table) onGraceExpired(playerID string, gen uint64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	g, ok := t.grace[playerID]
+	if !ok || g.gen != gen {
+		return
+	}
+	delete(t.grace, playerID)
+	s := t.seatOf(playerID)
+	if s == nil || !s.Disconnected {
+		return
+	}
+	t.sitOut(s)
+}
+
+// sitOut は席を離席扱いにします（呼び出し側でロックを取ること）。
+// ラウンド中でなければ席を外し、ラウンド中で手番なら即座に自動で行動します。
+func (t *Table) sitOut(s *Seat) {
+	s.SittingOut = true
+	t.emit(Event{Type: EventPlayerSatOut, Seat: s.Number, PlayerID: s.PlayerID})
+
+	if t.phase != PlayerTurns {
+		t.removeSeat(s)
+		return
+	}
+	if s.Number == t.turn {
+		t.stopDecisionTimer()
+		t.autoPlay(s)
+	}
+}
+
+func (t *Table) stopGrace(playerID string) {
+	if g, ok := t.grace[playerID]; ok {
+		g.timer.Stop()
+		delete(t.grace, playerID)
+	}
+}
+
+// beginDecision は手番の席に判断を求めます（呼び出し側でロックを取ること）。
+// 離席中の席は待たずに自動で行動し、それ以外は持ち時間のタイマーを開始します。
+func (t *Table) beginDecision(s *Seat) {
+	if s.SittingOut {
+		t.autoPlay(s)
+		return
+	}
+	if t.opts.DecisionTimeout <= 0 {
+		return
+	}
+	t.stopDecisionTimer()
+	t.decisionGen++
+	gen := t.decisionGen
+	deadline := t.clock.Now().Add(t.opts.DecisionTimeout)
+	t.deadline = &deadline
+	t.decisionTimer = t.clock.AfterFunc(t.opts.DecisionTimeout, func() { t.onDecisionTimeout(gen) })
+	t.emit(Event{Type: EventTimerStarted, Seat: s.Number, PlayerID: s.PlayerID, Deadline: &deadline})
+}
+
+// stopDecisionTimer は持ち時間のタイマーを止めます（呼び出し側でロックを取ること）。
+func (t *Table) stopDecisionTimer() {
+	if t.decisionTimer != nil {
+		t.decisionTimer.Stop()
+		t.decisionTimer = nil
+	}
+	// 停止が間に合わずに発火したコールバックを無効にする
+	t.decisionGen++
+	t.deadline = nil
+}
+
+func (t *Table) onDecisionTimeout(gen uint64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if gen != t.decisionGen || t.phase != PlayerTurns || t.turn < 0 {
+		return
+	}
+	s := t.seats[t.turn]
+	t.decisionTimer = nil
+	t.deadline = nil
+	t.emit(Event{Type: EventTimerExpired, Seat: s.Number, PlayerID: s.PlayerID})
+	t.autoPlay(s)
+}
+
+// autoPlay は手番の席の代わりに 1 回行動します（呼び出し側でロックを取ること）。
+// ヒットして手番が続く場合は beginDecision で次の判断に進みます。
+func (t *Table) autoPlay(s *Seat) {
+	switch t.autoAction(s) {
+	case strategy.ActionHit:
+		t.hit(s, true)
+	case strategy.ActionSurrender:
+		if err := t.surrender(s, true); err != nil {
+			t.stand(s, true)
+		}
+	default:
+		t.stand(s, true)
+	}
+}
+
+// autoAction は自動行動で選ぶ行動を返します。オートパイロットの席は最適戦略、それ以外はスタンドです。
+func (t *Table) autoAction(s *Seat) strategy.Action {
+	if !s.Autopilot || t.opts.Advisor == nil {
+		return strategy.ActionStand
+	}
+	payouts, err := t.opts.Advisor.Advise(s.Game, &t.config)
+	if err != nil {
+		return strategy.ActionStand
+	}
+	return payouts.BestAction()
+}
+
+func boolPtr(b bool) *bool {
+	return &b
+}
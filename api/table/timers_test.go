@@ -0,0 +1,294 @@
+package table
+
+import (
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"blackjack/api/game"
+	"blackjack/api/strategy"
+)
+
+// fakeClock は Advance で時刻を進めたときだけタイマーを発火させるテスト用の時計です
+type fakeClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	timers []*fakeTimer
+}
+
+type fakeTimer struct {
+	at      time.Time
+	f       func()
+	stopped bool
+	clock   *fakeClock
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) AfterFunc(d time.Duration, f func()) Timer {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	t := &fakeTimer{at: c.now.Add(d), f: f, clock: c}
+	c.timers = append(c.timers, t)
+	return t
+}
+
+func (t *fakeTimer) Stop() bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+	wasActive := !t.stopped
+	t.stopped = true
+	return wasActive
+}
+
+// Advance は時刻を d 進め、期限の来たタイマーを期限順に同期的に発火させます
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	c.now = c.now.Add(d)
+	c.mu.Unlock()
+	for {
+		c.mu.Lock()
+		var due []*fakeTimer
+		for _, t := range c.timers {
+			if !t.stopped && !t.at.After(c.now) {
+				due = append(due, t)
+			}
+		}
+		sort.Slice(due, func(i, j int) bool { return due[i].at.Before(due[j].at) })
+		if len(due) == 0 {
+			c.mu.Unlock()
+			return
+		}
+		due[0].stopped = true
+		c.mu.Unlock()
+		due[0].f()
+	}
+}
+
+// hitBelow17Advisor は 17 未満ならヒット、それ以外はスタンドを勧める
+type hitBelow17Advisor struct{}
+
+func (hitBelow17Advisor) Advise(g game.Game, config *game.GameConfig) (strategy.StrategyExpectedPayouts, error) {
+	if g.PlayerHand.Score < 17 {
+		return strategy.StrategyExpectedPayouts{HitPayout: 1, StandPayout: 0.5, BestPayout: 1}, nil
+	}
+	return strategy.StrategyExpectedPayouts{HitPayout: 0.5, StandPayout: 1, BestPayout: 1}, nil
+}
+
+func newTimedTable(clock *fakeClock, opts Options, cards ...game.Card) *Table {
+	opts.Clock = clock
+	return NewWithOptions("t1", &scriptedShoe{cards: cards}, game.GameConfig{DealerStandThreshold: 17}, opts)
+}
+
+func drain(sub *Subscription) []Event {
+	var events []Event
+	for len(sub.C) > 0 {
+		events = append(events, <-sub.C)
+	}
+	return events
+}
+
+func findEvent(events []Event, typ EventType, seat int) (Event, bool) {
+	for _, e := range events {
+		if e.Type == typ && e.Seat == seat {
+			return e, true
+		}
+	}
+	return Event{}, false
+}
+
+func TestTable_DecisionTimeoutStands(t *testing.T) {
+	clock := newFakeClock()
+	// alice 10+8=18, ディーラー 9 → 8 で 17
+	tbl := newTimedTable(clock, Options{DecisionTimeout: 10 * time.Second},
+		card("10"), card("9"), card("8"), card("8"))
+	tbl.Join("alice", 0)
+	tbl.PlaceBet("alice", 100)
+	sub := tbl.Subscribe(0, false)
+	defer sub.Close()
+
+	if err := tbl.Deal(); err != nil {
+		t.Fatalf("deal: %v", err)
+	}
+	started, ok := findEvent(drain(sub), EventTimerStarted, 0)
+	if !ok || !started.Deadline.Equal(clock.Now().Add(10*time.Second)) {
+		t.Fatalf("expected timer_started with deadline, got %+v", started)
+	}
+	if snap := tbl.Snapshot(); snap.TurnDeadline == nil || !snap.TurnDeadline.Equal(*started.Deadline) {
+		t.Fatalf("expected snapshot to carry the turn deadline, got %v", snap.TurnDeadline)
+	}
+
+	clock.Advance(9 * time.Second)
+	if got := tbl.Snapshot().Phase; got != PlayerTurns {
+		t.Fatalf("expected alice to still be deciding, got %s", got)
+	}
+
+	clock.Advance(time.Second)
+	events := drain(sub)
+	if _, ok := findEvent(events, EventTimerExpired, 0); !ok {
+		t.Fatalf("expected timer_expired, got %+v", events)
+	}
+	action, ok := findEvent(events, EventPlayerAction, 0)
+	if !ok || action.Action != "stand" || !action.Auto {
+		t.Fatalf("expected automatic stand, got %+v", action)
+	}
+	snap := tbl.Snapshot()
+	if snap.Phase != RoundOver || snap.Seats[0].Game.Result != game.PlayerWin {
+		t.Fatalf("expected alice's 18 to beat dealer's 17, got %+v", snap)
+	}
+	if snap.TurnDeadline != nil {
+		t.Fatalf("expected no deadline after the round, got %v", snap.TurnDeadline)
+	}
+}
+
+func TestTable_ActingCancelsTimer(t *testing.T) {
+	clock := newFakeClock()
+	// alice 10+2=12 → ヒットで 5 (17)、bob 10+8=18
+	tbl := newTimedTable(clock, Options{DecisionTimeout: 10 * time.Second},
+		card("10"), card("10"), card("9"), card("2"), card("8"), card("5"), card("8"))
+	tbl.Join("alice", 0)
+	tbl.Join("bob", 1)
+	tbl.PlaceBet("alice", 100)
+	tbl.PlaceBet("bob", 100)
+	tbl.Deal()
+
+	clock.Advance(8 * time.Second)
+	if err := tbl.Hit("alice"); err != nil {
+		t.Fatalf("alice hit: %v", err)
+	}
+	// ヒット後は次の判断の持ち時間が新たに始まるので、元の期限を過ぎても手番は続く
+	clock.Advance(8 * time.Second)
+	if got := tbl.Snapshot().TurnSeat; got != 0 {
+		t.Fatalf("expected alice to still be deciding after hit, got seat %d", got)
+	}
+	if err := tbl.Stand("alice"); err != nil {
+		t.Fatalf("alice stand: %v", err)
+	}
+	clock.Advance(9 * time.Second)
+	if got := tbl.Snapshot().TurnSeat; got != 1 {
+		t.Fatalf("expected bob to still be deciding, got seat %d", got)
+	}
+	clock.Advance(time.Second)
+	if got := tbl.Snapshot().Phase; got != RoundOver {
+		t.Fatalf("expected bob to auto-stand, got %s", got)
+	}
+}
+
+func TestTable_AutopilotFollowsAdvisor(t *testing.T) {
+	clock := newFakeClock()
+	// alice 10+6=16 → オートパイロットで 3 を引いて 19 → スタンド、ディーラー 9+8=17
+	tbl := newTimedTable(clock, Options{DecisionTimeout: 10 * time.Second, Advisor: hitBelow17Advisor{}},
+		card("10"), card("9"), card("6"), card("3"), card("8"))
+	tbl.Join("alice", 0)
+	if err := tbl.SetAutopilot("alice", true); err != nil {
+		t.Fatalf("set autopilot: %v", err)
+	}
+	tbl.PlaceBet("alice", 100)
+	tbl.Deal()
+
+	clock.Advance(10 * time.Second)
+	snap := tbl.Snapshot()
+	if snap.Phase != PlayerTurns || snap.Seats[0].Game.PlayerHand.Score != 19 {
+		t.Fatalf("expected autopilot to hit to 19 and wait for the next decision, got %+v", snap.Seats[0].Game.PlayerHand)
+	}
+	clock.Advance(10 * time.Second)
+	snap = tbl.Snapshot()
+	if snap.Phase != RoundOver || snap.Seats[0].Game.Result != game.PlayerWin {
+		t.Fatalf("expected autopilot to stand on 19 and win, got %+v", snap.Seats[0].Game)
+	}
+}
+
+func TestTable_DisconnectGracePeriod(t *testing.T) {
+	clock := newFakeClock()
+	// 持ち時間の制限はなし。alice 10+8=18, bob 10+7=17, ディーラー 9 → 8 で 17
+	tbl := newTimedTable(clock, Options{DisconnectGrace: 30 * time.Second},
+		card("10"), card("10"), card("9"), card("8"), card("7"), card("8"))
+	tbl.Join("alice", 0)
+	tbl.Join("bob", 1)
+	tbl.PlaceBet("alice", 100)
+	tbl.PlaceBet("bob", 100)
+	tbl.Deal()
+
+	// 猶予内に再接続すれば何も起きない
+	tbl.Disconnect("alice")
+	clock.Advance(20 * time.Second)
+	tbl.Reconnect("alice")
+	clock.Advance(20 * time.Second)
+	if snap := tbl.Snapshot(); snap.TurnSeat != 0 || snap.Seats[0].SittingOut || snap.Seats[0].Disconnected {
+		t.Fatalf("expected alice to keep her turn after reconnecting, got %+v", snap)
+	}
+
+	// 猶予が切れると離席扱いになり、手番は自動でスタンドする
+	tbl.Disconnect("alice")
+	clock.Advance(30 * time.Second)
+	snap := tbl.Snapshot()
+	if !snap.Seats[0].SittingOut || snap.TurnSeat != 1 {
+		t.Fatalf("expected alice to sit out and bob to act, got %+v", snap)
+	}
+	if err := tbl.Stand("bob"); err != nil {
+		t.Fatalf("bob stand: %v", err)
+	}
+	if got := tbl.Snapshot().Seats[0].Game.Result; got != game.PlayerWin {
+		t.Fatalf("expected alice's auto-stand on 18 to win, got %s", got)
+	}
+
+	// 次のラウンドでは離席扱いの席は外される
+	if err := tbl.NewRound(); err != nil {
+		t.Fatalf("new round: %v", err)
+	}
+	snap = tbl.Snapshot()
+	if len(snap.Seats) != 1 || snap.Seats[0].PlayerID != "bob" {
+		t.Fatalf("expected only bob to remain seated, got %+v", snap.Seats)
+	}
+}
+
+func TestTable_DisconnectWhileBettingRemovesSeat(t *testing.T) {
+	clock := newFakeClock()
+	tbl := newTimedTable(clock, Options{DisconnectGrace: 30 * time.Second})
+	tbl.Join("alice", 0)
+	tbl.Disconnect("alice")
+
+	clock.Advance(29 * time.Second)
+	if len(tbl.Snapshot().Seats) != 1 {
+		t.Fatalf("expected alice to keep her seat during the grace period")
+	}
+	clock.Advance(time.Second)
+	if len(tbl.Snapshot().Seats) != 0 {
+		t.Fatalf("expected alice's seat to be released after the grace period")
+	}
+}
+
+func TestTable_DisconnectWaitsForLastConnection(t *testing.T) {
+	clock := newFakeClock()
+	tbl := newTimedTable(clock, Options{DisconnectGrace: 30 * time.Second})
+	// 2 つのソケットで接続してから着席する
+	tbl.Reconnect("alice")
+	tbl.Reconnect("alice")
+	tbl.Join("alice", 0)
+
+	// 片方のソケットを閉じても切断扱いにしない
+	tbl.Disconnect("alice")
+	clock.Advance(30 * time.Second)
+	if snap := tbl.Snapshot(); len(snap.Seats) != 1 || snap.Seats[0].Disconnected {
+		t.Fatalf("expected alice to stay connected while another socket is open, got %+v", snap.Seats)
+	}
+
+	// 最後のソケットが閉じたら猶予を開始する
+	tbl.Disconnect("alice")
+	if snap := tbl.Snapshot(); !snap.Seats[0].Disconnected {
+		t.Fatalf("expected alice to be disconnected after the last socket closed")
+	}
+	clock.Advance(30 * time.Second)
+	if len(tbl.Snapshot().Seats) != 0 {
+		t.Fatalf("expected alice's seat to be released after the grace period")
+	}
+}
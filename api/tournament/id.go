@@ -0,0 +1,15 @@
+package tournament
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+)
+
+// newID はランダムな 16 進数の ID を生成します。
+func newID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
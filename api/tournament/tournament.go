@@ -0,0 +1,555 @@
+package tournament
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"blackjack/api/game"
+	"blackjack/api/services"
+)
+
+// Status はトーナメントの進行状況を表します。
+type Status string
+
+const (
+	Registering Status = "Registering" // 参加受付中
+	Running     Status = "Running"     // ラウンド進行中
+	Finished    Status = "Finished"    // 決着済み
+)
+
+const (
+	// finishedTournamentTTL は終了したトーナメントの結果を保持する期間
+	finishedTournamentTTL = 24 * time.Hour
+	// registrationTTL は開始されないまま参加受付を続けるトーナメントを捨てるまでの期間
+	registrationTTL = 24 * time.Hour
+)
+
+var (
+	ErrNotFound          = errors.New("tournament not found")
+	ErrNotRegistering    = errors.New("tournament is not accepting registrations")
+	ErrAlreadyRegistered = errors.New("player is already registered")
+	ErrNotRegistered     = errors.New("player is not registered in this tournament")
+	ErrNotEnoughPlayers  = errors.New("at least 2 players are required to start")
+	ErrNotRunning        = errors.New("tournament is not running")
+	ErrNotCreator        = errors.New("only the tournament creator can start it")
+	ErrEliminated        = errors.New("player has been eliminated")
+	ErrHandInProgress    = errors.New("player already has a hand in progress")
+	ErrNoHandInProgress  = errors.New("player has no hand in progress")
+	ErrNoHandsLeft       = errors.New("player has played all hands in this round")
+	ErrBetOutOfRange     = errors.New("bet is outside the tournament limits")
+	ErrInsufficientChips = errors.New("not enough chips for this bet")
+	ErrInvalidConfig     = errors.New("invalid tournament config")
+)
+
+// Config はトーナメントの設定です。
+type Config struct {
+	StartingChips int `json:"starting_chips"`   // 参加者全員に配る初期チップ
+	HandsPerRound int `json:"hands_per_round"`  // 1 ラウンドで各プレイヤーがプレイするハンド数
+	MinBet        int `json:"min_bet"`          // 最低ベット。これを下回るチップしかないプレイヤーは脱落する
+	MaxBet        int `json:"max_bet"`          // 最高ベット（0 なら上限なし）
+	FinalTable    int `json:"final_table_size"` // ファイナルテーブルの人数
+	// RoundSeconds はラウンドの制限時間（0 なら全員がハンドを終えるまで待つ）
+	RoundSeconds int             `json:"round_seconds"`
+	Game         game.GameConfig `json:"game"`
+}
+
+// Validate は設定値の範囲を検証します。
+func (c Config) Validate() error {
+	if c.StartingChips <= 0 {
+		return fmt.Errorf("%w: starting chips must be positive", ErrInvalidConfig)
+	}
+	if c.HandsPerRound <= 0 {
+		return fmt.Errorf("%w: hands per round must be positive", ErrInvalidConfig)
+	}
+	if c.MinBet <= 0 || c.MinBet > c.StartingChips {
+		return fmt.Errorf("%w: min bet must be between 1 and the starting chips", ErrInvalidConfig)
+	}
+	if c.MaxBet != 0 && c.MaxBet < c.MinBet {
+		return fmt.Errorf("%w: max bet must be 0 (no limit) or at least the min bet", ErrInvalidConfig)
+	}
+	if c.FinalTable < 1 {
+		return fmt.Errorf("%w: final table size must be at least 1", ErrInvalidConfig)
+	}
+	if c.RoundSeconds < 0 {
+		return fmt.Errorf("%w: round seconds must be non-negative", ErrInvalidConfig)
+	}
+	if c.Game.DealerStandThreshold < 1 || c.Game.DealerStandThreshold > 21 {
+		return fmt.Errorf("%w: dealer stand threshold must be between 1 and 21", ErrInvalidConfig)
+	}
+	if _, err := c.Game.Rules(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Standing は順位表の1行です。
+type Standing struct {
+	Rank        int    `json:"rank"`
+	PlayerID    string `json:"player_id"`
+	Chips       int    `json:"chips"`        // トーナメント内のチップ残高（進行中のハンドの掛け金は含まない）
+	HandsPlayed int    `json:"hands_played"` // 現在のラウンドで決着したハンド数
+	InHand      bool   `json:"in_hand"`      // ハンドの途中か
+	Eliminated  bool   `json:"eliminated"`
+	// EliminatedRound は脱落したラウンド（脱落していなければ 0）
+	EliminatedRound int `json:"eliminated_round,omitempty"`
+}
+
+// Tournament はクライアントに返すトーナメントの状態です。
+type Tournament struct {
+	ID        string `json:"id"`
+	CreatedBy string `json:"created_by"` // トーナメントを作成したプレイヤー（開始できるのはこのプレイヤーのみ）
+	Config    Config `json:"config"`
+	Status    Status `json:"status"`
+	Round     int    `json:"round"` // 現在（終了後は最後）のラウンド。開始前は 0
+	// FinalTable は現在のラウンドがファイナルテーブルか
+	FinalTable    bool       `json:"final_table"`
+	RoundDeadline *time.Time `json:"round_deadline"`
+	Standings     []Standing `json:"standings"`
+	Winner        string     `json:"winner,omitempty"`
+}
+
+// Service はトーナメントの登録・進行・結果を提供するインタフェースです。
+// チップ残高はトーナメントごとに独立しており、各ハンドは GameService で進めます。
+// 終了したトーナメントは finishedTournamentTTL、開始されないトーナメントは registrationTTL を過ぎたら捨てます。
+type Service interface {
+	// Create は creator が作成したトーナメントとして参加受付を始めます
+	Create(creator string, config Config) (Tournament, error)
+	Get(id string) (Tournament, error)
+	Register(id, playerID string) (Tournament, error)
+	// Start はトーナメントを作成したプレイヤーだけが呼べます
+	Start(id, playerID string) (Tournament, error)
+	// Bet はチップから掛け金を引いて新しいハンドを配ります
+	Bet(id, playerID string, bet int) (game.Game, error)
+	Hit(id, playerID string) (game.Game, error)
+	Stand(id, playerID string) (game.Game, error)
+	Surrender(id, playerID string) (game.Game, error)
+	// Switch はブラックジャック・スイッチのトーナメントで2枚目のカードを入れ替えます
+	Switch(id, playerID string) (game.Game, error)
+}
+
+// entry はトーナメント参加者の状態
+type entry struct {
+	playerID        string
+	chips           int
+	handsPlayed     int
+	hand            *game.Game // 進行中のハンド
+	eliminated      bool
+	eliminatedRound int
+	order           int // 登録順。同じチップ数の並び順に使う
+}
+
+type tournament struct {
+	id         string
+	creator    string
+	config     Config
+	createdAt  time.Time
+	finishedAt time.Time // 終了していなければゼロ値
+	status     Status
+	round      int
+	finalTable bool
+	deadline   time.Time // 制限時間がなければゼロ値
+	entries    []*entry
+	winner     string
+}
+
+type service struct {
+	gameSvc     services.GameService
+	now         func() time.Time
+	tournaments map[string]*tournament
+	mu          sync.Mutex
+}
+
+// NewService は GameService でハンドを進めるトーナメントサービスを生成します。
+func NewService(gameSvc services.GameService) Service {
+	return newService(gameSvc, time.Now)
+}
+
+func newService(gameSvc services.GameService, now func() time.Time) *service {
+	if gameSvc == nil {
+		panic("gameSvc must not be nil")
+	}
+	return &service{gameSvc: gameSvc, now: now, tournaments: make(map[string]*tournament)}
+}
+
+// Create は参加受付中のトーナメントを作成します。
+func (s *service) Create(creator string, config Config) (Tournament, error) {
+	if creator == "" {
+		return Tournament{}, services.ErrPlayerIDRequired
+	}
+	if err := config.Validate(); err != nil {
+		return Tournament{}, err
+	}
+	id, err := newID()
+	if err != nil {
+		return Tournament{}, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	s.removeExpired(now)
+	t := &tournament{id: id, creator: creator, config: config, status: Registering, createdAt: now}
+	s.tournaments[id] = t
+	return t.view(), nil
+}
+
+// Get はトーナメントの状態と順位表を返します。制限時間を過ぎたラウンドはここで締め切られます。
+func (s *service) Get(id string) (Tournament, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t, err := s.get(id)
+	if err != nil {
+		return Tournament{}, err
+	}
+	return t.view(), nil
+}
+
+// Register はプレイヤーを参加登録し、初期チップを配ります。
+func (s *service) Register(id, playerID string) (Tournament, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t, err := s.get(id)
+	if err != nil {
+		return Tournament{}, err
+	}
+	if playerID == "" {
+		return Tournament{}, services.ErrPlayerIDRequired
+	}
+	if t.status != Registering {
+		return Tournament{}, ErrNotRegistering
+	}
+	if t.entry(playerID) != nil {
+		return Tournament{}, ErrAlreadyRegistered
+	}
+	t.entries = append(t.entries, &entry{playerID: playerID, chips: t.config.StartingChips, order: len(t.entries)})
+	return t.view(), nil
+}
+
+// Start は参加受付を締め切り、最初のラウンドを開始します。
+func (s *service) Start(id, playerID string) (Tournament, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t, err := s.get(id)
+	if err != nil {
+		return Tournament{}, err
+	}
+	if playerID != t.creator {
+		return Tournament{}, ErrNotCreator
+	}
+	if t.status != Registering {
+		return Tournament{}, ErrNotRegistering
+	}
+	if len(t.entries) < 2 {
+		return Tournament{}, ErrNotEnoughPlayers
+	}
+	t.status = Running
+	t.finalTable = len(t.entries) <= t.config.FinalTable
+	s.startRound(t)
+	return t.view(), nil
+}
+
+func (s *service) Bet(id, playerID string, bet int) (game.Game, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t, e, err := s.player(id, playerID)
+	if err != nil {
+		return game.Game{}, err
+	}
+	if e.hand != nil {
+		return game.Game{}, ErrHandInProgress
+	}
+	if e.handsPlayed >= t.config.HandsPerRound {
+		return game.Game{}, ErrNoHandsLeft
+	}
+	if bet < t.config.MinBet || (t.config.MaxBet > 0 && bet > t.config.MaxBet) {
+		return game.Game{}, ErrBetOutOfRange
+	}
+	// スイッチのように複数の手でプレイするルールでは、手の数だけ掛け金が必要
+	rules, err := t.config.Game.Rules()
+	if err != nil {
+		return game.Game{}, err
+	}
+	if bet*rules.Hands > e.chips {
+		return game.Game{}, ErrInsufficientChips
+	}
+
+	g, err := s.gameSvc.NewGame(bet, &t.config.Game)
+	if err != nil {
+		return game.Game{}, err
+	}
+	e.chips -= g.Bet
+	e.hand = &g
+	s.afterAction(t, e)
+	return g, nil
+}
+
+func (s *service) Hit(id, playerID string) (game.Game, error) {
+	return s.act(id, playerID, s.gameSvc.Hit)
+}
+
+func (s *service) Stand(id, playerID string) (game.Game, error) {
+	return s.act(id, playerID, s.gameSvc.Stand)
+}
+
+func (s *service) Surrender(id, playerID string) (game.Game, error) {
+	return s.act(id, playerID, s.gameSvc.Surrender)
+}
+
+func (s *service) Switch(id, playerID string) (game.Game, error) {
+	return s.act(id, playerID, s.gameSvc.Switch)
+}
+
+// act は進行中のハンドに行動を適用します。
+func (s *service) act(id, playerID string, action func(*game.Game, *game.GameConfig) error) (game.Game, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t, e, err := s.player(id, playerID)
+	if err != nil {
+		return game.Game{}, err
+	}
+	if e.hand == nil {
+		return game.Game{}, ErrNoHandInProgress
+	}
+	g := *e.hand
+	if err := action(&g, &t.config.Game); err != nil {
+		return game.Game{}, err
+	}
+	e.hand = &g
+	s.afterAction(t, e)
+	return g, nil
+}
+
+// get は ID のトーナメントを返し、制限時間を過ぎたラウンドを締め切ります（呼び出し側でロックを取ること）。
+func (s *service) get(id string) (*tournament, error) {
+	t, ok := s.tournaments[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	if t.expired(s.now()) {
+		delete(s.tournaments, id)
+		return nil, ErrNotFound
+	}
+	if t.status == Running && !t.deadline.IsZero() && !s.now().Before(t.deadline) {
+		s.closeRound(t)
+	}
+	return t, nil
+}
+
+// removeExpired は結果の保持期間を過ぎたトーナメントと、開始されないまま期限を過ぎたトーナメントを捨てます（呼び出し側でロックを取ること）。
+func (s *service) removeExpired(now time.Time) {
+	for id, t := range s.tournaments {
+		if t.expired(now) {
+			delete(s.tournaments, id)
+		}
+	}
+}
+
+// player はプレイ中のトーナメントと、脱落していない参加者を返します（呼び出し側でロックを取ること）。
+func (s *service) player(id, playerID string) (*tournament, *entry, error) {
+	t, err := s.get(id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if t.status != Running {
+		return nil, nil, ErrNotRunning
+	}
+	e := t.entry(playerID)
+	if e == nil {
+		return nil, nil, ErrNotRegistered
+	}
+	if e.eliminated {
+		return nil, nil, ErrEliminated
+	}
+	return t, e, nil
+}
+
+// afterAction は決着したハンドを精算し、全員がラウンドを終えていればラウンドを締め切ります。
+func (s *service) afterAction(t *tournament, e *entry) {
+	if e.hand.State == game.Finished {
+		e.chips += e.hand.Payout
+		e.handsPlayed++
+		e.hand = nil
+	}
+	for _, other := range t.entries {
+		if !other.eliminated && !t.roundDone(other) {
+			return
+		}
+	}
+	s.closeRound(t)
+}
+
+// closeRound はラウンドを締め切ります。進行中のハンドはスタンドで決着させ、
+// チップが最低ベットに届かないプレイヤーと、下位半分（ファイナルテーブルの人数は残す）を脱落させます。
+// ファイナルテーブルのラウンドが終わるか、残りが1人以下になればトーナメントは終了します。
+func (s *service) closeRound(t *tournament) {
+	for _, e := range t.active() {
+		if e.hand != nil {
+			// スイッチのように手が複数あれば、決着するまで残りの手もスタンドする
+			g := *e.hand
+			var err error
+			for g.State != game.Finished && err == nil {
+				err = s.gameSvc.Stand(&g, &t.config.Game)
+			}
+			if err == nil {
+				e.chips += g.Payout
+				e.handsPlayed++
+			}
+			// スタンドできないハンドは掛け金を失ったものとして扱う
+			e.hand = nil
+		}
+		if e.chips < t.config.MinBet {
+			t.eliminate(e)
+		}
+	}
+
+	active := t.active()
+	if t.finalTable || len(active) <= 1 {
+		t.finish(s.now())
+		return
+	}
+	if len(active) > t.config.FinalTable {
+		keep := (len(active) + 1) / 2
+		if keep < t.config.FinalTable {
+			keep = t.config.FinalTable
+		}
+		sortByChips(active)
+		for _, e := range active[keep:] {
+			t.eliminate(e)
+		}
+		active = active[:keep]
+	}
+	if len(active) <= 1 {
+		t.finish(s.now())
+		return
+	}
+	t.finalTable = len(active) <= t.config.FinalTable
+	s.startRound(t)
+}
+
+func (s *service) startRound(t *tournament) {
+	t.round++
+	for _, e := range t.entries {
+		e.handsPlayed = 0
+	}
+	t.deadline = time.Time{}
+	if t.config.RoundSeconds > 0 {
+		t.deadline = s.now().Add(time.Duration(t.config.RoundSeconds) * time.Second)
+	}
+}
+
+// roundDone はプレイヤーが現在のラウンドを終えたか（規定のハンド数を終えたか、もうベットできないか）を返します。
+func (t *tournament) roundDone(e *entry) bool {
+	if e.hand != nil {
+		return false
+	}
+	return e.handsPlayed >= t.config.HandsPerRound || e.chips < t.config.MinBet
+}
+
+func (t *tournament) eliminate(e *entry) {
+	e.eliminated = true
+	e.eliminatedRound = t.round
+}
+
+func (t *tournament) finish(now time.Time) {
+	t.status = Finished
+	t.finishedAt = now
+	t.deadline = time.Time{}
+	standings := t.standings()
+	t.winner = standings[0].PlayerID
+}
+
+// expired は終了後の保持期間、または開始されないまま参加受付の期限を過ぎたかを返します。
+func (t *tournament) expired(now time.Time) bool {
+	switch t.status {
+	case Finished:
+		return now.Sub(t.finishedAt) >= finishedTournamentTTL
+	case Registering:
+		return now.Sub(t.createdAt) >= registrationTTL
+	}
+	return false
+}
+
+func (t *tournament) active() []*entry {
+	var active []*entry
+	for _, e := range t.entries {
+		if !e.eliminated {
+			active = append(active, e)
+		}
+	}
+	return active
+}
+
+func (t *tournament) entry(playerID string) *entry {
+	for _, e := range t.entries {
+		if e.playerID == playerID {
+			return e
+		}
+	}
+	return nil
+}
+
+// standings は順位表を返します。残っているプレイヤーはチップ順、脱落したプレイヤーは遅く脱落した順・チップ順に並べます。
+func (t *tournament) standings() []Standing {
+	entries := append([]*entry(nil), t.entries...)
+	sort.SliceStable(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if a.eliminated != b.eliminated {
+			return !a.eliminated
+		}
+		if a.eliminatedRound != b.eliminatedRound {
+			return a.eliminatedRound > b.eliminatedRound
+		}
+		if a.chips != b.chips {
+			return a.chips > b.chips
+		}
+		return a.order < b.order
+	})
+	standings := make([]Standing, 0, len(entries))
+	for i, e := range entries {
+		standings = append(standings, Standing{
+			Rank:            i + 1,
+			PlayerID:        e.playerID,
+			Chips:           e.chips,
+			HandsPlayed:     e.handsPlayed,
+			InHand:          e.hand != nil,
+			Eliminated:      e.eliminated,
+			EliminatedRound: e.eliminatedRound,
+		})
+	}
+	return standings
+}
+
+func (t *tournament) view() Tournament {
+	v := Tournament{
+		ID:         t.id,
+		CreatedBy:  t.creator,
+		Config:     t.config,
+		Status:     t.status,
+		Round:      t.round,
+		FinalTable: t.finalTable,
+		Standings:  t.standings(),
+		Winner:     t.winner,
+	}
+	if !t.deadline.IsZero() {
+		d := t.deadline
+		v.RoundDeadline = &d
+	}
+	return v
+}
+
+// sortByChips はチップの多い順（同数なら登録順）に並べます。
+func sortByChips(entries []*entry) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].chips != entries[j].chips {
+			return entries[i].chips > entries[j].chips
+		}
+		return entries[i].order < entries[j].order
+	})
+}
@@ -0,0 +1,246 @@
+package tournament
+
+import (
+	"testing"
+	"time"
+
+	"blackjack/api/game"
+	"blackjack/api/services"
+)
+
+// scriptedDeck はテスト用に決められたカードを順番に配るデッキです
+type scriptedDeck struct {
+	cards []game.Card
+}
+
+func (d *scriptedDeck) Deal() game.Card {
+	c := d.cards[0]
+	d.cards = d.cards[1:]
+	return c
+}
+
+func cards(ranks ...game.Rank) []game.Card {
+	cs := make([]game.Card, len(ranks))
+	for i, r := range ranks {
+		cs[i] = game.Card{Suit: game.Spade, Rank: r}
+	}
+	return cs
+}
+
+func testConfig() Config {
+	return Config{
+		StartingChips: 1000,
+		HandsPerRound: 1,
+		MinBet:        100,
+		FinalTable:    2,
+		Game:          game.GameConfig{DealerStandThreshold: 17},
+	}
+}
+
+func setup(t *testing.T, svc *service, config Config, players ...string) string {
+	t.Helper()
+	tour, err := svc.Create("host", config)
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	for _, p := range players {
+		if _, err := svc.Register(tour.ID, p); err != nil {
+			t.Fatalf("register %s: %v", p, err)
+		}
+	}
+	if _, err := svc.Start(tour.ID, "host"); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	return tour.ID
+}
+
+// mustPlay はハンドの操作が成功したことを確認する関数を返します
+func mustPlay(t *testing.T) func(game.Game, error) game.Game {
+	return func(g game.Game, err error) game.Game {
+		t.Helper()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return g
+	}
+}
+
+func TestTournament_EliminatesLowestStacksAndPlaysFinalTable(t *testing.T) {
+	deck := &scriptedDeck{cards: cards(
+		// ラウンド 1
+		"10", "9", "7", "10", // p1: 19 vs 7→17 勝ち
+		"10", "7", "10", "9", // p2: 17 vs 10→19 負け
+		"10", "8", "10", "8", // p3: 18 vs 10→18 引き分け
+		"10", "6", "10", // p4: サレンダー
+		// ラウンド 2（ファイナルテーブル）
+		"A", "K", "10", // p1: ブラックジャック
+		"10", "5", "10", "K", // p3: ヒットでバースト
+	)}
+	svc := newService(services.NewGameService(deck), time.Now)
+	id := setup(t, svc, testConfig(), "p1", "p2", "p3", "p4")
+	must := mustPlay(t)
+
+	must(svc.Bet(id, "p1", 100))
+	must(svc.Stand(id, "p1"))
+	must(svc.Bet(id, "p2", 200))
+	must(svc.Stand(id, "p2"))
+	must(svc.Bet(id, "p3", 100))
+	must(svc.Stand(id, "p3"))
+	must(svc.Bet(id, "p4", 500))
+
+	// 規定のハンド数を終えたプレイヤーはラウンド中にもうベットできない
+	if _, err := svc.Bet(id, "p1", 100); err != ErrNoHandsLeft {
+		t.Fatalf("expected ErrNoHandsLeft, got %v", err)
+	}
+
+	must(svc.Surrender(id, "p4"))
+
+	tour, _ := svc.Get(id)
+	if tour.Round != 2 || !tour.FinalTable {
+		t.Fatalf("expected final table in round 2, got round %d final=%v", tour.Round, tour.FinalTable)
+	}
+	if _, err := svc.Bet(id, "p2", 100); err != ErrEliminated {
+		t.Fatalf("expected p2 to be eliminated, got %v", err)
+	}
+
+	must(svc.Bet(id, "p1", 100))
+	must(svc.Bet(id, "p3", 1000))
+	g := must(svc.Hit(id, "p3"))
+	if g.Result != game.DealerWin {
+		t.Fatalf("expected p3 to bust, got %+v", g)
+	}
+
+	tour, _ = svc.Get(id)
+	if tour.Status != Finished || tour.Winner != "p1" {
+		t.Fatalf("expected p1 to win, got %+v", tour)
+	}
+	want := []struct {
+		player string
+		chips  int
+		round  int
+	}{
+		{"p1", 1250, 0}, {"p3", 0, 2}, {"p2", 800, 1}, {"p4", 750, 1},
+	}
+	for i, w := range want {
+		s := tour.Standings[i]
+		if s.Rank != i+1 || s.PlayerID != w.player || s.Chips != w.chips || s.EliminatedRound != w.round {
+			t.Fatalf("standing %d: expected %+v, got %+v", i, w, s)
+		}
+	}
+}
+
+func TestTournament_RoundDeadlineClosesRound(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	// p1: 10+8=18 のハンドを放置 → 締め切りでスタンド、ディーラー 10→7 で 17
+	deck := &scriptedDeck{cards: cards("10", "8", "10", "7")}
+	svc := newService(services.NewGameService(deck), func() time.Time { return now })
+	config := testConfig()
+	config.RoundSeconds = 60
+	id := setup(t, svc, config, "p1", "p2")
+	must := mustPlay(t)
+
+	tour, _ := svc.Get(id)
+	if tour.RoundDeadline == nil || !tour.RoundDeadline.Equal(now.Add(time.Minute)) {
+		t.Fatalf("expected round deadline, got %v", tour.RoundDeadline)
+	}
+	must(svc.Bet(id, "p1", 100))
+
+	now = now.Add(time.Minute)
+	tour, _ = svc.Get(id)
+	// 2 人はファイナルテーブルの人数以下なので、最初のラウンドがファイナルテーブル
+	if tour.Status != Finished || tour.Winner != "p1" || tour.Standings[0].Chips != 1100 {
+		t.Fatalf("expected p1 to win after the deadline auto-stand, got %+v", tour)
+	}
+}
+
+func TestTournament_Validation(t *testing.T) {
+	svc := newService(services.NewGameService(&scriptedDeck{}), time.Now)
+
+	bad := testConfig()
+	bad.MinBet = 0
+	if _, err := svc.Create("host", bad); err == nil {
+		t.Fatalf("expected invalid config error")
+	}
+
+	tour, _ := svc.Create("host", testConfig())
+	svc.Register(tour.ID, "p1")
+	if _, err := svc.Register(tour.ID, "p1"); err != ErrAlreadyRegistered {
+		t.Fatalf("expected ErrAlreadyRegistered, got %v", err)
+	}
+	if _, err := svc.Start(tour.ID, "p1"); err != ErrNotCreator {
+		t.Fatalf("expected ErrNotCreator, got %v", err)
+	}
+	if _, err := svc.Start(tour.ID, "host"); err != ErrNotEnoughPlayers {
+		t.Fatalf("expected ErrNotEnoughPlayers, got %v", err)
+	}
+	if _, err := svc.Bet(tour.ID, "p1", 100); err != ErrNotRunning {
+		t.Fatalf("expected ErrNotRunning, got %v", err)
+	}
+	svc.Register(tour.ID, "p2")
+	svc.Start(tour.ID, "host")
+	if _, err := svc.Register(tour.ID, "p3"); err != ErrNotRegistering {
+		t.Fatalf("expected ErrNotRegistering, got %v", err)
+	}
+	if _, err := svc.Bet(tour.ID, "p1", 50); err != ErrBetOutOfRange {
+		t.Fatalf("expected ErrBetOutOfRange, got %v", err)
+	}
+	if _, err := svc.Bet(tour.ID, "p1", 2000); err != ErrInsufficientChips {
+		t.Fatalf("expected ErrInsufficientChips, got %v", err)
+	}
+	if _, err := svc.Stand(tour.ID, "p1"); err != ErrNoHandInProgress {
+		t.Fatalf("expected ErrNoHandInProgress, got %v", err)
+	}
+	if _, err := svc.Get("missing"); err != ErrNotFound {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestTournament_RoundDeadlineFinishesBothSwitchHands(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	// p1: 1つ目の手 10+8=18、2つ目の手 10+7=17 を放置 → 締め切りで両方の手をスタンド、ディーラー 10→7 で 17
+	deck := &scriptedDeck{cards: cards("10", "8", "10", "7", "10", "7")}
+	svc := newService(services.NewGameService(deck), func() time.Time { return now })
+	config := testConfig()
+	config.RoundSeconds = 60
+	config.Game.Variant = game.VariantSwitch
+	id := setup(t, svc, config, "p1", "p2")
+
+	mustPlay(t)(svc.Bet(id, "p1", 100))
+	now = now.Add(time.Minute)
+	tour, _ := svc.Get(id)
+	// 掛け金 200 に対し、18 の手が勝って 200、17 の手が引き分けで 100 が戻る
+	if tour.Status != Finished || tour.Winner != "p1" || tour.Standings[0].Chips != 1100 {
+		t.Fatalf("expected both switch hands to be settled at the deadline, got %+v", tour)
+	}
+}
+
+func TestTournament_RemovesExpiredTournaments(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	deck := &scriptedDeck{cards: cards("10", "8", "10", "7")}
+	svc := newService(services.NewGameService(deck), func() time.Time { return now })
+	config := testConfig()
+	config.RoundSeconds = 60
+	finished := setup(t, svc, config, "p1", "p2")
+	mustPlay(t)(svc.Bet(finished, "p1", 100))
+	waiting, _ := svc.Create("host", testConfig())
+
+	// 締め切りで終了したトーナメントの結果は保持期間の間だけ取得できる
+	now = now.Add(time.Minute)
+	if tour, _ := svc.Get(finished); tour.Status != Finished {
+		t.Fatalf("expected the tournament to finish at the deadline, got %+v", tour)
+	}
+	now = now.Add(finishedTournamentTTL - time.Second)
+	if _, err := svc.Get(finished); err != nil {
+		t.Fatalf("finished tournament removed too early: %v", err)
+	}
+	if _, err := svc.Get(waiting.ID); err != ErrNotFound {
+		t.Fatalf("expected the tournament that never started to be removed, got %v", err)
+	}
+	now = now.Add(time.Second)
+	if _, err := svc.Create("host", testConfig()); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := svc.tournaments[finished]; ok {
+		t.Fatalf("expected the finished tournament to be removed when another is created")
+	}
+}
@@ -26,7 +26,7 @@ export default function GameClient({ onThresholdChange }: GameClientProps) {
   };
 
   // サレンダーは最初の2枚のカードを受け取った後にのみ可能
-  const canSurrender = !!(game && game.player_hand.cards.length === 2);
+  const canSurrender = !!game?.allowed_actions?.includes('surrender');
 
   const gameInProgress = game?.state === 'PlayerTurn';
   const controlsDisabled = !gameInProgress || loading;
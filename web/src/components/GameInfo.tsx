@@ -103,7 +103,14 @@ export default function GameInfo({ game }: GameInfoProps) {
       }}
     >
 
-      <HandView title="プレイヤー" cards={game.player_hand.cards} score={game.player_hand.score} />
+      {game.hands.map((hand, i) => (
+        <HandView
+          key={i}
+          title={game.hands.length > 1 ? `プレイヤー（手${i + 1}）` : 'プレイヤー'}
+          cards={hand.cards}
+          score={hand.score}
+        />
+      ))}
 
       <HandView title="ディーラー" cards={game.dealer_hand.cards} score={game.dealer_hand.score} />
 
@@ -20,8 +20,8 @@ export default function useGame(apiUrl?: string | null, dealerThreshold?: number
   /**
    * ゲーム状態を取得して state / balance / error / loading を一括で更新する共通関数
    *
-   * @param endpoint API のパス（例: '/api/game/new'）
-   * @param payload  リクエストボディ
+   * @param endpoint API のパス（例: '/api/v2/game/new'）
+   * @param payload  リクエストボディ（行動では { game_id }）
    * @param betAmount 掛け金（新規ゲーム開始時のみ指定）
    */
   const fetchAndUpdateGame = useCallback(
@@ -35,21 +35,10 @@ export default function useGame(apiUrl?: string | null, dealerThreshold?: number
       setError(null);
 
       try {
-        // ゲーム進行のAPIには設定を含める
-        let requestBody;
-        if (endpoint === '/api/game/new') {
-          requestBody = payload as object; // 新規ゲーム開始時はconfigは不要
-        } else {
-          requestBody = {
-            game: payload,
-            config: { dealer_stand_threshold: dealerThreshold || DEFAULT_DEALER_THRESHOLD }
-          };
-        }
-
         const res = await fetch(`${apiUrl}${endpoint}`, {
           method: 'POST',
           headers: { 'Content-Type': 'application/json' },
-          body: JSON.stringify(requestBody),
+          body: JSON.stringify(payload),
         });
 
         if (!res.ok) throw new Error('APIからの応答がありませんでした');
@@ -72,11 +61,12 @@ export default function useGame(apiUrl?: string | null, dealerThreshold?: number
         setLoading(false);
       }
     },
-    [apiUrl, dealerThreshold],
+    [apiUrl],
   );
 
   /**
    * ゲームを開始します。
+   * ルール（ディーラーのスタンド閾値）は開始時に送り、以後の行動はサーバーが保持するゲームに対して行います。
    *
    * @param bet 掛け金
    */
@@ -101,9 +91,10 @@ export default function useGame(apiUrl?: string | null, dealerThreshold?: number
         return;
       }
 
-      await fetchAndUpdateGame('/api/game/new', { bet }, bet);
+      const config = { dealer_stand_threshold: dealerThreshold || DEFAULT_DEALER_THRESHOLD };
+      await fetchAndUpdateGame('/api/v2/game/new', { bet, config }, bet);
     },
-    [balance, game, fetchAndUpdateGame],
+    [balance, game, dealerThreshold, fetchAndUpdateGame],
   );
 
   /**
@@ -115,7 +106,7 @@ export default function useGame(apiUrl?: string | null, dealerThreshold?: number
       return;
     }
 
-    await fetchAndUpdateGame('/api/game/stand', game);
+    await fetchAndUpdateGame('/api/v2/game/stand', { game_id: game.id });
   }, [game, fetchAndUpdateGame]);
 
   /**
@@ -127,7 +118,7 @@ export default function useGame(apiUrl?: string | null, dealerThreshold?: number
       return;
     }
 
-    await fetchAndUpdateGame('/api/game/hit', game);
+    await fetchAndUpdateGame('/api/v2/game/hit', { game_id: game.id });
   }, [game, fetchAndUpdateGame]);
 
   /**
@@ -140,7 +131,7 @@ export default function useGame(apiUrl?: string | null, dealerThreshold?: number
       return;
     }
 
-    await fetchAndUpdateGame('/api/game/surrender', game);
+    await fetchAndUpdateGame('/api/v2/game/surrender', { game_id: game.id });
   }, [game, fetchAndUpdateGame]);
 
   /**
@@ -165,7 +156,7 @@ export default function useGame(apiUrl?: string | null, dealerThreshold?: number
       };
 
       // ゲーム状態と設定を一緒に送信
-      const res = await fetch(`${apiUrl}/api/strategy/advise`, {
+      const res = await fetch(`${apiUrl}/api/v2/strategy/advise`, {
         method: 'POST',
         headers: { 'Content-Type': 'application/json' },
         body: JSON.stringify({
@@ -218,7 +209,7 @@ export default function useGame(apiUrl?: string | null, dealerThreshold?: number
         setLoading(true);
         setError(null);
         
-        fetch(`${apiUrl}/api/strategy/advise`, {
+        fetch(`${apiUrl}/api/v2/strategy/advise`, {
           method: 'POST',
           headers: { 'Content-Type': 'application/json' },
           body: JSON.stringify({
@@ -10,15 +10,31 @@ export interface Hand {
 
 export type GameState = 'PlayerTurn' | 'Finished';
 export type Result = 'Pending' | 'PlayerWin' | 'DealerWin' | 'Push';
+export type Action = 'hit' | 'stand' | 'surrender' | 'switch';
 
+/** プレイヤーの手。掛け金と精算結果を手ごとに持つ */
+export interface PlayerHand extends Hand {
+  bet: number;
+  result: Result;
+  result_reason?: string;
+  payout: number;
+}
+
+/** /api/v2 のゲーム（プレイヤーの手は常に hands の配列。bet・payout は全ての手の合計） */
 export interface Game {
-  player_hand: Hand;
+  /** サーバーが保持するゲームの ID（行動のリクエストで game_id に指定する） */
+  id: string;
+  hands: PlayerHand[];
+  /** 行動中の手の添字 */
+  active_hand: number;
   dealer_hand: Hand;
   state: GameState;
   result: Result;
   result_message: string;
+  result_reason?: string;
   bet: number;
   payout: number;
+  allowed_actions: Action[];
 } 
 
 export interface StrategyAdvice {